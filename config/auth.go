@@ -0,0 +1,35 @@
+package config
+
+import "time"
+
+type Auth struct {
+	// Lifetime of the (JWT) access tokens, sessions are checked for revocation on each use.
+	AccessTokenTTL time.Duration `yaml:"access_token_ttl" envconfig:"AUTH_ACCESS_TOKEN_TTL"`
+	// Lifetime of the refresh tokens, every refresh rotates the token and extends the session.
+	RefreshTokenTTL time.Duration `yaml:"refresh_token_ttl" envconfig:"AUTH_REFRESH_TOKEN_TTL"`
+	// How long a Sign-In with Ethereum challenge (and its nonce) stays valid.
+	ChallengeTTL time.Duration `yaml:"challenge_ttl" envconfig:"AUTH_CHALLENGE_TTL"`
+	// How long an OpenID Connect authorization request (state, nonce and PKCE verifier) stays valid.
+	OIDCStateTTL time.Duration `yaml:"oidc_state_ttl" envconfig:"AUTH_OIDC_STATE_TTL"`
+	// OpenID Connect identity providers by name, only configurable from the config file.
+	OIDCProviders map[string]OIDCProvider `yaml:"oidc_providers" ignored:"true"`
+}
+
+type OIDCProvider struct {
+	DisplayName string `yaml:"display_name"`
+	// Issuer URL, the provider configuration is discovered from "<issuer>/.well-known/openid-configuration".
+	Issuer       string `yaml:"issuer"`
+	ClientID     string `yaml:"client_id"`
+	ClientSecret string `yaml:"client_secret"`
+	// Redirect URL registered at the provider, usually a frontend page passing code and state back to the node.
+	RedirectURL string `yaml:"redirect_url"`
+	// Additional scopes, "openid" is always requested.
+	Scopes []string `yaml:"scopes"`
+}
+
+func (x *Auth) Init() {
+	x.AccessTokenTTL = 15 * time.Minute
+	x.RefreshTokenTTL = 30 * 24 * time.Hour
+	x.ChallengeTTL = 5 * time.Minute
+	x.OIDCStateTTL = 10 * time.Minute
+}
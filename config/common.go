@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Common struct {
 	AgoraAppCertificate string `yaml:"agora_app_certificate" envconfig:"AGORA_APP_CERTIFICATE"`
 	AllowCORS           bool   `yaml:"allow_cors" envconfig:"ALLOW_CORS"`
@@ -7,7 +9,11 @@ type Common struct {
 
 	// Enabled pprof http endpoints. If enabled, point your browser at /debug/pprof
 	PProfAPI bool `yaml:"pprof_api" envconfig:"PPROF_API"`
+
+	// How long removed objects and worlds are kept in the trash before they are purged.
+	TrashRetention time.Duration `yaml:"trash_retention" envconfig:"TRASH_RETENTION"`
 }
 
 func (x *Common) Init() {
+	x.TrashRetention = 30 * 24 * time.Hour
 }
@@ -15,6 +15,7 @@ import (
 // Config : structure to hold configuration
 type Config struct {
 	Common     Common     `yaml:"common"`
+	Auth       Auth       `yaml:"auth"`
 	Settings   Local      `yaml:"settings"`
 	Postgres   Postgres   `yaml:"postgres"`
 	Influx     Influx     `yaml:"influx"`
@@ -30,6 +31,7 @@ const configFileName = "config.yaml"
 
 func (x *Config) Init() {
 	x.Common.Init()
+	x.Auth.Init()
 	x.Postgres.Init()
 	x.Settings.Init()
 	x.Influx.Init()
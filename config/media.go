@@ -1,5 +1,7 @@
 package config
 
+import "time"
+
 type Media struct {
 	Fontpath   string `yaml:"fontpath" envconfig:"RENDER_FONT_PATH"`
 	Imagepath  string `yaml:"image_path" envconfig:"RENDER_IMAGE_PATH"`
@@ -8,6 +10,29 @@ type Media struct {
 	Assetpath  string `yaml:"asset_path" envconfig:"RENDER_ASSET_PATH"`
 	Pluginpath string `yaml:"plugin_path" envconfig:"RENDER_PLUGIN_PATH"`
 	LogLevel   int8   `yaml:"loglevel"  envconfig:"RENDER_LOGLEVEL"`
+
+	// Where the uploaded files are kept: "filesystem" (in the paths above) or "s3".
+	Storage string  `yaml:"storage" envconfig:"MEDIA_STORAGE"`
+	S3      MediaS3 `yaml:"s3"`
+	// Redirect the render requests to signed urls of the storage instead of serving the files, only for "s3".
+	Redirect bool `yaml:"redirect" envconfig:"MEDIA_REDIRECT"`
+	// How long the signed urls of the redirects stay valid.
+	RedirectTTL time.Duration `yaml:"redirect_ttl" envconfig:"MEDIA_REDIRECT_TTL"`
+}
+
+type MediaS3 struct {
+	// Endpoint URL, e.g. "https://s3.eu-central-1.amazonaws.com" or "http://localhost:9000" for MinIO.
+	Endpoint  string `yaml:"endpoint" envconfig:"MEDIA_S3_ENDPOINT"`
+	Region    string `yaml:"region" envconfig:"MEDIA_S3_REGION"`
+	Bucket    string `yaml:"bucket" envconfig:"MEDIA_S3_BUCKET"`
+	AccessKey string `yaml:"access_key" envconfig:"MEDIA_S3_ACCESS_KEY"`
+	SecretKey string `yaml:"secret_key" envconfig:"MEDIA_S3_SECRET_KEY"`
+	// Address the bucket in the path instead of the host name, MinIO needs it.
+	PathStyle bool `yaml:"path_style" envconfig:"MEDIA_S3_PATH_STYLE"`
+	// Prefix of the keys, the files are stored under "<prefix>images/", "<prefix>videos/" etc.
+	Prefix string `yaml:"prefix" envconfig:"MEDIA_S3_PREFIX"`
+	// Base URL of a CDN or a public bucket, redirects go to "<public url>/<key>" without signature then.
+	PublicURL string `yaml:"public_url" envconfig:"MEDIA_S3_PUBLIC_URL"`
 }
 
 func (x *Media) Init() {
@@ -18,4 +43,7 @@ func (x *Media) Init() {
 	x.Assetpath = "./storage/assets"
 	x.Pluginpath = "./storage/plugins"
 	x.LogLevel = 0
+	x.Storage = "filesystem"
+	x.S3.Region = "us-east-1"
+	x.RedirectTTL = 15 * time.Minute
 }
@@ -0,0 +1,113 @@
+package api_keys
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getAPIKeysQuery    = `SELECT * FROM api_key ORDER BY created_at DESC;`
+	getAPIKeyByIDQuery = `SELECT * FROM api_key WHERE api_key_id = $1;`
+
+	insertAPIKeyQuery = `INSERT INTO api_key
+							(api_key_id, name, key_hash, scopes, created_by, expires_at)
+						VALUES
+							($1, $2, $3, $4, $5, $6);`
+
+	updateAPIKeyLastUsedAtQuery = `UPDATE api_key SET last_used_at = $2 WHERE api_key_id = $1;`
+	revokeAPIKeyQuery           = `UPDATE api_key SET revoked_at = NOW(), updated_at = NOW()
+										WHERE api_key_id = $1 AND revoked_at IS NULL;`
+
+	getAPIKeyAuditEntriesQuery = `SELECT * FROM api_key_audit
+									WHERE api_key_id = $1
+									ORDER BY created_at DESC
+									LIMIT $2;`
+	insertAPIKeyAuditEntryQuery = `INSERT INTO api_key_audit
+										(audit_id, api_key_id, method, path, scope, status, ip_address)
+									VALUES
+										($1, $2, $3, $4, $5, $6, $7);`
+)
+
+var _ database.APIKeysDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetAPIKeys(ctx context.Context) ([]*entry.APIKey, error) {
+	var apiKeys []*entry.APIKey
+	if err := pgxscan.Select(ctx, db.conn, &apiKeys, getAPIKeysQuery); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return apiKeys, nil
+}
+
+func (db *DB) GetAPIKeyByID(ctx context.Context, apiKeyID umid.UMID) (*entry.APIKey, error) {
+	var apiKey entry.APIKey
+	if err := pgxscan.Get(ctx, db.conn, &apiKey, getAPIKeyByIDQuery, apiKeyID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &apiKey, nil
+}
+
+func (db *DB) InsertAPIKey(ctx context.Context, apiKey *entry.APIKey) error {
+	if _, err := db.conn.Exec(
+		ctx, insertAPIKeyQuery,
+		apiKey.APIKeyID, apiKey.Name, apiKey.KeyHash, apiKey.Scopes, apiKey.CreatedBy, apiKey.ExpiresAt,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) UpdateAPIKeyLastUsedAt(ctx context.Context, apiKeyID umid.UMID, lastUsedAt time.Time) error {
+	if _, err := db.conn.Exec(ctx, updateAPIKeyLastUsedAtQuery, apiKeyID, lastUsedAt); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) RevokeAPIKey(ctx context.Context, apiKeyID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, revokeAPIKeyQuery, apiKeyID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) GetAPIKeyAuditEntries(
+	ctx context.Context, apiKeyID umid.UMID, limit uint,
+) ([]*entry.APIKeyAuditEntry, error) {
+	var auditEntries []*entry.APIKeyAuditEntry
+	if err := pgxscan.Select(ctx, db.conn, &auditEntries, getAPIKeyAuditEntriesQuery, apiKeyID, limit); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return auditEntries, nil
+}
+
+func (db *DB) InsertAPIKeyAuditEntry(ctx context.Context, auditEntry *entry.APIKeyAuditEntry) error {
+	if _, err := db.conn.Exec(
+		ctx, insertAPIKeyAuditEntryQuery,
+		auditEntry.AuditID, auditEntry.APIKeyID, auditEntry.Method, auditEntry.Path, auditEntry.Scope,
+		auditEntry.Status, auditEntry.IPAddress,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
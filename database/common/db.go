@@ -1,11 +1,27 @@
 package common
 
 import (
+	"context"
+
 	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
 
 	"github.com/momentum-xyz/ubercontroller/database"
 )
 
+const (
+	checkIsMediaReferencedQuery = `SELECT EXISTS (SELECT 1 FROM "user" WHERE profile::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM activity WHERE data::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM object WHERE options::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM node_attribute WHERE value::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM object_attribute WHERE value::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM object_user_attribute WHERE value::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM user_attribute WHERE value::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM user_user_attribute WHERE value::text LIKE $1)
+										OR EXISTS (SELECT 1 FROM object_attribute_history
+													WHERE old_value::text LIKE $1 OR new_value::text LIKE $1);`
+)
+
 var _ database.CommonDB = (*DB)(nil)
 
 type DB struct {
@@ -22,3 +38,11 @@ func NewDB(conn *pgxpool.Pool) *DB {
 func (db *DB) GetConnection() *pgxpool.Pool {
 	return db.conn
 }
+
+func (db *DB) CheckIsMediaReferenced(ctx context.Context, hash string) (bool, error) {
+	var referenced bool
+	if err := db.conn.QueryRow(ctx, checkIsMediaReferencedQuery, "%"+hash+"%").Scan(&referenced); err != nil {
+		return false, errors.WithMessage(err, "failed to query db")
+	}
+	return referenced, nil
+}
@@ -17,6 +17,7 @@ type DB struct {
 	database.ObjectActivitiesDB
 	database.UserActivitiesDB
 	database.ObjectsDB
+	database.ObjectTrashDB
 	database.UsersDB
 	database.Assets2dDB
 	database.Assets3dDB
@@ -27,9 +28,16 @@ type DB struct {
 	database.AttributeTypesDB
 	database.NodeAttributesDB
 	database.ObjectAttributesDB
+	database.ObjectAttributesHistoryDB
 	database.ObjectUserAttributesDB
 	database.UserAttributesDB
 	database.UserUserAttributesDB
+	database.UserSessionsDB
+	database.APIKeysDB
+	database.ModerationDB
+	database.UserRelationsDB
+	database.PrefabsDB
+	database.SearchDB
 	database.StakesDB
 	database.NFTsDB
 }
@@ -40,6 +48,7 @@ func NewDB(
 	nodes database.NodesDB,
 	worlds database.WorldsDB,
 	objects database.ObjectsDB,
+	objectTrash database.ObjectTrashDB,
 	activities database.ActivitiesDB,
 	userActivities database.UserActivitiesDB,
 	objectActivities database.ObjectActivitiesDB,
@@ -53,36 +62,51 @@ func NewDB(
 	attributeTypes database.AttributeTypesDB,
 	nodeAttributes database.NodeAttributesDB,
 	objectAttributes database.ObjectAttributesDB,
+	objectAttributesHistory database.ObjectAttributesHistoryDB,
 	objectUserAttributes database.ObjectUserAttributesDB,
 	userAttributes database.UserAttributesDB,
 	userUserAttributes database.UserUserAttributesDB,
+	userSessions database.UserSessionsDB,
+	apiKeys database.APIKeysDB,
+	moderation database.ModerationDB,
+	userRelations database.UserRelationsDB,
+	prefabs database.PrefabsDB,
+	search database.SearchDB,
 	stakesDB database.StakesDB,
 	nftsDB database.NFTsDB,
 ) *DB {
 	return &DB{
-		conn:                   conn,
-		CommonDB:               common,
-		NodesDB:                nodes,
-		WorldsDB:               worlds,
-		ActivitiesDB:           activities,
-		UserActivitiesDB:       userActivities,
-		ObjectActivitiesDB:     objectActivities,
-		ObjectsDB:              objects,
-		UsersDB:                users,
-		Assets2dDB:             assets2d,
-		Assets3dDB:             assets3d,
-		PluginsDB:              plugins,
-		ObjectTypesDB:          objectTypes,
-		UserObjectsDB:          userObjects,
-		UserTypesDB:            userTypes,
-		AttributeTypesDB:       attributeTypes,
-		NodeAttributesDB:       nodeAttributes,
-		ObjectAttributesDB:     objectAttributes,
-		ObjectUserAttributesDB: objectUserAttributes,
-		UserAttributesDB:       userAttributes,
-		UserUserAttributesDB:   userUserAttributes,
-		StakesDB:               stakesDB,
-		NFTsDB:                 nftsDB,
+		conn:                      conn,
+		CommonDB:                  common,
+		NodesDB:                   nodes,
+		WorldsDB:                  worlds,
+		ActivitiesDB:              activities,
+		UserActivitiesDB:          userActivities,
+		ObjectActivitiesDB:        objectActivities,
+		ObjectsDB:                 objects,
+		ObjectTrashDB:             objectTrash,
+		UsersDB:                   users,
+		Assets2dDB:                assets2d,
+		Assets3dDB:                assets3d,
+		PluginsDB:                 plugins,
+		ObjectTypesDB:             objectTypes,
+		UserObjectsDB:             userObjects,
+		UserTypesDB:               userTypes,
+		AttributeTypesDB:          attributeTypes,
+		NodeAttributesDB:          nodeAttributes,
+		ObjectAttributesDB:        objectAttributes,
+		ObjectAttributesHistoryDB: objectAttributesHistory,
+		ObjectUserAttributesDB:    objectUserAttributes,
+		UserAttributesDB:          userAttributes,
+		UserUserAttributesDB:      userUserAttributes,
+		UserSessionsDB:            userSessions,
+		APIKeysDB:                 apiKeys,
+		ModerationDB:              moderation,
+		UserRelationsDB:           userRelations,
+		PrefabsDB:                 prefabs,
+		SearchDB:                  search,
+		StakesDB:                  stakesDB,
+		NFTsDB:                    nftsDB,
 	}
 }
 
@@ -114,6 +138,10 @@ func (DB *DB) GetObjectsDB() database.ObjectsDB {
 	return DB.ObjectsDB
 }
 
+func (DB *DB) GetObjectTrashDB() database.ObjectTrashDB {
+	return DB.ObjectTrashDB
+}
+
 func (DB *DB) GetUsersDB() database.UsersDB {
 	return DB.UsersDB
 }
@@ -150,6 +178,10 @@ func (DB *DB) GetObjectAttributesDB() database.ObjectAttributesDB {
 	return DB.ObjectAttributesDB
 }
 
+func (DB *DB) GetObjectAttributesHistoryDB() database.ObjectAttributesHistoryDB {
+	return DB.ObjectAttributesHistoryDB
+}
+
 func (DB *DB) GetObjectUserAttributesDB() database.ObjectUserAttributesDB {
 	return DB.ObjectUserAttributesDB
 }
@@ -166,6 +198,30 @@ func (DB *DB) GetUserObjectsDB() database.UserObjectsDB {
 	return DB.UserObjectsDB
 }
 
+func (DB *DB) GetUserSessionsDB() database.UserSessionsDB {
+	return DB.UserSessionsDB
+}
+
+func (DB *DB) GetAPIKeysDB() database.APIKeysDB {
+	return DB.APIKeysDB
+}
+
+func (DB *DB) GetModerationDB() database.ModerationDB {
+	return DB.ModerationDB
+}
+
+func (DB *DB) GetUserRelationsDB() database.UserRelationsDB {
+	return DB.UserRelationsDB
+}
+
+func (DB *DB) GetPrefabsDB() database.PrefabsDB {
+	return DB.PrefabsDB
+}
+
+func (DB *DB) GetSearchDB() database.SearchDB {
+	return DB.SearchDB
+}
+
 func (DB *DB) GetStakesDB() database.StakesDB {
 	return DB.StakesDB
 }
@@ -20,6 +20,7 @@ type DB interface {
 	GetNodesDB() NodesDB
 	GetWorldsDB() WorldsDB
 	GetObjectsDB() ObjectsDB
+	GetObjectTrashDB() ObjectTrashDB
 	GetActivitiesDB() ActivitiesDB
 	GetObjectActivitiesDB() ObjectActivitiesDB
 	GetUserActivitiesDB() UserActivitiesDB
@@ -33,15 +34,25 @@ type DB interface {
 	GetAttributeTypesDB() AttributeTypesDB
 	GetNodeAttributesDB() NodeAttributesDB
 	GetObjectAttributesDB() ObjectAttributesDB
+	GetObjectAttributesHistoryDB() ObjectAttributesHistoryDB
 	GetObjectUserAttributesDB() ObjectUserAttributesDB
 	GetUserAttributesDB() UserAttributesDB
 	GetUserUserAttributesDB() UserUserAttributesDB
+	GetUserSessionsDB() UserSessionsDB
+	GetAPIKeysDB() APIKeysDB
+	GetModerationDB() ModerationDB
+	GetUserRelationsDB() UserRelationsDB
+	GetPrefabsDB() PrefabsDB
+	GetSearchDB() SearchDB
 	GetStakesDB() StakesDB
 	GetNFTsDB() NFTsDB
 }
 
 type CommonDB interface {
 	GetConnection() *pgxpool.Pool
+
+	// CheckIsMediaReferenced looks for the media file hash in all stored json documents.
+	CheckIsMediaReferenced(ctx context.Context, hash string) (bool, error)
 }
 
 type NodesDB interface {
@@ -54,6 +65,21 @@ type WorldsDB interface {
 	GetWorlds(ctx context.Context) ([]*entry.Object, error)
 }
 
+type ObjectTrashDB interface {
+	// GetObjectTrashItemsByWorldID returns the trashed objects of the world, the most recently deleted first.
+	GetObjectTrashItemsByWorldID(ctx context.Context, worldID umid.UMID) ([]*entry.ObjectTrashItem, error)
+	GetObjectTrashItemByID(ctx context.Context, objectID umid.UMID) (*entry.ObjectTrashItem, error)
+	UpsertObjectTrashItem(ctx context.Context, item *entry.ObjectTrashItem) error
+	// RemoveObjectTrashItemByID restores the object, returns false if it isn't trashed.
+	RemoveObjectTrashItemByID(ctx context.Context, objectID umid.UMID) (bool, error)
+
+	// PurgeObjectTrashItemByID removes the trashed object with its children, returns false if it isn't trashed.
+	PurgeObjectTrashItemByID(ctx context.Context, objectID umid.UMID) (bool, error)
+	// PurgeExpiredObjectTrashItems removes objects expired before the time with their children
+	// and returns the purged items.
+	PurgeExpiredObjectTrashItems(ctx context.Context, before time.Time) ([]umid.UMID, error)
+}
+
 type ActivitiesDB interface {
 	GetActivities(ctx context.Context) ([]*entry.Activity, error)
 
@@ -80,6 +106,9 @@ type ObjectsDB interface {
 	GetObjectIDsByParentID(ctx context.Context, parentID umid.UMID) ([]umid.UMID, error)
 	GetObjectsByParentID(ctx context.Context, parentID umid.UMID) ([]*entry.Object, error)
 	GetObjectsByOwnerID(ctx context.Context, ownerID umid.UMID) ([]*entry.Object, error)
+	// GetExistingObjectIDs returns the given umids of objects in the db, trashed included,
+	// except the root and its descendants.
+	GetExistingObjectIDs(ctx context.Context, objectIDs []umid.UMID, exceptRootID umid.UMID) ([]umid.UMID, error)
 
 	UpsertObject(ctx context.Context, object *entry.Object) error
 	UpsertObjects(ctx context.Context, objects []*entry.Object) error
@@ -94,6 +123,9 @@ type ObjectsDB interface {
 
 	RemoveObjectByID(ctx context.Context, objectID umid.UMID) error
 	RemoveObjectsByIDs(ctx context.Context, objectIDs []umid.UMID) error
+
+	// ApplyObjectsBatch saves the batch in one transaction, nothing is saved if any part of it fails.
+	ApplyObjectsBatch(ctx context.Context, batch *entry.ObjectsBatch) error
 }
 
 type ObjectActivitiesDB interface {
@@ -115,6 +147,7 @@ type UsersDB interface {
 	GetUsersByIDs(ctx context.Context, userIDs []umid.UMID) ([]*entry.User, error)
 	GetAllUsers(ctx context.Context, userTypeID umid.UMID) ([]*entry.User, error)
 	GetUserByWallet(ctx context.Context, wallet string) (*entry.User, error)
+	GetUserByOIDCIdentity(ctx context.Context, provider, subject string) (*entry.User, error)
 	GetUserWalletByUserID(ctx context.Context, userID umid.UMID) (*string, error)
 	GetUserWalletsByUserID(ctx context.Context, userID umid.UMID) ([]*string, error)
 	GetUserProfileByUserID(ctx context.Context, userID umid.UMID) (*entry.UserProfile, error)
@@ -129,10 +162,12 @@ type UsersDB interface {
 	UpsertUsers(ctx context.Context, user []*entry.User) error
 
 	UpdateUserUserTypeID(ctx context.Context, userID, userTypeID umid.UMID) error
+	ReplaceUserUserTypeID(ctx context.Context, userID, oldUserTypeID, newUserTypeID umid.UMID) (bool, error)
 	UpdateUserOptions(ctx context.Context, userID umid.UMID, options *entry.UserOptions) error
 	UpdateUserProfile(ctx context.Context, userID umid.UMID, profile *entry.UserProfile) error
 
 	RemoveUserByID(ctx context.Context, userID umid.UMID) error
+	RemoveUserByIDAndUserTypeID(ctx context.Context, userID, userTypeID umid.UMID) (bool, error)
 	RemoveUsersByIDs(ctx context.Context, userID []umid.UMID) error
 }
 
@@ -184,6 +219,9 @@ type UserObjectsDB interface {
 	GetUserObjectByID(ctx context.Context, userObjectID entry.UserObjectID) (*entry.UserObject, error)
 	GetUserObjectsByUserID(ctx context.Context, userID umid.UMID) ([]*entry.UserObject, error)
 	GetUserObjectsByObjectID(ctx context.Context, objectID umid.UMID) ([]*entry.UserObject, error)
+	GetUserObjectsByUserIDAndObjectIDs(
+		ctx context.Context, userID umid.UMID, objectIDs []umid.UMID,
+	) ([]*entry.UserObject, error)
 	GetUserObjectValueByID(ctx context.Context, userObjectID entry.UserObjectID) (*entry.UserObjectValue, error)
 
 	GetObjectIndirectAdmins(ctx context.Context, objectID umid.UMID) ([]*umid.UMID, error)
@@ -310,6 +348,125 @@ type ObjectAttributesDB interface {
 	RemoveObjectAttributesByPluginIDAndObjectID(ctx context.Context, pluginID umid.UMID, objectID umid.UMID) error
 }
 
+type ObjectAttributesHistoryDB interface {
+	GetObjectAttributeHistoryByID(
+		ctx context.Context, objectAttributeID entry.ObjectAttributeID, limit uint, offset uint,
+	) ([]*entry.ObjectAttributeHistory, error)
+	GetObjectAttributesHistoryByObjectID(
+		ctx context.Context, objectID umid.UMID, limit uint, offset uint,
+	) ([]*entry.ObjectAttributeHistory, error)
+	// GetObjectAttributesHistorySince returns changes made after "since", oldest first.
+	GetObjectAttributesHistorySince(
+		ctx context.Context, objectIDs []umid.UMID, since time.Time,
+	) ([]*entry.ObjectAttributeHistory, error)
+
+	InsertObjectAttributeHistory(ctx context.Context, history *entry.ObjectAttributeHistory) error
+
+	// AnonymizeObjectAttributesHistoryByUserID keeps the changes but forgets who made them.
+	AnonymizeObjectAttributesHistoryByUserID(ctx context.Context, userID umid.UMID) error
+}
+
+type UserSessionsDB interface {
+	GetUserSessionByID(ctx context.Context, sessionID umid.UMID) (*entry.UserSession, error)
+	GetActiveUserSessionsByUserID(ctx context.Context, userID umid.UMID) ([]*entry.UserSession, error)
+
+	InsertUserSession(ctx context.Context, session *entry.UserSession) error
+	// RotateUserSessionRefreshToken returns false if the session is not active or the old hash doesn't match.
+	RotateUserSessionRefreshToken(
+		ctx context.Context, sessionID umid.UMID, oldHash, newHash string, expiresAt time.Time,
+	) (bool, error)
+
+	RevokeUserSession(ctx context.Context, sessionID umid.UMID) error
+	// RevokeUserSessionsByUserID returns ids of the sessions which were revoked.
+	RevokeUserSessionsByUserID(ctx context.Context, userID umid.UMID) ([]umid.UMID, error)
+
+	RemoveExpiredUserSessions(ctx context.Context, before time.Time) error
+}
+
+type APIKeysDB interface {
+	GetAPIKeys(ctx context.Context) ([]*entry.APIKey, error)
+	GetAPIKeyByID(ctx context.Context, apiKeyID umid.UMID) (*entry.APIKey, error)
+
+	InsertAPIKey(ctx context.Context, apiKey *entry.APIKey) error
+
+	UpdateAPIKeyLastUsedAt(ctx context.Context, apiKeyID umid.UMID, lastUsedAt time.Time) error
+	// RevokeAPIKey returns false if the key doesn't exist or is already revoked.
+	RevokeAPIKey(ctx context.Context, apiKeyID umid.UMID) (bool, error)
+
+	GetAPIKeyAuditEntries(ctx context.Context, apiKeyID umid.UMID, limit uint) ([]*entry.APIKeyAuditEntry, error)
+	InsertAPIKeyAuditEntry(ctx context.Context, auditEntry *entry.APIKeyAuditEntry) error
+}
+
+type UserRelationsDB interface {
+	GetUserRelationsBySourceUserID(
+		ctx context.Context, sourceUserID umid.UMID, relationType entry.UserRelationType,
+	) ([]*entry.UserRelation, error)
+	GetUserRelationsByTargetUserID(
+		ctx context.Context, targetUserID umid.UMID, relationType entry.UserRelationType,
+	) ([]*entry.UserRelation, error)
+	CheckUserRelationExists(ctx context.Context, relationID entry.UserRelationID) (bool, error)
+
+	// InsertUserRelation returns false if the relation already exists.
+	InsertUserRelation(ctx context.Context, relationID entry.UserRelationID) (bool, error)
+	RemoveUserRelationByID(ctx context.Context, relationID entry.UserRelationID) (bool, error)
+
+	// AcceptFriendRequest replaces the pending request with the mutual friendship,
+	// returns false if there is no such request.
+	AcceptFriendRequest(ctx context.Context, requesterID, addresseeID umid.UMID) (bool, error)
+	// RemoveFriendship removes the friendship or pending requests between the users in both directions.
+	RemoveFriendship(ctx context.Context, userID, otherUserID umid.UMID) (bool, error)
+	// BlockUser removes all other relations between the users.
+	BlockUser(ctx context.Context, userID, blockedUserID umid.UMID) error
+}
+
+type SearchDB interface {
+	SearchDocuments(
+		ctx context.Context, query *entry.SearchQuery, after *entry.SearchCursor, limit uint,
+	) ([]*entry.SearchDocument, error)
+	// GetSearchFacets returns up to limit most frequent values of each facet over all matching documents.
+	GetSearchFacets(ctx context.Context, query *entry.SearchQuery, limit uint) (*entry.SearchFacets, error)
+
+	// ReindexSearchDocuments rebuilds the whole index, it is normally kept up to date by db triggers.
+	ReindexSearchDocuments(ctx context.Context) error
+}
+
+type PrefabsDB interface {
+	// GetPrefabs returns prefabs with the name containing the query, of the owner if it's set, the newest first.
+	GetPrefabs(ctx context.Context, query string, ownerID *umid.UMID, limit, offset uint) ([]*entry.Prefab, error)
+	GetPrefabByID(ctx context.Context, prefabID umid.UMID) (*entry.Prefab, error)
+	GetPrefabVersion(ctx context.Context, prefabID umid.UMID, version int) (*entry.PrefabVersion, error)
+	GetPrefabVersions(ctx context.Context, prefabID umid.UMID) ([]*entry.PrefabVersion, error)
+
+	// InsertPrefab inserts the prefab with its first version.
+	InsertPrefab(ctx context.Context, prefab *entry.Prefab, template []byte) error
+	// InsertPrefabVersion updates the prefab and adds its next version, which is returned.
+	InsertPrefabVersion(ctx context.Context, prefab *entry.Prefab, template []byte) (int, error)
+	RemovePrefabByID(ctx context.Context, prefabID umid.UMID) (bool, error)
+
+	GetPrefabInstancesByPrefabID(ctx context.Context, prefabID umid.UMID) ([]*entry.PrefabInstance, error)
+	UpsertPrefabInstance(ctx context.Context, instance *entry.PrefabInstance) error
+}
+
+type ModerationDB interface {
+	GetActiveUserBans(ctx context.Context) ([]*entry.UserBan, error)
+	InsertUserBan(ctx context.Context, ban *entry.UserBan) error
+	// RemoveUserBanByID returns false if the ban doesn't exist.
+	RemoveUserBanByID(ctx context.Context, banID umid.UMID) (bool, error)
+
+	GetActiveUserMutes(ctx context.Context) ([]*entry.UserMute, error)
+	InsertUserMute(ctx context.Context, mute *entry.UserMute) error
+	// RemoveUserMuteByID returns false if the mute doesn't exist.
+	RemoveUserMuteByID(ctx context.Context, muteID umid.UMID) (bool, error)
+
+	GetUserReportsByStatus(ctx context.Context, status entry.UserReportStatus, limit uint) ([]*entry.UserReport, error)
+	GetUserReportByID(ctx context.Context, reportID umid.UMID) (*entry.UserReport, error)
+	InsertUserReport(ctx context.Context, report *entry.UserReport) error
+	// UpdateUserReportStatus returns false if the report doesn't exist.
+	UpdateUserReportStatus(
+		ctx context.Context, reportID umid.UMID, status entry.UserReportStatus, resolvedBy *umid.UMID, resolution string,
+	) (bool, error)
+}
+
 type ObjectUserAttributesDB interface {
 	GetObjectUserAttributes(ctx context.Context) ([]*entry.ObjectUserAttribute, error)
 	GetObjectUserAttributeByID(
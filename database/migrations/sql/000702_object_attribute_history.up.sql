@@ -0,0 +1,19 @@
+BEGIN;
+
+CREATE TABLE object_attribute_history
+(
+    history_id     uuid                                                  NOT NULL,
+    plugin_id      uuid                                                  NOT NULL,
+    attribute_name character varying(255)                                NOT NULL,
+    object_id      uuid                                                  NOT NULL,
+    changed_by     uuid,
+    old_value      jsonb,
+    new_value      jsonb,
+    created_at     timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT object_attribute_history_pk PRIMARY KEY (history_id)
+);
+
+CREATE INDEX object_attribute_history_object_idx ON object_attribute_history USING btree (object_id, created_at);
+CREATE INDEX object_attribute_history_attribute_idx ON object_attribute_history USING btree (plugin_id, attribute_name, object_id, created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM object_attribute WHERE plugin_id = '{{CORE_PLUGIN_ID}}' AND attribute_name = 'world_roles';
+
+DELETE FROM attribute_type WHERE plugin_id = '{{CORE_PLUGIN_ID}}' AND attribute_name = 'world_roles';
+
+COMMIT;
@@ -0,0 +1,23 @@
+BEGIN;
+
+INSERT INTO attribute_type
+(
+    plugin_id,
+    attribute_name,
+    description,
+    options
+)
+VALUES
+    (
+        '{{CORE_PLUGIN_ID}}',
+        'world_roles',
+        'Custom roles defined by the world',
+        '{
+          "permissions": {
+            "read": "any",
+            "write": "admin"
+          }
+        }'::jsonb
+    );
+
+COMMIT;
@@ -0,0 +1,20 @@
+BEGIN;
+
+CREATE TABLE user_session
+(
+    session_id         uuid                                                  NOT NULL,
+    user_id            uuid                                                  NOT NULL,
+    refresh_token_hash character varying(255)                                NOT NULL,
+    user_agent         text                                                  NOT NULL DEFAULT '',
+    ip_address         character varying(255)                                NOT NULL DEFAULT '',
+    expires_at         timestamp without time zone                           NOT NULL,
+    revoked_at         timestamp without time zone,
+    created_at         timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at         timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT user_session_pk PRIMARY KEY (session_id),
+    CONSTRAINT user_session_user_id_fk FOREIGN KEY (user_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX user_session_user_idx ON user_session USING btree (user_id, created_at);
+
+COMMIT;
@@ -0,0 +1,11 @@
+BEGIN;
+
+INSERT INTO attribute_type (plugin_id, attribute_name, description, options)
+VALUES ('86dc3ae7-9f3d-42cb-85a3-a71abc3c3cb8', 'challenge_store', 'auth challenge store', null)
+ON CONFLICT DO NOTHING;
+
+INSERT INTO node_attribute (plugin_id, attribute_name, value, options)
+VALUES ('86dc3ae7-9f3d-42cb-85a3-a71abc3c3cb8', 'challenge_store', '{}'::jsonb, null)
+ON CONFLICT DO NOTHING;
+
+COMMIT;
@@ -0,0 +1,14 @@
+BEGIN;
+
+-- auth challenges are kept in the node memory with expiration
+DELETE
+FROM node_attribute
+WHERE plugin_id = '86dc3ae7-9f3d-42cb-85a3-a71abc3c3cb8'
+  AND attribute_name = 'challenge_store';
+
+DELETE
+FROM attribute_type
+WHERE plugin_id = '86dc3ae7-9f3d-42cb-85a3-a71abc3c3cb8'
+  AND attribute_name = 'challenge_store';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DELETE FROM user_attribute WHERE plugin_id = '{{CORE_PLUGIN_ID}}' AND attribute_name = 'oidc_identity';
+
+DELETE FROM attribute_type WHERE plugin_id = '{{CORE_PLUGIN_ID}}' AND attribute_name = 'oidc_identity';
+
+COMMIT;
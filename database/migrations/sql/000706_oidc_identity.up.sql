@@ -0,0 +1,23 @@
+BEGIN;
+
+INSERT INTO attribute_type
+(
+    plugin_id,
+    attribute_name,
+    description,
+    options
+)
+VALUES
+    (
+        '{{CORE_PLUGIN_ID}}',
+        'oidc_identity',
+        'OpenID Connect identities linked to the user',
+        '{
+          "permissions": {
+            "read": "admin+user_owner",
+            "write": "admin"
+          }
+        }'::jsonb
+    );
+
+COMMIT;
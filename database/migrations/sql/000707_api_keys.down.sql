@@ -0,0 +1,6 @@
+BEGIN;
+
+DROP TABLE IF EXISTS api_key_audit;
+DROP TABLE IF EXISTS api_key;
+
+COMMIT;
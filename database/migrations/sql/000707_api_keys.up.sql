@@ -0,0 +1,36 @@
+BEGIN;
+
+CREATE TABLE api_key
+(
+    api_key_id   uuid                                                  NOT NULL,
+    name         character varying(255)                                NOT NULL,
+    key_hash     character varying(255)                                NOT NULL,
+    scopes       text[]                                                NOT NULL DEFAULT '{}',
+    -- user the key acts as
+    created_by   uuid                                                  NOT NULL,
+    expires_at   timestamp without time zone                           NOT NULL,
+    last_used_at timestamp without time zone,
+    revoked_at   timestamp without time zone,
+    created_at   timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at   timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT api_key_pk PRIMARY KEY (api_key_id),
+    CONSTRAINT api_key_created_by_fk FOREIGN KEY (created_by) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE TABLE api_key_audit
+(
+    audit_id   uuid                                                  NOT NULL,
+    api_key_id uuid                                                  NOT NULL,
+    method     character varying(16)                                 NOT NULL,
+    path       text                                                  NOT NULL,
+    scope      character varying(255)                                NOT NULL DEFAULT '',
+    status     integer                                               NOT NULL,
+    ip_address character varying(255)                                NOT NULL DEFAULT '',
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT api_key_audit_pk PRIMARY KEY (audit_id),
+    CONSTRAINT api_key_audit_api_key_id_fk FOREIGN KEY (api_key_id) REFERENCES api_key (api_key_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX api_key_audit_api_key_idx ON api_key_audit USING btree (api_key_id, created_at);
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS user_report;
+DROP TABLE IF EXISTS user_mute;
+DROP TABLE IF EXISTS user_ban;
+
+COMMIT;
@@ -0,0 +1,62 @@
+BEGIN;
+
+CREATE TABLE user_ban
+(
+    ban_id     uuid                                                  NOT NULL,
+    user_id    uuid                                                  NOT NULL,
+    -- node-wide ban if null
+    world_id   uuid,
+    reason     text                                                  NOT NULL DEFAULT '',
+    created_by uuid,
+    -- permanent ban if null
+    expires_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT user_ban_pk PRIMARY KEY (ban_id),
+    CONSTRAINT user_ban_user_id_fk FOREIGN KEY (user_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT user_ban_world_id_fk FOREIGN KEY (world_id) REFERENCES object (object_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT user_ban_created_by_fk FOREIGN KEY (created_by) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX user_ban_user_idx ON user_ban USING btree (user_id);
+
+CREATE TABLE user_mute
+(
+    mute_id    uuid                                                  NOT NULL,
+    user_id    uuid                                                  NOT NULL,
+    -- node-wide mute if null
+    world_id   uuid,
+    reason     text                                                  NOT NULL DEFAULT '',
+    created_by uuid,
+    -- permanent mute if null
+    expires_at timestamp without time zone,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT user_mute_pk PRIMARY KEY (mute_id),
+    CONSTRAINT user_mute_user_id_fk FOREIGN KEY (user_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT user_mute_world_id_fk FOREIGN KEY (world_id) REFERENCES object (object_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT user_mute_created_by_fk FOREIGN KEY (created_by) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX user_mute_user_idx ON user_mute USING btree (user_id);
+
+CREATE TABLE user_report
+(
+    report_id      uuid                                                  NOT NULL,
+    reporter_id    uuid,
+    target_user_id uuid                                                  NOT NULL,
+    world_id       uuid,
+    reason         text                                                  NOT NULL,
+    status         character varying(16)                                 NOT NULL DEFAULT 'open',
+    resolved_by    uuid,
+    resolution     text                                                  NOT NULL DEFAULT '',
+    created_at     timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at     timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT user_report_pk PRIMARY KEY (report_id),
+    CONSTRAINT user_report_reporter_id_fk FOREIGN KEY (reporter_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT user_report_target_user_id_fk FOREIGN KEY (target_user_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT user_report_world_id_fk FOREIGN KEY (world_id) REFERENCES object (object_id) ON UPDATE CASCADE ON DELETE SET NULL,
+    CONSTRAINT user_report_resolved_by_fk FOREIGN KEY (resolved_by) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX user_report_status_idx ON user_report USING btree (status, created_at);
+
+COMMIT;
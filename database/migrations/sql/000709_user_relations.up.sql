@@ -0,0 +1,17 @@
+BEGIN;
+
+CREATE TABLE user_relation
+(
+    source_user_id uuid                                                  NOT NULL,
+    target_user_id uuid                                                  NOT NULL,
+    -- friend_request, friend (stored in both directions), follow or block
+    relation_type  character varying(16)                                 NOT NULL,
+    created_at     timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT user_relation_pk PRIMARY KEY (source_user_id, target_user_id, relation_type),
+    CONSTRAINT user_relation_source_user_id_fk FOREIGN KEY (source_user_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT user_relation_target_user_id_fk FOREIGN KEY (target_user_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX user_relation_target_idx ON user_relation USING btree (target_user_id, relation_type);
+
+COMMIT;
@@ -0,0 +1,27 @@
+BEGIN;
+
+DROP TRIGGER IF EXISTS search_object_insert_delete ON object;
+DROP TRIGGER IF EXISTS search_object_update ON object;
+DROP TRIGGER IF EXISTS search_object_attribute_insert_delete ON object_attribute;
+DROP TRIGGER IF EXISTS search_object_attribute_update ON object_attribute;
+DROP TRIGGER IF EXISTS search_user_insert_delete ON "user";
+DROP TRIGGER IF EXISTS search_user_update ON "user";
+DROP TRIGGER IF EXISTS search_plugin_insert_delete ON plugin;
+DROP TRIGGER IF EXISTS search_plugin_update ON plugin;
+
+DROP FUNCTION IF EXISTS search_object_trigger();
+DROP FUNCTION IF EXISTS search_object_attribute_trigger();
+DROP FUNCTION IF EXISTS search_user_trigger();
+DROP FUNCTION IF EXISTS search_plugin_trigger();
+DROP FUNCTION IF EXISTS search_reindex();
+DROP FUNCTION IF EXISTS search_index_plugin(uuid);
+DROP FUNCTION IF EXISTS search_index_user(uuid);
+DROP FUNCTION IF EXISTS search_index_object_tree(uuid);
+DROP FUNCTION IF EXISTS search_index_object(uuid);
+
+DELETE FROM object_attribute WHERE plugin_id = '{{CORE_PLUGIN_ID}}' AND attribute_name = 'tags';
+DELETE FROM attribute_type WHERE plugin_id = '{{CORE_PLUGIN_ID}}' AND attribute_name = 'tags';
+
+DROP TABLE IF EXISTS search_document;
+
+COMMIT;
@@ -0,0 +1,370 @@
+BEGIN;
+
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+CREATE TABLE search_document
+(
+    -- world, object, user or plugin
+    kind              character varying(16)                                 NOT NULL,
+    document_id       uuid                                                  NOT NULL,
+    world_id          uuid,
+    object_type_id    uuid,
+    owner_id          uuid,
+    -- nearest private object (the object itself or an ancestor), only its admins can find the document
+    private_object_id uuid,
+    tags              text[]                      DEFAULT '{}'::text[]      NOT NULL,
+    title             text                        DEFAULT ''::text          NOT NULL,
+    body              text                        DEFAULT ''::text          NOT NULL,
+    -- values of the attributes with the "searchable" option
+    attributes        text                        DEFAULT ''::text          NOT NULL,
+    search_vector     tsvector GENERATED ALWAYS AS (
+                          setweight(to_tsvector('simple'::regconfig, title), 'A') ||
+                          setweight(to_tsvector('simple'::regconfig, body), 'B') ||
+                          setweight(to_tsvector('simple'::regconfig, attributes), 'C')
+                          ) STORED,
+    updated_at        timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT search_document_pk PRIMARY KEY (kind, document_id)
+);
+
+CREATE INDEX search_document_search_vector_idx ON search_document USING gin (search_vector);
+CREATE INDEX search_document_title_trgm_idx ON search_document USING gin (title gin_trgm_ops);
+CREATE INDEX search_document_tags_idx ON search_document USING gin (tags);
+CREATE INDEX search_document_world_id_idx ON search_document USING btree (world_id);
+CREATE INDEX search_document_object_type_id_idx ON search_document USING btree (object_type_id);
+CREATE INDEX search_document_owner_id_idx ON search_document USING btree (owner_id);
+
+INSERT INTO attribute_type
+(
+    plugin_id,
+    attribute_name,
+    description,
+    options
+)
+VALUES
+    (
+        '{{CORE_PLUGIN_ID}}',
+        'tags',
+        'Object tags used by search',
+        '{
+          "permissions": {
+            "read": "any",
+            "write": "admin"
+          }
+        }'::jsonb
+    );
+
+--
+-- Name: search_index_object(uuid); Type: FUNCTION;
+--
+
+CREATE FUNCTION search_index_object(t_object_id uuid) RETURNS void
+    LANGUAGE plpgsql
+AS
+$$
+DECLARE
+    t_object            object%ROWTYPE;
+    t_node_id           uuid;
+    t_kind              character varying(16);
+    t_world_id          uuid;
+    t_private_object_id uuid;
+    t_tags              text[];
+BEGIN
+    SELECT * INTO t_object FROM object WHERE object_id = t_object_id;
+    IF NOT FOUND OR t_object.object_id = t_object.parent_id THEN
+        DELETE FROM search_document WHERE kind IN ('world', 'object') AND document_id = t_object_id;
+        RETURN;
+    END IF;
+
+    SELECT object_id INTO t_node_id FROM object WHERE object_id = parent_id;
+
+    -- world is the ancestor right below the node
+    SELECT a.id
+    INTO t_world_id
+    FROM getobjectancestorsids(t_object_id, 1000) a
+    WHERE a.parent_id = t_node_id
+      AND a.id != t_node_id;
+
+    t_kind := CASE WHEN t_world_id = t_object_id THEN 'world' ELSE 'object' END;
+
+    SELECT a.id
+    INTO t_private_object_id
+    FROM getobjectancestorsids(t_object_id, 1000) a
+             INNER JOIN object o ON o.object_id = a.id
+             INNER JOIN object_type ot ON ot.object_type_id = o.object_type_id
+    WHERE COALESCE((o.options ->> 'private')::boolean, (ot.options ->> 'private')::boolean, false)
+    ORDER BY a.level
+    LIMIT 1;
+
+    SELECT COALESCE(array_agg(DISTINCT lower(tag)), '{}'::text[])
+    INTO t_tags
+    FROM object_attribute oa,
+         jsonb_array_elements_text(
+                 CASE jsonb_typeof(oa.value -> 'tags') WHEN 'array' THEN oa.value -> 'tags' ELSE '[]'::jsonb END
+             ) tag
+    WHERE oa.object_id = t_object_id
+      AND oa.plugin_id = '{{CORE_PLUGIN_ID}}'
+      AND oa.attribute_name = 'tags';
+
+    -- the object can turn into a world and vice versa
+    DELETE FROM search_document WHERE kind IN ('world', 'object') AND kind != t_kind AND document_id = t_object_id;
+
+    INSERT INTO search_document
+    (kind, document_id, world_id, object_type_id, owner_id, private_object_id, tags, title, body, attributes)
+    SELECT t_kind,
+           t_object.object_id,
+           t_world_id,
+           t_object.object_type_id,
+           t_object.owner_id,
+           t_private_object_id,
+           t_tags,
+           COALESCE((SELECT value ->> 'name'
+                     FROM object_attribute
+                     WHERE object_id = t_object_id
+                       AND plugin_id = '{{CORE_PLUGIN_ID}}'
+                       AND attribute_name = 'name'), ''),
+           concat_ws(' ',
+                     (SELECT value ->> 'description'
+                      FROM object_attribute
+                      WHERE object_id = t_object_id
+                        AND plugin_id = '{{CORE_PLUGIN_ID}}'
+                        AND attribute_name = 'description'),
+                     array_to_string(t_tags, ' ')),
+           COALESCE((SELECT string_agg(v #>> '{}', ' ')
+                     FROM object_attribute oa
+                              INNER JOIN attribute_type atype
+                                         ON atype.plugin_id = oa.plugin_id AND atype.attribute_name = oa.attribute_name,
+                          jsonb_path_query(oa.value, 'strict $.** ? (@.type() == "string")') v
+                     WHERE oa.object_id = t_object_id
+                       AND COALESCE((atype.options ->> 'searchable')::boolean, false)), '')
+    ON CONFLICT (kind, document_id) DO UPDATE SET world_id          = excluded.world_id,
+                                                  object_type_id    = excluded.object_type_id,
+                                                  owner_id          = excluded.owner_id,
+                                                  private_object_id = excluded.private_object_id,
+                                                  tags              = excluded.tags,
+                                                  title             = excluded.title,
+                                                  body              = excluded.body,
+                                                  attributes        = excluded.attributes,
+                                                  updated_at        = CURRENT_TIMESTAMP;
+END;
+$$;
+
+--
+-- Name: search_index_object_tree(uuid); Type: FUNCTION;
+--
+
+CREATE FUNCTION search_index_object_tree(t_object_id uuid) RETURNS void
+    LANGUAGE sql
+AS
+$$
+WITH RECURSIVE tree AS (SELECT t_object_id AS object_id
+                        UNION ALL
+                        SELECT o.object_id
+                        FROM object o
+                                 INNER JOIN tree ON o.parent_id = tree.object_id
+                        WHERE o.object_id != o.parent_id)
+SELECT search_index_object(object_id)
+FROM tree;
+$$;
+
+--
+-- Name: search_index_user(uuid); Type: FUNCTION;
+--
+
+CREATE FUNCTION search_index_user(t_user_id uuid) RETURNS void
+    LANGUAGE sql
+AS
+$$
+DELETE
+FROM search_document
+WHERE kind = 'user'
+  AND document_id = t_user_id
+  AND NOT EXISTS(SELECT 1 FROM "user" WHERE user_id = t_user_id);
+
+INSERT INTO search_document (kind, document_id, title, body)
+SELECT 'user',
+       user_id,
+       COALESCE(profile ->> 'name', ''),
+       concat_ws(' ', profile ->> 'bio', profile ->> 'location')
+FROM "user"
+WHERE user_id = t_user_id
+ON CONFLICT (kind, document_id) DO UPDATE SET title      = excluded.title,
+                                              body       = excluded.body,
+                                              updated_at = CURRENT_TIMESTAMP;
+$$;
+
+--
+-- Name: search_index_plugin(uuid); Type: FUNCTION;
+--
+
+CREATE FUNCTION search_index_plugin(t_plugin_id uuid) RETURNS void
+    LANGUAGE sql
+AS
+$$
+DELETE
+FROM search_document
+WHERE kind = 'plugin'
+  AND document_id = t_plugin_id
+  AND NOT EXISTS(SELECT 1 FROM plugin WHERE plugin_id = t_plugin_id);
+
+INSERT INTO search_document (kind, document_id, title, body)
+SELECT 'plugin',
+       plugin_id,
+       COALESCE(meta ->> 'name', ''),
+       COALESCE(meta ->> 'description', '')
+FROM plugin
+WHERE plugin_id = t_plugin_id
+ON CONFLICT (kind, document_id) DO UPDATE SET title      = excluded.title,
+                                              body       = excluded.body,
+                                              updated_at = CURRENT_TIMESTAMP;
+$$;
+
+--
+-- Name: search_reindex(); Type: FUNCTION;
+--
+
+CREATE FUNCTION search_reindex() RETURNS void
+    LANGUAGE sql
+AS
+$$
+DELETE
+FROM search_document;
+SELECT search_index_object(object_id)
+FROM object;
+SELECT search_index_user(user_id)
+FROM "user";
+SELECT search_index_plugin(plugin_id)
+FROM plugin;
+$$;
+
+--
+-- Triggers keeping search_document up to date.
+--
+
+CREATE FUNCTION search_object_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM search_index_object(OLD.object_id);
+    ELSIF TG_OP = 'INSERT' THEN
+        PERFORM search_index_object(NEW.object_id);
+    ELSE
+        -- world and privacy are inherited by the children
+        PERFORM search_index_object_tree(NEW.object_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER search_object_insert_delete
+    AFTER INSERT OR DELETE
+    ON object
+    FOR EACH ROW
+EXECUTE FUNCTION search_object_trigger();
+
+CREATE TRIGGER search_object_update
+    AFTER UPDATE OF object_type_id, owner_id, parent_id, options
+    ON object
+    FOR EACH ROW
+    WHEN (OLD.object_type_id IS DISTINCT FROM NEW.object_type_id
+        OR OLD.owner_id IS DISTINCT FROM NEW.owner_id
+        OR OLD.parent_id IS DISTINCT FROM NEW.parent_id
+        OR OLD.options IS DISTINCT FROM NEW.options)
+EXECUTE FUNCTION search_object_trigger();
+
+CREATE FUNCTION search_object_attribute_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+DECLARE
+    t_attribute object_attribute%ROWTYPE;
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        t_attribute := OLD;
+    ELSE
+        t_attribute := NEW;
+    END IF;
+
+    IF (t_attribute.plugin_id = '{{CORE_PLUGIN_ID}}' AND t_attribute.attribute_name IN ('name', 'description', 'tags'))
+        OR EXISTS(SELECT 1
+                  FROM attribute_type
+                  WHERE plugin_id = t_attribute.plugin_id
+                    AND attribute_name = t_attribute.attribute_name
+                    AND COALESCE((options ->> 'searchable')::boolean, false)) THEN
+        PERFORM search_index_object(t_attribute.object_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER search_object_attribute_insert_delete
+    AFTER INSERT OR DELETE
+    ON object_attribute
+    FOR EACH ROW
+EXECUTE FUNCTION search_object_attribute_trigger();
+
+CREATE TRIGGER search_object_attribute_update
+    AFTER UPDATE OF value
+    ON object_attribute
+    FOR EACH ROW
+    WHEN (OLD.value IS DISTINCT FROM NEW.value)
+EXECUTE FUNCTION search_object_attribute_trigger();
+
+CREATE FUNCTION search_user_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM search_index_user(OLD.user_id);
+    ELSE
+        PERFORM search_index_user(NEW.user_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER search_user_insert_delete
+    AFTER INSERT OR DELETE
+    ON "user"
+    FOR EACH ROW
+EXECUTE FUNCTION search_user_trigger();
+
+CREATE TRIGGER search_user_update
+    AFTER UPDATE OF profile
+    ON "user"
+    FOR EACH ROW
+    WHEN (OLD.profile IS DISTINCT FROM NEW.profile)
+EXECUTE FUNCTION search_user_trigger();
+
+CREATE FUNCTION search_plugin_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM search_index_plugin(OLD.plugin_id);
+    ELSE
+        PERFORM search_index_plugin(NEW.plugin_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER search_plugin_insert_delete
+    AFTER INSERT OR DELETE
+    ON plugin
+    FOR EACH ROW
+EXECUTE FUNCTION search_plugin_trigger();
+
+CREATE TRIGGER search_plugin_update
+    AFTER UPDATE OF meta
+    ON plugin
+    FOR EACH ROW
+    WHEN (OLD.meta IS DISTINCT FROM NEW.meta)
+EXECUTE FUNCTION search_plugin_trigger();
+
+SELECT search_reindex();
+
+COMMIT;
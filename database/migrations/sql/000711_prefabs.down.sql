@@ -0,0 +1,7 @@
+BEGIN;
+
+DROP TABLE IF EXISTS prefab_instance;
+DROP TABLE IF EXISTS prefab_version;
+DROP TABLE IF EXISTS prefab;
+
+COMMIT;
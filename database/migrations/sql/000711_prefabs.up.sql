@@ -0,0 +1,48 @@
+BEGIN;
+
+CREATE TABLE prefab
+(
+    prefab_id   uuid                                                  NOT NULL,
+    name        character varying(255)                                NOT NULL,
+    description text                        DEFAULT ''::text          NOT NULL,
+    owner_id    uuid                                                  NOT NULL,
+    -- latest version, its parameters are copied for browsing
+    version     integer                     DEFAULT 1                 NOT NULL,
+    parameters  jsonb                       DEFAULT '[]'::jsonb       NOT NULL,
+    created_at  timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at  timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT prefab_pk PRIMARY KEY (prefab_id),
+    CONSTRAINT prefab_owner_id_fk FOREIGN KEY (owner_id) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX prefab_name_idx ON prefab USING btree (name);
+
+CREATE TABLE prefab_version
+(
+    prefab_id  uuid                                                  NOT NULL,
+    version    integer                                               NOT NULL,
+    -- object template with "{{parameter}}" placeholders
+    template   jsonb                                                 NOT NULL,
+    parameters jsonb                       DEFAULT '[]'::jsonb       NOT NULL,
+    created_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT prefab_version_pk PRIMARY KEY (prefab_id, version),
+    CONSTRAINT prefab_version_prefab_id_fk FOREIGN KEY (prefab_id) REFERENCES prefab (prefab_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+-- instances linked to their prefab, they are rebuilt from new versions
+CREATE TABLE prefab_instance
+(
+    object_id        uuid                                                  NOT NULL,
+    prefab_id        uuid                                                  NOT NULL,
+    version          integer                                               NOT NULL,
+    parameter_values jsonb                       DEFAULT '{}'::jsonb       NOT NULL,
+    created_at       timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    updated_at       timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    CONSTRAINT prefab_instance_pk PRIMARY KEY (object_id),
+    CONSTRAINT prefab_instance_object_id_fk FOREIGN KEY (object_id) REFERENCES object (object_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT prefab_instance_prefab_id_fk FOREIGN KEY (prefab_id) REFERENCES prefab (prefab_id) ON UPDATE CASCADE ON DELETE CASCADE
+);
+
+CREATE INDEX prefab_instance_prefab_id_idx ON prefab_instance USING btree (prefab_id);
+
+COMMIT;
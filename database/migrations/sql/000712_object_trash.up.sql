@@ -0,0 +1,146 @@
+BEGIN;
+
+-- soft deleted objects, only the root of the removed subtree is listed,
+-- the object rows are kept with their children, attributes and activities until the item expires
+CREATE TABLE object_trash
+(
+    object_id  uuid                                                  NOT NULL,
+    -- the world itself for trashed worlds
+    world_id   uuid                                                  NOT NULL,
+    parent_id  uuid                                                  NOT NULL,
+    name       character varying(255)      DEFAULT ''                NOT NULL,
+    deleted_by uuid,
+    deleted_at timestamp without time zone DEFAULT CURRENT_TIMESTAMP NOT NULL,
+    expires_at timestamp without time zone                           NOT NULL,
+    CONSTRAINT object_trash_pk PRIMARY KEY (object_id),
+    CONSTRAINT object_trash_object_id_fk FOREIGN KEY (object_id) REFERENCES object (object_id) ON UPDATE CASCADE ON DELETE CASCADE,
+    CONSTRAINT object_trash_deleted_by_fk FOREIGN KEY (deleted_by) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE SET NULL
+);
+
+CREATE INDEX object_trash_world_id_idx ON object_trash USING btree (world_id);
+CREATE INDEX object_trash_expires_at_idx ON object_trash USING btree (expires_at);
+
+--
+-- Name: search_index_object(uuid); Type: FUNCTION;
+--
+
+CREATE OR REPLACE FUNCTION search_index_object(t_object_id uuid) RETURNS void
+    LANGUAGE plpgsql
+AS
+$$
+DECLARE
+    t_object            object%ROWTYPE;
+    t_node_id           uuid;
+    t_kind              character varying(16);
+    t_world_id          uuid;
+    t_private_object_id uuid;
+    t_tags              text[];
+BEGIN
+    SELECT * INTO t_object FROM object WHERE object_id = t_object_id;
+    IF NOT FOUND OR t_object.object_id = t_object.parent_id THEN
+        DELETE FROM search_document WHERE kind IN ('world', 'object') AND document_id = t_object_id;
+        RETURN;
+    END IF;
+
+    -- trashed objects and their children are hidden until restored
+    IF EXISTS(SELECT 1
+              FROM getobjectancestorsids(t_object_id, 1000) a
+                       INNER JOIN object_trash t ON t.object_id = a.id) THEN
+        DELETE FROM search_document WHERE kind IN ('world', 'object') AND document_id = t_object_id;
+        RETURN;
+    END IF;
+
+    SELECT object_id INTO t_node_id FROM object WHERE object_id = parent_id;
+
+    -- world is the ancestor right below the node
+    SELECT a.id
+    INTO t_world_id
+    FROM getobjectancestorsids(t_object_id, 1000) a
+    WHERE a.parent_id = t_node_id
+      AND a.id != t_node_id;
+
+    t_kind := CASE WHEN t_world_id = t_object_id THEN 'world' ELSE 'object' END;
+
+    SELECT a.id
+    INTO t_private_object_id
+    FROM getobjectancestorsids(t_object_id, 1000) a
+             INNER JOIN object o ON o.object_id = a.id
+             INNER JOIN object_type ot ON ot.object_type_id = o.object_type_id
+    WHERE COALESCE((o.options ->> 'private')::boolean, (ot.options ->> 'private')::boolean, false)
+    ORDER BY a.level
+    LIMIT 1;
+
+    SELECT COALESCE(array_agg(DISTINCT lower(tag)), '{}'::text[])
+    INTO t_tags
+    FROM object_attribute oa,
+         jsonb_array_elements_text(
+                 CASE jsonb_typeof(oa.value -> 'tags') WHEN 'array' THEN oa.value -> 'tags' ELSE '[]'::jsonb END
+             ) tag
+    WHERE oa.object_id = t_object_id
+      AND oa.plugin_id = '{{CORE_PLUGIN_ID}}'
+      AND oa.attribute_name = 'tags';
+
+    -- the object can turn into a world and vice versa
+    DELETE FROM search_document WHERE kind IN ('world', 'object') AND kind != t_kind AND document_id = t_object_id;
+
+    INSERT INTO search_document
+    (kind, document_id, world_id, object_type_id, owner_id, private_object_id, tags, title, body, attributes)
+    SELECT t_kind,
+           t_object.object_id,
+           t_world_id,
+           t_object.object_type_id,
+           t_object.owner_id,
+           t_private_object_id,
+           t_tags,
+           COALESCE((SELECT value ->> 'name'
+                     FROM object_attribute
+                     WHERE object_id = t_object_id
+                       AND plugin_id = '{{CORE_PLUGIN_ID}}'
+                       AND attribute_name = 'name'), ''),
+           concat_ws(' ',
+                     (SELECT value ->> 'description'
+                      FROM object_attribute
+                      WHERE object_id = t_object_id
+                        AND plugin_id = '{{CORE_PLUGIN_ID}}'
+                        AND attribute_name = 'description'),
+                     array_to_string(t_tags, ' ')),
+           COALESCE((SELECT string_agg(v #>> '{}', ' ')
+                     FROM object_attribute oa
+                              INNER JOIN attribute_type atype
+                                         ON atype.plugin_id = oa.plugin_id AND atype.attribute_name = oa.attribute_name,
+                          jsonb_path_query(oa.value, 'strict $.** ? (@.type() == "string")') v
+                     WHERE oa.object_id = t_object_id
+                       AND COALESCE((atype.options ->> 'searchable')::boolean, false)), '')
+    ON CONFLICT (kind, document_id) DO UPDATE SET world_id          = excluded.world_id,
+                                                  object_type_id    = excluded.object_type_id,
+                                                  owner_id          = excluded.owner_id,
+                                                  private_object_id = excluded.private_object_id,
+                                                  tags              = excluded.tags,
+                                                  title             = excluded.title,
+                                                  body              = excluded.body,
+                                                  attributes        = excluded.attributes,
+                                                  updated_at        = CURRENT_TIMESTAMP;
+END;
+$$;
+
+CREATE FUNCTION search_object_trash_trigger() RETURNS trigger
+    LANGUAGE plpgsql
+AS
+$$
+BEGIN
+    IF TG_OP = 'DELETE' THEN
+        PERFORM search_index_object_tree(OLD.object_id);
+    ELSE
+        PERFORM search_index_object_tree(NEW.object_id);
+    END IF;
+    RETURN NULL;
+END;
+$$;
+
+CREATE TRIGGER search_object_trash_insert_delete
+    AFTER INSERT OR DELETE
+    ON object_trash
+    FOR EACH ROW
+EXECUTE FUNCTION search_object_trash_trigger();
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_session
+    DROP COLUMN IF EXISTS previous_refresh_token_hashes;
+
+COMMIT;
@@ -0,0 +1,6 @@
+BEGIN;
+
+ALTER TABLE user_session
+    ADD COLUMN previous_refresh_token_hashes character varying(255)[] NOT NULL DEFAULT '{}';
+
+COMMIT;
@@ -0,0 +1,7 @@
+BEGIN;
+
+ALTER TABLE prefab_instance
+    DROP COLUMN IF EXISTS object_ids,
+    DROP COLUMN IF EXISTS created_by;
+
+COMMIT;
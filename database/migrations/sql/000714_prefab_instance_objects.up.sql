@@ -0,0 +1,12 @@
+BEGIN;
+
+-- user who linked the instance, it is rebuilt with the permissions of the user
+ALTER TABLE prefab_instance
+    ADD COLUMN created_by uuid,
+    ADD CONSTRAINT prefab_instance_created_by_fk FOREIGN KEY (created_by) REFERENCES "user" (user_id) ON UPDATE CASCADE ON DELETE SET NULL;
+
+-- umids of the instance objects by umids of the template objects they were made from
+ALTER TABLE prefab_instance
+    ADD COLUMN object_ids jsonb DEFAULT '{}'::jsonb NOT NULL;
+
+COMMIT;
@@ -0,0 +1,150 @@
+package moderation
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getActiveUserBansQuery = `SELECT * FROM user_ban
+								WHERE expires_at IS NULL OR expires_at > NOW()
+								ORDER BY created_at DESC;`
+	insertUserBanQuery = `INSERT INTO user_ban
+							(ban_id, user_id, world_id, reason, created_by, expires_at)
+						VALUES
+							($1, $2, $3, $4, $5, $6);`
+	removeUserBanByIDQuery = `DELETE FROM user_ban WHERE ban_id = $1;`
+
+	getActiveUserMutesQuery = `SELECT * FROM user_mute
+								WHERE expires_at IS NULL OR expires_at > NOW()
+								ORDER BY created_at DESC;`
+	insertUserMuteQuery = `INSERT INTO user_mute
+							(mute_id, user_id, world_id, reason, created_by, expires_at)
+						VALUES
+							($1, $2, $3, $4, $5, $6);`
+	removeUserMuteByIDQuery = `DELETE FROM user_mute WHERE mute_id = $1;`
+
+	getUserReportsByStatusQuery = `SELECT * FROM user_report
+									WHERE status = $1
+									ORDER BY created_at
+									LIMIT $2;`
+	getUserReportByIDQuery = `SELECT * FROM user_report WHERE report_id = $1;`
+	insertUserReportQuery  = `INSERT INTO user_report
+								(report_id, reporter_id, target_user_id, world_id, reason, status)
+							VALUES
+								($1, $2, $3, $4, $5, $6);`
+	updateUserReportStatusQuery = `UPDATE user_report
+									SET status = $2, resolved_by = $3, resolution = $4, updated_at = NOW()
+									WHERE report_id = $1;`
+)
+
+var _ database.ModerationDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetActiveUserBans(ctx context.Context) ([]*entry.UserBan, error) {
+	var bans []*entry.UserBan
+	if err := pgxscan.Select(ctx, db.conn, &bans, getActiveUserBansQuery); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return bans, nil
+}
+
+func (db *DB) InsertUserBan(ctx context.Context, ban *entry.UserBan) error {
+	if _, err := db.conn.Exec(
+		ctx, insertUserBanQuery,
+		ban.BanID, ban.UserID, ban.WorldID, ban.Reason, ban.CreatedBy, ban.ExpiresAt,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) RemoveUserBanByID(ctx context.Context, banID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, removeUserBanByIDQuery, banID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) GetActiveUserMutes(ctx context.Context) ([]*entry.UserMute, error) {
+	var mutes []*entry.UserMute
+	if err := pgxscan.Select(ctx, db.conn, &mutes, getActiveUserMutesQuery); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return mutes, nil
+}
+
+func (db *DB) InsertUserMute(ctx context.Context, mute *entry.UserMute) error {
+	if _, err := db.conn.Exec(
+		ctx, insertUserMuteQuery,
+		mute.MuteID, mute.UserID, mute.WorldID, mute.Reason, mute.CreatedBy, mute.ExpiresAt,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) RemoveUserMuteByID(ctx context.Context, muteID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, removeUserMuteByIDQuery, muteID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) GetUserReportsByStatus(
+	ctx context.Context, status entry.UserReportStatus, limit uint,
+) ([]*entry.UserReport, error) {
+	var reports []*entry.UserReport
+	if err := pgxscan.Select(ctx, db.conn, &reports, getUserReportsByStatusQuery, status, limit); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return reports, nil
+}
+
+func (db *DB) GetUserReportByID(ctx context.Context, reportID umid.UMID) (*entry.UserReport, error) {
+	var report entry.UserReport
+	if err := pgxscan.Get(ctx, db.conn, &report, getUserReportByIDQuery, reportID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &report, nil
+}
+
+func (db *DB) InsertUserReport(ctx context.Context, report *entry.UserReport) error {
+	if _, err := db.conn.Exec(
+		ctx, insertUserReportQuery,
+		report.ReportID, report.ReporterID, report.TargetUserID, report.WorldID, report.Reason, report.Status,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) UpdateUserReportStatus(
+	ctx context.Context, reportID umid.UMID, status entry.UserReportStatus, resolvedBy *umid.UMID, resolution string,
+) (bool, error) {
+	res, err := db.conn.Exec(ctx, updateUserReportStatusQuery, reportID, status, resolvedBy, resolution)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
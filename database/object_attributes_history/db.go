@@ -0,0 +1,105 @@
+package object_attributes_history
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getObjectAttributeHistoryByIDQuery = `SELECT * FROM object_attribute_history
+											WHERE plugin_id = $1 AND attribute_name = $2 AND object_id = $3
+											ORDER BY created_at DESC
+											LIMIT $4 OFFSET $5;`
+	getObjectAttributesHistoryByObjectIDQuery = `SELECT * FROM object_attribute_history
+											WHERE object_id = $1
+											ORDER BY created_at DESC
+											LIMIT $2 OFFSET $3;`
+	getObjectAttributesHistorySinceQuery = `SELECT * FROM object_attribute_history
+											WHERE object_id = ANY($1) AND created_at > $2
+											ORDER BY created_at ASC;`
+
+	insertObjectAttributeHistoryQuery = `INSERT INTO object_attribute_history
+											(history_id, plugin_id, attribute_name, object_id, changed_by, old_value, new_value)
+										VALUES
+											($1, $2, $3, $4, $5, $6, $7);`
+
+	anonymizeObjectAttributesHistoryByUserIDQuery = `UPDATE object_attribute_history SET changed_by = NULL
+														WHERE changed_by = $1;`
+)
+
+var _ database.ObjectAttributesHistoryDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetObjectAttributeHistoryByID(
+	ctx context.Context, objectAttributeID entry.ObjectAttributeID, limit uint, offset uint,
+) ([]*entry.ObjectAttributeHistory, error) {
+	var history []*entry.ObjectAttributeHistory
+	if err := pgxscan.Select(
+		ctx, db.conn, &history, getObjectAttributeHistoryByIDQuery,
+		objectAttributeID.PluginID, objectAttributeID.Name, objectAttributeID.ObjectID, limit, offset,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return history, nil
+}
+
+func (db *DB) GetObjectAttributesHistoryByObjectID(
+	ctx context.Context, objectID umid.UMID, limit uint, offset uint,
+) ([]*entry.ObjectAttributeHistory, error) {
+	var history []*entry.ObjectAttributeHistory
+	if err := pgxscan.Select(
+		ctx, db.conn, &history, getObjectAttributesHistoryByObjectIDQuery, objectID, limit, offset,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return history, nil
+}
+
+func (db *DB) GetObjectAttributesHistorySince(
+	ctx context.Context, objectIDs []umid.UMID, since time.Time,
+) ([]*entry.ObjectAttributeHistory, error) {
+	var history []*entry.ObjectAttributeHistory
+	if err := pgxscan.Select(
+		ctx, db.conn, &history, getObjectAttributesHistorySinceQuery, objectIDs, since,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return history, nil
+}
+
+func (db *DB) InsertObjectAttributeHistory(ctx context.Context, history *entry.ObjectAttributeHistory) error {
+	if _, err := db.conn.Exec(
+		ctx, insertObjectAttributeHistoryQuery,
+		history.HistoryID, history.PluginID, history.Name, history.ObjectID,
+		history.ChangedBy, history.OldValue, history.NewValue,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) AnonymizeObjectAttributesHistoryByUserID(ctx context.Context, userID umid.UMID) error {
+	if _, err := db.conn.Exec(ctx, anonymizeObjectAttributesHistoryByUserIDQuery, userID); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package object_trash
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getObjectTrashItemsByWorldIDQuery = `SELECT * FROM object_trash WHERE world_id = $1 ORDER BY deleted_at DESC;`
+	getObjectTrashItemByIDQuery       = `SELECT * FROM object_trash WHERE object_id = $1;`
+	upsertObjectTrashItemQuery        = `INSERT INTO object_trash
+											(object_id, world_id, parent_id, name, deleted_by, deleted_at, expires_at)
+										VALUES
+											($1, $2, $3, $4, $5, $6, $7)
+										ON CONFLICT (object_id)
+											DO UPDATE SET world_id = $2, parent_id = $3, name = $4, deleted_by = $5,
+												deleted_at = $6, expires_at = $7;`
+	removeObjectTrashItemByIDQuery = `DELETE FROM object_trash WHERE object_id = $1;`
+
+	// children, attributes and activities of the purged objects are removed by cascade
+	purgeObjectTrashItemByIDQuery = `DELETE FROM object
+										WHERE object_id IN (SELECT object_id FROM object_trash WHERE object_id = $1);`
+	purgeExpiredObjectTrashItemsQuery = `DELETE FROM object
+											WHERE object_id IN (SELECT object_id FROM object_trash WHERE expires_at < $1)
+										RETURNING object_id;`
+)
+
+var _ database.ObjectTrashDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetObjectTrashItemsByWorldID(ctx context.Context, worldID umid.UMID) ([]*entry.ObjectTrashItem, error) {
+	var items []*entry.ObjectTrashItem
+	if err := pgxscan.Select(ctx, db.conn, &items, getObjectTrashItemsByWorldIDQuery, worldID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return items, nil
+}
+
+func (db *DB) GetObjectTrashItemByID(ctx context.Context, objectID umid.UMID) (*entry.ObjectTrashItem, error) {
+	var item entry.ObjectTrashItem
+	if err := pgxscan.Get(ctx, db.conn, &item, getObjectTrashItemByIDQuery, objectID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &item, nil
+}
+
+func (db *DB) UpsertObjectTrashItem(ctx context.Context, item *entry.ObjectTrashItem) error {
+	if _, err := db.conn.Exec(
+		ctx, upsertObjectTrashItemQuery,
+		item.ObjectID, item.WorldID, item.ParentID, item.Name, item.DeletedBy, item.DeletedAt, item.ExpiresAt,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) RemoveObjectTrashItemByID(ctx context.Context, objectID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, removeObjectTrashItemByIDQuery, objectID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) PurgeObjectTrashItemByID(ctx context.Context, objectID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, purgeObjectTrashItemByIDQuery, objectID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) PurgeExpiredObjectTrashItems(ctx context.Context, before time.Time) ([]umid.UMID, error) {
+	var ids []umid.UMID
+	if err := pgxscan.Select(ctx, db.conn, &ids, purgeExpiredObjectTrashItemsQuery, before); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return ids, nil
+}
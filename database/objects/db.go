@@ -17,10 +17,21 @@ import (
 )
 
 const (
-	getObjectByIDQuery          = `SELECT * FROM object WHERE object_id = $1;`
-	getObjectIDsByParentIDQuery = `SELECT object_id FROM object WHERE parent_id = $1;`
-	getObjectsByParentIDQuery   = `SELECT * FROM object WHERE parent_id = $1;`
-	getObjectsByOwnerIDQuery    = `SELECT * FROM object WHERE owner_id = $1;`
+	getObjectByIDQuery = `SELECT * FROM object WHERE object_id = $1;`
+	// trashed objects are skipped, so their subtrees aren't loaded
+	getObjectIDsByParentIDQuery = `SELECT object_id FROM object
+									WHERE parent_id = $1 AND object_id NOT IN (SELECT object_id FROM object_trash);`
+	getObjectsByParentIDQuery = `SELECT * FROM object
+									WHERE parent_id = $1 AND object_id NOT IN (SELECT object_id FROM object_trash);`
+	getObjectsByOwnerIDQuery = `SELECT * FROM object WHERE owner_id = $1;`
+	// trashed objects are included, the root is excluded with all its descendants
+	getExistingObjectIDsQuery = `WITH RECURSIVE subtree AS (
+										SELECT object_id FROM object WHERE object_id = $2
+										UNION
+										SELECT o.object_id FROM object o JOIN subtree s ON o.parent_id = s.object_id
+									)
+									SELECT object_id FROM object
+									WHERE object_id = ANY($1) AND object_id NOT IN (SELECT object_id FROM subtree);`
 
 	upsertObjectQuery = `INSERT INTO object
     						(object_id, object_type_id, owner_id, parent_id, asset_2d_id,
@@ -32,6 +43,12 @@ const (
 							object_type_id = $2, owner_id = $3, parent_id = $4, asset_2d_id = $5,
 							asset_3d_id = $6, options = $7, transform = $8, updated_at = CURRENT_TIMESTAMP;`
 
+	insertObjectQuery = `INSERT INTO object
+    						(object_id, object_type_id, owner_id, parent_id, asset_2d_id,
+    						asset_3d_id, options, transform, created_at, updated_at)
+						VALUES
+							($1, $2, $3, $4, $5, $6, $7, $8, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP);`
+
 	updateObjectParentIDQuery     = `UPDATE object SET parent_id = $2, updated_at = CURRENT_TIMESTAMP WHERE object_id = $1;`
 	updateObjectTransformQuery    = `UPDATE object SET transform = $2, updated_at = CURRENT_TIMESTAMP WHERE object_id = $1;`
 	updateObjectOwnerIDQuery      = `UPDATE object SET owner_id = $2, updated_at = CURRENT_TIMESTAMP WHERE object_id = $1;`
@@ -42,6 +59,22 @@ const (
 
 	removeObjectByIDQuery   = `DELETE FROM object WHERE object_id = $1;`
 	removeObjectsByIDsQuery = `DELETE FROM object WHERE object_id = ANY($1);`
+
+	upsertObjectTrashItemQuery = `INSERT INTO object_trash
+									(object_id, world_id, parent_id, name, deleted_by, deleted_at, expires_at)
+								VALUES
+									($1, $2, $3, $4, $5, $6, $7)
+								ON CONFLICT (object_id)
+									DO UPDATE SET world_id = $2, parent_id = $3, name = $4, deleted_by = $5,
+										deleted_at = $6, expires_at = $7;`
+
+	upsertObjectAttributeQuery = `INSERT INTO object_attribute
+    									(plugin_id, attribute_name, object_id, value, options)
+									VALUES
+									    ($1, $2, $3, $4, $5)
+									ON CONFLICT (plugin_id, attribute_name, object_id)
+									DO UPDATE SET
+									    value = $4, options = $5;`
 )
 
 var _ database.ObjectsDB = (*DB)(nil)
@@ -90,6 +123,16 @@ func (db *DB) GetObjectsByOwnerID(ctx context.Context, ownerID umid.UMID) ([]*en
 	return objects, nil
 }
 
+func (db *DB) GetExistingObjectIDs(
+	ctx context.Context, objectIDs []umid.UMID, exceptRootID umid.UMID,
+) ([]umid.UMID, error) {
+	var ids []umid.UMID
+	if err := pgxscan.Select(ctx, db.conn, &ids, getExistingObjectIDsQuery, objectIDs, exceptRootID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return ids, nil
+}
+
 func (db *DB) RemoveObjectByID(ctx context.Context, objectID umid.UMID) error {
 	if _, err := db.conn.Exec(ctx, removeObjectByIDQuery, objectID); err != nil {
 		return errors.WithMessage(err, "failed to exec db")
@@ -187,3 +230,44 @@ func (db *DB) UpsertObjects(ctx context.Context, objects []*entry.Object) error
 
 	return errs.ErrorOrNil()
 }
+
+func (db *DB) ApplyObjectsBatch(ctx context.Context, batch *entry.ObjectsBatch) error {
+	return db.conn.BeginFunc(ctx, func(tx pgx.Tx) error {
+		for _, object := range batch.Objects {
+			query := upsertObjectQuery
+			if batch.CreatedIDs[object.ObjectID] {
+				query = insertObjectQuery
+			}
+			if _, err := tx.Exec(
+				ctx, query,
+				object.ObjectID, object.ObjectTypeID, object.OwnerID, object.ParentID, object.Asset2dID,
+				object.Asset3dID, object.Options, object.Transform,
+			); err != nil {
+				return errors.WithMessagef(err, "failed to upsert object: %s", object.ObjectID)
+			}
+		}
+
+		for _, objectAttribute := range batch.ObjectAttributes {
+			if _, err := tx.Exec(
+				ctx, upsertObjectAttributeQuery,
+				objectAttribute.PluginID, objectAttribute.Name, objectAttribute.ObjectID,
+				objectAttribute.Value, objectAttribute.Options,
+			); err != nil {
+				return errors.WithMessagef(
+					err, "failed to upsert object attribute: %+v", objectAttribute.ObjectAttributeID,
+				)
+			}
+		}
+
+		for _, item := range batch.TrashItems {
+			if _, err := tx.Exec(
+				ctx, upsertObjectTrashItemQuery,
+				item.ObjectID, item.WorldID, item.ParentID, item.Name, item.DeletedBy, item.DeletedAt, item.ExpiresAt,
+			); err != nil {
+				return errors.WithMessagef(err, "failed to upsert object trash item: %s", item.ObjectID)
+			}
+		}
+
+		return nil
+	})
+}
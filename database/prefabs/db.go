@@ -0,0 +1,151 @@
+package prefabs
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getPrefabsQuery = `SELECT * FROM prefab
+						WHERE name ILIKE '%' || $1 || '%' AND ($2::uuid IS NULL OR owner_id = $2)
+						ORDER BY updated_at DESC
+						LIMIT $3 OFFSET $4;`
+	getPrefabByIDQuery        = `SELECT * FROM prefab WHERE prefab_id = $1;`
+	getPrefabVersionQuery     = `SELECT * FROM prefab_version WHERE prefab_id = $1 AND version = $2;`
+	getPrefabVersionsQuery    = `SELECT * FROM prefab_version WHERE prefab_id = $1 ORDER BY version DESC;`
+	removePrefabByIDQuery     = `DELETE FROM prefab WHERE prefab_id = $1;`
+	getPrefabInstancesQuery   = `SELECT * FROM prefab_instance WHERE prefab_id = $1;`
+	upsertPrefabInstanceQuery = `INSERT INTO prefab_instance
+									(object_id, prefab_id, version, parameter_values, created_by, object_ids,
+									created_at, updated_at)
+								VALUES
+									($1, $2, $3, $4, $5, $6, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+								ON CONFLICT (object_id)
+									DO UPDATE SET prefab_id = $2, version = $3, parameter_values = $4,
+										object_ids = $6, updated_at = CURRENT_TIMESTAMP;`
+
+	insertPrefabQuery = `WITH inserted AS (
+							INSERT INTO prefab
+								(prefab_id, name, description, owner_id, version, parameters, created_at, updated_at)
+							VALUES
+								($1, $2, $3, $4, 1, $5, CURRENT_TIMESTAMP, CURRENT_TIMESTAMP)
+							RETURNING prefab_id, version, parameters
+						)
+						INSERT INTO prefab_version (prefab_id, version, template, parameters)
+							SELECT prefab_id, version, $6, parameters FROM inserted;`
+	insertPrefabVersionQuery = `WITH updated AS (
+									UPDATE prefab
+									SET name = $2, description = $3, version = version + 1, parameters = $4,
+										updated_at = CURRENT_TIMESTAMP
+									WHERE prefab_id = $1
+									RETURNING prefab_id, version, parameters
+								)
+								INSERT INTO prefab_version (prefab_id, version, template, parameters)
+									SELECT prefab_id, version, $5, parameters FROM updated
+								RETURNING version;`
+)
+
+var _ database.PrefabsDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetPrefabs(
+	ctx context.Context, query string, ownerID *umid.UMID, limit, offset uint,
+) ([]*entry.Prefab, error) {
+	var prefabs []*entry.Prefab
+	if err := pgxscan.Select(ctx, db.conn, &prefabs, getPrefabsQuery, query, ownerID, limit, offset); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return prefabs, nil
+}
+
+func (db *DB) GetPrefabByID(ctx context.Context, prefabID umid.UMID) (*entry.Prefab, error) {
+	var prefab entry.Prefab
+	if err := pgxscan.Get(ctx, db.conn, &prefab, getPrefabByIDQuery, prefabID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &prefab, nil
+}
+
+func (db *DB) GetPrefabVersion(ctx context.Context, prefabID umid.UMID, version int) (*entry.PrefabVersion, error) {
+	var prefabVersion entry.PrefabVersion
+	if err := pgxscan.Get(ctx, db.conn, &prefabVersion, getPrefabVersionQuery, prefabID, version); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &prefabVersion, nil
+}
+
+func (db *DB) GetPrefabVersions(ctx context.Context, prefabID umid.UMID) ([]*entry.PrefabVersion, error) {
+	var versions []*entry.PrefabVersion
+	if err := pgxscan.Select(ctx, db.conn, &versions, getPrefabVersionsQuery, prefabID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return versions, nil
+}
+
+func (db *DB) InsertPrefab(ctx context.Context, prefab *entry.Prefab, template []byte) error {
+	if _, err := db.conn.Exec(
+		ctx, insertPrefabQuery,
+		prefab.PrefabID, prefab.Name, prefab.Description, prefab.OwnerID, prefab.Parameters, template,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) InsertPrefabVersion(ctx context.Context, prefab *entry.Prefab, template []byte) (int, error) {
+	var version int
+	if err := db.conn.QueryRow(
+		ctx, insertPrefabVersionQuery,
+		prefab.PrefabID, prefab.Name, prefab.Description, prefab.Parameters, template,
+	).Scan(&version); err != nil {
+		return 0, errors.WithMessage(err, "failed to query db")
+	}
+	return version, nil
+}
+
+func (db *DB) RemovePrefabByID(ctx context.Context, prefabID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, removePrefabByIDQuery, prefabID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) GetPrefabInstancesByPrefabID(
+	ctx context.Context, prefabID umid.UMID,
+) ([]*entry.PrefabInstance, error) {
+	var instances []*entry.PrefabInstance
+	if err := pgxscan.Select(ctx, db.conn, &instances, getPrefabInstancesQuery, prefabID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return instances, nil
+}
+
+func (db *DB) UpsertPrefabInstance(ctx context.Context, instance *entry.PrefabInstance) error {
+	if _, err := db.conn.Exec(
+		ctx, upsertPrefabInstanceQuery,
+		instance.ObjectID, instance.PrefabID, instance.Version, instance.ParameterValues, instance.CreatedBy,
+		instance.ObjectIDs,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
@@ -0,0 +1,170 @@
+package search
+
+import (
+	"context"
+	"strings"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	// documents matching the query parameters from $1 to $8, see queryArgs
+	searchFilter = `
+		WHERE ($1::text = '' OR d.search_vector @@ q.ts_query OR d.title % $1::text OR d.title ILIKE $8::text)
+			AND (COALESCE(cardinality($2::text[]), 0) = 0 OR d.kind = ANY($2::text[]))
+			AND (COALESCE(cardinality($3::uuid[]), 0) = 0 OR d.object_type_id = ANY($3::uuid[]))
+			AND (COALESCE(cardinality($4::uuid[]), 0) = 0 OR d.owner_id = ANY($4::uuid[]))
+			AND (COALESCE(cardinality($5::text[]), 0) = 0 OR d.tags @> $5::text[])
+			AND ($6::uuid IS NULL OR d.world_id = $6::uuid)
+			AND (d.private_object_id IS NULL OR $7::uuid IN (SELECT GetIndirectObjectAdmins(d.private_object_id)))`
+	searchFrom = `FROM search_document d, (SELECT websearch_to_tsquery('simple', $1::text) AS ts_query) q`
+
+	// full-text rank plus trigram similarity of the title, so typos still match
+	searchDocumentsQuery = `SELECT kind, document_id, world_id, object_type_id, owner_id, tags, title, body, rank, updated_at
+							FROM (
+								SELECT d.*, (ts_rank_cd(d.search_vector, q.ts_query) + similarity(d.title, $1::text))::float8 AS rank
+								` + searchFrom + searchFilter + `
+							) AS documents
+							WHERE $9::float8 IS NULL OR (rank, document_id) < ($9::float8, $10::uuid)
+							ORDER BY rank DESC, document_id DESC
+							LIMIT $11;`
+	getSearchFacetsQuery = `WITH matches AS (
+								SELECT d.kind, d.object_type_id, d.owner_id, d.tags
+								` + searchFrom + searchFilter + `
+							)
+							SELECT facet, value, count
+							FROM (
+								SELECT *, row_number() OVER (PARTITION BY facet ORDER BY count DESC, value) AS facet_rank
+								FROM (
+									SELECT 'kind' AS facet, kind::text AS value, COUNT(*) AS count
+									FROM matches GROUP BY kind
+									UNION ALL
+									SELECT 'object_type', object_type_id::text, COUNT(*)
+									FROM matches WHERE object_type_id IS NOT NULL GROUP BY object_type_id
+									UNION ALL
+									SELECT 'owner', owner_id::text, COUNT(*)
+									FROM matches WHERE owner_id IS NOT NULL GROUP BY owner_id
+									UNION ALL
+									SELECT 'tag', tag, COUNT(*)
+									FROM matches, unnest(matches.tags) AS tag GROUP BY tag
+								) AS facets
+							) AS ranked_facets
+							WHERE facet_rank <= $9
+							ORDER BY facet, facet_rank;`
+
+	reindexSearchDocumentsQuery = `SELECT search_reindex();`
+)
+
+var _ database.SearchDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) SearchDocuments(
+	ctx context.Context, query *entry.SearchQuery, after *entry.SearchCursor, limit uint,
+) ([]*entry.SearchDocument, error) {
+	var afterRank *float64
+	afterDocumentID := umid.Nil
+	if after != nil {
+		afterRank = &after.Rank
+		afterDocumentID = after.DocumentID
+	}
+
+	args := append(queryArgs(query), afterRank, afterDocumentID, limit)
+
+	var documents []*entry.SearchDocument
+	if err := pgxscan.Select(ctx, db.conn, &documents, searchDocumentsQuery, args...); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return documents, nil
+}
+
+func (db *DB) GetSearchFacets(ctx context.Context, query *entry.SearchQuery, limit uint) (*entry.SearchFacets, error) {
+	type facetValue struct {
+		Facet string `db:"facet"`
+		entry.SearchFacetValue
+	}
+
+	args := append(queryArgs(query), limit)
+
+	var values []*facetValue
+	if err := pgxscan.Select(ctx, db.conn, &values, getSearchFacetsQuery, args...); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+
+	facets := &entry.SearchFacets{
+		Kinds:       []*entry.SearchFacetValue{},
+		ObjectTypes: []*entry.SearchFacetValue{},
+		Owners:      []*entry.SearchFacetValue{},
+		Tags:        []*entry.SearchFacetValue{},
+	}
+	for _, value := range values {
+		switch value.Facet {
+		case "kind":
+			facets.Kinds = append(facets.Kinds, &value.SearchFacetValue)
+		case "object_type":
+			facets.ObjectTypes = append(facets.ObjectTypes, &value.SearchFacetValue)
+		case "owner":
+			facets.Owners = append(facets.Owners, &value.SearchFacetValue)
+		case "tag":
+			facets.Tags = append(facets.Tags, &value.SearchFacetValue)
+		}
+	}
+
+	return facets, nil
+}
+
+func (db *DB) ReindexSearchDocuments(ctx context.Context) error {
+	if _, err := db.conn.Exec(ctx, reindexSearchDocumentsQuery); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+// queryArgs returns the arguments of searchFilter.
+func queryArgs(query *entry.SearchQuery) []any {
+	text := strings.TrimSpace(query.Text)
+
+	var pattern string
+	if text != "" {
+		pattern = "%" + likeEscaper.Replace(text) + "%"
+	}
+
+	kinds := make([]string, len(query.Kinds))
+	for i := range query.Kinds {
+		kinds[i] = string(query.Kinds[i])
+	}
+
+	tags := make([]string, len(query.Tags))
+	for i := range query.Tags {
+		tags[i] = strings.ToLower(query.Tags[i])
+	}
+
+	objectTypeIDs := query.ObjectTypeIDs
+	if objectTypeIDs == nil {
+		objectTypeIDs = []umid.UMID{}
+	}
+	ownerIDs := query.OwnerIDs
+	if ownerIDs == nil {
+		ownerIDs = []umid.UMID{}
+	}
+
+	return []any{text, kinds, objectTypeIDs, ownerIDs, tags, query.WorldID, query.ViewerID, pattern}
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
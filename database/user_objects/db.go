@@ -17,11 +17,12 @@ import (
 )
 
 const (
-	getUserObjectsQuery           = `SELECT * FROM user_object;`
-	getUserObjectByIDQuery        = `SELECT * FROM user_object WHERE user_id = $1 AND object_id = $2;`
-	getUserObjectsByUserIDQuery   = `SELECT * FROM user_object WHERE user_id = $1;`
-	getUserObjectsByObjectIDQuery = `SELECT * FROM user_object WHERE object_id = $1;`
-	getUserObjectValueByIDQuery   = `SELECT value FROM user_object WHERE user_id = $1 AND object_id = $2;`
+	getUserObjectsQuery                     = `SELECT * FROM user_object;`
+	getUserObjectByIDQuery                  = `SELECT * FROM user_object WHERE user_id = $1 AND object_id = $2;`
+	getUserObjectsByUserIDQuery             = `SELECT * FROM user_object WHERE user_id = $1;`
+	getUserObjectsByObjectIDQuery           = `SELECT * FROM user_object WHERE object_id = $1;`
+	getUserObjectsByUserIDAndObjectIDsQuery = `SELECT * FROM user_object WHERE user_id = $1 AND object_id = ANY($2);`
+	getUserObjectValueByIDQuery             = `SELECT value FROM user_object WHERE user_id = $1 AND object_id = $2;`
 
 	getObjectIndirectAdminsQuery  = `SELECT GetIndirectObjectAdmins($1);`
 	checkIsIndirectAdminByIDQuery = `WITH object_admins AS (
@@ -77,6 +78,18 @@ func (db *DB) GetUserObjectsByObjectID(ctx context.Context, objectID umid.UMID)
 	return userObjects, nil
 }
 
+func (db *DB) GetUserObjectsByUserIDAndObjectIDs(
+	ctx context.Context, userID umid.UMID, objectIDs []umid.UMID,
+) ([]*entry.UserObject, error) {
+	var userObjects []*entry.UserObject
+	if err := pgxscan.Select(
+		ctx, db.conn, &userObjects, getUserObjectsByUserIDAndObjectIDsQuery, userID, objectIDs,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return userObjects, nil
+}
+
 func (db *DB) GetUserObjectByID(ctx context.Context, userObjectID entry.UserObjectID) (*entry.UserObject, error) {
 	var userObject *entry.UserObject
 	if err := pgxscan.Select(
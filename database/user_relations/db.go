@@ -0,0 +1,148 @@
+package user_relations
+
+import (
+	"context"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getUserRelationsBySourceUserIDQuery = `SELECT * FROM user_relation
+											WHERE source_user_id = $1 AND relation_type = $2
+											ORDER BY created_at DESC;`
+	getUserRelationsByTargetUserIDQuery = `SELECT * FROM user_relation
+											WHERE target_user_id = $1 AND relation_type = $2
+											ORDER BY created_at DESC;`
+	checkUserRelationExistsQuery = `SELECT EXISTS (SELECT 1 FROM user_relation
+										WHERE source_user_id = $1 AND target_user_id = $2 AND relation_type = $3);`
+
+	insertUserRelationQuery = `INSERT INTO user_relation
+									(source_user_id, target_user_id, relation_type)
+								VALUES
+									($1, $2, $3)
+								ON CONFLICT DO NOTHING;`
+	removeUserRelationByIDQuery = `DELETE FROM user_relation
+									WHERE source_user_id = $1 AND target_user_id = $2 AND relation_type = $3;`
+
+	// request is replaced with the friendship in both directions
+	acceptFriendRequestQuery = `WITH request AS (
+									DELETE FROM user_relation
+									WHERE source_user_id = $1 AND target_user_id = $2 AND relation_type = 'friend_request'
+									RETURNING source_user_id, target_user_id
+								)
+								INSERT INTO user_relation (source_user_id, target_user_id, relation_type)
+									SELECT source_user_id, target_user_id, 'friend' FROM request
+									UNION ALL
+									SELECT target_user_id, source_user_id, 'friend' FROM request
+								ON CONFLICT DO NOTHING;`
+	removeFriendshipQuery = `DELETE FROM user_relation
+								WHERE ((source_user_id = $1 AND target_user_id = $2) OR (source_user_id = $2 AND target_user_id = $1))
+									AND relation_type IN ('friend', 'friend_request');`
+	// blocking drops every other relation between the users
+	blockUserQuery = `WITH removed AS (
+							DELETE FROM user_relation
+							WHERE ((source_user_id = $1 AND target_user_id = $2) OR (source_user_id = $2 AND target_user_id = $1))
+								AND relation_type <> 'block'
+						)
+						INSERT INTO user_relation (source_user_id, target_user_id, relation_type)
+						VALUES ($1, $2, 'block')
+						ON CONFLICT DO NOTHING;`
+)
+
+var _ database.UserRelationsDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetUserRelationsBySourceUserID(
+	ctx context.Context, sourceUserID umid.UMID, relationType entry.UserRelationType,
+) ([]*entry.UserRelation, error) {
+	var relations []*entry.UserRelation
+	if err := pgxscan.Select(
+		ctx, db.conn, &relations, getUserRelationsBySourceUserIDQuery, sourceUserID, relationType,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return relations, nil
+}
+
+func (db *DB) GetUserRelationsByTargetUserID(
+	ctx context.Context, targetUserID umid.UMID, relationType entry.UserRelationType,
+) ([]*entry.UserRelation, error) {
+	var relations []*entry.UserRelation
+	if err := pgxscan.Select(
+		ctx, db.conn, &relations, getUserRelationsByTargetUserIDQuery, targetUserID, relationType,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return relations, nil
+}
+
+func (db *DB) CheckUserRelationExists(ctx context.Context, relationID entry.UserRelationID) (bool, error) {
+	var exists bool
+	if err := db.conn.QueryRow(
+		ctx, checkUserRelationExistsQuery,
+		relationID.SourceUserID, relationID.TargetUserID, relationID.RelationType,
+	).Scan(&exists); err != nil {
+		return false, errors.WithMessage(err, "failed to query db")
+	}
+	return exists, nil
+}
+
+func (db *DB) InsertUserRelation(ctx context.Context, relationID entry.UserRelationID) (bool, error) {
+	res, err := db.conn.Exec(
+		ctx, insertUserRelationQuery, relationID.SourceUserID, relationID.TargetUserID, relationID.RelationType,
+	)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) RemoveUserRelationByID(ctx context.Context, relationID entry.UserRelationID) (bool, error) {
+	res, err := db.conn.Exec(
+		ctx, removeUserRelationByIDQuery, relationID.SourceUserID, relationID.TargetUserID, relationID.RelationType,
+	)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) AcceptFriendRequest(ctx context.Context, requesterID, addresseeID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, acceptFriendRequestQuery, requesterID, addresseeID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) RemoveFriendship(ctx context.Context, userID, otherUserID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, removeFriendshipQuery, userID, otherUserID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) BlockUser(ctx context.Context, userID, blockedUserID umid.UMID) error {
+	if _, err := db.conn.Exec(ctx, blockUserQuery, userID, blockedUserID); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
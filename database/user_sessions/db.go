@@ -0,0 +1,116 @@
+package user_sessions
+
+import (
+	"context"
+	"time"
+
+	"github.com/georgysavva/scany/pgxscan"
+	"github.com/jackc/pgx/v4/pgxpool"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	getUserSessionByIDQuery          = `SELECT * FROM user_session WHERE session_id = $1;`
+	getActiveUserSessionsByUserQuery = `SELECT * FROM user_session
+											WHERE user_id = $1 AND revoked_at IS NULL AND expires_at > NOW()
+											ORDER BY created_at DESC;`
+
+	insertUserSessionQuery = `INSERT INTO user_session
+									(session_id, user_id, refresh_token_hash, user_agent, ip_address, expires_at)
+								VALUES
+									($1, $2, $3, $4, $5, $6);`
+
+	// keeps the last 100 rotated hashes to detect reuse
+	rotateUserSessionRefreshTokenQuery = `UPDATE user_session
+											SET refresh_token_hash = $3, expires_at = $4, updated_at = NOW(),
+												previous_refresh_token_hashes = (
+													previous_refresh_token_hashes || refresh_token_hash
+												)[GREATEST(cardinality(previous_refresh_token_hashes) - 98, 1):]
+											WHERE session_id = $1 AND refresh_token_hash = $2
+												AND revoked_at IS NULL AND expires_at > NOW();`
+
+	revokeUserSessionQuery = `UPDATE user_session SET revoked_at = NOW(), updated_at = NOW()
+										WHERE session_id = $1 AND revoked_at IS NULL;`
+	revokeUserSessionsByUserIDQuery = `UPDATE user_session SET revoked_at = NOW(), updated_at = NOW()
+										WHERE user_id = $1 AND revoked_at IS NULL
+										RETURNING session_id;`
+
+	removeExpiredUserSessionsQuery = `DELETE FROM user_session WHERE expires_at < $1 OR revoked_at < $1;`
+)
+
+var _ database.UserSessionsDB = (*DB)(nil)
+
+type DB struct {
+	conn   *pgxpool.Pool
+	common database.CommonDB
+}
+
+func NewDB(conn *pgxpool.Pool, commonDB database.CommonDB) *DB {
+	return &DB{
+		conn:   conn,
+		common: commonDB,
+	}
+}
+
+func (db *DB) GetUserSessionByID(ctx context.Context, sessionID umid.UMID) (*entry.UserSession, error) {
+	var session entry.UserSession
+	if err := pgxscan.Get(ctx, db.conn, &session, getUserSessionByIDQuery, sessionID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &session, nil
+}
+
+func (db *DB) GetActiveUserSessionsByUserID(ctx context.Context, userID umid.UMID) ([]*entry.UserSession, error) {
+	var sessions []*entry.UserSession
+	if err := pgxscan.Select(ctx, db.conn, &sessions, getActiveUserSessionsByUserQuery, userID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return sessions, nil
+}
+
+func (db *DB) InsertUserSession(ctx context.Context, session *entry.UserSession) error {
+	if _, err := db.conn.Exec(
+		ctx, insertUserSessionQuery,
+		session.SessionID, session.UserID, session.RefreshTokenHash, session.UserAgent, session.IPAddress,
+		session.ExpiresAt,
+	); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) RotateUserSessionRefreshToken(
+	ctx context.Context, sessionID umid.UMID, oldHash, newHash string, expiresAt time.Time,
+) (bool, error) {
+	res, err := db.conn.Exec(ctx, rotateUserSessionRefreshTokenQuery, sessionID, oldHash, newHash, expiresAt)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
+func (db *DB) RevokeUserSession(ctx context.Context, sessionID umid.UMID) error {
+	if _, err := db.conn.Exec(ctx, revokeUserSessionQuery, sessionID); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
+
+func (db *DB) RevokeUserSessionsByUserID(ctx context.Context, userID umid.UMID) ([]umid.UMID, error) {
+	var sessionIDs []umid.UMID
+	if err := pgxscan.Select(ctx, db.conn, &sessionIDs, revokeUserSessionsByUserIDQuery, userID); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return sessionIDs, nil
+}
+
+func (db *DB) RemoveExpiredUserSessions(ctx context.Context, before time.Time) error {
+	if _, err := db.conn.Exec(ctx, removeExpiredUserSessionsQuery, before); err != nil {
+		return errors.WithMessage(err, "failed to exec db")
+	}
+	return nil
+}
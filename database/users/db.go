@@ -26,6 +26,14 @@ const (
 							FROM user_attribute,
 							LATERAL jsonb_array_elements_text(value->'wallet') AS wallet_address
 							WHERE UPPER(wallet_address) = UPPER($1) LIMIT 1);`
+	getUserByOIDCIdentityQuery = `SELECT * FROM "user" WHERE user_id = (SELECT user_id
+							FROM user_attribute
+							WHERE plugin_id = $1
+							  AND attribute_name = 'oidc_identity'
+							  AND value->'identities' @> jsonb_build_array(
+							      jsonb_build_object('provider', $2::text, 'subject', $3::text)
+							  )
+							LIMIT 1);`
 	getUsersByUserType = `SELECT * FROM "user" WHERE user_type_id = $1;`
 	getWalletByUserID  = `SELECT value -> 'wallet' ->> 0 AS wallet
 						FROM user_attribute
@@ -57,8 +65,13 @@ const (
 	updateUserOptionsQuery    = `UPDATE "user" SET options = $2, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1;`
 	updateUserProfileQuery    = `UPDATE "user" SET profile = $2, updated_at = CURRENT_TIMESTAMP WHERE user_id = $1;`
 
+	replaceUserUserTypeIDQuery = `UPDATE "user" SET user_type_id = $3, updated_at = CURRENT_TIMESTAMP
+							WHERE user_id = $1 AND user_type_id = $2;`
+
 	removeUserByIDQuery   = `DELETE FROM "user" WHERE user_id = $1;`
 	removeUsersByIDsQuery = `DELETE FROM "user" WHERE user_id = ANY($1);`
+
+	removeUserByIDAndUserTypeIDQuery = `DELETE FROM "user" WHERE user_id = $1 AND user_type_id = $2;`
 )
 
 var _ database.UsersDB = (*DB)(nil)
@@ -125,6 +138,16 @@ func (db *DB) GetUserByWallet(ctx context.Context, wallet string) (*entry.User,
 	return &user, nil
 }
 
+func (db *DB) GetUserByOIDCIdentity(ctx context.Context, provider, subject string) (*entry.User, error) {
+	var user entry.User
+	if err := pgxscan.Get(
+		ctx, db.conn, &user, getUserByOIDCIdentityQuery, universe.GetSystemPluginID(), provider, subject,
+	); err != nil {
+		return nil, errors.WithMessage(err, "failed to query db")
+	}
+	return &user, nil
+}
+
 func (db *DB) GetUserWalletByUserID(ctx context.Context, userID umid.UMID) (*string, error) {
 	var wallet string
 	if err := db.conn.QueryRow(ctx, getWalletByUserID, userID).
@@ -220,6 +243,16 @@ func (db *DB) RemoveUserByID(ctx context.Context, userID umid.UMID) error {
 	return nil
 
 }
+
+// RemoveUserByIDAndUserTypeID removes the user only if it still has the user type, returns true if removed.
+func (db *DB) RemoveUserByIDAndUserTypeID(ctx context.Context, userID, userTypeID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, removeUserByIDAndUserTypeIDQuery, userID, userTypeID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
 func (db *DB) UpdateUserUserTypeID(ctx context.Context, userID umid.UMID, userTypeID umid.UMID) error {
 	if _, err := db.conn.Exec(ctx, updateUserUserTypeIDQuery, userID, userTypeID); err != nil {
 		return errors.WithMessage(err, "failed to exec db")
@@ -227,6 +260,15 @@ func (db *DB) UpdateUserUserTypeID(ctx context.Context, userID umid.UMID, userTy
 	return nil
 }
 
+// ReplaceUserUserTypeID changes the user type only if the user has the old user type, returns true if changed.
+func (db *DB) ReplaceUserUserTypeID(ctx context.Context, userID, oldUserTypeID, newUserTypeID umid.UMID) (bool, error) {
+	res, err := db.conn.Exec(ctx, replaceUserUserTypeIDQuery, userID, oldUserTypeID, newUserTypeID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to exec db")
+	}
+	return res.RowsAffected() > 0, nil
+}
+
 func (db *DB) UpdateUserOptions(ctx context.Context, userID umid.UMID, options *entry.UserOptions) error {
 	if _, err := db.conn.Exec(ctx, updateUserOptionsQuery, userID, options); err != nil {
 		return errors.WithMessage(err, "failed to exec db")
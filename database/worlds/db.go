@@ -17,17 +17,21 @@ import (
 const (
 	getAllWorldIDsQuery = `SELECT object_id FROM object
                  			WHERE parent_id = (SELECT object_id FROM object WHERE object_id = parent_id)
-                 			AND object_id != parent_id;`
+                 			AND object_id != parent_id
+                 			AND object_id NOT IN (SELECT object_id FROM object_trash);`
 	getWorldIDsQuery = `SELECT object_id FROM object
                  			WHERE parent_id = (SELECT object_id FROM object WHERE object_id = parent_id)
-                 			AND object_id != parent_id 
+                 			AND object_id != parent_id
+                 			AND object_id NOT IN (SELECT object_id FROM object_trash)
                  			ORDER BY created_at `
 	getWorldsQuery = `SELECT * FROM object
          					WHERE parent_id = (SELECT object_id FROM object WHERE object_id = parent_id)
-         					AND object_id != parent_id;`
+         					AND object_id != parent_id
+         					AND object_id NOT IN (SELECT object_id FROM object_trash);`
 	getRecentWorldIDsQuery = `SELECT object_id FROM object
          					WHERE parent_id = (SELECT object_id FROM object WHERE object_id = parent_id)
          					AND object_id != parent_id
+         					AND object_id NOT IN (SELECT object_id FROM object_trash)
          					ORDER BY created_at DESC
 							LIMIT 6;`
 )
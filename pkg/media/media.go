@@ -1,12 +1,15 @@
 package media
 
 import (
+	"context"
 	"fmt"
 	"io"
 	"mime/multipart"
 	"net/http"
 	"os"
 	"path"
+	"regexp"
+	"strconv"
 
 	"go.uber.org/zap"
 
@@ -17,6 +20,7 @@ import (
 
 	"github.com/momentum-xyz/ubercontroller/config"
 	"github.com/momentum-xyz/ubercontroller/pkg/media/processor"
+	"github.com/momentum-xyz/ubercontroller/pkg/media/storage"
 	"github.com/momentum-xyz/ubercontroller/types"
 )
 
@@ -41,21 +45,87 @@ func (m *Media) Initialize(ctx types.NodeContext) error {
 	m.cfg = ctx.Config()
 
 	p := processor.NewProcessor()
-	p.Initialize(ctx)
+	if err := p.Initialize(ctx); err != nil {
+		return errors.WithMessage(err, "failed to initialize processor")
+	}
 	m.processor = p
 
 	return nil
 }
 
-func (m *Media) GetImage(filename string) (*types.MetaDef, *string, error) {
+// File is an uploaded file in one of the media storages.
+type File struct {
+	Storage storage.Storage
+	Key     string
+}
+
+// Open returns the content of the file.
+func (f *File) Open(ctx context.Context) (io.ReadCloser, error) {
+	reader, _, err := f.Storage.Get(ctx, f.Key)
+	return reader, err
+}
+
+// ServeFile writes the file to the response.
+// With redirects enabled the client is redirected to a signed url of the file instead, if the storage supports it.
+func (m *Media) ServeFile(c *gin.Context, file *File, contentType string) error {
+	if m.cfg.Media.Redirect {
+		if signer, ok := file.Storage.(storage.Signer); ok {
+			url, err := signer.SignedURL(file.Key, m.cfg.Media.RedirectTTL)
+			if err != nil {
+				return errors.WithMessage(err, "error signing url")
+			}
+			c.Redirect(http.StatusTemporaryRedirect, url)
+			return nil
+		}
+	}
+
+	c.Header("Content-Type", contentType)
+	if local, ok := file.Storage.(storage.Local); ok {
+		c.File(local.Path(file.Key))
+		return nil
+	}
+
+	reader, info, err := file.Storage.Get(c.Request.Context(), file.Key)
+	if err != nil {
+		return errors.WithMessage(err, "error opening file")
+	}
+	defer reader.Close()
+
+	c.Header("Content-Length", strconv.FormatInt(info.Size, 10))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, reader); err != nil {
+		return errors.WithMessage(err, "error copying file")
+	}
+
+	return nil
+}
+
+// readFileHead returns up to size first bytes of the file.
+func (m *Media) readFileHead(file *File, size int) ([]byte, error) {
+	reader, err := file.Open(m.ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "error opening file")
+	}
+	defer reader.Close()
+
+	buf := make([]byte, size)
+	n, err := io.ReadFull(reader, buf)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return nil, errors.WithMessage(err, "error reading file buffer")
+	}
+
+	return buf[:n], nil
+}
+
+func (m *Media) GetImage(filename string) (*types.MetaDef, *File, error) {
 	m.log.Debug("Endpoint Hit: Image Get: ", filename)
 
-	meta, filepath := m.processor.Present(filename)
+	meta := m.processor.Present(filename)
 	if meta == nil {
 		return nil, nil, errors.New("no meta for file")
 	}
 
-	return meta, filepath, nil
+	return meta, &File{Storage: m.processor.Images, Key: processor.ImageKey(filename)}, nil
 }
 
 func (m *Media) AddImage(file multipart.File) (string, error) {
@@ -72,19 +142,19 @@ func (m *Media) AddImage(file multipart.File) (string, error) {
 	return hash, err
 }
 
-func (m *Media) GetTexture(rsize string, filename string) (*types.MetaDef, *string, error) {
+func (m *Media) GetTexture(rsize string, filename string) (*types.MetaDef, *File, error) {
 	if _, ok := types.Tsizes[rsize]; !ok {
 		return nil, nil, errors.New("tsize not found for texture")
 	}
 
 	m.log.Debug("Endpoint Hit: Texture Get: ", filename, rsize)
 
-	meta, filepath := m.processor.PresentTexture(&(filename), rsize)
-	if meta == nil || filepath == nil {
-		return nil, nil, errors.New("no meta or filepath for file")
+	meta := m.processor.PresentTexture(&(filename), rsize)
+	if meta == nil {
+		return nil, nil, errors.New("no meta for file")
 	}
 
-	return meta, filepath, nil
+	return meta, &File{Storage: m.processor.Images, Key: processor.ScaledImageKey(rsize, filename)}, nil
 }
 
 func (m *Media) AddFrame(file []byte) (string, error) {
@@ -107,34 +177,17 @@ func (m *Media) AddTube(file []byte) (string, error) {
 	return hash, err
 }
 
-func (m *Media) GetVideo(filename string) (*os.File, os.FileInfo, string, error) {
+func (m *Media) GetVideo(filename string) (*File, string, error) {
 	m.log.Debug("Endpoint Hit: Video Get: ", filename)
 
-	filepath := path.Join(m.processor.Videopath, path.Base(filename))
-	file, err := os.Open(filepath)
-	if err != nil {
-		return nil, nil, "", errors.WithMessage(err, "error opening file")
-	}
-
-	fileInfo, err := file.Stat()
-	if err != nil {
-		return nil, nil, "", errors.WithMessage(err, "error getting file info")
-	}
-
-	buf := make([]byte, 512)
-
-	_, err = file.Read(buf)
-	if err != nil {
-		return nil, nil, "", errors.WithMessage(err, "error reading file buffer")
-	}
-
-	_, err = file.Seek(0, 0)
+	file := &File{Storage: m.processor.Videos, Key: path.Base(filename)}
+	buf, err := m.readFileHead(file, 512)
 	if err != nil {
-		return nil, nil, "", errors.WithMessage(err, "error seeking file")
+		return nil, "", err
 	}
 
 	contentType := http.DetectContentType(buf)
-	return file, fileInfo, contentType, nil
+	return file, contentType, nil
 }
 
 func (m *Media) AddVideo(file multipart.File) (string, error) {
@@ -148,28 +201,21 @@ func (m *Media) AddVideo(file multipart.File) (string, error) {
 	return hash, err
 }
 
-func (m *Media) GetAudio(filename string) (*fileTypes.Type, string, error) {
+func (m *Media) GetAudio(filename string) (*fileTypes.Type, *File, error) {
 	m.log.Debug("Endpoint Hit: Audio Get: ", filename)
 
-	filepath := path.Join(m.processor.Audiopath, path.Base(filename))
-	buf := make([]byte, 264)
-	file, err := os.Open(filepath)
+	file := &File{Storage: m.processor.Audio, Key: path.Base(filename)}
+	buf, err := m.readFileHead(file, 264)
 	if err != nil {
-		return nil, "", errors.WithMessage(err, "error opening audio")
+		return nil, nil, errors.WithMessage(err, "error reading audio")
 	}
-	defer file.Close()
 
-	n, err := file.Read(buf)
+	fileType, err := filetype.Get(buf)
 	if err != nil {
-		return nil, "", errors.WithMessage(err, "error reading buffer")
+		return nil, nil, errors.WithMessage(err, "error getting audio type")
 	}
 
-	fileType, err := filetype.Get(buf[:n])
-	if err != nil {
-		return nil, "", errors.WithMessage(err, "error getting audio type")
-	}
-
-	return &fileType, filepath, nil
+	return &fileType, file, nil
 }
 
 func (m *Media) AddAudio(file multipart.File) (string, error) {
@@ -186,9 +232,7 @@ func (m *Media) AddAudio(file multipart.File) (string, error) {
 func (m *Media) DeleteAudio(filename string) error {
 	m.log.Info("Endpoint Hit: Track Delete: ", filename)
 
-	filepath := path.Join(m.processor.Audiopath, path.Base(filename))
-	err := os.Remove(filepath)
-	if err != nil {
+	if err := m.processor.Audio.Delete(m.ctx, path.Base(filename)); err != nil {
 		return errors.WithMessage(err, "error deleting audio")
 	}
 
@@ -197,6 +241,103 @@ func (m *Media) DeleteAudio(filename string) error {
 	return nil
 }
 
+// FindFile returns the uploaded image or video with the given hash.
+func (m *Media) FindFile(filename string) (*File, bool) {
+	for _, kind := range []string{FileKindImage, FileKindVideo} {
+		if file, ok := m.FindFileOfKind(kind, filename); ok {
+			return file, true
+		}
+	}
+	return nil, false
+}
+
+// Kinds of the uploaded media files.
+const (
+	FileKindImage = "images"
+	FileKindVideo = "videos"
+	FileKindAudio = "audio"
+	FileKindAsset = "assets"
+)
+
+var FileKinds = []string{FileKindImage, FileKindVideo, FileKindAudio, FileKindAsset}
+
+// FindFileOfKind returns the uploaded file of the kind with the given hash.
+func (m *Media) FindFileOfKind(kind string, filename string) (*File, bool) {
+	file, ok := m.getFileOfKind(kind, filename)
+	if !ok {
+		return nil, false
+	}
+	return file, m.processor.FileExists(file.Storage, file.Key)
+}
+
+// ImportFile stores the file of the kind with the given hash as is, an already existing file is kept.
+// Scaled versions of images are generated on request.
+func (m *Media) ImportFile(kind string, filename string, file io.Reader) error {
+	filename = path.Base(filename)
+	if !fileHashRegexp.MatchString(filename) {
+		return errors.Errorf("invalid file name: %s", filename)
+	}
+	dst, ok := m.getFileOfKind(kind, filename)
+	if !ok {
+		return errors.Errorf("unknown file kind: %s", kind)
+	}
+	if m.processor.FileExists(dst.Storage, dst.Key) {
+		return nil
+	}
+
+	// the storage needs a seekable body
+	tmp, err := os.CreateTemp("", "tmp")
+	if err != nil {
+		return errors.WithMessage(err, "error creating file")
+	}
+	defer func() {
+		tmp.Close()
+		os.Remove(tmp.Name())
+	}()
+
+	if _, err := io.Copy(tmp, file); err != nil {
+		return errors.WithMessage(err, "error writing file")
+	}
+	if _, err := tmp.Seek(0, io.SeekStart); err != nil {
+		return errors.WithMessage(err, "error seeking file")
+	}
+
+	return dst.Storage.Put(m.ctx, dst.Key, tmp)
+}
+
+func (m *Media) getFileOfKind(kind string, filename string) (*File, bool) {
+	filename = path.Base(filename)
+	switch kind {
+	case FileKindImage:
+		return &File{Storage: m.processor.Images, Key: processor.ImageKey(filename)}, true
+	case FileKindVideo:
+		return &File{Storage: m.processor.Videos, Key: filename}, true
+	case FileKindAudio:
+		return &File{Storage: m.processor.Audio, Key: filename}, true
+	case FileKindAsset:
+		return &File{Storage: m.processor.Assets, Key: filename}, true
+	}
+	return nil, false
+}
+
+var fileHashRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+// DeleteFile removes the uploaded image (with its scaled versions) or video with the given hash.
+func (m *Media) DeleteFile(filename string) error {
+	m.log.Info("Media: DeleteFile: ", filename)
+
+	filename = path.Base(filename)
+	if err := m.processor.RemoveImage(filename); err != nil {
+		return errors.WithMessage(err, "error deleting image")
+	}
+
+	if err := m.processor.Videos.Delete(m.ctx, filename); err != nil {
+		return errors.WithMessage(err, "error deleting video")
+	}
+
+	return nil
+}
+
 func (m *Media) GetPluginManifest(pluginHash string) (*processor.Manifest, error) {
 	meta, err := m.processor.LoadPluginManifest(pluginHash)
 	if err != nil {
@@ -206,28 +347,31 @@ func (m *Media) GetPluginManifest(pluginHash string) (*processor.Manifest, error
 	return meta, nil
 }
 
-func (m *Media) GetAsset(filename string) (*fileTypes.Type, string, error) {
-	m.log.Debug("Endpoint Hit: Asset Get: ", filename)
-
-	filepath := path.Join(m.processor.Assetpath, path.Base(filename))
-	buf := make([]byte, 264)
-	file, err := os.Open(filepath)
-	if err != nil {
-		return nil, "", errors.WithMessage(err, "error opening file")
+// GetPluginFile returns the file of the uploaded plugin, name is the path of the file prefixed with the plugin hash.
+func (m *Media) GetPluginFile(name string) (*File, error) {
+	file := &File{Storage: m.processor.Plugins, Key: name}
+	if _, err := file.Storage.Stat(m.ctx, file.Key); err != nil {
+		return nil, errors.WithMessage(err, "error getting file info")
 	}
-	defer file.Close()
 
-	n, err := file.Read(buf)
+	return file, nil
+}
+
+func (m *Media) GetAsset(filename string) (*fileTypes.Type, *File, error) {
+	m.log.Debug("Endpoint Hit: Asset Get: ", filename)
+
+	file := &File{Storage: m.processor.Assets, Key: path.Base(filename)}
+	buf, err := m.readFileHead(file, 264)
 	if err != nil {
-		return nil, "", errors.WithMessage(err, "error reading file buffer")
+		return nil, nil, err
 	}
 
-	fileType, err := filetype.Get(buf[:n])
+	fileType, err := filetype.Get(buf)
 	if err != nil {
-		return nil, "", errors.WithMessage(err, "error getting filetype")
+		return nil, nil, errors.WithMessage(err, "error getting filetype")
 	}
 
-	return &fileType, filepath, nil
+	return &fileType, file, nil
 }
 
 func (m *Media) AddAsset(file multipart.File) (string, error) {
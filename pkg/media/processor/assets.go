@@ -43,16 +43,14 @@ func (p *Processor) ProcessAsset(body io.ReadCloser) (string, error) {
 		return "", fmt.Errorf("Not accepted asset type: %s", t.MIME.Value)
 	}
 
-	file, err := os.CreateTemp(p.Assetpath, "tmp")
+	file, err := os.CreateTemp("", "tmp")
 	if err != nil {
 		return "", err
 	}
 
 	defer func() {
-		_, err := os.Stat(file.Name())
-		if err == nil {
-			os.Remove(file.Name())
-		}
+		file.Close()
+		os.Remove(file.Name())
 	}()
 
 	file.Write(buf[:n])
@@ -61,11 +59,11 @@ func (p *Processor) ProcessAsset(body io.ReadCloser) (string, error) {
 		return "", err
 	}
 
-	file.Close()
-
 	bhash := hasher.Sum(nil)
 	hash := hex.EncodeToString(bhash[:])
-	os.Rename(file.Name(), p.Assetpath+hash)
+	if err := p.storeFile(p.Assets, hash, file); err != nil {
+		return "", err
+	}
 
-	return hash, err
+	return hash, nil
 }
@@ -40,16 +40,14 @@ func (p *Processor) ProcessTrack(body io.ReadCloser) (string, error) {
 		return "", errors.New("audio type not accepted")
 	}
 
-	file, err := os.CreateTemp(p.Audiopath, "tmp")
+	file, err := os.CreateTemp("", "tmp")
 	if err != nil {
 		return "", err
 	}
 
 	defer func() {
-		_, err := os.Stat(file.Name())
-		if err == nil {
-			os.Remove(file.Name())
-		}
+		file.Close()
+		os.Remove(file.Name())
 	}()
 
 	file.Write(buf[:n])
@@ -58,11 +56,11 @@ func (p *Processor) ProcessTrack(body io.ReadCloser) (string, error) {
 		return "", err
 	}
 
-	file.Close()
-
 	bhash := hasher.Sum(nil)
 	hash := hex.EncodeToString(bhash[:])
-	os.Rename(file.Name(), p.Audiopath+hash)
+	if err := p.storeFile(p.Audio, hash, file); err != nil {
+		return "", err
+	}
 
-	return hash, err
+	return hash, nil
 }
@@ -2,14 +2,14 @@ package processor
 
 import (
 	"bytes"
-	"errors"
 	"image"
 	_ "image/gif"
 	_ "image/jpeg"
 	"image/png"
 	_ "image/png"
 	"math"
-	"os"
+
+	"github.com/pkg/errors"
 
 	"github.com/momentum-xyz/ubercontroller/types"
 
@@ -25,54 +25,34 @@ func (p *Processor) WriteToF(img image.Image) (error, string) {
 	}
 	body := w.Bytes()
 	ID := p.GetMD5HashByte(body)
-	return p.SaveWriteToFile(p.ImPathF+ID, body), ID
+	return p.SaveWriteToFile(ImageKey(ID), body), ID
 }
 
 func (p *Processor) WriteToScaled(base string, img image.Image, rsize string) error {
 	if size, ok := types.Tsizes[rsize]; ok {
-		fpath := p.ImPathS[rsize] + base
-		if p.FileExists(fpath) {
-			p.log.Debugf("Scaled file %s already exist, skip", fpath)
+		key := ScaledImageKey(rsize, base)
+		if p.FileExists(p.Images, key) {
+			p.log.Debugf("Scaled file %s already exist, skip", key)
 			return nil
 		}
-		return p.SaveWriteToPNG(fpath, DownSampleTo(img, size))
+		return p.SaveWriteToPNG(key, DownSampleTo(img, size))
 	}
 	return errors.New("Not such size defined in the size map")
 
 }
 
-func (p *Processor) SaveWriteToPNG(fname string, img image.Image) error {
-	tfilename := fname + ".tmp"
-
-	w, err := os.Create(tfilename)
-	if err != nil {
-		return err
-	}
-
-	err = png.Encode(w, img)
-	w.Close()
-	if err != nil {
-		os.Remove(tfilename)
-		return err
-	}
-	if err = os.Rename(tfilename, fname); err != nil {
-		os.Remove(tfilename)
+// SaveWriteToPNG stores the image encoded as png under the key of the image storage.
+func (p *Processor) SaveWriteToPNG(key string, img image.Image) error {
+	var w bytes.Buffer
+	if err := png.Encode(&w, img); err != nil {
 		return err
 	}
-	return nil
+	return p.SaveWriteToFile(key, w.Bytes())
 }
 
-func (p *Processor) SaveWriteToFile(fname string, data []byte) error {
-	tfilename := fname + ".tmp"
-	err := os.WriteFile(tfilename, data, 0666)
-	if err != nil {
-		os.Remove(tfilename)
-		return err
-	}
-	if err = os.Rename(tfilename, fname); err != nil {
-		return err
-	}
-	return nil
+// SaveWriteToFile stores the data under the key of the image storage.
+func (p *Processor) SaveWriteToFile(key string, data []byte) error {
+	return p.Images.Put(p.ctx, key, bytes.NewReader(data))
 }
 
 func (p *Processor) ProcessImage(src []byte) (string, error) {
@@ -85,7 +65,7 @@ func (p *Processor) ProcessImage(src []byte) (string, error) {
 	var ID string
 	if format == "gif" {
 		ID = p.GetMD5HashByte(src)
-		err = p.SaveWriteToFile(p.ImPathF+ID, src)
+		err = p.SaveWriteToFile(ImageKey(ID), src)
 	} else {
 		err, ID = p.WriteToF(img)
 	}
@@ -116,3 +96,20 @@ func DownSampleTo(img image.Image, NewPixelCount int) image.Image {
 	imgout := resize.Thumbnail(nx, ny, img, resize.Bilinear)
 	return imgout
 }
+
+// RemoveImage deletes the original image with all its scaled versions.
+func (p *Processor) RemoveImage(ID string) error {
+	if err := p.Images.Delete(p.ctx, ImageKey(ID)); err != nil {
+		return errors.WithMessage(err, "failed to remove image")
+	}
+	p.ImageMapF.Remove(ID)
+
+	for rsize := range types.Tsizes {
+		if err := p.Images.Delete(p.ctx, ScaledImageKey(rsize, ID)); err != nil {
+			return errors.WithMessagef(err, "failed to remove scaled image: %s", rsize)
+		}
+		p.ImageMapS[rsize].Remove(ID)
+	}
+
+	return nil
+}
@@ -15,6 +15,8 @@ import (
 	"strings"
 
 	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/media/storage"
 )
 
 func dirMD5(dirPath string) (string, error) {
@@ -152,22 +154,23 @@ type Manifest struct {
 }
 
 func (p *Processor) LoadPluginManifest(pluginHash string) (*Manifest, error) {
-	manifestFn := filepath.Join(p.Pluginpath, pluginHash, "manifest.json")
-
-	_, err := os.Stat(manifestFn)
-	if err != nil {
-		err := errors.WithMessage(err, "manifest.json not found")
-		return nil, err
-	}
-
-	file, err := os.Open(manifestFn)
+	file, _, err := p.Plugins.Get(p.ctx, path.Join(pluginHash, "manifest.json"))
 	if err != nil {
+		if errors.Is(err, storage.ErrNotFound) {
+			err := errors.WithMessage(err, "manifest.json not found")
+			return nil, err
+		}
 		err := errors.WithMessage(err, "Error opening file")
 		return nil, err
 	}
+	defer file.Close()
 
+	return decodeManifest(file)
+}
+
+func decodeManifest(file io.Reader) (*Manifest, error) {
 	var manifest Manifest
-	err = json.NewDecoder(file).Decode(&manifest)
+	err := json.NewDecoder(file).Decode(&manifest)
 	if err != nil {
 		err := errors.WithMessage(err, "Error decoding manifest")
 		return nil, err
@@ -187,7 +190,7 @@ func (p *Processor) ProcessPlugin(body io.ReadCloser) (string, error) {
 		os.Remove(archivePath)
 	}()
 
-	tempDir, err := os.MkdirTemp(p.Pluginpath, ".upload-")
+	tempDir, err := os.MkdirTemp("", ".upload-")
 	if err != nil {
 		err := errors.WithMessage(err, "Error creating temporary directory")
 		return "", err
@@ -211,7 +214,13 @@ func (p *Processor) ProcessPlugin(body io.ReadCloser) (string, error) {
 		return "", err
 	}
 
-	if _, err := p.LoadPluginManifest(path.Base(tempDir)); err != nil {
+	manifestFile, err := os.Open(filepath.Join(tempDir, "manifest.json"))
+	if err != nil {
+		err := errors.WithMessage(err, "manifest.json not found")
+		return "", err
+	}
+	defer manifestFile.Close()
+	if _, err := decodeManifest(manifestFile); err != nil {
 		err := errors.WithMessage(err, "Error loading manifest")
 		return "", err
 	}
@@ -222,34 +231,55 @@ func (p *Processor) ProcessPlugin(body io.ReadCloser) (string, error) {
 		return "", err
 	}
 
-	targetDir := filepath.Join(p.Pluginpath, hash)
-	tStat, err := os.Stat(targetDir)
-	if err == nil {
-		if tStat.IsDir() {
-			return hash, nil
-		} else {
-			return "", errors.New("There is already regular file with such name")
-		}
+	if p.FileExists(p.Plugins, path.Join(hash, "manifest.json")) {
+		return hash, nil
 	}
 
-	fmt.Println("Renaming", tempDir, "to", targetDir)
-	err = os.Rename(tempDir, targetDir)
+	if err := p.storePluginDir(hash, tempDir); err != nil {
+		err := errors.WithMessage(err, "Error storing plugin files")
+		return "", err
+	}
 
-	if err != nil {
-		if errors.Is(err, fs.ErrExist) {
-			tStat, err = os.Stat(targetDir)
-			if err == nil {
-				if tStat.IsDir() {
-					return hash, nil
-				}
-			}
-		}
+	return hash, nil
+}
 
-		err := errors.WithMessage(err, "Error renaming temp dir to target name")
-		return "", err
+// storePluginDir stores the files of the extracted plugin under its hash.
+// The manifest is stored last, so a plugin with a manifest in the storage is complete.
+func (p *Processor) storePluginDir(hash string, dir string) error {
+	err := filepath.WalkDir(
+		dir, func(filePath string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.Type().IsRegular() {
+				return nil
+			}
+			name, err := filepath.Rel(dir, filePath)
+			if err != nil {
+				return err
+			}
+			if name == "manifest.json" {
+				return nil
+			}
+			return p.storePluginFile(hash, dir, name)
+		},
+	)
+	if err != nil {
+		return err
 	}
 
-	os.Chmod(targetDir, 0755)
+	return p.storePluginFile(hash, dir, "manifest.json")
+}
 
-	return hash, nil
+func (p *Processor) storePluginFile(hash string, dir string, name string) error {
+	file, err := os.Open(filepath.Join(dir, name))
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	if err := p.Plugins.Put(p.ctx, path.Join(hash, filepath.ToSlash(name)), file); err != nil {
+		return errors.WithMessagef(err, "failed to store file: %s", name)
+	}
+	return nil
 }
@@ -5,8 +5,8 @@ import (
 	"encoding/hex"
 	"encoding/json"
 	"image"
+	"io"
 	"os"
-	"path"
 	"strconv"
 	"strings"
 	"sync"
@@ -19,6 +19,7 @@ import (
 	lru "github.com/hashicorp/golang-lru"
 
 	"github.com/momentum-xyz/ubercontroller/config"
+	"github.com/momentum-xyz/ubercontroller/pkg/media/storage"
 	"github.com/momentum-xyz/ubercontroller/types"
 )
 
@@ -27,15 +28,14 @@ type Processor struct {
 	log *zap.SugaredLogger
 	cfg *config.Config
 
-	Fontpath   string
-	Imagepath  string
-	Audiopath  string
-	Videopath  string
-	Assetpath  string
-	Pluginpath string
+	Fontpath string
 
-	ImPathF string
-	ImPathS map[string]string
+	// originals of the images are kept under "F/", scaled versions under the name of their size
+	Images  storage.Storage
+	Videos  storage.Storage
+	Audio   storage.Storage
+	Assets  storage.Storage
+	Plugins storage.Storage
 
 	ImageMapF *lru.Cache
 	ImageMapS map[string]*lru.Cache
@@ -58,40 +58,52 @@ func NewProcessor() *Processor {
 	return processor
 }
 
-func (p *Processor) Initialize(ctx types.NodeContext) *Processor {
+func (p *Processor) Initialize(ctx types.NodeContext) error {
 	p.ctx = ctx
 	p.log = ctx.Logger()
 	p.cfg = ctx.Config()
-	p.Imagepath = strings.TrimSuffix(p.cfg.Media.Imagepath, "/") + "/"
-	p.Videopath = strings.TrimSuffix(p.cfg.Media.Videopath, "/") + "/"
-	p.Audiopath = strings.TrimSuffix(p.cfg.Media.Audiopath, "/") + "/"
-	p.Assetpath = strings.TrimSuffix(p.cfg.Media.Assetpath, "/") + "/"
-	p.Pluginpath = strings.TrimSuffix(p.cfg.Media.Pluginpath, "/") + "/"
 	p.Fontpath = strings.TrimSuffix(p.cfg.Media.Fontpath, "/") + "/"
 	p.framesinprogress = make(map[string]bool)
 	p.RenderQueue = make(chan *types.FrameRenderRequest, 512)
 	p.RenderDone = make(chan *types.FrameRenderRequest, 512)
-	os.MkdirAll(p.Imagepath, os.ModePerm)
-	os.MkdirAll(p.Videopath, os.ModePerm)
-	os.MkdirAll(p.Audiopath, os.ModePerm)
-	os.MkdirAll(p.Assetpath, os.ModePerm)
-	os.MkdirAll(p.Pluginpath, os.ModePerm)
 
-	os.MkdirAll(p.Imagepath+"F", os.ModePerm)
-	p.ImPathF = p.Imagepath + "F/"
+	storages := []struct {
+		storage *storage.Storage
+		dir     string
+		prefix  string
+	}{
+		{storage: &p.Images, dir: p.cfg.Media.Imagepath, prefix: "images/"},
+		{storage: &p.Videos, dir: p.cfg.Media.Videopath, prefix: "videos/"},
+		{storage: &p.Audio, dir: p.cfg.Media.Audiopath, prefix: "tracks/"},
+		{storage: &p.Assets, dir: p.cfg.Media.Assetpath, prefix: "assets/"},
+		{storage: &p.Plugins, dir: p.cfg.Media.Pluginpath, prefix: "plugins/"},
+	}
+	for _, s := range storages {
+		st, err := storage.New(&p.cfg.Media, s.dir, s.prefix)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to create storage: %s", s.prefix)
+		}
+		*s.storage = st
+	}
 
 	p.ImageMapF, _ = lru.New(defaultCacheSize)
 	p.ImageMapS = make(map[string]*lru.Cache)
-	p.ImPathS = make(map[string]string)
 	for rs := range types.Tsizes {
-		os.MkdirAll(p.Imagepath+rs, os.ModePerm)
-		p.ImPathS[rs] = p.Imagepath + rs + "/"
 		p.ImageMapS[rs], _ = lru.New(defaultCacheSize)
 	}
 
-	os.MkdirAll(strings.TrimSuffix(p.ImPathF, "/"), 0775)
 	go p.run()
-	return p
+	return nil
+}
+
+// ImageKey returns the key of the original image in the image storage.
+func ImageKey(ID string) string {
+	return "F/" + ID
+}
+
+// ScaledImageKey returns the key of the scaled version of the image in the image storage.
+func ScaledImageKey(rsize string, ID string) string {
+	return rsize + "/" + ID
 }
 
 func (p *Processor) checkError(err error) bool {
@@ -139,12 +151,24 @@ func (p *Processor) HandleError(err error) bool {
 	return false
 }
 
-func (p *Processor) FileExists(filename string) bool {
-	info, err := os.Stat(filename)
-	if os.IsNotExist(err) {
-		return false
+// FileExists reports whether the key is in the storage, failing lookups are logged and count as missing.
+func (p *Processor) FileExists(s storage.Storage, key string) bool {
+	exists, err := storage.Exists(p.ctx, s, key)
+	if err != nil {
+		p.log.Error(errors.WithMessagef(err, "Processor: FileExists: failed to stat file: %s", key))
+	}
+	return exists
+}
+
+// storeFile stores the temporary file under the key, a file already stored under the key is kept.
+func (p *Processor) storeFile(s storage.Storage, key string, file *os.File) error {
+	if p.FileExists(s, key) {
+		return nil
+	}
+	if _, err := file.Seek(0, io.SeekStart); err != nil {
+		return errors.WithMessage(err, "failed to seek file")
 	}
-	return !info.IsDir()
+	return s.Put(p.ctx, key, file)
 }
 
 func (p *Processor) GetMD5HashByte(text []byte) string {
@@ -172,8 +196,7 @@ func (p *Processor) ProcessFrame(body []byte) (string, error) {
 	}
 
 	p.log.Debug(string(str))
-	res, _ := p.Present(hash)
-	if res == nil {
+	if res := p.Present(hash); res == nil {
 		req := &types.FrameRenderRequest{ID: &hash, Frame: &payload}
 		req.Wg.Add(1)
 		p.RenderQueue <- req
@@ -183,18 +206,17 @@ func (p *Processor) ProcessFrame(body []byte) (string, error) {
 	return hash, nil
 }
 
-func (p *Processor) Present(imageID string) (*types.MetaDef, *string) {
-	filePath := path.Join(p.ImPathF, imageID)
+func (p *Processor) Present(imageID string) *types.MetaDef {
 	res, ok := p.ImageMapF.Get(imageID)
 	if ok {
 		p.log.Debug(imageID + " is already in the map")
-		return res.(*types.MetaDef), &filePath
+		return res.(*types.MetaDef)
 	}
 
-	reader, err := os.Open(filePath)
-	p.log.Debug(filePath)
+	reader, _, err := p.Images.Get(p.ctx, ImageKey(imageID))
+	p.log.Debug(ImageKey(imageID))
 	if err != nil {
-		return nil, nil
+		return nil
 	}
 
 	defer reader.Close()
@@ -212,22 +234,22 @@ func (p *Processor) Present(imageID string) (*types.MetaDef, *string) {
 	p.log.Debug("Mime:", meta.Mime)
 	p.ImageMapF.Add(imageID, meta)
 
-	return meta, &filePath
+	return meta
 }
 
-func (p *Processor) PresentTexture(ID *string, rsize string) (*types.MetaDef, *string) {
-	fpath := p.ImPathS[rsize] + *ID
+func (p *Processor) PresentTexture(ID *string, rsize string) *types.MetaDef {
+	key := ScaledImageKey(rsize, *ID)
 	meta0, ok := p.ImageMapS[rsize].Get(*ID)
 	if ok {
 		p.log.Debug(*ID + " is already in the map")
-		return meta0.(*types.MetaDef), &fpath
+		return meta0.(*types.MetaDef)
 	}
 
 	defer func() {
-		p.ImagesRescaleInProgress.Delete(fpath)
+		p.ImagesRescaleInProgress.Delete(key)
 	}()
 
-	if _, ok := p.ImagesRescaleInProgress.Load(fpath); ok {
+	if _, ok := p.ImagesRescaleInProgress.Load(key); ok {
 		maxRetries := 20
 		retryInterval := time.Millisecond * 300
 		for i := 1; i <= maxRetries; i++ {
@@ -235,24 +257,26 @@ func (p *Processor) PresentTexture(ID *string, rsize string) (*types.MetaDef, *s
 			meta0, ok := p.ImageMapS[rsize].Get(*ID)
 			if ok {
 				p.log.Debug(*ID + " found in map on retry round N: " + strconv.Itoa(i))
-				return meta0.(*types.MetaDef), &fpath
+				return meta0.(*types.MetaDef)
 			}
 		}
 		p.log.Error(*ID + " timeout reached while waiting image to rescale:" + strconv.Itoa(int(retryInterval.Milliseconds())*maxRetries) + " ms")
-		return nil, nil
+		return nil
 	}
 
-	p.log.Debug(fpath)
-	reader, err := os.Open(fpath)
+	p.log.Debug(key)
+	reader, _, err := p.Images.Get(p.ctx, key)
 	if err != nil {
-		p.ImagesRescaleInProgress.Store(fpath, true)
+		p.ImagesRescaleInProgress.Store(key, true)
 		converted := false
 		p.log.Debug(*ID + " : converting from full")
-		if meta, filepath := p.Present(*ID); meta != nil {
-			if reader, err = os.Open(*filepath); !p.checkError(err) {
-				if img, _, errl := image.Decode(reader); !p.checkError(errl) {
+		if meta := p.Present(*ID); meta != nil {
+			if reader, _, err = p.Images.Get(p.ctx, ImageKey(*ID)); !p.checkError(err) {
+				img, _, errl := image.Decode(reader)
+				reader.Close()
+				if !p.checkError(errl) {
 					if err = p.WriteToScaled(*ID, img, rsize); !p.checkError(err) {
-						if reader, err = os.Open(fpath); err == nil {
+						if reader, _, err = p.Images.Get(p.ctx, key); err == nil {
 							converted = true
 						}
 					}
@@ -261,7 +285,7 @@ func (p *Processor) PresentTexture(ID *string, rsize string) (*types.MetaDef, *s
 		}
 
 		if !converted {
-			return nil, nil
+			return nil
 		}
 	}
 
@@ -279,5 +303,5 @@ func (p *Processor) PresentTexture(ID *string, rsize string) (*types.MetaDef, *s
 	}
 	p.log.Debug("Mime:", meta.Mime)
 	p.ImageMapS[rsize].Add(*ID, meta)
-	return meta, &fpath
+	return meta
 }
@@ -3,9 +3,8 @@ package processor
 import (
 	"image"
 	"image/color"
-	"image/png"
+	"image/draw"
 	"math"
-	"os"
 	"path/filepath"
 	"strings"
 	"sync"
@@ -15,7 +14,9 @@ import (
 	"github.com/oakmound/oak/v3/alg/floatgeom"
 	"github.com/oakmound/oak/v3/render"
 	"github.com/oakmound/oak/v3/render/mod"
+	"github.com/pkg/errors"
 
+	"github.com/momentum-xyz/ubercontroller/pkg/media/storage"
 	"github.com/momentum-xyz/ubercontroller/types"
 )
 
@@ -58,23 +59,11 @@ func (p *Processor) RenderFrame(req *types.FrameRenderRequest) {
 
 	p.renderSubFrame(req.Frame, req.Frame.X, req.Frame.Y, img)
 
-	fname := p.ImPathF + *req.ID
-	tfilename := fname + ".tmp"
-
-	f, err := os.Create(fname)
-	if !p.HandleError(err) {
-		defer f.Close()
-		res := img.ToSprite().Modify(mod.CropToSize(req.Frame.Width, req.Frame.Height, gift.TopLeftAnchor))
-		p.HandleError(png.Encode(f, res.GetRGBA()))
-	}
-
-	os.Rename(tfilename, fname)
-
-	if reader, err := os.Open(fname); !p.HandleError(err) {
-		if img, _, err := image.Decode(reader); !p.HandleError(err) {
-			for _, v := range types.Tprecalcs {
-				p.WriteToScaled(*req.ID, img, v)
-			}
+	res := img.ToSprite().Modify(mod.CropToSize(req.Frame.Width, req.Frame.Height, gift.TopLeftAnchor))
+	rgba := res.GetRGBA()
+	if err := p.SaveWriteToPNG(ImageKey(*req.ID), rgba); !p.HandleError(err) {
+		for _, v := range types.Tprecalcs {
+			p.WriteToScaled(*req.ID, rgba, v)
 		}
 	}
 
@@ -83,6 +72,24 @@ func (p *Processor) RenderFrame(req *types.FrameRenderRequest) {
 	req.Wg.Done()
 }
 
+// loadSprite loads the image of the key from the image storage.
+func (p *Processor) loadSprite(key string) (*render.Sprite, error) {
+	reader, _, err := p.Images.Get(p.ctx, key)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+
+	src, _, err := image.Decode(reader)
+	if err != nil {
+		return nil, err
+	}
+	rgba := image.NewRGBA(src.Bounds())
+	draw.Draw(rgba, rgba.Bounds(), src, src.Bounds().Min, draw.Src)
+
+	return render.NewSprite(0, 0, rgba), nil
+}
+
 func (p *Processor) renderSubFrame(frame *types.FrameDesc, xul int, yul int, img *render.CompositeM) {
 	if frame == nil {
 		return
@@ -93,15 +100,13 @@ func (p *Processor) renderSubFrame(frame *types.FrameDesc, xul int, yul int, img
 		floatgeom.Point2{float64(xul), float64(yul)},
 	)
 	if frame.BGimage != "" {
-		bgimpath := p.ImPathF + frame.BGimage
-		p.log.Debug("bgimage path:", bgimpath)
-		if p.FileExists(bgimpath) {
-			p.log.Debug("bgimage exists")
-			img1, err := render.LoadSprite(bgimpath)
-			p.HandleError(err)
+		img1, err := p.loadSprite(ImageKey(frame.BGimage))
+		if err == nil {
 			p.log.Debug("sprite loaded")
 			img1.Modify(mod.Resize(frame.Width, frame.Height, gift.NearestNeighborResampling))
 			img.AppendOffset(img1, floatgeom.Point2{float64(xul), float64(yul)})
+		} else if !errors.Is(err, storage.ErrNotFound) {
+			p.HandleError(err)
 		}
 	}
 	p.log.Debug("subframe5:", "xul:", xul, "yul:", yul)
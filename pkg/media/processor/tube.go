@@ -86,7 +86,7 @@ func (p *Processor) ProcessTube(src []byte) (string, error) {
 	}
 
 	hash := p.GetMD5HashByte(url)
-	meta, _ := p.Present(hash)
+	meta := p.Present(hash)
 	if meta == nil {
 		resp, err := p.getThumbnail(string(url))
 		if err != nil {
@@ -103,7 +103,7 @@ func (p *Processor) ProcessTube(src []byte) (string, error) {
 			draw.Draw(imout, thumb.Bounds(), thumb, image.ZP, draw.Src)
 			offset := image.Pt((nx-imgovl.Bounds().Max.X)/2, 0)
 			draw.Draw(imout, imgovl.Bounds().Add(offset), imgovl, image.ZP, draw.Over)
-			err := p.SaveWriteToPNG(p.ImPathF+hash, imout)
+			err := p.SaveWriteToPNG(ImageKey(hash), imout)
 			if err != nil {
 				return "", err
 			}
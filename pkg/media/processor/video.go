@@ -5,7 +5,6 @@ import (
 	"encoding/hex"
 	"io"
 	"os"
-	"path/filepath"
 
 	"github.com/h2non/filetype"
 	"github.com/h2non/filetype/matchers"
@@ -40,15 +39,14 @@ func (p *Processor) ProcessVideo(body io.ReadCloser) (string, error) {
 		return "", errors.New("Video type not supported")
 	}
 
-	file, err := os.CreateTemp(p.Videopath, "tmp")
+	file, err := os.CreateTemp("", "tmp")
 	if err != nil {
 		return "", errors.Wrap(err, "Error creating temp file")
 	}
 
 	defer func() {
-		if err != nil {
-			os.Remove(file.Name())
-		}
+		file.Close()
+		os.Remove(file.Name())
 	}()
 
 	mw := io.MultiWriter(file, hasher)
@@ -61,13 +59,9 @@ func (p *Processor) ProcessVideo(body io.ReadCloser) (string, error) {
 		return "", errors.Wrap(err, "Error copying to temp file")
 	}
 
-	if err = file.Close(); err != nil {
-		return "", errors.Wrap(err, "Error closing the file")
-	}
-
 	hash := hex.EncodeToString(hasher.Sum(nil))
-	if err = os.Rename(file.Name(), filepath.Join(p.Videopath, hash)); err != nil {
-		return "", errors.Wrap(err, "Error renaming the file")
+	if err = p.storeFile(p.Videos, hash, file); err != nil {
+		return "", errors.Wrap(err, "Error storing the file")
 	}
 
 	return hash, nil
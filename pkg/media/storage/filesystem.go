@@ -0,0 +1,105 @@
+package storage
+
+import (
+	"context"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+
+	"github.com/pkg/errors"
+)
+
+var _ Storage = (*FileStorage)(nil)
+var _ Local = (*FileStorage)(nil)
+
+// FileStorage keeps the files in a directory, keys are paths relative to it.
+type FileStorage struct {
+	dir string
+}
+
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return nil, errors.WithMessage(err, "failed to create directory")
+	}
+	return &FileStorage{dir: dir}, nil
+}
+
+func (s *FileStorage) Path(key string) string {
+	// cleaning the rooted key keeps the path inside of the directory
+	return filepath.Join(s.dir, filepath.FromSlash(path.Clean("/"+key)))
+}
+
+func (s *FileStorage) Get(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	file, err := os.Open(s.Path(key))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil, ErrNotFound
+		}
+		return nil, nil, errors.WithMessage(err, "failed to open file")
+	}
+
+	info, err := s.getInfo(file.Stat())
+	if err != nil {
+		file.Close()
+		return nil, nil, err
+	}
+
+	return file, info, nil
+}
+
+func (s *FileStorage) Stat(ctx context.Context, key string) (*Info, error) {
+	return s.getInfo(os.Stat(s.Path(key)))
+}
+
+func (s *FileStorage) getInfo(fileInfo os.FileInfo, err error) (*Info, error) {
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, ErrNotFound
+		}
+		return nil, errors.WithMessage(err, "failed to stat file")
+	}
+	if fileInfo.IsDir() {
+		return nil, ErrNotFound
+	}
+	return &Info{Size: fileInfo.Size(), ModTime: fileInfo.ModTime()}, nil
+}
+
+// Put writes the body to a temporary file first, so a partly written file is never served.
+func (s *FileStorage) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	filePath := s.Path(key)
+	dir := filepath.Dir(filePath)
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return errors.WithMessage(err, "failed to create directory")
+	}
+
+	tmp, err := os.CreateTemp(dir, ".tmp-")
+	if err != nil {
+		return errors.WithMessage(err, "failed to create file")
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := io.Copy(tmp, body); err != nil {
+		tmp.Close()
+		return errors.WithMessage(err, "failed to write file")
+	}
+	if err := tmp.Close(); err != nil {
+		return errors.WithMessage(err, "failed to close file")
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return errors.WithMessage(err, "failed to change file mode")
+	}
+
+	if err := os.Rename(tmp.Name(), filePath); err != nil {
+		return errors.WithMessage(err, "failed to rename file")
+	}
+
+	return nil
+}
+
+func (s *FileStorage) Delete(ctx context.Context, key string) error {
+	if err := os.Remove(s.Path(key)); err != nil && !os.IsNotExist(err) {
+		return errors.WithMessage(err, "failed to remove file")
+	}
+	return nil
+}
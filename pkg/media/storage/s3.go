@@ -0,0 +1,333 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/config"
+)
+
+var _ Storage = (*S3Storage)(nil)
+var _ Signer = (*S3Storage)(nil)
+
+const (
+	s3Algorithm      = "AWS4-HMAC-SHA256"
+	s3Service        = "s3"
+	s3DateFormat     = "20060102"
+	s3DateTimeFormat = "20060102T150405Z"
+	// sha256 of the empty payload
+	s3EmptyPayloadHash = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+	s3UnsignedPayload  = "UNSIGNED-PAYLOAD"
+	s3MaxSignedURLTTL  = 7 * 24 * time.Hour
+)
+
+// S3Storage keeps the files in a bucket of S3-compatible object storage (AWS S3, MinIO etc.),
+// requests are signed with AWS signature version 4.
+type S3Storage struct {
+	cfg      config.MediaS3
+	endpoint *url.URL
+	// prefix of all keys of the storage
+	prefix string
+	client *http.Client
+	now    func() time.Time
+}
+
+func NewS3Storage(cfg config.MediaS3, prefix string) (*S3Storage, error) {
+	if cfg.Bucket == "" {
+		return nil, errors.New("bucket is not set")
+	}
+	endpoint, err := url.Parse(cfg.Endpoint)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to parse endpoint")
+	}
+	if endpoint.Scheme == "" || endpoint.Host == "" {
+		return nil, errors.Errorf("invalid endpoint: %s", cfg.Endpoint)
+	}
+	if cfg.Region == "" {
+		cfg.Region = "us-east-1"
+	}
+
+	return &S3Storage{
+		cfg:      cfg,
+		endpoint: endpoint,
+		prefix:   cfg.Prefix + prefix,
+		client:   &http.Client{},
+		now:      time.Now,
+	}, nil
+}
+
+func (s *S3Storage) Get(ctx context.Context, key string) (io.ReadCloser, *Info, error) {
+	resp, err := s.do(ctx, http.MethodGet, key, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp.Body, getS3Info(resp), nil
+}
+
+func (s *S3Storage) Stat(ctx context.Context, key string) (*Info, error) {
+	resp, err := s.do(ctx, http.MethodHead, key, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp.Body.Close()
+	return getS3Info(resp), nil
+}
+
+func (s *S3Storage) Put(ctx context.Context, key string, body io.ReadSeeker) error {
+	resp, err := s.do(ctx, http.MethodPut, key, body)
+	if err != nil {
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+func (s *S3Storage) Delete(ctx context.Context, key string) error {
+	resp, err := s.do(ctx, http.MethodDelete, key, nil)
+	if err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	resp.Body.Close()
+	return nil
+}
+
+// SignedURL returns a presigned GET url of the key,
+// or the url of the key under the public url if there is one configured.
+func (s *S3Storage) SignedURL(key string, expires time.Duration) (string, error) {
+	if s.cfg.PublicURL != "" {
+		return strings.TrimSuffix(s.cfg.PublicURL, "/") + "/" + s3Escape(s.getObjectKey(key), false), nil
+	}
+	if expires <= 0 || expires > s3MaxSignedURLTTL {
+		return "", errors.Errorf("invalid signed url expiration: %s", expires)
+	}
+
+	host, escapedPath := s.getObjectLocation(key)
+	now := s.now().UTC()
+	scope := s.getScope(now)
+
+	query := url.Values{
+		"X-Amz-Algorithm":     {s3Algorithm},
+		"X-Amz-Credential":    {s.cfg.AccessKey + "/" + scope},
+		"X-Amz-Date":          {now.Format(s3DateTimeFormat)},
+		"X-Amz-Expires":       {strconv.Itoa(int(expires.Seconds()))},
+		"X-Amz-SignedHeaders": {"host"},
+	}
+	canonicalQuery := getS3CanonicalQuery(query)
+	canonicalRequest := strings.Join(
+		[]string{http.MethodGet, escapedPath, canonicalQuery, "host:" + host + "\n", "host", s3UnsignedPayload}, "\n",
+	)
+	signature := s.getSignature(now, scope, canonicalRequest)
+
+	return fmt.Sprintf(
+		"%s://%s%s?%s&X-Amz-Signature=%s", s.endpoint.Scheme, host, escapedPath, canonicalQuery, signature,
+	), nil
+}
+
+// do sends the signed request of the key, ErrNotFound is returned for the 404 responses.
+func (s *S3Storage) do(ctx context.Context, method string, key string, body io.ReadSeeker) (*http.Response, error) {
+	payloadHash := s3EmptyPayloadHash
+	var size int64
+	var contentType string
+	if body != nil {
+		var err error
+		if contentType, err = detectContentType(body); err != nil {
+			return nil, errors.WithMessage(err, "failed to detect content type")
+		}
+		hasher := sha256.New()
+		if size, err = io.Copy(hasher, body); err != nil {
+			return nil, errors.WithMessage(err, "failed to hash body")
+		}
+		if _, err := body.Seek(0, io.SeekStart); err != nil {
+			return nil, errors.WithMessage(err, "failed to seek body")
+		}
+		payloadHash = hex.EncodeToString(hasher.Sum(nil))
+	}
+
+	host, escapedPath := s.getObjectLocation(key)
+	req, err := http.NewRequestWithContext(ctx, method, s.endpoint.Scheme+"://"+host+escapedPath, nil)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create request")
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", contentType)
+		req.ContentLength = size
+		req.Body = http.NoBody
+		if size > 0 {
+			req.Body = io.NopCloser(body)
+		}
+	}
+	s.sign(req, payloadHash)
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to send request: %s %s", method, key)
+	}
+	if resp.StatusCode == http.StatusNotFound {
+		resp.Body.Close()
+		return nil, ErrNotFound
+	}
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		defer resp.Body.Close()
+		message, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return nil, errors.Errorf(
+			"unexpected response: %s %s: %s: %s", method, key, resp.Status, strings.TrimSpace(string(message)),
+		)
+	}
+
+	return resp, nil
+}
+
+// sign adds the authorization header to the request.
+func (s *S3Storage) sign(req *http.Request, payloadHash string) {
+	now := s.now().UTC()
+	req.Header.Set("X-Amz-Date", now.Format(s3DateTimeFormat))
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		name = strings.ToLower(name)
+		if name == "content-type" || name == "range" || strings.HasPrefix(name, "x-amz-") {
+			headers[name] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join(
+		[]string{
+			req.Method, req.URL.EscapedPath(), getS3CanonicalQuery(req.URL.Query()),
+			canonicalHeaders.String(), signedHeaders, payloadHash,
+		}, "\n",
+	)
+	scope := s.getScope(now)
+	signature := s.getSignature(now, scope, canonicalRequest)
+
+	req.Header.Set(
+		"Authorization",
+		fmt.Sprintf(
+			"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+			s3Algorithm, s.cfg.AccessKey, scope, signedHeaders, signature,
+		),
+	)
+}
+
+func (s *S3Storage) getScope(now time.Time) string {
+	return strings.Join([]string{now.Format(s3DateFormat), s.cfg.Region, s3Service, "aws4_request"}, "/")
+}
+
+func (s *S3Storage) getSignature(now time.Time, scope string, canonicalRequest string) string {
+	requestHash := sha256.Sum256([]byte(canonicalRequest))
+	stringToSign := strings.Join(
+		[]string{s3Algorithm, now.Format(s3DateTimeFormat), scope, hex.EncodeToString(requestHash[:])}, "\n",
+	)
+
+	key := hmacSHA256([]byte("AWS4"+s.cfg.SecretKey), now.Format(s3DateFormat))
+	key = hmacSHA256(key, s.cfg.Region)
+	key = hmacSHA256(key, s3Service)
+	key = hmacSHA256(key, "aws4_request")
+
+	return hex.EncodeToString(hmacSHA256(key, stringToSign))
+}
+
+// getObjectKey returns the key of the object in the bucket.
+func (s *S3Storage) getObjectKey(key string) string {
+	return s.prefix + strings.TrimPrefix(path.Clean("/"+key), "/")
+}
+
+// getObjectLocation returns the host and the escaped path of the object.
+func (s *S3Storage) getObjectLocation(key string) (string, string) {
+	basePath := strings.TrimSuffix(s.endpoint.Path, "/")
+	objectPath := s3Escape(s.getObjectKey(key), false)
+	if s.cfg.PathStyle {
+		return s.endpoint.Host, basePath + "/" + s3Escape(s.cfg.Bucket, true) + "/" + objectPath
+	}
+	return s.cfg.Bucket + "." + s.endpoint.Host, basePath + "/" + objectPath
+}
+
+func getS3Info(resp *http.Response) *Info {
+	info := &Info{Size: resp.ContentLength}
+	if modTime, err := http.ParseTime(resp.Header.Get("Last-Modified")); err == nil {
+		info.ModTime = modTime
+	}
+	return info
+}
+
+func getS3CanonicalQuery(query url.Values) string {
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	params := make([]string, 0, len(query))
+	for _, key := range keys {
+		values := append([]string(nil), query[key]...)
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+
+	return strings.Join(params, "&")
+}
+
+// s3Escape percent-encodes everything but the unreserved characters (and the slashes, unless escapeSlash).
+func s3Escape(value string, escapeSlash bool) string {
+	var escaped strings.Builder
+	for i := 0; i < len(value); i++ {
+		c := value[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9', c == '-', c == '_', c == '.', c == '~':
+			escaped.WriteByte(c)
+		case c == '/' && !escapeSlash:
+			escaped.WriteByte(c)
+		default:
+			fmt.Fprintf(&escaped, "%%%02X", c)
+		}
+	}
+	return escaped.String()
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	hash := hmac.New(sha256.New, key)
+	hash.Write([]byte(data))
+	return hash.Sum(nil)
+}
+
+// detectContentType returns the content type of the body by its first bytes, the body is rewound after.
+func detectContentType(body io.ReadSeeker) (string, error) {
+	buf := make([]byte, 512)
+	n, err := io.ReadFull(body, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	if _, err := body.Seek(0, io.SeekStart); err != nil {
+		return "", err
+	}
+	return http.DetectContentType(buf[:n]), nil
+}
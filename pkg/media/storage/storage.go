@@ -0,0 +1,72 @@
+// Package storage keeps the uploaded media files, on the local filesystem or in S3-compatible object storage.
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/config"
+)
+
+// Storage backends.
+const (
+	BackendFilesystem = "filesystem"
+	BackendS3         = "s3"
+)
+
+var ErrNotFound = errors.New("file not found")
+
+// Info describes a stored file.
+type Info struct {
+	Size    int64
+	ModTime time.Time
+}
+
+// Storage keeps files by key, keys are slash separated paths like "F/<hash>" or "<plugin hash>/manifest.json".
+type Storage interface {
+	// Get returns the content of the file, ErrNotFound if there is none.
+	Get(ctx context.Context, key string) (io.ReadCloser, *Info, error)
+	// Stat returns info of the file, ErrNotFound if there is none.
+	Stat(ctx context.Context, key string) (*Info, error)
+	// Put stores the body under the key, an existing file is replaced.
+	Put(ctx context.Context, key string, body io.ReadSeeker) error
+	// Delete removes the file, removing a missing file is not an error.
+	Delete(ctx context.Context, key string) error
+}
+
+// Signer is implemented by storages which can give clients temporary urls to download files directly.
+type Signer interface {
+	SignedURL(key string, expires time.Duration) (string, error)
+}
+
+// Local is implemented by storages keeping the files on the local filesystem.
+type Local interface {
+	// Path returns the filesystem path of the key.
+	Path(key string) string
+}
+
+// Exists reports whether the file is in the storage.
+func Exists(ctx context.Context, storage Storage, key string) (bool, error) {
+	if _, err := storage.Stat(ctx, key); err != nil {
+		if errors.Is(err, ErrNotFound) {
+			return false, nil
+		}
+		return false, err
+	}
+	return true, nil
+}
+
+// New returns the storage of the configured backend for one kind of files,
+// they are kept in the dir on the filesystem or under the prefix in the bucket.
+func New(cfg *config.Media, dir string, prefix string) (Storage, error) {
+	switch cfg.Storage {
+	case BackendFilesystem, "":
+		return NewFileStorage(dir)
+	case BackendS3:
+		return NewS3Storage(cfg.S3, prefix)
+	}
+	return nil, errors.Errorf("unknown storage backend: %s", cfg.Storage)
+}
@@ -0,0 +1,101 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/generic"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// AuthRequest holds the secrets of a pending authorization, bound to the state parameter.
+type AuthRequest struct {
+	Provider     string
+	State        string
+	Nonce        string
+	CodeVerifier string
+	// User to link the identity to, umid.Nil for login.
+	UserID umid.UMID
+}
+
+// NewAuthRequest generates random state, nonce and PKCE code verifier.
+func NewAuthRequest(provider string, userID umid.UMID) (*AuthRequest, error) {
+	state, err := randomString()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate state")
+	}
+	nonce, err := randomString()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate nonce")
+	}
+	verifier, err := randomString()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate code verifier")
+	}
+
+	return &AuthRequest{
+		Provider:     provider,
+		State:        state,
+		Nonce:        nonce,
+		CodeVerifier: verifier,
+		UserID:       userID,
+	}, nil
+}
+
+// CodeChallenge returns S256 PKCE challenge of the code verifier.
+func (r *AuthRequest) CodeChallenge() string {
+	return CodeChallenge(r.CodeVerifier)
+}
+
+func CodeChallenge(verifier string) string {
+	hash := sha256.Sum256([]byte(verifier))
+	return base64.RawURLEncoding.EncodeToString(hash[:])
+}
+
+// AuthRequestStore keeps pending authorizations until they are used or expire.
+type AuthRequestStore struct {
+	requests *generic.SyncMap[string, *AuthRequest]
+	timers   *generic.TimerSet[string]
+}
+
+func NewAuthRequestStore() *AuthRequestStore {
+	return &AuthRequestStore{
+		requests: generic.NewSyncMap[string, *AuthRequest](0),
+		timers:   generic.NewTimerSet[string](),
+	}
+}
+
+func (s *AuthRequestStore) Put(ctx context.Context, request *AuthRequest, ttl time.Duration) {
+	s.requests.Store(request.State, request)
+	s.timers.Set(ctx, request.State, ttl, func(state string) error {
+		s.requests.Remove(state)
+		return nil
+	})
+}
+
+// Pop returns and removes the request, so the state can be used only once.
+func (s *AuthRequestStore) Pop(state string) (*AuthRequest, bool) {
+	s.requests.Mu.Lock()
+	request, ok := s.requests.Data[state]
+	delete(s.requests.Data, state)
+	s.requests.Mu.Unlock()
+
+	if ok {
+		s.timers.Stop(state)
+	}
+
+	return request, ok
+}
+
+func randomString() (string, error) {
+	data := make([]byte, 32)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
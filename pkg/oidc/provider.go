@@ -0,0 +1,397 @@
+// Package oidc implements OpenID Connect relying party, authorization code flow with PKCE.
+package oidc
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/config"
+)
+
+const (
+	discoveryPath = "/.well-known/openid-configuration"
+	scopeOpenID   = "openid"
+	httpTimeout   = 10 * time.Second
+)
+
+var ErrInvalidToken = errors.New("invalid id token")
+
+// Claims of the ID token used to identify the user.
+type Claims struct {
+	Subject           string `json:"sub"`
+	Email             string `json:"email"`
+	EmailVerified     bool   `json:"email_verified"`
+	Name              string `json:"name"`
+	PreferredUsername string `json:"preferred_username"`
+	Picture           string `json:"picture"`
+}
+
+type discovery struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type jsonWebKey struct {
+	Kid string `json:"kid"`
+	Kty string `json:"kty"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+type tokenResponse struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	IDToken          string `json:"id_token"`
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// Provider is a configured identity provider, its configuration and keys are discovered on first use.
+type Provider struct {
+	name   string
+	cfg    config.OIDCProvider
+	client *http.Client
+
+	mu        sync.Mutex
+	discovery *discovery
+	keys      map[string]any
+}
+
+func NewProvider(name string, cfg config.OIDCProvider) *Provider {
+	return &Provider{
+		name:   name,
+		cfg:    cfg,
+		client: &http.Client{Timeout: httpTimeout},
+	}
+}
+
+func (p *Provider) GetName() string {
+	return p.name
+}
+
+func (p *Provider) GetDisplayName() string {
+	if p.cfg.DisplayName == "" {
+		return p.name
+	}
+	return p.cfg.DisplayName
+}
+
+// AuthCodeURL returns the provider url to redirect the user to.
+func (p *Provider) AuthCodeURL(ctx context.Context, state, nonce, codeChallenge string) (string, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	authURL, err := url.Parse(d.AuthorizationEndpoint)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to parse authorization endpoint")
+	}
+
+	query := authURL.Query()
+	query.Set("response_type", "code")
+	query.Set("client_id", p.cfg.ClientID)
+	query.Set("redirect_uri", p.cfg.RedirectURL)
+	query.Set("scope", strings.Join(p.scopes(), " "))
+	query.Set("state", state)
+	query.Set("nonce", nonce)
+	query.Set("code_challenge", codeChallenge)
+	query.Set("code_challenge_method", "S256")
+	authURL.RawQuery = query.Encode()
+
+	return authURL.String(), nil
+}
+
+// Exchange redeems the authorization code and returns verified claims of the ID token.
+func (p *Provider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", p.cfg.RedirectURL)
+	form.Set("client_id", p.cfg.ClientID)
+	form.Set("code_verifier", codeVerifier)
+	if p.cfg.ClientSecret != "" {
+		form.Set("client_secret", p.cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.TokenEndpoint, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create token request")
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+
+	var token tokenResponse
+	status, err := p.do(req, &token)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to request token")
+	}
+	if status != http.StatusOK || token.Error != "" {
+		return nil, errors.Errorf("token request failed: %d: %s: %s", status, token.Error, token.ErrorDescription)
+	}
+	if token.IDToken == "" {
+		return nil, errors.Wrap(ErrInvalidToken, "id token is missing")
+	}
+
+	return p.VerifyIDToken(ctx, token.IDToken, nonce)
+}
+
+// VerifyIDToken checks signature, issuer, audience, expiration and nonce of the ID token.
+func (p *Provider) VerifyIDToken(ctx context.Context, idToken, nonce string) (*Claims, error) {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	mapClaims := jwt.MapClaims{}
+	if _, err := jwt.ParseWithClaims(
+		idToken, mapClaims, func(token *jwt.Token) (any, error) {
+			switch token.Method.(type) {
+			case *jwt.SigningMethodRSA, *jwt.SigningMethodECDSA:
+			default:
+				return nil, errors.Errorf("unexpected signing method: %s", token.Header["alg"])
+			}
+			kid, _ := token.Header["kid"].(string)
+			return p.getKey(ctx, kid)
+		},
+	); err != nil {
+		return nil, errors.Wrap(ErrInvalidToken, err.Error())
+	}
+
+	if !mapClaims.VerifyIssuer(d.Issuer, true) {
+		return nil, errors.Wrapf(ErrInvalidToken, "unexpected issuer: %v", mapClaims["iss"])
+	}
+	if !mapClaims.VerifyAudience(p.cfg.ClientID, true) {
+		return nil, errors.Wrapf(ErrInvalidToken, "unexpected audience: %v", mapClaims["aud"])
+	}
+	if _, ok := mapClaims["exp"]; !ok {
+		return nil, errors.Wrap(ErrInvalidToken, "expiration is missing")
+	}
+	if tokenNonce, _ := mapClaims["nonce"].(string); tokenNonce != nonce {
+		return nil, errors.Wrap(ErrInvalidToken, "nonce mismatch")
+	}
+
+	data, err := json.Marshal(mapClaims)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal claims")
+	}
+	var claims Claims
+	if err := json.Unmarshal(data, &claims); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal claims")
+	}
+	if claims.Subject == "" {
+		return nil, errors.Wrap(ErrInvalidToken, "subject is missing")
+	}
+
+	return &claims, nil
+}
+
+func (p *Provider) scopes() []string {
+	scopes := []string{scopeOpenID}
+	for _, scope := range p.cfg.Scopes {
+		if scope != scopeOpenID {
+			scopes = append(scopes, scope)
+		}
+	}
+	return scopes
+}
+
+func (p *Provider) getDiscovery(ctx context.Context) (*discovery, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.discovery != nil {
+		return p.discovery, nil
+	}
+
+	req, err := http.NewRequestWithContext(
+		ctx, http.MethodGet, strings.TrimSuffix(p.cfg.Issuer, "/")+discoveryPath, nil,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create discovery request")
+	}
+
+	var d discovery
+	status, err := p.do(req, &d)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to discover provider configuration")
+	}
+	if status != http.StatusOK {
+		return nil, errors.Errorf("failed to discover provider configuration: %d", status)
+	}
+	if d.Issuer != p.cfg.Issuer {
+		return nil, errors.Errorf("issuer mismatch: %s != %s", d.Issuer, p.cfg.Issuer)
+	}
+	if d.AuthorizationEndpoint == "" || d.TokenEndpoint == "" || d.JWKSURI == "" {
+		return nil, errors.New("incomplete provider configuration")
+	}
+
+	p.discovery = &d
+
+	return p.discovery, nil
+}
+
+// getKey returns the signing key by id, keys are fetched again on unknown id to support key rotation.
+func (p *Provider) getKey(ctx context.Context, kid string) (any, error) {
+	p.mu.Lock()
+	key, ok := p.findKey(kid)
+	p.mu.Unlock()
+	if ok {
+		return key, nil
+	}
+
+	if err := p.fetchKeys(ctx); err != nil {
+		return nil, err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	key, ok = p.findKey(kid)
+	if !ok {
+		return nil, errors.Errorf("signing key not found: %s", kid)
+	}
+	return key, nil
+}
+
+func (p *Provider) findKey(kid string) (any, bool) {
+	if kid == "" && len(p.keys) == 1 {
+		for _, key := range p.keys {
+			return key, true
+		}
+	}
+	key, ok := p.keys[kid]
+	return key, ok
+}
+
+func (p *Provider) fetchKeys(ctx context.Context) error {
+	d, err := p.getDiscovery(ctx)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, d.JWKSURI, nil)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create keys request")
+	}
+
+	var jwks struct {
+		Keys []jsonWebKey `json:"keys"`
+	}
+	status, err := p.do(req, &jwks)
+	if err != nil {
+		return errors.WithMessage(err, "failed to fetch keys")
+	}
+	if status != http.StatusOK {
+		return errors.Errorf("failed to fetch keys: %d", status)
+	}
+
+	keys := make(map[string]any, len(jwks.Keys))
+	for _, jwk := range jwks.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			// unsupported keys are skipped
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+
+	p.mu.Lock()
+	p.keys = keys
+	p.mu.Unlock()
+
+	return nil
+}
+
+func (p *Provider) do(req *http.Request, out any) (int, error) {
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return resp.StatusCode, errors.WithMessage(err, "failed to read response")
+	}
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, out); err != nil && resp.StatusCode == http.StatusOK {
+			return resp.StatusCode, errors.WithMessage(err, "failed to unmarshal response")
+		}
+	}
+
+	return resp.StatusCode, nil
+}
+
+func (k jsonWebKey) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := decodeBigInt(k.N)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode modulus")
+		}
+		e, err := decodeBigInt(k.E)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode exponent")
+		}
+		return &rsa.PublicKey{N: n, E: int(e.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, errors.Errorf("unsupported curve: %s", k.Crv)
+		}
+		x, err := decodeBigInt(k.X)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode x")
+		}
+		y, err := decodeBigInt(k.Y)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to decode y")
+		}
+		return &ecdsa.PublicKey{Curve: curve, X: x, Y: y}, nil
+	}
+	return nil, errors.Errorf("unsupported key type: %s", k.Kty)
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(data), nil
+}
@@ -0,0 +1,246 @@
+package oidc
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/momentum-xyz/ubercontroller/config"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	mockClientID    = "ubercontroller"
+	mockRedirectURL = "https://odyssey.example/oidc/callback"
+	mockKeyID       = "mock-key"
+)
+
+// mockIdP is a minimal OpenID provider issuing codes for the "authorize" requests without user interaction.
+type mockIdP struct {
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	// claims of the issued ID tokens
+	claims jwt.MapClaims
+
+	mu    sync.Mutex
+	codes map[string]url.Values
+}
+
+func newMockIdP(t *testing.T) *mockIdP {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+
+	idp := &mockIdP{
+		key:   key,
+		codes: make(map[string]url.Values),
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(discoveryPath, func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(discovery{
+			Issuer:                idp.server.URL,
+			AuthorizationEndpoint: idp.server.URL + "/authorize",
+			TokenEndpoint:         idp.server.URL + "/token",
+			JWKSURI:               idp.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"keys": []jsonWebKey{
+				{
+					Kid: mockKeyID,
+					Kty: "RSA",
+					Use: "sig",
+					N:   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+					E:   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+				},
+			},
+		})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		idp.mu.Lock()
+		authQuery, ok := idp.codes[r.PostForm.Get("code")]
+		delete(idp.codes, r.PostForm.Get("code"))
+		idp.mu.Unlock()
+
+		if !ok ||
+			r.PostForm.Get("client_id") != mockClientID ||
+			r.PostForm.Get("redirect_uri") != mockRedirectURL ||
+			CodeChallenge(r.PostForm.Get("code_verifier")) != authQuery.Get("code_challenge") {
+			w.WriteHeader(http.StatusBadRequest)
+			_ = json.NewEncoder(w).Encode(tokenResponse{Error: "invalid_grant"})
+			return
+		}
+
+		claims := jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   []string{mockClientID},
+			"iat":   time.Now().Unix(),
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": authQuery.Get("nonce"),
+		}
+		for k, v := range idp.claims {
+			claims[k] = v
+		}
+
+		_ = json.NewEncoder(w).Encode(tokenResponse{
+			AccessToken: "access",
+			TokenType:   "Bearer",
+			IDToken:     idp.sign(t, claims),
+		})
+	})
+	idp.server = httptest.NewServer(mux)
+	t.Cleanup(idp.server.Close)
+
+	return idp
+}
+
+// authorize simulates the user approving the authorization request, returns the code and state.
+func (idp *mockIdP) authorize(t *testing.T, authURL string) (string, string) {
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	assert.Equal(t, idp.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+
+	query := u.Query()
+	assert.Equal(t, "code", query.Get("response_type"))
+	assert.Equal(t, "S256", query.Get("code_challenge_method"))
+	assert.Equal(t, "openid email", query.Get("scope"))
+
+	code := umid.New().String()
+	idp.mu.Lock()
+	idp.codes[code] = query
+	idp.mu.Unlock()
+
+	return code, query.Get("state")
+}
+
+func (idp *mockIdP) sign(t *testing.T, claims jwt.MapClaims) string {
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = mockKeyID
+	signed, err := token.SignedString(idp.key)
+	require.NoError(t, err)
+	return signed
+}
+
+func (idp *mockIdP) provider() *Provider {
+	return NewProvider("mock", config.OIDCProvider{
+		Issuer:      idp.server.URL,
+		ClientID:    mockClientID,
+		RedirectURL: mockRedirectURL,
+		Scopes:      []string{"openid", "email"},
+	})
+}
+
+func TestProviderAuthorizationCodeFlow(t *testing.T) {
+	t.Parallel()
+
+	idp := newMockIdP(t)
+	idp.claims = jwt.MapClaims{
+		"sub":            "user-1",
+		"email":          "user@example.com",
+		"email_verified": true,
+		"name":           "User One",
+	}
+	provider := idp.provider()
+	ctx := context.Background()
+
+	store := NewAuthRequestStore()
+	request, err := NewAuthRequest(provider.GetName(), umid.Nil)
+	require.NoError(t, err)
+	store.Put(ctx, request, time.Minute)
+
+	authURL, err := provider.AuthCodeURL(ctx, request.State, request.Nonce, request.CodeChallenge())
+	require.NoError(t, err)
+	code, state := idp.authorize(t, authURL)
+
+	stored, ok := store.Pop(state)
+	require.True(t, ok)
+	_, ok = store.Pop(state)
+	assert.False(t, ok, "state can be used only once")
+
+	// wrong verifier is rejected by the provider, code is consumed
+	_, err = provider.Exchange(ctx, code, "wrong", stored.Nonce)
+	assert.Error(t, err)
+
+	code, _ = idp.authorize(t, authURL)
+	claims, err := provider.Exchange(ctx, code, stored.CodeVerifier, stored.Nonce)
+	require.NoError(t, err)
+	assert.Equal(
+		t,
+		&Claims{Subject: "user-1", Email: "user@example.com", EmailVerified: true, Name: "User One"},
+		claims,
+	)
+
+	code, _ = idp.authorize(t, authURL)
+	_, err = provider.Exchange(ctx, code, stored.CodeVerifier, "other")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+}
+
+func TestProviderVerifyIDToken(t *testing.T) {
+	t.Parallel()
+
+	idp := newMockIdP(t)
+	provider := idp.provider()
+	ctx := context.Background()
+
+	valid := func() jwt.MapClaims {
+		return jwt.MapClaims{
+			"iss":   idp.server.URL,
+			"aud":   mockClientID,
+			"sub":   "user-1",
+			"exp":   time.Now().Add(time.Minute).Unix(),
+			"nonce": "nonce",
+		}
+	}
+
+	_, err := provider.VerifyIDToken(ctx, idp.sign(t, valid()), "nonce")
+	assert.NoError(t, err)
+
+	expired := valid()
+	expired["exp"] = time.Now().Add(-time.Minute).Unix()
+	_, err = provider.VerifyIDToken(ctx, idp.sign(t, expired), "nonce")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+
+	audience := valid()
+	audience["aud"] = "other"
+	_, err = provider.VerifyIDToken(ctx, idp.sign(t, audience), "nonce")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+
+	issuer := valid()
+	issuer["iss"] = "https://other.example"
+	_, err = provider.VerifyIDToken(ctx, idp.sign(t, issuer), "nonce")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, valid())
+	token.Header["kid"] = mockKeyID
+	forged, err := token.SignedString(otherKey)
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(ctx, forged, "nonce")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, valid()).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	require.NoError(t, err)
+	_, err = provider.VerifyIDToken(ctx, unsigned, "nonce")
+	assert.True(t, errors.Is(err, ErrInvalidToken))
+}
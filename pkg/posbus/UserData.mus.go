@@ -75,6 +75,17 @@ func (v UserData) MarshalMUS(buf []byte) int {
 		}
 		i++
 	}
+	if v.AvatarDefinition == nil {
+		buf[i] = 0
+		i++
+	} else {
+		buf[i] = 1
+		i++
+		{
+			si := (*v.AvatarDefinition).MarshalMUS(buf[i:])
+			i += si
+		}
+	}
 	return i
 }
 
@@ -215,6 +226,28 @@ func (v *UserData) UnmarshalMUS(buf []byte) (int, error) {
 	if err != nil {
 		return i, muserrs.NewFieldError("IsGuest", err)
 	}
+	v.AvatarDefinition = new(AvatarDefinition)
+	if buf[i] == 0 {
+		i++
+		v.AvatarDefinition = nil
+	} else if buf[i] != 1 {
+		i++
+		return i, muserrs.ErrWrongByte
+	} else {
+		i++
+		{
+			var sv AvatarDefinition
+			si := 0
+			si, err = sv.UnmarshalMUS(buf[i:])
+			if err == nil {
+				(*v.AvatarDefinition) = sv
+				i += si
+			}
+		}
+	}
+	if err != nil {
+		return i, muserrs.NewFieldError("AvatarDefinition", err)
+	}
 	return i, err
 }
 
@@ -261,5 +294,12 @@ func (v UserData) SizeMUS() int {
 		_ = v.IsGuest
 		size++
 	}
+	size++
+	if v.AvatarDefinition != nil {
+		{
+			ss := (*v.AvatarDefinition).SizeMUS()
+			size += ss
+		}
+	}
 	return size
 }
@@ -0,0 +1,45 @@
+package posbus
+
+import "github.com/momentum-xyz/ubercontroller/utils/umid"
+
+// Subscribe to changes of a single attribute.
+// The current value is sent back as AttributeValueChanged, followed by all later changes.
+type SubscribeAttribute struct {
+	// The plugin that owns the attribute
+	PluginID umid.UMID `json:"plugin_id"`
+	// Name of attribute (scoped to plugin)
+	AttributeName string `json:"attribute_name"`
+	// Kind of the attribute target: "object", "user" or "object_user"
+	TargetType string `json:"target_type"`
+	// ID of the related object/user
+	TargetID umid.UMID `json:"target_id"`
+	// ID of the user, for "object_user" targets
+	UserID umid.UMID `json:"user_id"`
+}
+
+// Stop receiving changes of an attribute subscribed to with SubscribeAttribute.
+type UnsubscribeAttribute struct {
+	// The plugin that owns the attribute
+	PluginID umid.UMID `json:"plugin_id"`
+	// Name of attribute (scoped to plugin)
+	AttributeName string `json:"attribute_name"`
+	// Kind of the attribute target: "object", "user" or "object_user"
+	TargetType string `json:"target_type"`
+	// ID of the related object/user
+	TargetID umid.UMID `json:"target_id"`
+	// ID of the user, for "object_user" targets
+	UserID umid.UMID `json:"user_id"`
+}
+
+func init() {
+	registerMessage(SubscribeAttribute{})
+	registerMessage(UnsubscribeAttribute{})
+}
+
+func (s *SubscribeAttribute) GetType() MsgType {
+	return 0x5B2E7C14
+}
+
+func (u *UnsubscribeAttribute) GetType() MsgType {
+	return 0x5B2E7C15
+}
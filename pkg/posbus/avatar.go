@@ -0,0 +1,36 @@
+package posbus
+
+import "github.com/momentum-xyz/ubercontroller/utils/umid"
+
+// AvatarDefinition describes the appearance of a user avatar.
+type AvatarDefinition struct {
+	// The Asset3d used as base model
+	Model umid.UMID `json:"model"`
+	// Colors by part name, as hex strings (#RRGGBB or #RRGGBBAA)
+	Colors      map[string]string `json:"colors"`
+	Accessories []AvatarAccessory `json:"accessories"`
+	// Name of the animation set
+	AnimationSet string `json:"animation_set"`
+}
+
+// AvatarAccessory is an Asset3d attached to an avatar slot.
+type AvatarAccessory struct {
+	Slot  string    `json:"slot"`
+	Model umid.UMID `json:"model"`
+}
+
+// UserAvatarChanged is broadcast to the world when a user changes its avatar.
+type UserAvatarChanged struct {
+	UserID umid.UMID         `json:"user_id"`
+	Avatar *AvatarDefinition `json:"avatar"`
+}
+
+func init() {
+	registerMessage(UserAvatarChanged{})
+	addExtraType(AvatarDefinition{})
+	addExtraType(AvatarAccessory{})
+}
+
+func (a *UserAvatarChanged) GetType() MsgType {
+	return 0x8B0A3C51
+}
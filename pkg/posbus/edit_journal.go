@@ -0,0 +1,50 @@
+package posbus
+
+import "github.com/momentum-xyz/ubercontroller/utils/umid"
+
+type EditJournalActionType uint32
+
+const (
+	// EditJournalActionNone is not used. If received, the message was not initialized properly.
+	EditJournalActionNone EditJournalActionType = iota
+
+	// Starts a gesture, edits made until it's committed are undone and redone together.
+	EditJournalActionBegin
+
+	// Ends a gesture.
+	EditJournalActionCommit
+
+	// Reverts the last edit (or gesture) of the user in the current world.
+	EditJournalActionUndo
+
+	// Reapplies the last undone edit (or gesture) of the user in the current world.
+	EditJournalActionRedo
+)
+
+// EditJournalAction is send by the client to control the edit journal of the user in the current world.
+type EditJournalAction struct {
+	Action EditJournalActionType `json:"action"`
+}
+
+// EditJournalState is send to the user when the edit journal of the user changes.
+type EditJournalState struct {
+	WorldID   umid.UMID `json:"world_id"`
+	UndoCount uint32    `json:"undo_count"`
+	RedoCount uint32    `json:"redo_count"`
+	// Objects changed by other editors which prevented the last undo or redo.
+	Conflicts []umid.UMID `json:"conflicts"`
+}
+
+func init() {
+	registerMessage(EditJournalAction{})
+	registerMessage(EditJournalState{})
+	addExtraType(EditJournalActionType(0))
+}
+
+func (e *EditJournalAction) GetType() MsgType {
+	return 0x5E1A7C2D
+}
+
+func (e *EditJournalState) GetType() MsgType {
+	return 0xC03B9E64
+}
@@ -21,6 +21,9 @@ const (
 
 	NotificationGatheringStart NotificationType = 20
 
+	NotificationFriendOnline       NotificationType = 30
+	NotificationFriendEnteredWorld NotificationType = 31
+
 	NotificationTextMessage NotificationType = 500
 	NotificationRelay       NotificationType = 501
 
@@ -6,6 +6,11 @@ type LockObject struct {
 	ID umid.UMID `json:"id"`
 }
 
+// LockObjectSubtree locks the object with all its children, like LockObject it renews the lease of the user.
+type LockObjectSubtree struct {
+	ID umid.UMID `json:"id"`
+}
+
 type UnlockObject struct {
 	ID umid.UMID `json:"id"`
 }
@@ -18,6 +23,7 @@ type LockObjectResponse struct {
 
 func init() {
 	registerMessage(LockObject{})
+	registerMessage(LockObjectSubtree{})
 	registerMessage(UnlockObject{})
 	registerMessage(LockObjectResponse{})
 }
@@ -26,6 +32,10 @@ func (l *LockObject) GetType() MsgType {
 	return 0xA7DE9F59
 }
 
+func (l *LockObjectSubtree) GetType() MsgType {
+	return 0x3F0C8D1B
+}
+
 func (l *UnlockObject) GetType() MsgType {
 	return 0xA54EDEB9
 }
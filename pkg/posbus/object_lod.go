@@ -0,0 +1,16 @@
+package posbus
+
+// SetObjectsLOD is send by the client to switch objects of the level of detail group to the level,
+// level 0 is the full detail. The objects are spawned again with the asset of the level.
+type SetObjectsLOD struct {
+	Group string `json:"group"`
+	Level uint32 `json:"level"`
+}
+
+func init() {
+	registerMessage(SetObjectsLOD{})
+}
+
+func (s *SetObjectsLOD) GetType() MsgType {
+	return 0x6B2E4F91
+}
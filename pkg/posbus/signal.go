@@ -39,6 +39,15 @@ const (
 
 	// Send when user tries to teleport to a world that can't be found.
 	SignalWorldDoesNotExist
+
+	// Send when user is removed from the world by an admin.
+	SignalKicked
+
+	// Send when user is banned from the world (or the node) or tries to join a world while banned.
+	SignalBanned
+
+	// Send when user chat and high-fives are muted by an admin.
+	SignalMuted
 )
 
 // A Signal is a predefined (small) message to notify the other side of some state or event.
@@ -6,6 +6,8 @@ const (
 	TypeAddPendingStake       MsgType = 0xF020D682
 	TypeAddUsers              MsgType = 0xF51F2AFF
 	TypeAttributeValueChanged MsgType = 0x10DACDB7
+	TypeEditJournalAction     MsgType = 0x5E1A7C2D
+	TypeEditJournalState      MsgType = 0xC03B9E64
 	TypeEventStart            MsgType = 0xAA854D2C
 	TypeFlyToMe               MsgType = 0xA6EB70C6
 	TypeGenericMessage        MsgType = 0xF508E4A3
@@ -13,6 +15,7 @@ const (
 	TypeHighFive              MsgType = 0x3D501432
 	TypeLockObject            MsgType = 0xA7DE9F59
 	TypeLockObjectResponse    MsgType = 0x0924668C
+	TypeLockObjectSubtree     MsgType = 0x3F0C8D1B
 	TypeMyTransform           MsgType = 0xF878C4BF
 	TypeNotification          MsgType = 0xC1FB41D7
 	TypeObjectData            MsgType = 0xCACE197C
@@ -20,12 +23,16 @@ const (
 	TypeObjectTransform       MsgType = 0xEA6DA4B4
 	TypeRemoveObjects         MsgType = 0x6BF88C24
 	TypeRemoveUsers           MsgType = 0xF5A14BB0
+	TypeSetObjectsLOD         MsgType = 0x6B2E4F91
 	TypeSetWorld              MsgType = 0xCCDF2E49
 	TypeSignal                MsgType = 0xADC1964D
+	TypeSubscribeAttribute    MsgType = 0x5B2E7C14
 	TypeTeleportRequest       MsgType = 0x78DA55D9
 	TypeTriggerVisualEffects  MsgType = 0xD96089C6
 	TypeUnlockObject          MsgType = 0xA54EDEB9
+	TypeUnsubscribeAttribute  MsgType = 0x5B2E7C15
 	TypeUserAction            MsgType = 0xEF1A2E75
+	TypeUserAvatarChanged     MsgType = 0x8B0A3C51
 	TypeUserData              MsgType = 0xF702EF5F
 	TypeUserStakedToOdyssey   MsgType = 0x10DACABC
 	TypeUserTransform         MsgType = 0x3BC97EBB
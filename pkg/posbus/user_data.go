@@ -11,6 +11,8 @@ type UserData struct {
 	Avatar    string                 `json:"avatar"`
 	Transform cmath.TransformNoScale `json:"transform"`
 	IsGuest   bool                   `json:"is_guest"`
+	// Structured avatar, nil when the user has none
+	AvatarDefinition *AvatarDefinition `json:"avatar_definition"`
 }
 
 func init() {
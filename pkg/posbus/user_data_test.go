@@ -0,0 +1,51 @@
+package posbus_test
+
+import (
+	"testing"
+
+	"github.com/goccy/go-reflect"
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+func TestUserDataMarshalling(t *testing.T) {
+	subTests := []struct {
+		name string
+		in   posbus.UserData
+	}{
+		{
+			name: "without avatar",
+			in: posbus.UserData{
+				ID:   umid.New(),
+				Name: "foo",
+			},
+		},
+		{
+			name: "with avatar",
+			in: posbus.UserData{
+				ID:     umid.New(),
+				Name:   "foo",
+				Avatar: "bar",
+				AvatarDefinition: &posbus.AvatarDefinition{
+					Model:        umid.New(),
+					Colors:       map[string]string{"skin": "#aabbcc", "hair": "#112233ff"},
+					Accessories:  []posbus.AvatarAccessory{{Slot: "head", Model: umid.New()}},
+					AnimationSet: "default",
+				},
+			},
+		},
+	}
+
+	for _, subTest := range subTests {
+		t.Run("Roundtrip marshalling "+subTest.name, func(t *testing.T) {
+			in := subTest.in
+			buf := make([]byte, in.SizeMUS())
+			in.MarshalMUS(buf)
+			out := posbus.UserData{}
+			out.UnmarshalMUS(buf)
+			if !reflect.DeepEqual(in, out) {
+				t.Fatalf("%+v != %+v", in, out)
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package position_algo
+
+import (
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/utils"
+)
+
+const (
+	expressionXDefaultValue = "0"
+	expressionYDefaultValue = "10"
+	expressionZDefaultValue = "0"
+
+	// expressionMaxLength and expressionMaxDepth bound the recursion of the parser on untrusted expressions.
+	expressionMaxLength = 256
+	expressionMaxDepth  = 32
+)
+
+// expressionVariables are the variables available in expressions:
+// i is the child index, n the children count, t is i/(n-1) in [0, 1] and theta the parent angle.
+var expressionVariables = []string{"i", "n", "t", "theta", "pi", "e"}
+
+var expressionFunctions = map[string]struct {
+	args int
+	fn   func(args []float64) float64
+}{
+	"sin":   {1, func(a []float64) float64 { return math.Sin(a[0]) }},
+	"cos":   {1, func(a []float64) float64 { return math.Cos(a[0]) }},
+	"tan":   {1, func(a []float64) float64 { return math.Tan(a[0]) }},
+	"asin":  {1, func(a []float64) float64 { return math.Asin(a[0]) }},
+	"acos":  {1, func(a []float64) float64 { return math.Acos(a[0]) }},
+	"atan":  {1, func(a []float64) float64 { return math.Atan(a[0]) }},
+	"atan2": {2, func(a []float64) float64 { return math.Atan2(a[0], a[1]) }},
+	"sqrt":  {1, func(a []float64) float64 { return math.Sqrt(a[0]) }},
+	"abs":   {1, func(a []float64) float64 { return math.Abs(a[0]) }},
+	"floor": {1, func(a []float64) float64 { return math.Floor(a[0]) }},
+	"ceil":  {1, func(a []float64) float64 { return math.Ceil(a[0]) }},
+	"round": {1, func(a []float64) float64 { return math.Round(a[0]) }},
+	"exp":   {1, func(a []float64) float64 { return math.Exp(a[0]) }},
+	"log":   {1, func(a []float64) float64 { return math.Log(a[0]) }},
+	"pow":   {2, func(a []float64) float64 { return math.Pow(a[0], a[1]) }},
+	"mod":   {2, func(a []float64) float64 { return math.Mod(a[0], a[1]) }},
+	"min":   {2, func(a []float64) float64 { return math.Min(a[0], a[1]) }},
+	"max":   {2, func(a []float64) float64 { return math.Max(a[0], a[1]) }},
+}
+
+// expression places children at the parent position shifted by the x, y and z expressions.
+type expression struct {
+	X string `json:"x"`
+	Y string `json:"y"`
+	Z string `json:"z"`
+
+	x Expression
+	y Expression
+	z Expression
+}
+
+func NewExpression(parameterMap map[string]interface{}) (Algo, error) {
+	e := &expression{
+		X: utils.GetFromAnyMap(parameterMap, "x", expressionXDefaultValue),
+		Y: utils.GetFromAnyMap(parameterMap, "y", expressionYDefaultValue),
+		Z: utils.GetFromAnyMap(parameterMap, "z", expressionZDefaultValue),
+	}
+
+	var err error
+	if e.x, err = ParseExpression(e.X); err != nil {
+		return nil, errors.WithMessage(err, "invalid x expression")
+	}
+	if e.y, err = ParseExpression(e.Y); err != nil {
+		return nil, errors.WithMessage(err, "invalid y expression")
+	}
+	if e.z, err = ParseExpression(e.Z); err != nil {
+		return nil, errors.WithMessage(err, "invalid z expression")
+	}
+
+	return e, nil
+}
+
+func (e *expression) CalcPos(parentTheta float64, parentPosition cmath.Transform, i, n int) (
+	cmath.Transform, float64,
+) {
+	parent := parentPosition.Position.ToVec3f64()
+
+	t := 0.0
+	if n > 1 {
+		t = float64(i) / float64(n-1)
+	}
+	vars := map[string]float64{
+		"i":     float64(i),
+		"n":     float64(n),
+		"t":     t,
+		"theta": parentTheta,
+		"pi":    math.Pi,
+		"e":     math.E,
+	}
+
+	p := cmath.Vec3f64{
+		X: math.Round((parent.X+finite(e.x(vars)))*10.0) / 10.0,
+		Y: parent.Y + finite(e.y(vars)),
+		Z: math.Round((parent.Z+finite(e.z(vars)))*10.0) / 10.0,
+	}
+
+	np := cmath.Transform{Position: p.ToVec3()}
+	return np, math.Atan2(p.Z-parent.Z, p.X-parent.X) /* theta */
+}
+
+func (*expression) Name() string {
+	return "expression"
+}
+
+// finite replaces NaN and infinities, which a division by zero or sqrt of a negative gives, with 0.
+func finite(v float64) float64 {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return 0
+	}
+	return v
+}
+
+// Expression is a compiled arithmetic expression.
+type Expression func(vars map[string]float64) float64
+
+// ParseExpression compiles the expression with +, -, *, /, %, ^, parentheses,
+// the expressionVariables and the expressionFunctions.
+func ParseExpression(s string) (Expression, error) {
+	if len(s) > expressionMaxLength {
+		return nil, errors.Errorf("expression is longer than %d characters", expressionMaxLength)
+	}
+
+	tokens, err := tokenizeExpression(s)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &expressionParser{tokens: tokens}
+	expr, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	if p.pos < len(p.tokens) {
+		return nil, errors.Errorf("unexpected %q", p.tokens[p.pos])
+	}
+
+	return expr, nil
+}
+
+func tokenizeExpression(s string) ([]string, error) {
+	var tokens []string
+	for i := 0; i < len(s); {
+		r := rune(s[i])
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case unicode.IsDigit(r) || r == '.':
+			j := i
+			for j < len(s) && (unicode.IsDigit(rune(s[j])) || s[j] == '.') {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		case unicode.IsLetter(r) || r == '_':
+			j := i
+			for j < len(s) && (unicode.IsLetter(rune(s[j])) || unicode.IsDigit(rune(s[j])) || s[j] == '_') {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		case strings.ContainsRune("+-*/%^(),", r):
+			tokens = append(tokens, string(r))
+			i++
+		default:
+			return nil, errors.Errorf("unexpected character %q", r)
+		}
+	}
+	return tokens, nil
+}
+
+type expressionParser struct {
+	tokens []string
+	pos    int
+	depth  int
+}
+
+func (p *expressionParser) peek() string {
+	if p.pos < len(p.tokens) {
+		return p.tokens[p.pos]
+	}
+	return ""
+}
+
+func (p *expressionParser) next() string {
+	token := p.peek()
+	p.pos++
+	return token
+}
+
+func (p *expressionParser) parseSum() (Expression, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+
+	for op := p.peek(); op == "+" || op == "-"; op = p.peek() {
+		p.next()
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		if op == "+" {
+			left = func(vars map[string]float64) float64 { return l(vars) + right(vars) }
+		} else {
+			left = func(vars map[string]float64) float64 { return l(vars) - right(vars) }
+		}
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseProduct() (Expression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+
+	for op := p.peek(); op == "*" || op == "/" || op == "%"; op = p.peek() {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		l := left
+		switch op {
+		case "*":
+			left = func(vars map[string]float64) float64 { return l(vars) * right(vars) }
+		case "/":
+			left = func(vars map[string]float64) float64 { return l(vars) / right(vars) }
+		case "%":
+			left = func(vars map[string]float64) float64 { return math.Mod(l(vars), right(vars)) }
+		}
+	}
+
+	return left, nil
+}
+
+func (p *expressionParser) parseUnary() (Expression, error) {
+	// every nesting of parentheses, calls, signs and exponents goes through here
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > expressionMaxDepth {
+		return nil, errors.Errorf("expression is nested deeper than %d", expressionMaxDepth)
+	}
+
+	switch p.peek() {
+	case "-":
+		p.next()
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return func(vars map[string]float64) float64 { return -operand(vars) }, nil
+	case "+":
+		p.next()
+		return p.parseUnary()
+	}
+
+	return p.parsePower()
+}
+
+func (p *expressionParser) parsePower() (Expression, error) {
+	base, err := p.parsePrimary()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek() != "^" {
+		return base, nil
+	}
+
+	p.next()
+	// right associative
+	exponent, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	return func(vars map[string]float64) float64 { return math.Pow(base(vars), exponent(vars)) }, nil
+}
+
+func (p *expressionParser) parsePrimary() (Expression, error) {
+	token := p.next()
+	switch {
+	case token == "":
+		return nil, errors.New("unexpected end of expression")
+	case token == "(":
+		expr, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ")" {
+			return nil, errors.New("missing closing parenthesis")
+		}
+		return expr, nil
+	case unicode.IsDigit(rune(token[0])) || token[0] == '.':
+		v, err := strconv.ParseFloat(token, 64)
+		if err != nil {
+			return nil, errors.Errorf("invalid number %q", token)
+		}
+		return func(map[string]float64) float64 { return v }, nil
+	case unicode.IsLetter(rune(token[0])) || token[0] == '_':
+		if p.peek() == "(" {
+			return p.parseCall(token)
+		}
+		for _, name := range expressionVariables {
+			if name == token {
+				return func(vars map[string]float64) float64 { return vars[token] }, nil
+			}
+		}
+		return nil, errors.Errorf("unknown variable %q", token)
+	}
+
+	return nil, errors.Errorf("unexpected %q", token)
+}
+
+func (p *expressionParser) parseCall(name string) (Expression, error) {
+	function, ok := expressionFunctions[name]
+	if !ok {
+		return nil, errors.Errorf("unknown function %q", name)
+	}
+
+	p.next() // (
+	var args []Expression
+	if p.peek() != ")" {
+		for {
+			arg, err := p.parseSum()
+			if err != nil {
+				return nil, err
+			}
+			args = append(args, arg)
+			if p.peek() != "," {
+				break
+			}
+			p.next()
+		}
+	}
+	if p.next() != ")" {
+		return nil, errors.Errorf("missing closing parenthesis of %s", name)
+	}
+	if len(args) != function.args {
+		return nil, errors.Errorf("%s expects %d arguments, got %d", name, function.args, len(args))
+	}
+
+	return func(vars map[string]float64) float64 {
+		values := make([]float64, len(args))
+		for i := range args {
+			values[i] = args[i](vars)
+		}
+		return function.fn(values)
+	}, nil
+}
@@ -0,0 +1,56 @@
+package position_algo
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+)
+
+func TestParseExpression(t *testing.T) {
+	t.Parallel()
+
+	vars := map[string]float64{"i": 2, "n": 5, "pi": math.Pi}
+	for s, expected := range map[string]float64{
+		"1 + 2 * 3":         7,
+		"(1 + 2) * 3":       9,
+		"-2 ^ 2":            -4,
+		"2 ^ 3 ^ 2":         512,
+		"7 % 4":             3,
+		"i * 10 - n":        15,
+		"max(i, n) / 2":     2.5,
+		"round(cos(pi))":    -1,
+		"pow(2, i) + .5":    4.5,
+		"atan2(0, 1) + i*0": 0,
+	} {
+		expr, err := ParseExpression(s)
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, expected, expr(vars), s)
+		}
+	}
+
+	for _, s := range []string{"", "1 +", "(1", "x", "foo(1)", "min(1)", "1 2", "1 $ 2",
+		strings.Repeat("1+", 128) + "1", strings.Repeat("(", 40) + "1" + strings.Repeat(")", 40),
+		strings.Repeat("-", 40) + "1",
+	} {
+		_, err := ParseExpression(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestExpressionCalcPos(t *testing.T) {
+	t.Parallel()
+
+	algo, err := NewExpression(map[string]any{"x": "i * 10", "y": "5", "z": "t * 100"})
+	assert.NoError(t, err)
+
+	parent := cmath.Transform{Position: cmath.Vec3{X: 1, Y: 2, Z: 3}}
+	pos, _ := algo.CalcPos(0, parent, 2, 3)
+	assert.Equal(t, cmath.Vec3{X: 21, Y: 7, Z: 103}, pos.Position)
+
+	_, err = NewExpression(map[string]any{"x": "i +"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package position_algo
+
+import (
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/utils"
+)
+
+const (
+	gridColsDefaultValue        = 0.0
+	gridRowsDefaultValue        = 0.0
+	gridSpacingDefaultValue     = 10.0
+	gridLayerHeightDefaultValue = 10.0
+	gridVShiftDefaultValue      = 10.0
+)
+
+// grid places children in rows of cols centered on the parent, a full grid of rows is continued one layer up.
+// Zero cols makes the grid square, zero rows makes it unbounded.
+type grid struct {
+	Cols        float64 `json:"cols"`
+	Rows        float64 `json:"rows"`
+	Spacing     float64 `json:"spacing"`
+	LayerHeight float64 `json:"layerHeight"`
+	VShift      float64 `json:"Vshift"`
+}
+
+func NewGrid(parameterMap map[string]interface{}) (Algo, error) {
+	g := &grid{
+		Cols:        utils.GetFromAnyMap(parameterMap, "cols", gridColsDefaultValue),
+		Rows:        utils.GetFromAnyMap(parameterMap, "rows", gridRowsDefaultValue),
+		Spacing:     utils.GetFromAnyMap(parameterMap, "spacing", gridSpacingDefaultValue),
+		LayerHeight: utils.GetFromAnyMap(parameterMap, "layerHeight", gridLayerHeightDefaultValue),
+		VShift:      utils.GetFromAnyMap(parameterMap, "Vshift", gridVShiftDefaultValue),
+	}
+	if g.Cols < 0 || g.Rows < 0 {
+		return nil, errors.New("cols and rows must not be negative")
+	}
+
+	return g, nil
+}
+
+func (g *grid) CalcPos(parentTheta float64, parentPosition cmath.Transform, i, n int) (
+	cmath.Transform, float64,
+) {
+	parent := parentPosition.Position.ToVec3f64()
+
+	cols := int(g.Cols)
+	if cols == 0 {
+		cols = int(math.Ceil(math.Sqrt(float64(n))))
+	}
+	if cols < 1 {
+		cols = 1
+	}
+	rows := int(math.Ceil(float64(n) / float64(cols)))
+	if g.Rows > 0 && rows > int(g.Rows) {
+		rows = int(g.Rows)
+	}
+	if rows < 1 {
+		rows = 1
+	}
+
+	layer := i / (cols * rows)
+	row := i % (cols * rows) / cols
+	col := i % cols
+
+	x := (float64(col) - float64(cols-1)/2) * g.Spacing
+	z := (float64(row) - float64(rows-1)/2) * g.Spacing
+	sin, cos := math.Sincos(parentTheta)
+
+	p := cmath.Vec3f64{
+		X: math.Round((parent.X+x*cos-z*sin)*10.0) / 10.0,
+		Y: parent.Y + g.VShift + float64(layer)*g.LayerHeight,
+		Z: math.Round((parent.Z+x*sin+z*cos)*10.0) / 10.0,
+	}
+
+	np := cmath.Transform{Position: p.ToVec3()}
+	return np, math.Atan2(p.Z-parent.Z, p.X-parent.X) /* theta */
+}
+
+func (*grid) Name() string {
+	return "grid"
+}
@@ -0,0 +1,64 @@
+package position_algo
+
+import (
+	"encoding/json"
+	"math"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/utils"
+)
+
+const (
+	slotsVShiftDefaultValue = 10.0
+)
+
+// slots places children at the explicit positions and rotations relative to the parent,
+// children beyond the slots repeat them shifted up by Vshift.
+type slots struct {
+	Slots  []cmath.TransformNoScale `json:"slots"`
+	VShift float64                  `json:"Vshift"`
+}
+
+func NewSlots(parameterMap map[string]interface{}) (Algo, error) {
+	s := &slots{
+		VShift: utils.GetFromAnyMap(parameterMap, "Vshift", slotsVShiftDefaultValue),
+	}
+
+	data, err := json.Marshal(parameterMap["slots"])
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to marshal slots")
+	}
+	if err := json.Unmarshal(data, &s.Slots); err != nil {
+		return nil, errors.WithMessage(err, "invalid slots")
+	}
+	if len(s.Slots) == 0 {
+		return nil, errors.New("slots are required")
+	}
+
+	return s, nil
+}
+
+func (s *slots) CalcPos(parentTheta float64, parentPosition cmath.Transform, i, n int) (
+	cmath.Transform, float64,
+) {
+	parent := parentPosition.Position.ToVec3f64()
+
+	slot := s.Slots[i%len(s.Slots)]
+	round := i / len(s.Slots)
+	offset := slot.Position.ToVec3f64()
+
+	p := cmath.Vec3f64{
+		X: math.Round((parent.X+offset.X)*10.0) / 10.0,
+		Y: parent.Y + offset.Y + float64(round)*s.VShift,
+		Z: math.Round((parent.Z+offset.Z)*10.0) / 10.0,
+	}
+
+	np := cmath.Transform{Position: p.ToVec3(), Rotation: slot.Rotation}
+	return np, math.Atan2(p.Z-parent.Z, p.X-parent.X) /* theta */
+}
+
+func (*slots) Name() string {
+	return "slots"
+}
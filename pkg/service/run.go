@@ -32,22 +32,30 @@ import (
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
 
 	activitiesDB "github.com/momentum-xyz/ubercontroller/database/activities"
+	apiKeysDB "github.com/momentum-xyz/ubercontroller/database/api_keys"
 	assets2dDB "github.com/momentum-xyz/ubercontroller/database/assets_2d"
 	assets3dDB "github.com/momentum-xyz/ubercontroller/database/assets_3d"
 	attributesTypeDB "github.com/momentum-xyz/ubercontroller/database/attribute_types"
 	commonDB "github.com/momentum-xyz/ubercontroller/database/common"
+	moderationDB "github.com/momentum-xyz/ubercontroller/database/moderation"
 	nftsDB "github.com/momentum-xyz/ubercontroller/database/nfts"
 	nodeAttributesDB "github.com/momentum-xyz/ubercontroller/database/node_attributes"
 	nodesDB "github.com/momentum-xyz/ubercontroller/database/nodes"
 	objectActivitiesDB "github.com/momentum-xyz/ubercontroller/database/object_activities"
 	objectAttributesDB "github.com/momentum-xyz/ubercontroller/database/object_attributes"
+	objectAttributesHistoryDB "github.com/momentum-xyz/ubercontroller/database/object_attributes_history"
 	objectTypesDB "github.com/momentum-xyz/ubercontroller/database/object_types"
 	objectUserAttributesDB "github.com/momentum-xyz/ubercontroller/database/object_user_attributes"
+	objectTrashDB "github.com/momentum-xyz/ubercontroller/database/object_trash"
 	objectsDB "github.com/momentum-xyz/ubercontroller/database/objects"
 	pluginsDB "github.com/momentum-xyz/ubercontroller/database/plugins"
+	prefabsDB "github.com/momentum-xyz/ubercontroller/database/prefabs"
+	searchDB "github.com/momentum-xyz/ubercontroller/database/search"
 	userActivitiesDB "github.com/momentum-xyz/ubercontroller/database/user_activities"
 	userAttributesDB "github.com/momentum-xyz/ubercontroller/database/user_attributes"
 	userObjectsDB "github.com/momentum-xyz/ubercontroller/database/user_objects"
+	userRelationsDB "github.com/momentum-xyz/ubercontroller/database/user_relations"
+	userSessionsDB "github.com/momentum-xyz/ubercontroller/database/user_sessions"
 	userTypesDB "github.com/momentum-xyz/ubercontroller/database/user_types"
 	userUserAttributesDB "github.com/momentum-xyz/ubercontroller/database/user_user_attributes"
 	usersDB "github.com/momentum-xyz/ubercontroller/database/users"
@@ -199,6 +207,7 @@ func createDB(conn *pgxpool.Pool) (database.DB, error) {
 		nodesDB.NewDB(conn, common),
 		worldsDB.NewDB(conn, common, objects),
 		objects,
+		objectTrashDB.NewDB(conn, common),
 		activitiesDB.NewDB(conn, common),
 		userActivitiesDB.NewDB(conn, common),
 		objectActivitiesDB.NewDB(conn, common),
@@ -212,9 +221,16 @@ func createDB(conn *pgxpool.Pool) (database.DB, error) {
 		attributesTypeDB.NewDB(conn, common),
 		nodeAttributesDB.NewDB(conn, common),
 		objectAttributesDB.NewDB(conn, common),
+		objectAttributesHistoryDB.NewDB(conn, common),
 		objectUserAttributesDB.NewDB(conn, common),
 		userAttributesDB.NewDB(conn, common),
 		userUserAttributesDB.NewDB(conn, common),
+		userSessionsDB.NewDB(conn, common),
+		apiKeysDB.NewDB(conn, common),
+		moderationDB.NewDB(conn, common),
+		userRelationsDB.NewDB(conn, common),
+		prefabsDB.NewDB(conn, common),
+		searchDB.NewDB(conn, common),
 		stakes.NewDB(conn),
 		nftsDB.NewDB(conn),
 	), nil
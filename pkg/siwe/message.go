@@ -0,0 +1,219 @@
+// Package siwe implements Sign-In with Ethereum (EIP-4361) messages.
+package siwe
+
+import (
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	gonanoid "github.com/matoous/go-nanoid/v2"
+	"github.com/pkg/errors"
+)
+
+const (
+	Version = "1"
+
+	headerSuffix   = " wants you to sign in with your Ethereum account:"
+	uriTag         = "URI: "
+	versionTag     = "Version: "
+	chainIDTag     = "Chain ID: "
+	nonceTag       = "Nonce: "
+	issuedAtTag    = "Issued At: "
+	expirationTag  = "Expiration Time: "
+	notBeforeTag   = "Not Before: "
+	requestIDTag   = "Request ID: "
+	resourcesTag   = "Resources:"
+	resourcePrefix = "- "
+
+	nonceAlphabet = "0123456789ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz"
+	nonceLength   = 17
+)
+
+type Message struct {
+	Domain         string
+	Address        string
+	Statement      string
+	URI            string
+	Version        string
+	ChainID        int64
+	Nonce          string
+	IssuedAt       time.Time
+	ExpirationTime *time.Time
+	NotBefore      *time.Time
+	RequestID      string
+	Resources      []string
+}
+
+func GenerateNonce() (string, error) {
+	return gonanoid.Generate(nonceAlphabet, nonceLength)
+}
+
+// String returns the message in the EIP-4361 format, which is the text to be signed.
+func (m *Message) String() string {
+	var b strings.Builder
+
+	b.WriteString(m.Domain + headerSuffix + "\n")
+	b.WriteString(m.Address + "\n\n")
+	if m.Statement != "" {
+		b.WriteString(m.Statement + "\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(uriTag + m.URI + "\n")
+	b.WriteString(versionTag + m.Version + "\n")
+	b.WriteString(chainIDTag + strconv.FormatInt(m.ChainID, 10) + "\n")
+	b.WriteString(nonceTag + m.Nonce + "\n")
+	b.WriteString(issuedAtTag + m.IssuedAt.UTC().Format(time.RFC3339))
+	if m.ExpirationTime != nil {
+		b.WriteString("\n" + expirationTag + m.ExpirationTime.UTC().Format(time.RFC3339))
+	}
+	if m.NotBefore != nil {
+		b.WriteString("\n" + notBeforeTag + m.NotBefore.UTC().Format(time.RFC3339))
+	}
+	if m.RequestID != "" {
+		b.WriteString("\n" + requestIDTag + m.RequestID)
+	}
+	if len(m.Resources) > 0 {
+		b.WriteString("\n" + resourcesTag)
+		for _, resource := range m.Resources {
+			b.WriteString("\n" + resourcePrefix + resource)
+		}
+	}
+
+	return b.String()
+}
+
+// ParseMessage parses the EIP-4361 formatted message.
+func ParseMessage(s string) (*Message, error) {
+	lines := strings.Split(strings.ReplaceAll(s, "\r\n", "\n"), "\n")
+	if len(lines) < 8 {
+		return nil, errors.New("message is too short")
+	}
+
+	var m Message
+
+	if !strings.HasSuffix(lines[0], headerSuffix) {
+		return nil, errors.New("invalid message header")
+	}
+	m.Domain = strings.TrimSuffix(lines[0], headerSuffix)
+	m.Address = lines[1]
+	if lines[2] != "" {
+		return nil, errors.New("missing empty line after address")
+	}
+
+	i := 3
+	if lines[i] != "" {
+		m.Statement = lines[i]
+		i++
+	}
+	if lines[i] != "" {
+		return nil, errors.New("missing empty line before fields")
+	}
+	i++
+
+	var (
+		chainID  string
+		issuedAt string
+	)
+	required := []struct {
+		tag   string
+		value *string
+	}{
+		{uriTag, &m.URI},
+		{versionTag, &m.Version},
+		{chainIDTag, &chainID},
+		{nonceTag, &m.Nonce},
+		{issuedAtTag, &issuedAt},
+	}
+	for _, field := range required {
+		if i >= len(lines) || !strings.HasPrefix(lines[i], field.tag) {
+			return nil, errors.Errorf("missing field: %q", strings.TrimSpace(field.tag))
+		}
+		*field.value = strings.TrimPrefix(lines[i], field.tag)
+		i++
+	}
+
+	var err error
+	if m.ChainID, err = strconv.ParseInt(chainID, 10, 64); err != nil {
+		return nil, errors.WithMessage(err, "invalid chain id")
+	}
+	if m.IssuedAt, err = time.Parse(time.RFC3339Nano, issuedAt); err != nil {
+		return nil, errors.WithMessage(err, "invalid issued at")
+	}
+
+	if i < len(lines) && strings.HasPrefix(lines[i], expirationTag) {
+		t, err := time.Parse(time.RFC3339Nano, strings.TrimPrefix(lines[i], expirationTag))
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid expiration time")
+		}
+		m.ExpirationTime = &t
+		i++
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], notBeforeTag) {
+		t, err := time.Parse(time.RFC3339Nano, strings.TrimPrefix(lines[i], notBeforeTag))
+		if err != nil {
+			return nil, errors.WithMessage(err, "invalid not before")
+		}
+		m.NotBefore = &t
+		i++
+	}
+	if i < len(lines) && strings.HasPrefix(lines[i], requestIDTag) {
+		m.RequestID = strings.TrimPrefix(lines[i], requestIDTag)
+		i++
+	}
+	if i < len(lines) && lines[i] == resourcesTag {
+		i++
+		for ; i < len(lines) && strings.HasPrefix(lines[i], resourcePrefix); i++ {
+			m.Resources = append(m.Resources, strings.TrimPrefix(lines[i], resourcePrefix))
+		}
+	}
+	if i != len(lines) {
+		return nil, errors.Errorf("unexpected line: %q", lines[i])
+	}
+
+	return &m, nil
+}
+
+// Validate checks the message was created for the given domain and uri origin and is valid at the given time.
+func (m *Message) Validate(domain, uri string, now time.Time) error {
+	if m.Version != Version {
+		return errors.Errorf("unsupported version: %q", m.Version)
+	}
+	if m.Domain != domain {
+		return errors.Errorf("domain mismatch: %q", m.Domain)
+	}
+
+	expectedOrigin, err := origin(uri)
+	if err != nil {
+		return errors.WithMessage(err, "invalid expected uri")
+	}
+	messageOrigin, err := origin(m.URI)
+	if err != nil {
+		return errors.WithMessage(err, "invalid uri")
+	}
+	if messageOrigin != expectedOrigin {
+		return errors.Errorf("uri mismatch: %q", m.URI)
+	}
+
+	if m.ExpirationTime != nil && !now.Before(*m.ExpirationTime) {
+		return errors.New("message expired")
+	}
+	if m.NotBefore != nil && now.Before(*m.NotBefore) {
+		return errors.New("message not yet valid")
+	}
+
+	return nil
+}
+
+func origin(uri string) (string, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return "", err
+	}
+	if u.Scheme == "" || u.Host == "" {
+		return "", errors.Errorf("not an absolute uri: %q", uri)
+	}
+	return fmt.Sprintf("%s://%s", u.Scheme, u.Host), nil
+}
@@ -0,0 +1,62 @@
+package siwe
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessage(t *testing.T) {
+	t.Parallel()
+
+	issuedAt := time.Date(2023, 7, 1, 12, 0, 0, 0, time.UTC)
+	expiration := issuedAt.Add(5 * time.Minute)
+	message := &Message{
+		Domain:         "example.com",
+		Address:        "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2",
+		Statement:      "Sign in to Momentum",
+		URI:            "https://example.com/login",
+		Version:        Version,
+		ChainID:        1,
+		Nonce:          "32891756abcdefgh",
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expiration,
+		Resources:      []string{"https://example.com/terms"},
+	}
+
+	text := message.String()
+	assert.Equal(
+		t,
+		"example.com wants you to sign in with your Ethereum account:\n"+
+			"0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2\n\n"+
+			"Sign in to Momentum\n\n"+
+			"URI: https://example.com/login\n"+
+			"Version: 1\n"+
+			"Chain ID: 1\n"+
+			"Nonce: 32891756abcdefgh\n"+
+			"Issued At: 2023-07-01T12:00:00Z\n"+
+			"Expiration Time: 2023-07-01T12:05:00Z\n"+
+			"Resources:\n"+
+			"- https://example.com/terms",
+		text,
+	)
+
+	parsed, err := ParseMessage(text)
+	assert.NoError(t, err)
+	assert.Equal(t, message, parsed)
+
+	withoutStatement := *message
+	withoutStatement.Statement = ""
+	parsed, err = ParseMessage(withoutStatement.String())
+	assert.NoError(t, err)
+	assert.Equal(t, &withoutStatement, parsed)
+
+	_, err = ParseMessage("Please sign this message")
+	assert.Error(t, err)
+
+	assert.NoError(t, parsed.Validate("example.com", "https://example.com", issuedAt))
+	assert.Error(t, parsed.Validate("other.com", "https://example.com", issuedAt))
+	assert.Error(t, parsed.Validate("example.com", "https://other.com", issuedAt))
+	assert.Error(t, parsed.Validate("example.com", "https://example.com", expiration))
+}
@@ -0,0 +1,45 @@
+package siwe
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/types/generic"
+)
+
+// NonceStore keeps issued messages until they are used or expire, one outstanding message per address.
+type NonceStore struct {
+	messages *generic.SyncMap[string, *Message]
+	timers   *generic.TimerSet[string]
+}
+
+func NewNonceStore() *NonceStore {
+	return &NonceStore{
+		messages: generic.NewSyncMap[string, *Message](0),
+		timers:   generic.NewTimerSet[string](),
+	}
+}
+
+// Put replaces the outstanding message of the address, it is removed at the message expiration time.
+func (s *NonceStore) Put(ctx context.Context, message *Message, ttl time.Duration) {
+	key := strings.ToLower(message.Address)
+
+	s.messages.Store(key, message)
+	s.timers.Set(ctx, key, ttl, func(key string) error {
+		s.messages.Remove(key)
+		return nil
+	})
+}
+
+func (s *NonceStore) Get(address string) (*Message, bool) {
+	return s.messages.Load(strings.ToLower(address))
+}
+
+// Remove the message so its nonce can't be used again.
+func (s *NonceStore) Remove(address string) {
+	key := strings.ToLower(address)
+
+	s.timers.Stop(key)
+	s.messages.Remove(key)
+}
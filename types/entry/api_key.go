@@ -0,0 +1,38 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// APIKey is issued by a node admin for server-to-server access, it acts as its creator limited by the scopes.
+type APIKey struct {
+	APIKeyID   umid.UMID  `db:"api_key_id" json:"api_key_id"`
+	Name       string     `db:"name" json:"name"`
+	KeyHash    string     `db:"key_hash" json:"-"`
+	Scopes     []string   `db:"scopes" json:"scopes"`
+	CreatedBy  umid.UMID  `db:"created_by" json:"created_by"`
+	ExpiresAt  time.Time  `db:"expires_at" json:"expires_at"`
+	LastUsedAt *time.Time `db:"last_used_at" json:"last_used_at,omitempty"`
+	RevokedAt  *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt  time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// IsActive checks if the key is neither revoked nor expired.
+func (k *APIKey) IsActive() bool {
+	return k.RevokedAt == nil && time.Now().Before(k.ExpiresAt)
+}
+
+// APIKeyAuditEntry records a request made with an API key.
+type APIKeyAuditEntry struct {
+	AuditID   umid.UMID `db:"audit_id" json:"audit_id"`
+	APIKeyID  umid.UMID `db:"api_key_id" json:"api_key_id"`
+	Method    string    `db:"method" json:"method"`
+	Path      string    `db:"path" json:"path"`
+	Scope     string    `db:"scope" json:"scope"`
+	Status    int       `db:"status" json:"status"`
+	IPAddress string    `db:"ip_address" json:"ip_address"`
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
@@ -0,0 +1,49 @@
+package entry
+
+import (
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type AttributeSubscriptionTargetType string
+
+const (
+	InvalidAttributeSubscriptionTargetType    AttributeSubscriptionTargetType = ""
+	ObjectAttributeSubscriptionTargetType     AttributeSubscriptionTargetType = "object"
+	UserAttributeSubscriptionTargetType       AttributeSubscriptionTargetType = "user"
+	ObjectUserAttributeSubscriptionTargetType AttributeSubscriptionTargetType = "object_user"
+)
+
+// AttributeSubscriptionID identifies a single attribute a client can subscribe to.
+type AttributeSubscriptionID struct {
+	AttributeID
+	TargetType AttributeSubscriptionTargetType `json:"target_type"`
+	// Object or user the attribute belongs to.
+	TargetID umid.UMID `json:"target_id"`
+	// User of an object user attribute, umid.Nil for other target types.
+	UserID umid.UMID `json:"user_id"`
+}
+
+func NewObjectAttributeSubscriptionID(objectAttributeID ObjectAttributeID) AttributeSubscriptionID {
+	return AttributeSubscriptionID{
+		AttributeID: objectAttributeID.AttributeID,
+		TargetType:  ObjectAttributeSubscriptionTargetType,
+		TargetID:    objectAttributeID.ObjectID,
+	}
+}
+
+func NewUserAttributeSubscriptionID(userAttributeID UserAttributeID) AttributeSubscriptionID {
+	return AttributeSubscriptionID{
+		AttributeID: userAttributeID.AttributeID,
+		TargetType:  UserAttributeSubscriptionTargetType,
+		TargetID:    userAttributeID.UserID,
+	}
+}
+
+func NewObjectUserAttributeSubscriptionID(objectUserAttributeID ObjectUserAttributeID) AttributeSubscriptionID {
+	return AttributeSubscriptionID{
+		AttributeID: objectUserAttributeID.AttributeID,
+		TargetType:  ObjectUserAttributeSubscriptionTargetType,
+		TargetID:    objectUserAttributeID.ObjectID,
+		UserID:      objectUserAttributeID.UserID,
+	}
+}
@@ -0,0 +1,43 @@
+package entry
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// EditTargetKind is the part of an object changed by an edit.
+type EditTargetKind string
+
+const (
+	// EditTargetObject is the object with its children, it's created or removed.
+	EditTargetObject EditTargetKind = "object"
+	// EditTargetProperties are the name and assets of the object.
+	EditTargetProperties EditTargetKind = "properties"
+	EditTargetTransform  EditTargetKind = "transform"
+	EditTargetAttribute  EditTargetKind = "attribute"
+)
+
+type EditTarget struct {
+	ObjectID    umid.UMID      `json:"object_id"`
+	Kind        EditTargetKind `json:"kind"`
+	AttributeID *AttributeID   `json:"attribute_id,omitempty"`
+}
+
+// EditChange is a change of the target, states are json encoded and "null" stands for a missing object or attribute.
+type EditChange struct {
+	Target EditTarget      `json:"target"`
+	Before json.RawMessage `json:"before"`
+	After  json.RawMessage `json:"after"`
+}
+
+// EditTransaction is a group of changes which are undone and redone together.
+type EditTransaction struct {
+	TransactionID umid.UMID     `json:"transaction_id"`
+	UserID        umid.UMID     `json:"user_id"`
+	WorldID       umid.UMID     `json:"world_id"`
+	Changes       []*EditChange `json:"changes"`
+	CreatedAt     time.Time     `json:"created_at"`
+	UpdatedAt     time.Time     `json:"updated_at"`
+}
@@ -0,0 +1,71 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// UserBan prevents the user from joining a world, or the node if WorldID is nil.
+type UserBan struct {
+	BanID     umid.UMID  `db:"ban_id" json:"ban_id"`
+	UserID    umid.UMID  `db:"user_id" json:"user_id"`
+	WorldID   *umid.UMID `db:"world_id" json:"world_id,omitempty"`
+	Reason    string     `db:"reason" json:"reason"`
+	CreatedBy *umid.UMID `db:"created_by" json:"created_by,omitempty"`
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// IsActive checks if the ban is permanent or not expired yet.
+func (b *UserBan) IsActive() bool {
+	return b.ExpiresAt == nil || time.Now().Before(*b.ExpiresAt)
+}
+
+// Applies checks if the ban prevents the user from joining the world.
+func (b *UserBan) Applies(worldID *umid.UMID) bool {
+	return b.WorldID == nil || (worldID != nil && *b.WorldID == *worldID)
+}
+
+// UserMute silences chat and high-fives of the user in a world, or in the whole node if WorldID is nil.
+type UserMute struct {
+	MuteID    umid.UMID  `db:"mute_id" json:"mute_id"`
+	UserID    umid.UMID  `db:"user_id" json:"user_id"`
+	WorldID   *umid.UMID `db:"world_id" json:"world_id,omitempty"`
+	Reason    string     `db:"reason" json:"reason"`
+	CreatedBy *umid.UMID `db:"created_by" json:"created_by,omitempty"`
+	ExpiresAt *time.Time `db:"expires_at" json:"expires_at,omitempty"`
+	CreatedAt time.Time  `db:"created_at" json:"created_at"`
+}
+
+// IsActive checks if the mute is permanent or not expired yet.
+func (m *UserMute) IsActive() bool {
+	return m.ExpiresAt == nil || time.Now().Before(*m.ExpiresAt)
+}
+
+// Applies checks if the mute silences the user in the world.
+func (m *UserMute) Applies(worldID *umid.UMID) bool {
+	return m.WorldID == nil || (worldID != nil && *m.WorldID == *worldID)
+}
+
+type UserReportStatus string
+
+const (
+	UserReportStatusOpen      UserReportStatus = "open"
+	UserReportStatusResolved  UserReportStatus = "resolved"
+	UserReportStatusDismissed UserReportStatus = "dismissed"
+)
+
+// UserReport is filed by a user against another user and handled in the moderation queue.
+type UserReport struct {
+	ReportID     umid.UMID        `db:"report_id" json:"report_id"`
+	ReporterID   *umid.UMID       `db:"reporter_id" json:"reporter_id,omitempty"`
+	TargetUserID umid.UMID        `db:"target_user_id" json:"target_user_id"`
+	WorldID      *umid.UMID       `db:"world_id" json:"world_id,omitempty"`
+	Reason       string           `db:"reason" json:"reason"`
+	Status       UserReportStatus `db:"status" json:"status"`
+	ResolvedBy   *umid.UMID       `db:"resolved_by" json:"resolved_by,omitempty"`
+	Resolution   string           `db:"resolution" json:"resolution"`
+	CreatedAt    time.Time        `db:"created_at" json:"created_at"`
+	UpdatedAt    time.Time        `db:"updated_at" json:"updated_at"`
+}
@@ -45,6 +45,20 @@ type ObjectOptions struct {
 	Private          *bool                               `db:"private" json:"private,omitempty"`
 	DashboardPlugins []string                            `db:"dashboard_plugins" json:"dashboard_plugins,omitempty"`
 	Subs             map[string]any                      `db:"subs" json:"subs,omitempty"`
+	LOD              *ObjectLOD                          `db:"lod" json:"lod,omitempty"`
+}
+
+// ObjectLOD puts the object into a level of detail group, clients switch all objects of a group at once.
+type ObjectLOD struct {
+	Group string `db:"group" json:"group"`
+	// Levels are the lower detail assets starting with level 1, level 0 is the object asset.
+	Levels []ObjectLODLevel `db:"levels" json:"levels"`
+}
+
+type ObjectLODLevel struct {
+	// Distance from the user at which clients should switch to the level.
+	Distance  float32   `db:"distance" json:"distance"`
+	Asset3dID umid.UMID `db:"asset_3d_id" json:"asset_3d_id"`
 }
 
 type ObjectChildPlacement struct {
@@ -52,6 +66,12 @@ type ObjectChildPlacement struct {
 	Options map[string]any `db:"options" json:"options,omitempty"`
 }
 
+type ObjectChildPosition struct {
+	ObjectID  *umid.UMID      `json:"object_id"`
+	Index     int             `json:"index"`
+	Transform cmath.Transform `json:"transform"`
+}
+
 type ObjectAttributeID struct {
 	AttributeID
 	ObjectID umid.UMID `db:"object_id" json:"object_id"`
@@ -0,0 +1,28 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type ObjectAttributeHistory struct {
+	HistoryID umid.UMID `db:"history_id" json:"history_id"`
+	ObjectAttributeID
+	ChangedBy *umid.UMID      `db:"changed_by" json:"changed_by"`
+	OldValue  *AttributeValue `db:"old_value" json:"old_value"`
+	NewValue  *AttributeValue `db:"new_value" json:"new_value"`
+	CreatedAt time.Time       `db:"created_at" json:"created_at"`
+}
+
+func NewObjectAttributeHistory(
+	objectAttributeID ObjectAttributeID, changedBy *umid.UMID, oldValue, newValue *AttributeValue,
+) *ObjectAttributeHistory {
+	return &ObjectAttributeHistory{
+		HistoryID:         umid.New(),
+		ObjectAttributeID: objectAttributeID,
+		ChangedBy:         changedBy,
+		OldValue:          oldValue,
+		NewValue:          newValue,
+	}
+}
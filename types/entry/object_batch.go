@@ -0,0 +1,14 @@
+package entry
+
+import "github.com/momentum-xyz/ubercontroller/utils/umid"
+
+// ObjectsBatch is the outcome of a batch of object operations, saved in one transaction.
+// Objects are upserted in order, so parents must go before their children.
+// Removed objects are moved to the trash with their children, only roots of removed subtrees are listed.
+// Created objects are inserted instead, so the batch fails if any of them already exists.
+type ObjectsBatch struct {
+	Objects          []*Object
+	CreatedIDs       map[umid.UMID]bool
+	ObjectAttributes []*ObjectAttribute
+	TrashItems       []*ObjectTrashItem
+}
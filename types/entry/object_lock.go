@@ -0,0 +1,20 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// ObjectLock is an edit lease of the object, it expires unless renewed by the user.
+type ObjectLock struct {
+	ObjectID umid.UMID `json:"object_id"`
+	UserID   umid.UMID `json:"user_id"`
+	// the lease covers all children of the object
+	Subtree   bool      `json:"subtree"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func (l *ObjectLock) IsExpired(now time.Time) bool {
+	return !now.Before(l.ExpiresAt)
+}
@@ -0,0 +1,51 @@
+package entry
+
+import (
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type ObjectQueryField string
+
+const (
+	ObjectQueryFieldName          ObjectQueryField = "name"
+	ObjectQueryFieldObjectTypeID  ObjectQueryField = "object_type_id"
+	ObjectQueryFieldParentID      ObjectQueryField = "parent_id"
+	ObjectQueryFieldOwnerID       ObjectQueryField = "owner_id"
+	ObjectQueryFieldTransform     ObjectQueryField = "transform"
+	ObjectQueryFieldDepth         ObjectQueryField = "depth"
+	ObjectQueryFieldChildrenCount ObjectQueryField = "children_count"
+	ObjectQueryFieldCreatedAt     ObjectQueryField = "created_at"
+	ObjectQueryFieldUpdatedAt     ObjectQueryField = "updated_at"
+)
+
+// ObjectQuery filters objects of a tree, empty fields match everything.
+type ObjectQuery struct {
+	ObjectTypeIDs []umid.UMID                  `json:"object_type_ids"`
+	OwnerIDs      []umid.UMID                  `json:"owner_ids"`
+	Attributes    []ObjectQueryAttributeFilter `json:"attributes"`
+	Region        *ObjectQueryRegion           `json:"region"`
+	// Depth is counted from the root of the query, which has depth 0.
+	MinDepth *int `json:"min_depth"`
+	MaxDepth *int `json:"max_depth"`
+}
+
+// ObjectQueryAttributeFilter matches objects having the attribute, or not having it if Exists is false.
+// Objects having it must also have all the keys of Value with equal values.
+type ObjectQueryAttributeFilter struct {
+	AttributeID
+	Exists *bool          `json:"exists"`
+	Value  map[string]any `json:"value"`
+}
+
+// ObjectQueryRegion is an axis aligned box, objects are matched by the position of their actual transform.
+type ObjectQueryRegion struct {
+	Min cmath.Vec3 `json:"min"`
+	Max cmath.Vec3 `json:"max"`
+}
+
+// ObjectQueryProjection selects fields and attribute values of the matched objects, the object_id is always returned.
+type ObjectQueryProjection struct {
+	Fields     []ObjectQueryField `json:"fields"`
+	Attributes []AttributeID      `json:"attributes"`
+}
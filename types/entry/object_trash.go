@@ -0,0 +1,19 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// ObjectTrashItem is a soft deleted object, it is kept with its children until it expires.
+// For a trashed world both WorldID and ObjectID are the world umid.
+type ObjectTrashItem struct {
+	ObjectID  umid.UMID  `db:"object_id" json:"object_id"`
+	WorldID   umid.UMID  `db:"world_id" json:"world_id"`
+	ParentID  umid.UMID  `db:"parent_id" json:"parent_id"`
+	Name      string     `db:"name" json:"name"`
+	DeletedBy *umid.UMID `db:"deleted_by" json:"deleted_by"`
+	DeletedAt time.Time  `db:"deleted_at" json:"deleted_at"`
+	ExpiresAt time.Time  `db:"expires_at" json:"expires_at"`
+}
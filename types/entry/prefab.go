@@ -0,0 +1,62 @@
+package entry
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// PrefabParameterKind is the kind of value of a prefab parameter.
+type PrefabParameterKind string
+
+const (
+	PrefabParameterText PrefabParameterKind = "text"
+	// PrefabParameterTexture is a hash of an uploaded image.
+	PrefabParameterTexture PrefabParameterKind = "texture"
+	// PrefabParameterLink is a http(s) url.
+	PrefabParameterLink PrefabParameterKind = "link"
+)
+
+// PrefabParameter is a slot of the prefab template, "{{name}}" placeholders are replaced with its value.
+type PrefabParameter struct {
+	Name        string              `json:"name"`
+	Kind        PrefabParameterKind `json:"kind"`
+	Description string              `json:"description,omitempty"`
+	Default     any                 `json:"default,omitempty"`
+	Required    bool                `json:"required,omitempty"`
+}
+
+type Prefab struct {
+	PrefabID    umid.UMID         `db:"prefab_id" json:"prefab_id"`
+	Name        string            `db:"name" json:"name"`
+	Description string            `db:"description" json:"description"`
+	OwnerID     umid.UMID         `db:"owner_id" json:"owner_id"`
+	Version     int               `db:"version" json:"version"`
+	Parameters  []PrefabParameter `db:"parameters" json:"parameters"`
+	CreatedAt   time.Time         `db:"created_at" json:"created_at"`
+	UpdatedAt   time.Time         `db:"updated_at" json:"updated_at"`
+}
+
+type PrefabVersion struct {
+	PrefabID umid.UMID `db:"prefab_id" json:"prefab_id"`
+	Version  int       `db:"version" json:"version"`
+	// json encoded object template
+	Template   json.RawMessage   `db:"template" json:"template"`
+	Parameters []PrefabParameter `db:"parameters" json:"parameters"`
+	CreatedAt  time.Time         `db:"created_at" json:"created_at"`
+}
+
+// PrefabInstance is an object instantiated from a prefab which follows its new versions.
+type PrefabInstance struct {
+	ObjectID        umid.UMID      `db:"object_id" json:"object_id"`
+	PrefabID        umid.UMID      `db:"prefab_id" json:"prefab_id"`
+	Version         int            `db:"version" json:"version"`
+	ParameterValues map[string]any `db:"parameter_values" json:"parameter_values"`
+	// user who linked the instance, it is rebuilt with the permissions of the user
+	CreatedBy *umid.UMID `db:"created_by" json:"created_by"`
+	// umids of the instance objects by umids of the template objects they were made from
+	ObjectIDs map[umid.UMID]umid.UMID `db:"object_ids" json:"object_ids"`
+	CreatedAt time.Time               `db:"created_at" json:"created_at"`
+	UpdatedAt time.Time               `db:"updated_at" json:"updated_at"`
+}
@@ -0,0 +1,32 @@
+package entry
+
+import (
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// RoleDefinition describes a custom role defined by a world, e.g. "moderator", "builder" or "speaker".
+type RoleDefinition struct {
+	Name        PermissionsRoleType `json:"name"`
+	Description string              `json:"description"`
+	// Other roles granted together with this one, e.g. "admin" or another custom role.
+	Includes []PermissionsRoleType `json:"includes"`
+}
+
+type RoleDefinitions struct {
+	Roles []*RoleDefinition `json:"roles"`
+}
+
+// UserObjectRole is a role assigned to a user on an object through entry.UserObject.
+type UserObjectRole struct {
+	ObjectID umid.UMID           `json:"object_id"`
+	Role     PermissionsRoleType `json:"role"`
+}
+
+// IsBuiltinRole reports whether the role is one of the predefined permission roles.
+func IsBuiltinRole(role PermissionsRoleType) bool {
+	switch role {
+	case PermissionAny, PermissionUser, PermissionUserOwner, PermissionAdmin, PermissionTargetUser:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,60 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type SearchDocumentKind string
+
+const (
+	SearchDocumentKindWorld  SearchDocumentKind = "world"
+	SearchDocumentKindObject SearchDocumentKind = "object"
+	SearchDocumentKindUser   SearchDocumentKind = "user"
+	SearchDocumentKindPlugin SearchDocumentKind = "plugin"
+)
+
+// SearchDocument is an indexed world, object, user or plugin with the rank of the match.
+type SearchDocument struct {
+	Kind         SearchDocumentKind `db:"kind" json:"kind"`
+	DocumentID   umid.UMID          `db:"document_id" json:"document_id"`
+	WorldID      *umid.UMID         `db:"world_id" json:"world_id,omitempty"`
+	ObjectTypeID *umid.UMID         `db:"object_type_id" json:"object_type_id,omitempty"`
+	OwnerID      *umid.UMID         `db:"owner_id" json:"owner_id,omitempty"`
+	Tags         []string           `db:"tags" json:"tags"`
+	Title        string             `db:"title" json:"title"`
+	Body         string             `db:"body" json:"body"`
+	Rank         float64            `db:"rank" json:"rank"`
+	UpdatedAt    time.Time          `db:"updated_at" json:"updated_at"`
+}
+
+// SearchQuery filters the documents, empty fields match everything.
+type SearchQuery struct {
+	Text          string
+	Kinds         []SearchDocumentKind
+	ObjectTypeIDs []umid.UMID
+	OwnerIDs      []umid.UMID
+	Tags          []string
+	WorldID       *umid.UMID
+	// Private documents are only found by admins of the private object.
+	ViewerID umid.UMID
+}
+
+// SearchCursor points to the last document of a page.
+type SearchCursor struct {
+	Rank       float64   `json:"rank"`
+	DocumentID umid.UMID `json:"document_id"`
+}
+
+type SearchFacetValue struct {
+	Value string `db:"value" json:"value"`
+	Count int    `db:"count" json:"count"`
+}
+
+type SearchFacets struct {
+	Kinds       []*SearchFacetValue `json:"kinds"`
+	ObjectTypes []*SearchFacetValue `json:"object_types"`
+	Owners      []*SearchFacetValue `json:"owners"`
+	Tags        []*SearchFacetValue `json:"tags"`
+}
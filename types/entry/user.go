@@ -16,16 +16,18 @@ type User struct {
 }
 
 type UserOptions struct {
-	IsGuest *bool `db:"is_guest" json:"is_guest"`
+	IsGuest            *bool               `db:"is_guest" json:"is_guest"`
+	PresenceVisibility *PresenceVisibility `db:"presence_visibility" json:"presence_visibility,omitempty"`
 }
 
 type UserProfile struct {
-	Name        *string `db:"name" json:"name"`
-	Bio         *string `db:"bio" json:"bio"`
-	Location    *string `db:"location" json:"location"`
-	AvatarHash  *string `db:"avatar_hash" json:"avatar_hash"`
-	ProfileLink *string `db:"profile_link" json:"profile_link"`
-	OnBoarded   *bool   `db:"onboarded" json:"onboarded"`
+	Name        *string     `db:"name" json:"name"`
+	Bio         *string     `db:"bio" json:"bio"`
+	Location    *string     `db:"location" json:"location"`
+	AvatarHash  *string     `db:"avatar_hash" json:"avatar_hash"`
+	ProfileLink *string     `db:"profile_link" json:"profile_link"`
+	OnBoarded   *bool       `db:"onboarded" json:"onboarded"`
+	Avatar      *UserAvatar `db:"avatar" json:"avatar,omitempty"`
 }
 
 type UserAttributeID struct {
@@ -0,0 +1,73 @@
+package entry
+
+import (
+	"fmt"
+	"regexp"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	UserAvatarMaxColors      = 16
+	UserAvatarMaxAccessories = 16
+)
+
+var (
+	userAvatarNameRegexp  = regexp.MustCompile(`^[a-z0-9_-]{1,64}$`)
+	userAvatarColorRegexp = regexp.MustCompile(`^#[0-9a-fA-F]{6}([0-9a-fA-F]{2})?$`)
+)
+
+// UserAvatar is the structured appearance of the user, stored as part of the profile.
+type UserAvatar struct {
+	ModelAsset3dID umid.UMID             `db:"model_asset_3d_id" json:"model_asset_3d_id"`
+	Colors         map[string]string     `db:"colors" json:"colors,omitempty"`
+	Accessories    []UserAvatarAccessory `db:"accessories" json:"accessories,omitempty"`
+	AnimationSet   string                `db:"animation_set" json:"animation_set,omitempty"`
+}
+
+type UserAvatarAccessory struct {
+	Slot      string    `db:"slot" json:"slot"`
+	Asset3dID umid.UMID `db:"asset_3d_id" json:"asset_3d_id"`
+}
+
+// Validate checks the format of the avatar, the referenced assets are not checked.
+func (a *UserAvatar) Validate() error {
+	if a.ModelAsset3dID == umid.Nil {
+		return fmt.Errorf("model asset 3d id is required")
+	}
+
+	if len(a.Colors) > UserAvatarMaxColors {
+		return fmt.Errorf("too many colors: %d > %d", len(a.Colors), UserAvatarMaxColors)
+	}
+	for part, color := range a.Colors {
+		if !userAvatarNameRegexp.MatchString(part) {
+			return fmt.Errorf("invalid color part: %q", part)
+		}
+		if !userAvatarColorRegexp.MatchString(color) {
+			return fmt.Errorf("invalid color for %q: %q", part, color)
+		}
+	}
+
+	if len(a.Accessories) > UserAvatarMaxAccessories {
+		return fmt.Errorf("too many accessories: %d > %d", len(a.Accessories), UserAvatarMaxAccessories)
+	}
+	slots := make(map[string]struct{}, len(a.Accessories))
+	for _, accessory := range a.Accessories {
+		if !userAvatarNameRegexp.MatchString(accessory.Slot) {
+			return fmt.Errorf("invalid accessory slot: %q", accessory.Slot)
+		}
+		if _, ok := slots[accessory.Slot]; ok {
+			return fmt.Errorf("duplicate accessory slot: %q", accessory.Slot)
+		}
+		slots[accessory.Slot] = struct{}{}
+		if accessory.Asset3dID == umid.Nil {
+			return fmt.Errorf("asset 3d id is required for accessory slot: %q", accessory.Slot)
+		}
+	}
+
+	if a.AnimationSet != "" && !userAvatarNameRegexp.MatchString(a.AnimationSet) {
+		return fmt.Errorf("invalid animation set: %q", a.AnimationSet)
+	}
+
+	return nil
+}
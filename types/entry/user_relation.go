@@ -0,0 +1,53 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type UserRelationType string
+
+const (
+	// UserRelationFriendRequest is pending until the target user accepts it.
+	UserRelationFriendRequest UserRelationType = "friend_request"
+	// UserRelationFriend is mutual and stored in both directions.
+	UserRelationFriend UserRelationType = "friend"
+	UserRelationFollow UserRelationType = "follow"
+	UserRelationBlock  UserRelationType = "block"
+)
+
+type UserRelationID struct {
+	SourceUserID umid.UMID        `db:"source_user_id" json:"source_user_id"`
+	TargetUserID umid.UMID        `db:"target_user_id" json:"target_user_id"`
+	RelationType UserRelationType `db:"relation_type" json:"relation_type"`
+}
+
+type UserRelation struct {
+	UserRelationID
+	CreatedAt time.Time `db:"created_at" json:"created_at"`
+}
+
+func NewUserRelationID(sourceUserID, targetUserID umid.UMID, relationType UserRelationType) UserRelationID {
+	return UserRelationID{
+		SourceUserID: sourceUserID,
+		TargetUserID: targetUserID,
+		RelationType: relationType,
+	}
+}
+
+// PresenceVisibility defines who can see which world the user is in.
+type PresenceVisibility string
+
+const (
+	PresenceVisibilityEveryone PresenceVisibility = "everyone"
+	PresenceVisibilityFriends  PresenceVisibility = "friends"
+	PresenceVisibilityNobody   PresenceVisibility = "nobody"
+)
+
+// UserPresence is derived from the world the user is currently in.
+type UserPresence struct {
+	UserID  umid.UMID  `json:"user_id"`
+	Online  bool       `json:"online"`
+	WorldID *umid.UMID `json:"world_id,omitempty"`
+}
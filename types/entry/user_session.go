@@ -0,0 +1,33 @@
+package entry
+
+import (
+	"time"
+
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type UserSession struct {
+	SessionID        umid.UMID `db:"session_id" json:"session_id"`
+	UserID           umid.UMID `db:"user_id" json:"user_id"`
+	RefreshTokenHash string    `db:"refresh_token_hash" json:"-"`
+	// hashes of the refresh tokens rotated before, the most recent last
+	PreviousRefreshTokenHashes []string   `db:"previous_refresh_token_hashes" json:"-"`
+	UserAgent                  string     `db:"user_agent" json:"user_agent"`
+	IPAddress                  string     `db:"ip_address" json:"ip_address"`
+	ExpiresAt                  time.Time  `db:"expires_at" json:"expires_at"`
+	RevokedAt                  *time.Time `db:"revoked_at" json:"revoked_at,omitempty"`
+	CreatedAt                  time.Time  `db:"created_at" json:"created_at"`
+	UpdatedAt                  time.Time  `db:"updated_at" json:"updated_at"`
+}
+
+// IsActive checks if the session is neither revoked nor expired.
+func (s *UserSession) IsActive() bool {
+	return s.RevokedAt == nil && time.Now().Before(s.ExpiresAt)
+}
+
+// UserSessionTokens are issued on login and every refresh of a session.
+type UserSessionTokens struct {
+	AccessToken  string    `json:"token"`
+	RefreshToken string    `json:"refresh_token"`
+	ExpiresAt    time.Time `json:"expires_at"`
+}
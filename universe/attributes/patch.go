@@ -0,0 +1,124 @@
+package attributes
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/modify"
+	"github.com/momentum-xyz/ubercontroller/utils/patch"
+)
+
+var ErrVersionConflict = errors.New("attribute version conflict")
+
+// Struct to use for API input of attribute patches.
+// Exactly one of Patch (RFC 6902 JSON Patch) or MergePatch (RFC 7396 JSON Merge Patch) has to be provided.
+type PatchBody struct {
+	QueryPluginAttribute
+	Patch      []patch.Operation `json:"patch"`
+	MergePatch map[string]any    `json:"merge_patch"`
+	// JSON pointers of arrays to append to instead of replacing them, merge patch only.
+	Append []string `json:"append"`
+	// JSON pointers of arrays to remove duplicates from, merge patch only.
+	Unique []string `json:"unique"`
+	// Version (ETag) of the attribute value the patch is based on, "If-Match" header takes precedence.
+	Version string `json:"version"`
+}
+
+// Struct to use for API output of patched attributes.
+type PatchOut struct {
+	Value   *entry.AttributeValue `json:"value"`
+	Version string                `json:"version"`
+}
+
+// BindPatchBody binds and validates patch from the request.
+func BindPatchBody(c *gin.Context) (*PatchBody, error) {
+	var body PatchBody
+	if err := c.ShouldBindJSON(&body); err != nil {
+		return nil, errors.WithMessage(err, "failed to bind json")
+	}
+	if (body.Patch == nil) == (body.MergePatch == nil) {
+		return nil, errors.New("either patch or merge_patch is required")
+	}
+	if body.Patch != nil && (len(body.Append) > 0 || len(body.Unique) > 0) {
+		return nil, errors.New("append and unique are only supported for merge_patch")
+	}
+	if ifMatch := c.GetHeader("If-Match"); ifMatch != "" {
+		body.Version = ifMatch
+	}
+	return &body, nil
+}
+
+// ModifyFn creates a modify function applying the patch to the current attribute value.
+// Returns ErrVersionConflict from the modify function if current value doesn't match the requested version.
+func (b *PatchBody) ModifyFn() (modify.Fn[entry.AttributePayload], error) {
+	triggers, err := patch.MergeTriggers(b.Append, b.Unique)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create merge triggers")
+	}
+
+	return func(current *entry.AttributePayload) (*entry.AttributePayload, error) {
+		if current == nil {
+			current = entry.NewAttributePayload(nil, nil)
+		}
+
+		var value map[string]any
+		if current.Value != nil {
+			value = *current.Value
+		}
+
+		if b.Version != "" {
+			etag, err := patch.ETag(value)
+			if err != nil {
+				return nil, errors.WithMessage(err, "failed to get current version")
+			}
+			if !patch.MatchETag(etag, b.Version) {
+				return nil, errors.Wrapf(ErrVersionConflict, "current version: %s", etag)
+			}
+		}
+
+		var res map[string]any
+		if b.Patch != nil {
+			res, err = patch.ApplyJSONPatch(value, b.Patch)
+		} else {
+			res, err = patch.ApplyMergePatch(value, b.MergePatch, triggers...)
+		}
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to apply patch")
+		}
+
+		newValue := entry.AttributeValue(res)
+		current.Value = &newValue
+
+		return current, nil
+	}, nil
+}
+
+// PatchErrorStatus returns the http status and error code for the error returned while patching an attribute.
+func PatchErrorStatus(err error) (int, string) {
+	switch {
+	case errors.Is(err, ErrVersionConflict):
+		return http.StatusConflict, "version_conflict"
+	case errors.Is(err, patch.ErrTestFailed):
+		return http.StatusConflict, "patch_test_failed"
+	case errors.Is(err, patch.ErrInvalidPatch):
+		return http.StatusUnprocessableEntity, "invalid_patch"
+	}
+	return http.StatusInternalServerError, "failed_to_upsert"
+}
+
+// SetETag sets "ETag" header for the attribute value and returns it.
+func SetETag(c *gin.Context, value *entry.AttributeValue) string {
+	var v map[string]any
+	if value != nil {
+		v = *value
+	}
+	etag, err := patch.ETag(v)
+	if err != nil {
+		return ""
+	}
+	c.Header("ETag", etag)
+	return etag
+}
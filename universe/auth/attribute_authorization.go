@@ -44,6 +44,26 @@ func CheckAttributePermissions[ID comparable, S AttributePermissionsAuthorizer[I
 	userID umid.UMID, // The user executing
 	opType operationType,
 
+) (bool, error) {
+	return CheckAttributeRolesPermissions[ID](
+		ctx, attrType, attrStore, targetID,
+		func() ([]entry.PermissionsRoleType, error) {
+			return attrStore.GetUserRoles(ctx, attrType, targetID, userID)
+		},
+		opType,
+	)
+}
+
+// Same as CheckAttributePermissions, but roles of the user executing are given by rolesFn,
+// so they can be retrieved once when checking many attributes of the same target.
+// rolesFn is not called for attributes anyone is allowed to operate on.
+func CheckAttributeRolesPermissions[ID comparable](
+	ctx context.Context,
+	attrType entry.AttributeType,
+	attrStore universe.AttributeOptionsGetter[ID],
+	targetID ID,
+	rolesFn func() ([]entry.PermissionsRoleType, error),
+	opType operationType,
 ) (bool, error) {
 	permissions, err := getPermissions[ID](ctx, attrType, attrStore, targetID)
 	if err != nil {
@@ -58,24 +78,22 @@ func CheckAttributePermissions[ID comparable, S AttributePermissionsAuthorizer[I
 		return true, nil
 	}
 
-	roles, err := attrStore.GetUserRoles(ctx, attrType, targetID, userID)
+	roles, err := rolesFn()
 	if err != nil {
 		return false, fmt.Errorf("get user roles: %w", err)
 	}
 	return hasRole(roles, allowedRoles), nil
 }
 
-// Check if user is authorized to read all attributes.
-func CheckReadAllPermissions[ID comparable, T AttributePermissionsAuthorizer[ID]](
-	ctx context.Context,
+// Check if a user with the given roles is authorized to read all attributes of a type.
+func CheckReadAllPermissions(
 	attrType entry.AttributeType,
-	attrStore T,
-	userID umid.UMID, // The user executing
+	userRoles []entry.PermissionsRoleType, // Effective roles of the user executing, see universe.UserObjects.GetUserRoles
 ) (bool, error) {
 	// This is an edge case, that is a big TODO to do properly.
 	// Would need to check each individual attribute, since options can be overridden.
 	// And return a filtered list??
-	// For now, to keep stuff working: you need read: "user" permission on the attribute type
+	// For now, to keep stuff working: you need read permission on the attribute type,
 	// can not be overriden.
 	var permissions *entry.PermissionsAttributeOption
 	options := attrType.Options
@@ -93,8 +111,7 @@ func CheckReadAllPermissions[ID comparable, T AttributePermissionsAuthorizer[ID]
 	if slices.Contains(allowedRoles, entry.PermissionAny) {
 		return true, nil
 	}
-	// assume the use always has the 'user' role (no other user types implemented yet)
-	return hasRole([]entry.PermissionsRoleType{entry.PermissionUser}, allowedRoles), nil
+	return hasRole(userRoles, allowedRoles), nil
 }
 
 func getPermissions[ID comparable](
@@ -77,6 +77,32 @@ func TestCheckAttributePermissions(t *testing.T) {
 			want:    false,
 			wantErr: false,
 		},
+		{
+			name: "custom role write",
+			permissions: &entry.PermissionsAttributeOption{
+				Read:  "any",
+				Write: "admin+moderator",
+			},
+			userRoles: []entry.PermissionsRoleType{
+				entry.PermissionUser, "moderator",
+			},
+			opType:  WriteOperation,
+			want:    true,
+			wantErr: false,
+		},
+		{
+			name: "other custom role write",
+			permissions: &entry.PermissionsAttributeOption{
+				Read:  "any",
+				Write: "admin+moderator",
+			},
+			userRoles: []entry.PermissionsRoleType{
+				entry.PermissionUser, "builder",
+			},
+			opType:  WriteOperation,
+			want:    false,
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -172,3 +198,103 @@ func TestPermissionsFromOptions(t *testing.T) {
 		})
 	}
 }
+
+func TestCheckReadAllPermissions(t *testing.T) {
+	tests := []struct {
+		name        string
+		permissions *entry.PermissionsAttributeOption
+		userRoles   []entry.PermissionsRoleType
+		want        bool
+	}{
+		{
+			name:        "any",
+			permissions: &entry.PermissionsAttributeOption{Read: "any", Write: "admin"},
+			want:        true,
+		},
+		{
+			name:        "user",
+			permissions: &entry.PermissionsAttributeOption{Read: "user", Write: "admin"},
+			userRoles:   []entry.PermissionsRoleType{entry.PermissionUser},
+			want:        true,
+		},
+		{
+			name:        "admin only",
+			permissions: &entry.PermissionsAttributeOption{Read: "admin", Write: "admin"},
+			userRoles:   []entry.PermissionsRoleType{entry.PermissionUser},
+			want:        false,
+		},
+		{
+			name:        "custom role",
+			permissions: &entry.PermissionsAttributeOption{Read: "admin+speaker", Write: "admin"},
+			userRoles:   []entry.PermissionsRoleType{entry.PermissionUser, "speaker"},
+			want:        true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aType := entry.AttributeType{
+				Options: &entry.AttributeOptions{
+					"permissions": tt.permissions,
+				},
+			}
+			got, err := CheckReadAllPermissions(aType, tt.userRoles)
+			if err != nil {
+				t.Errorf("CheckReadAllPermissions() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("CheckReadAllPermissions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExpandRoles(t *testing.T) {
+	definitions := []*entry.RoleDefinition{
+		{Name: "moderator", Includes: []entry.PermissionsRoleType{"speaker"}},
+		{Name: "speaker"},
+		{Name: "builder", Includes: []entry.PermissionsRoleType{entry.PermissionAdmin}},
+		{Name: "loop_a", Includes: []entry.PermissionsRoleType{"loop_b"}},
+		{Name: "loop_b", Includes: []entry.PermissionsRoleType{"loop_a"}},
+	}
+
+	tests := []struct {
+		name  string
+		roles []entry.PermissionsRoleType
+		want  []entry.PermissionsRoleType
+	}{
+		{
+			name:  "no roles",
+			roles: nil,
+			want:  []entry.PermissionsRoleType{},
+		},
+		{
+			name:  "transitive",
+			roles: []entry.PermissionsRoleType{entry.PermissionUser, "moderator"},
+			want:  []entry.PermissionsRoleType{entry.PermissionUser, "moderator", "speaker"},
+		},
+		{
+			name:  "includes admin",
+			roles: []entry.PermissionsRoleType{"builder"},
+			want:  []entry.PermissionsRoleType{"builder", entry.PermissionAdmin},
+		},
+		{
+			name:  "cycle",
+			roles: []entry.PermissionsRoleType{"loop_a"},
+			want:  []entry.PermissionsRoleType{"loop_a", "loop_b"},
+		},
+		{
+			name:  "undefined role",
+			roles: []entry.PermissionsRoleType{"ghost", "ghost"},
+			want:  []entry.PermissionsRoleType{"ghost"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ExpandRoles(tt.roles, definitions)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ExpandRoles() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,41 @@
+package auth
+
+// Resolving custom (per world) roles.
+
+import (
+	"golang.org/x/exp/slices"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+)
+
+// Expand the given roles with all roles they (transitively) include according to the role definitions.
+func ExpandRoles(
+	roles []entry.PermissionsRoleType,
+	definitions []*entry.RoleDefinition,
+) []entry.PermissionsRoleType {
+	includes := make(map[entry.PermissionsRoleType][]entry.PermissionsRoleType, len(definitions))
+	for _, definition := range definitions {
+		if definition == nil {
+			continue
+		}
+		includes[definition.Name] = append(includes[definition.Name], definition.Includes...)
+	}
+
+	result := make([]entry.PermissionsRoleType, 0, len(roles))
+	queue := append([]entry.PermissionsRoleType{}, roles...)
+	for len(queue) > 0 {
+		role := queue[0]
+		queue = queue[1:]
+		if slices.Contains(result, role) {
+			continue
+		}
+		result = append(result, role)
+		queue = append(queue, includes[role]...)
+	}
+	return result
+}
+
+// Check if any of the user roles is one of the allowed roles.
+func HasRole(userRoles []entry.PermissionsRoleType, allowedRoles ...entry.PermissionsRoleType) bool {
+	return hasRole(userRoles, allowedRoles)
+}
@@ -0,0 +1,175 @@
+package auth
+
+// Scopes of API keys.
+//
+// A scope has the form "<resource>:<action>[:<target>]", e.g. "objects:write",
+// "attributes:read:<pluginID>" or "worlds:admin:<worldID>".
+// Without a target the scope applies to all resources of the kind.
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+)
+
+type ScopeAction string
+
+const (
+	ScopeActionRead  ScopeAction = "read"
+	ScopeActionWrite ScopeAction = "write"
+	ScopeActionAdmin ScopeAction = "admin"
+)
+
+const (
+	ScopeResourceObjects    = "objects"
+	ScopeResourceAttributes = "attributes"
+	ScopeResourceWorlds     = "worlds"
+	ScopeResourceUsers      = "users"
+	ScopeResourceNode       = "node"
+	ScopeResourcePlugins    = "plugins"
+	ScopeResourceMedia      = "media"
+	ScopeResourceAssets2d   = "assets-2d"
+	ScopeResourceAssets3d   = "assets-3d"
+)
+
+var scopeResources = []string{
+	ScopeResourceObjects,
+	ScopeResourceAttributes,
+	ScopeResourceWorlds,
+	ScopeResourceUsers,
+	ScopeResourceNode,
+	ScopeResourcePlugins,
+	ScopeResourceMedia,
+	ScopeResourceAssets2d,
+	ScopeResourceAssets3d,
+}
+
+// higher actions include the lower ones
+var scopeActionLevels = map[ScopeAction]int{
+	ScopeActionRead:  1,
+	ScopeActionWrite: 2,
+	ScopeActionAdmin: 3,
+}
+
+type Scope struct {
+	Resource string
+	Action   ScopeAction
+	Target   string
+}
+
+func NewScope(resource string, action ScopeAction, target string) Scope {
+	return Scope{
+		Resource: resource,
+		Action:   action,
+		Target:   target,
+	}
+}
+
+func ParseScope(s string) (Scope, error) {
+	parts := strings.SplitN(s, ":", 3)
+	if len(parts) < 2 {
+		return Scope{}, errors.Errorf("invalid scope format: %q", s)
+	}
+
+	scope := Scope{
+		Resource: parts[0],
+		Action:   ScopeAction(parts[1]),
+	}
+	if len(parts) == 3 {
+		if parts[2] == "" {
+			return Scope{}, errors.Errorf("empty scope target: %q", s)
+		}
+		scope.Target = parts[2]
+	}
+
+	if !slices.Contains(scopeResources, scope.Resource) {
+		return Scope{}, errors.Errorf("unknown scope resource: %q", scope.Resource)
+	}
+	if _, ok := scopeActionLevels[scope.Action]; !ok {
+		return Scope{}, errors.Errorf("unknown scope action: %q", scope.Action)
+	}
+
+	return scope, nil
+}
+
+// ParseScopes validates the given scopes.
+func ParseScopes(scopes []string) ([]Scope, error) {
+	result := make([]Scope, 0, len(scopes))
+	for _, s := range scopes {
+		scope, err := ParseScope(s)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, scope)
+	}
+	return result, nil
+}
+
+func (s Scope) String() string {
+	if s.Target == "" {
+		return s.Resource + ":" + string(s.Action)
+	}
+	return s.Resource + ":" + string(s.Action) + ":" + s.Target
+}
+
+// Grants checks if the scope allows the required one.
+func (s Scope) Grants(required Scope) bool {
+	if s.Resource != required.Resource {
+		return false
+	}
+	if scopeActionLevels[s.Action] < scopeActionLevels[required.Action] {
+		return false
+	}
+	return s.Target == "" || s.Target == required.Target
+}
+
+// GrantedScope returns the first of the required scopes allowed by the granted ones.
+// Invalid granted scopes are ignored.
+func GrantedScope(granted []string, required ...Scope) (Scope, bool) {
+	for _, r := range required {
+		for _, g := range granted {
+			scope, err := ParseScope(g)
+			if err != nil {
+				continue
+			}
+			if scope.Grants(r) {
+				return r, true
+			}
+		}
+	}
+	return Scope{}, false
+}
+
+// ScopeActionFromMethod maps http method of a request to the action it performs.
+func ScopeActionFromMethod(method string) ScopeAction {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return ScopeActionRead
+	default:
+		return ScopeActionWrite
+	}
+}
+
+// ScopeResourceFromRoute returns the resource of the api route (e.g. "/api/v4/objects/:objectID/attributes"),
+// attribute routes of any kind are considered to be the "attributes" resource.
+func ScopeResourceFromRoute(route string) (string, bool) {
+	segments := strings.Split(strings.Trim(route, "/"), "/")
+	// skip "api/v<N>"
+	if len(segments) < 3 || segments[0] != "api" {
+		return "", false
+	}
+	segments = segments[2:]
+
+	for _, segment := range segments {
+		if segment == "attributes" || segment == "attributes-with-children" {
+			return ScopeResourceAttributes, true
+		}
+	}
+
+	if !slices.Contains(scopeResources, segments[0]) {
+		return "", false
+	}
+	return segments[0], true
+}
@@ -0,0 +1,119 @@
+package auth
+
+import (
+	"testing"
+)
+
+func TestParseScope(t *testing.T) {
+	tests := []struct {
+		scope   string
+		want    Scope
+		wantErr bool
+	}{
+		{scope: "objects:write", want: NewScope(ScopeResourceObjects, ScopeActionWrite, "")},
+		{scope: "attributes:read:plugin", want: NewScope(ScopeResourceAttributes, ScopeActionRead, "plugin")},
+		{scope: "worlds:admin:world", want: NewScope(ScopeResourceWorlds, ScopeActionAdmin, "world")},
+		{scope: "objects", wantErr: true},
+		{scope: "objects:delete", wantErr: true},
+		{scope: "unknown:read", wantErr: true},
+		{scope: "objects:read:", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.scope, func(t *testing.T) {
+			got, err := ParseScope(tt.scope)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseScope() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("ParseScope() got = %v, want %v", got, tt.want)
+			}
+			if !tt.wantErr && got.String() != tt.scope {
+				t.Errorf("String() got = %v, want %v", got.String(), tt.scope)
+			}
+		})
+	}
+}
+
+func TestGrantedScope(t *testing.T) {
+	tests := []struct {
+		name     string
+		granted  []string
+		required []Scope
+		want     bool
+	}{
+		{
+			name:     "exact",
+			granted:  []string{"objects:write"},
+			required: []Scope{NewScope(ScopeResourceObjects, ScopeActionWrite, "")},
+			want:     true,
+		},
+		{
+			name:     "write includes read",
+			granted:  []string{"objects:write"},
+			required: []Scope{NewScope(ScopeResourceObjects, ScopeActionRead, "object")},
+			want:     true,
+		},
+		{
+			name:     "read excludes write",
+			granted:  []string{"objects:read"},
+			required: []Scope{NewScope(ScopeResourceObjects, ScopeActionWrite, "")},
+			want:     false,
+		},
+		{
+			name:     "other target",
+			granted:  []string{"attributes:read:plugin1"},
+			required: []Scope{NewScope(ScopeResourceAttributes, ScopeActionRead, "plugin2")},
+			want:     false,
+		},
+		{
+			name:     "targeted scope for untargeted request",
+			granted:  []string{"attributes:read:plugin1"},
+			required: []Scope{NewScope(ScopeResourceAttributes, ScopeActionRead, "")},
+			want:     false,
+		},
+		{
+			name:    "any of required",
+			granted: []string{"worlds:admin:world"},
+			required: []Scope{
+				NewScope(ScopeResourceObjects, ScopeActionWrite, ""),
+				NewScope(ScopeResourceWorlds, ScopeActionAdmin, "world"),
+			},
+			want: true,
+		},
+		{
+			name:     "other resource",
+			granted:  []string{"users:admin"},
+			required: []Scope{NewScope(ScopeResourceObjects, ScopeActionRead, "")},
+			want:     false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, got := GrantedScope(tt.granted, tt.required...); got != tt.want {
+				t.Errorf("GrantedScope() got = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestScopeResourceFromRoute(t *testing.T) {
+	tests := []struct {
+		route string
+		want  string
+		ok    bool
+	}{
+		{route: "/api/v4/objects/:objectID", want: ScopeResourceObjects, ok: true},
+		{route: "/api/v4/objects/:objectID/attributes/sub", want: ScopeResourceAttributes, ok: true},
+		{route: "/api/v4/worlds/:objectID/explore", want: ScopeResourceWorlds, ok: true},
+		{route: "/api/v4/skybox/styles", ok: false},
+		{route: "/health", ok: false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.route, func(t *testing.T) {
+			got, ok := ScopeResourceFromRoute(tt.route)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("ScopeResourceFromRoute() got = %v, %v, want %v, %v", got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
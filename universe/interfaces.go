@@ -106,6 +106,12 @@ type Node interface {
 	GetPlugins() Plugins
 
 	GetUserObjects() UserObjects
+	GetAttributeSubscriptions() AttributeSubscriptions
+	GetUserSessions() UserSessions
+	GetAPIKeys() APIKeys
+	GetModeration() Moderation
+	GetUserRelations() UserRelations
+	GetEditJournal() EditJournal
 
 	GetNodeAttributes() NodeAttributes
 	GetUserAttributes() UserAttributes
@@ -157,6 +163,9 @@ type World interface {
 
 	GetCalendar() Calendar
 
+	// SetObjectsLOD switches spawned objects of the level of detail group to the level for the user.
+	SetObjectsLOD(userID umid.UMID, group string, level uint32) error
+
 	WriteInfluxPoint(point *influxWrite.Point) error
 
 	TempSetSkybox(msg *websocket.PreparedMessage)
@@ -211,6 +220,9 @@ type Object interface {
 
 	Update(recursive bool) error
 	UpdateChildrenPosition(recursive bool) error
+	PreviewChildrenPosition(
+		objectTypeID umid.UMID, placement *entry.ObjectChildPlacement, extra int,
+	) ([]*entry.ObjectChildPosition, error)
 
 	CreateObject(objectID umid.UMID) (Object, error)
 	GetObject(objectID umid.UMID, recursive bool) (Object, bool)
@@ -228,13 +240,26 @@ type Object interface {
 
 	Send(msg *websocket.PreparedMessage, recursive bool) error
 
+	GetObjectDefinition() *posbus.ObjectDefinition
+	UpdateSpawnMessage() error
 	SendSpawnMessage(sendFn func(msg *websocket.PreparedMessage) error, recursive bool)
 	SendAttributes(sendFn func(*websocket.PreparedMessage), recursive bool)
 	SendAllAutoAttributes(sendFn func(msg *websocket.PreparedMessage) error, recursive bool)
 
-	LockUIObject(user User, state uint32) bool
+	// Lock acquires or renews the edit lease of the user, subtree makes it cover all children.
+	// It fails if the object, its ancestors subtree or, for subtree, any of its children is leased by another user,
+	// the lease preventing it is returned then.
+	Lock(userID umid.UMID, subtree bool) (*entry.ObjectLock, bool)
+	// Unlock releases the lease of the user, force releases the lease of any user.
+	Unlock(userID umid.UMID, force bool) (*entry.ObjectLock, bool)
+	// UnlockExpired releases the lease if it wasn't renewed in time.
+	UnlockExpired(now time.Time) (*entry.ObjectLock, bool)
+	// GetLock returns the active lease of the object, inherited includes the subtree lease of its ancestors.
+	GetLock(inherited bool) *entry.ObjectLock
 	GetLockUserID() umid.UMID
 	IsLockedByUser(user User) bool
+	// SendLockState sends the lock owner of the object, and of its children if recursive, to the world.
+	SendLockState(recursive bool) error
 
 	GetCreatedAt() time.Time
 	GetUpdatedAt() time.Time
@@ -254,6 +279,7 @@ type User interface {
 	SetUserType(userType UserType, updateDB bool) error
 
 	GetProfile() *entry.UserProfile
+	SetProfile(profile *entry.UserProfile)
 
 	GetTransform() *cmath.TransformNoScale
 	SetTransform(cmath.TransformNoScale)
@@ -294,6 +320,13 @@ type UserObjects interface {
 	GetObjectIndirectAdmins(objectID umid.UMID) ([]*umid.UMID, bool)
 	CheckIsIndirectAdmin(userObjectID entry.UserObjectID) (bool, error)
 
+	// Effective roles of the user on the object, including inherited and included roles.
+	GetUserRoles(userObjectID entry.UserObjectID) ([]entry.PermissionsRoleType, error)
+	// Roles assigned to the user on the object and its ancestors, nearest object first.
+	GetAssignedRoles(userObjectID entry.UserObjectID) ([]entry.UserObjectRole, error)
+	// Custom roles defined by the world the object belongs to.
+	GetRoleDefinitions(objectID umid.UMID) ([]*entry.RoleDefinition, error)
+
 	Upsert(
 		userObjectID entry.UserObjectID, modifyFn modify.Fn[entry.UserObjectValue], updateDB bool,
 	) (*entry.UserObjectValue, error)
@@ -306,6 +339,138 @@ type UserObjects interface {
 	RemoveMany(userObjectIDs []entry.UserObjectID, updateDB bool) (bool, error)
 }
 
+// UserSessions manages login sessions of users with their access and refresh tokens.
+type UserSessions interface {
+	// Create starts a new session for the user and issues tokens for it.
+	Create(userID umid.UMID, userAgent, ipAddress string) (*entry.UserSession, *entry.UserSessionTokens, error)
+	// Refresh rotates the refresh token of the session and issues a new access token.
+	// Reusing an already rotated refresh token revokes the whole session.
+	Refresh(refreshToken string) (*entry.UserSession, *entry.UserSessionTokens, error)
+
+	GetActive(userID umid.UMID) ([]*entry.UserSession, error)
+	IsActive(sessionID umid.UMID) (bool, error)
+
+	// Revoke the session and disconnect websocket connections authenticated with it.
+	Revoke(sessionID umid.UMID) error
+	// RevokeAll sessions of the user ("log out everywhere").
+	RevokeAll(userID umid.UMID) error
+
+	AddConnection(sessionID umid.UMID, user User)
+	RemoveConnection(user User)
+}
+
+// APIKeys are issued by node admins for server-to-server access to the REST api.
+type APIKeys interface {
+	// Create returns the key record and the key itself, only its hash is stored.
+	Create(name string, scopes []string, createdBy umid.UMID, expiresAt time.Time) (*entry.APIKey, string, error)
+	// Verify returns the active api key record for the given key.
+	Verify(key string) (*entry.APIKey, error)
+
+	GetAll() ([]*entry.APIKey, error)
+	Revoke(apiKeyID umid.UMID) error
+
+	GetAuditEntries(apiKeyID umid.UMID, limit uint) ([]*entry.APIKeyAuditEntry, error)
+	// Audit records a request made with the api key and updates the key last usage.
+	Audit(auditEntry *entry.APIKeyAuditEntry)
+}
+
+// UserRelations is the social graph of users (friends, follows and blocks) with presence of connected users.
+type UserRelations interface {
+	// RequestFriendship sends a friend request, a pending request from the target user is accepted instead.
+	// Returns the resulting relation type.
+	RequestFriendship(userID, targetUserID umid.UMID) (entry.UserRelationType, error)
+	AcceptFriendship(userID, requesterID umid.UMID) error
+	// RemoveFriendship unfriends the users or declines (cancels) a pending request between them.
+	RemoveFriendship(userID, otherUserID umid.UMID) error
+	GetFriends(userID umid.UMID) ([]*entry.UserRelation, error)
+	// GetFriendRequests returns incoming and outgoing pending requests.
+	GetFriendRequests(userID umid.UMID) ([]*entry.UserRelation, []*entry.UserRelation, error)
+
+	Follow(userID, targetUserID umid.UMID) error
+	Unfollow(userID, targetUserID umid.UMID) error
+	GetFollowing(userID umid.UMID) ([]*entry.UserRelation, error)
+	GetFollowers(userID umid.UMID) ([]*entry.UserRelation, error)
+
+	// Block removes all other relations between the users.
+	Block(userID, targetUserID umid.UMID) error
+	Unblock(userID, targetUserID umid.UMID) error
+	GetBlocked(userID umid.UMID) ([]*entry.UserRelation, error)
+
+	// GetPresence returns the presence of the user as seen by the viewer, hidden presence is reported as offline.
+	GetPresence(viewerID, userID umid.UMID) (*entry.UserPresence, error)
+	GetOnlineUser(userID umid.UMID) (User, bool)
+	// UserEnteredWorld updates the presence of the user and notifies online friends.
+	UserEnteredWorld(user User, world World)
+	UserDisconnected(user User)
+}
+
+// Moderation of users by node and world admins.
+// A nil world id means the whole node.
+type Moderation interface {
+	Loader
+
+	// Kick removes the user from the world, the user stays connected.
+	Kick(world World, userID umid.UMID) (bool, error)
+
+	// Ban kicks the user and prevents joining the world, node-wide bans also revoke all user sessions.
+	Ban(userID umid.UMID, worldID *umid.UMID, reason string, createdBy umid.UMID, expiresAt *time.Time) (
+		*entry.UserBan, error,
+	)
+	Unban(banID umid.UMID) error
+	GetBans(worldID *umid.UMID) []*entry.UserBan
+	// GetBan returns an active ban preventing the user from joining the world (or the node if nil).
+	GetBan(userID umid.UMID, worldID *umid.UMID) (*entry.UserBan, bool)
+
+	// Mute silences chat and high-fives of the user.
+	Mute(userID umid.UMID, worldID *umid.UMID, reason string, createdBy umid.UMID, expiresAt *time.Time) (
+		*entry.UserMute, error,
+	)
+	Unmute(muteID umid.UMID) error
+	GetMutes(worldID *umid.UMID) []*entry.UserMute
+	GetMute(userID umid.UMID, worldID *umid.UMID) (*entry.UserMute, bool)
+
+	Report(reporterID, targetUserID umid.UMID, worldID *umid.UMID, reason string) (*entry.UserReport, error)
+	GetReports(status entry.UserReportStatus, limit uint) ([]*entry.UserReport, error)
+	ResolveReport(reportID umid.UMID, status entry.UserReportStatus, resolvedBy umid.UMID, resolution string) (
+		*entry.UserReport, error,
+	)
+}
+
+// EditJournal records world edits of users with their inverse, per user and world, so they can be undone and redone.
+// Edits made between Begin and Commit, like a gesture, are undone and redone together.
+// Journals of users who stopped editing the world are dropped after a while.
+type EditJournal interface {
+	// Snapshot returns a change with the current state of the target, it's passed to Record after the edit.
+	Snapshot(target entry.EditTarget) (*entry.EditChange, error)
+	// Record completes the change with the current state of its target and adds it to the journal of the user.
+	Record(userID umid.UMID, worldID umid.UMID, change *entry.EditChange) error
+
+	Begin(userID umid.UMID, worldID umid.UMID)
+	Commit(userID umid.UMID, worldID umid.UMID)
+
+	// Undo reverts the last transaction of the user in the world.
+	// Nothing is reverted if any target was changed by someone else since, the conflicting changes are returned instead.
+	Undo(userID umid.UMID, worldID umid.UMID) (*entry.EditTransaction, []*entry.EditChange, error)
+	// Redo reapplies the last undone transaction, conflicts are handled the same way as by Undo.
+	Redo(userID umid.UMID, worldID umid.UMID) (*entry.EditTransaction, []*entry.EditChange, error)
+	// GetJournal returns transactions which can be undone and redone, the most recent first.
+	GetJournal(userID umid.UMID, worldID umid.UMID) ([]*entry.EditTransaction, []*entry.EditTransaction)
+}
+
+// AttributeSubscriptions keeps track of users explicitly subscribed to attribute changes.
+type AttributeSubscriptions interface {
+	// Subscribe checks user read permissions on the attribute and sends its current value.
+	Subscribe(user User, subscriptionID entry.AttributeSubscriptionID) error
+	Unsubscribe(user User, subscriptionID entry.AttributeSubscriptionID)
+	UnsubscribeAll(user User)
+
+	// Publish sends the change to all users subscribed to the attribute.
+	Publish(
+		subscriptionID entry.AttributeSubscriptionID, changeType posbus.AttributeChangeType,
+		value *entry.AttributeValue,
+	) error
+}
+
 type AttributeOptionsGetter[ID comparable] interface {
 	// Get the options set directly on this object.
 	GetOptions(attributeID ID) (*entry.AttributeOptions, bool)
@@ -353,6 +518,15 @@ type ObjectAttributes interface {
 	LoadSaver
 	Attributes[entry.AttributeID]
 
+	// Same as Upsert, UpdateValue and Remove, but the change is recorded in history as made by the given user.
+	UpsertByUser(
+		userID umid.UMID, attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributePayload], updateDB bool,
+	) (*entry.AttributePayload, error)
+	UpdateValueByUser(
+		userID umid.UMID, attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributeValue], updateDB bool,
+	) (*entry.AttributeValue, error)
+	RemoveByUser(userID umid.UMID, attributeID entry.AttributeID, updateDB bool) (bool, error)
+
 	GetAll() map[entry.AttributeID]*entry.AttributePayload
 
 	Len() int
@@ -2,6 +2,10 @@ package api
 
 import (
 	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
 	"fmt"
 	"strings"
 	"time"
@@ -60,13 +64,6 @@ func GetUserIDFromToken(token jwt.Token) (umid.UMID, error) {
 	return userID, nil
 }
 
-func GenerateChallenge(wallet string) (string, error) {
-	return fmt.Sprintf(
-		"Please sign this message with the private key for address %s to prove that you own it. %s",
-		wallet, umid.New().String(),
-	), nil
-}
-
 func VerifyPolkadotSignature(wallet, challenge, signature string) (bool, error) {
 	pub, err := schnorrkel.NewPublicKeyFromHex(wallet)
 	if err != nil {
@@ -119,28 +116,133 @@ func VerifyEthereumSignature(address string, challenge string, signature string)
 	return true, nil
 }
 
-// CreateJWTToken saves a jwt token with the given userID as subject
-// and signed with the given secret
-func CreateJWTToken(userID umid.UMID) (string, error) {
-	claims := jwt.StandardClaims{
-		IssuedAt:  time.Now().Unix(),
-		ExpiresAt: time.Now().Add(7 * 24 * time.Hour).Unix(),
-		Issuer:    "ubercontroller",
-		Subject:   userID.String(),
+type accessTokenClaims struct {
+	jwt.StandardClaims
+	SessionID string `json:"sid,omitempty"`
+}
+
+// CreateAccessToken creates a short-lived jwt token with the given userID as subject,
+// bound to the given user session and signed with the node jwt secret.
+func CreateAccessToken(userID umid.UMID, sessionID umid.UMID, ttl time.Duration) (string, time.Time, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+	claims := accessTokenClaims{
+		StandardClaims: jwt.StandardClaims{
+			IssuedAt:  now.Unix(),
+			ExpiresAt: expiresAt.Unix(),
+			Issuer:    "ubercontroller",
+			Subject:   userID.String(),
+		},
+		SessionID: sessionID.String(),
 	}
 
 	newJwt := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 	secret, err := GetJWTSecret()
 	if err != nil {
-		return "", errors.WithMessage(err, "failed to get jwt secret")
+		return "", time.Time{}, errors.WithMessage(err, "failed to get jwt secret")
 	}
 
 	signedString, err := newJwt.SignedString(secret)
 	if err != nil {
-		return "", errors.WithMessage(err, "failed to sign token")
+		return "", time.Time{}, errors.WithMessage(err, "failed to sign token")
+	}
+
+	return signedString, expiresAt, nil
+}
+
+// GetSessionIDFromToken returns umid.Nil for (legacy) tokens not bound to a session.
+func GetSessionIDFromToken(token jwt.Token) (umid.UMID, error) {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return umid.Nil, errors.New("failed to get token claims")
+	}
+	sid := utils.GetFromAnyMap(claims, "sid", "")
+	if sid == "" {
+		return umid.Nil, nil
 	}
+	sessionID, err := umid.Parse(sid)
+	if err != nil {
+		return umid.Nil, errors.WithMessage(err, "failed to parse session umid")
+	}
+	return sessionID, nil
+}
+
+func GetSessionIDFromContext(c *gin.Context) (umid.UMID, error) {
+	token, err := GetTokenFromContext(c)
+	if err != nil {
+		return umid.Nil, errors.WithMessage(err, "failed to get token from context")
+	}
+	return GetSessionIDFromToken(token)
+}
+
+// GenerateRefreshToken returns a new opaque refresh token for the session and its hash to store.
+func GenerateRefreshToken(sessionID umid.UMID) (string, string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", "", errors.WithMessage(err, "failed to generate secret")
+	}
+	token := sessionID.String() + "." + base64.RawURLEncoding.EncodeToString(secret)
+	return token, HashRefreshToken(token), nil
+}
 
-	return signedString, nil
+func HashRefreshToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
+
+// ParseRefreshToken returns the session the refresh token was issued for.
+func ParseRefreshToken(token string) (umid.UMID, error) {
+	sid, _, ok := strings.Cut(token, ".")
+	if !ok {
+		return umid.Nil, errors.New("invalid refresh token format")
+	}
+	sessionID, err := umid.Parse(sid)
+	if err != nil {
+		return umid.Nil, errors.WithMessage(err, "failed to parse session umid")
+	}
+	return sessionID, nil
+}
+
+// GenerateAPIKey returns a new opaque api key and its hash to store, keys have the same format as refresh tokens.
+func GenerateAPIKey(apiKeyID umid.UMID) (string, string, error) {
+	return GenerateRefreshToken(apiKeyID)
+}
+
+func HashAPIKey(key string) string {
+	return HashRefreshToken(key)
+}
+
+// ParseAPIKey returns the umid of the api key record.
+func ParseAPIKey(key string) (umid.UMID, error) {
+	apiKeyID, err := ParseRefreshToken(key)
+	if err != nil {
+		return umid.Nil, errors.New("invalid api key format")
+	}
+	return apiKeyID, nil
+}
+
+func GetAPIKeyFromRequest(c *gin.Context) string {
+	return c.GetHeader(APIKeyHeader)
+}
+
+// GetAPIKeyFromContext returns nil if the request is not authenticated with an api key.
+func GetAPIKeyFromContext(c *gin.Context) *entry.APIKey {
+	value, ok := c.Get(APIKeyContextKey)
+	if !ok {
+		return nil
+	}
+	return utils.GetFromAny[*entry.APIKey](value, nil)
+}
+
+// NewAPIKeyToken returns a token acting as the api key creator,
+// so handlers can get the user umid from context the same way as for user tokens.
+func NewAPIKeyToken(apiKey *entry.APIKey) jwt.Token {
+	return jwt.Token{
+		Claims: jwt.MapClaims{
+			"sub": apiKey.CreatedBy.String(),
+		},
+		Valid: true,
+	}
 }
 
 func GetJWTSecret() ([]byte, error) {
@@ -178,6 +178,9 @@ type User struct {
 	CreatedAt  string    `json:"createdAt"`
 	UpdatedAt  string    `json:"updatedAt,omitempty"`
 	IsGuest    bool      `json:"isGuest"`
+	// Only set when a new session is created for the user.
+	RefreshToken   *string `json:"refreshToken,omitempty"`
+	TokenExpiresAt string  `json:"tokenExpiresAt,omitempty"`
 }
 
 type UserSearchResult struct {
@@ -9,38 +9,50 @@ import (
 
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
 	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
 )
 
 func AuthorizeAdmin(log *zap.SugaredLogger) gin.HandlerFunc {
+	return AuthorizeRoles(log, entry.PermissionAdmin)
+}
+
+// AuthorizeRoles allows only users having one of the given roles on the object, either directly,
+// inherited from the object ancestors or included by a custom world role.
+func AuthorizeRoles(log *zap.SugaredLogger, allowedRoles ...entry.PermissionsRoleType) gin.HandlerFunc {
 	userObjects := universe.GetNode().GetUserObjects()
 
 	return func(c *gin.Context) {
 		objectID, err := umid.Parse(c.Param("objectID"))
 		if err != nil {
-			err := errors.WithMessage(err, "Middleware: AuthorizeAdmin: failed to parse object umid")
+			err := errors.WithMessage(err, "Middleware: AuthorizeRoles: failed to parse object umid")
 			api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, log)
 			return
 		}
 
 		userID, err := api.GetUserIDFromContext(c)
 		if err != nil {
-			err := errors.WithMessage(err, "Middleware: AuthorizeAdmin: failed to get user umid from context")
+			err := errors.WithMessage(err, "Middleware: AuthorizeRoles: failed to get user umid from context")
 			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, log)
 			return
 		}
 
-		isAdmin, err := userObjects.CheckIsIndirectAdmin(entry.NewUserObjectID(userID, objectID))
+		roles, err := userObjects.GetUserRoles(entry.NewUserObjectID(userID, objectID))
 		if err != nil {
-			err := errors.WithMessage(err, "Middleware: AuthorizeAdmin: failed to check is indirect admin")
+			err := errors.WithMessage(err, "Middleware: AuthorizeRoles: failed to get user roles")
 			api.AbortRequest(c, http.StatusInternalServerError, "check_failed", err, log)
 			return
 		}
 
-		if !isAdmin {
-			err := errors.New("Middleware: AuthorizeAdmin: user is not admin")
-			api.AbortRequest(c, http.StatusForbidden, "not_admin", err, log)
+		if !auth.HasRole(roles, allowedRoles...) {
+			// keep the error code existing clients expect from admin only endpoints
+			code := "not_allowed"
+			if len(allowedRoles) == 1 && allowedRoles[0] == entry.PermissionAdmin {
+				code = "not_admin"
+			}
+			err := errors.Errorf("Middleware: AuthorizeRoles: user has none of the roles: %v", allowedRoles)
+			api.AbortRequest(c, http.StatusForbidden, code, err, log)
 			return
 		}
 	}
@@ -0,0 +1,156 @@
+package middleware
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"go.uber.org/zap"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var errPluginIDMismatch = errors.New("plugin_id of query params and body differ")
+
+// verifyAPIKey authenticates the request as the api key creator, if one of the key scopes allows the route.
+// Every request made with a valid key is recorded in the api key audit log.
+func verifyAPIKey(c *gin.Context, key string, log *zap.SugaredLogger) {
+	apiKeys := universe.GetNode().GetAPIKeys()
+
+	apiKey, err := apiKeys.Verify(key)
+	if err != nil {
+		err = errors.WithMessage(err, "Middleware: VerifyUser: failed to verify api key")
+		api.AbortRequest(c, http.StatusUnauthorized, "invalid_api_key", err, log)
+		return
+	}
+
+	var granted auth.Scope
+	defer func() {
+		apiKeys.Audit(
+			&entry.APIKeyAuditEntry{
+				AuditID:   umid.New(),
+				APIKeyID:  apiKey.APIKeyID,
+				Method:    c.Request.Method,
+				Path:      c.Request.URL.Path,
+				Scope:     granted.String(),
+				Status:    c.Writer.Status(),
+				IPAddress: c.ClientIP(),
+				CreatedAt: time.Now(),
+			},
+		)
+	}()
+
+	required, err := getRequiredScopes(c)
+	if err != nil {
+		err = errors.WithMessage(err, "Middleware: VerifyUser: failed to get required scopes")
+		if errors.Is(err, errPluginIDMismatch) {
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, log)
+			return
+		}
+		api.AbortRequest(c, http.StatusForbidden, "scope_not_allowed", err, log)
+		return
+	}
+
+	granted, ok := auth.GrantedScope(apiKey.Scopes, required...)
+	if !ok {
+		err := errors.Errorf("Middleware: VerifyUser: api key has none of the scopes: %v", required)
+		api.AbortRequest(c, http.StatusForbidden, "scope_not_allowed", err, log)
+		return
+	}
+
+	c.Set(api.APIKeyContextKey, apiKey)
+	c.Set(api.TokenContextKey, api.NewAPIKeyToken(apiKey))
+
+	c.Next()
+}
+
+// getRequiredScopes returns scopes any of which allows the request.
+func getRequiredScopes(c *gin.Context) ([]auth.Scope, error) {
+	resource, ok := auth.ScopeResourceFromRoute(c.FullPath())
+	if !ok {
+		return nil, errors.Errorf("route is not available for api keys: %s", c.FullPath())
+	}
+	action := auth.ScopeActionFromMethod(c.Request.Method)
+
+	var target string
+	switch resource {
+	case auth.ScopeResourceAttributes:
+		pluginID, err := getPluginIDFromRequest(c)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to get plugin umid")
+		}
+		target = pluginID
+	case auth.ScopeResourceObjects, auth.ScopeResourceWorlds:
+		target = c.Param("objectID")
+	case auth.ScopeResourceUsers:
+		target = c.Param("userID")
+	}
+
+	required := []auth.Scope{auth.NewScope(resource, action, target)}
+
+	// world admins can access everything inside of the world
+	if objectID, err := umid.Parse(c.Param("objectID")); err == nil {
+		if object, ok := universe.GetNode().GetObjectFromAllObjects(objectID); ok {
+			if world := object.GetWorld(); world != nil {
+				required = append(
+					required, auth.NewScope(auth.ScopeResourceWorlds, auth.ScopeActionAdmin, world.GetID().String()),
+				)
+			}
+		}
+	}
+
+	return required, nil
+}
+
+// getPluginIDFromRequest returns the plugin from the same place the handler binds it:
+// query params for reading requests and json body for the others, the body is restored for the request handler.
+// Requests with different plugins in query params and body are rejected.
+func getPluginIDFromRequest(c *gin.Context) (string, error) {
+	queryPluginID := c.Query("plugin_id")
+	if c.Request.Method == http.MethodGet || c.Request.Method == http.MethodHead {
+		return queryPluginID, nil
+	}
+
+	var bodyPluginID string
+	if c.Request.Body != nil && c.ContentType() == gin.MIMEJSON {
+		body, err := io.ReadAll(c.Request.Body)
+		if err != nil {
+			return "", errors.WithMessage(err, "failed to read body")
+		}
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+
+		var in struct {
+			PluginID string `json:"plugin_id"`
+		}
+		// the handler will report malformed bodies
+		_ = json.Unmarshal(body, &in)
+		bodyPluginID = in.PluginID
+	}
+
+	if queryPluginID != "" && queryPluginID != bodyPluginID {
+		return "", errors.WithMessagef(
+			errPluginIDMismatch, "query: %q, body: %q", queryPluginID, bodyPluginID,
+		)
+	}
+
+	return bodyPluginID, nil
+}
+
+// DenyAPIKey allows only requests authenticated with a user token.
+func DenyAPIKey(log *zap.SugaredLogger) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if api.GetAPIKeyFromContext(c) != nil {
+			err := errors.New("Middleware: DenyAPIKey: route is not available for api keys")
+			api.AbortRequest(c, http.StatusForbidden, "scope_not_allowed", err, log)
+			return
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package middleware
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestGetPluginIDFromRequest(t *testing.T) {
+	tests := []struct {
+		name     string
+		method   string
+		url      string
+		body     string
+		want     string
+		mismatch bool
+	}{
+		{name: "get query", method: http.MethodGet, url: "/?plugin_id=a", want: "a"},
+		{name: "get ignores body", method: http.MethodGet, url: "/", body: `{"plugin_id":"b"}`, want: ""},
+		{name: "post body", method: http.MethodPost, url: "/", body: `{"plugin_id":"b"}`, want: "b"},
+		{name: "post same", method: http.MethodPost, url: "/?plugin_id=b", body: `{"plugin_id":"b"}`, want: "b"},
+		{name: "post mismatch", method: http.MethodPost, url: "/?plugin_id=a", body: `{"plugin_id":"b"}`, mismatch: true},
+		{name: "delete query only", method: http.MethodDelete, url: "/?plugin_id=a", body: `{}`, mismatch: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, _ := gin.CreateTestContext(httptest.NewRecorder())
+			c.Request = httptest.NewRequest(tt.method, tt.url, strings.NewReader(tt.body))
+			c.Request.Header.Set("Content-Type", gin.MIMEJSON)
+
+			got, err := getPluginIDFromRequest(c)
+			if tt.mismatch {
+				if !errors.Is(err, errPluginIDMismatch) {
+					t.Fatalf("getPluginIDFromRequest() error = %v, want mismatch", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("getPluginIDFromRequest() error = %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("getPluginIDFromRequest() got = %v, want %v", got, tt.want)
+			}
+
+			// the handler gets the body back
+			body, _ := io.ReadAll(c.Request.Body)
+			if string(body) != tt.body {
+				t.Errorf("body got = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
@@ -7,13 +7,21 @@ import (
 	"github.com/pkg/errors"
 	"go.uber.org/zap"
 
+	"github.com/momentum-xyz/ubercontroller/universe"
 	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
 )
 
+// VerifyUser accepts either a user access token or an api key limited by its scopes.
 func VerifyUser(log *zap.SugaredLogger) gin.HandlerFunc {
 	var secret []byte
 
 	return func(c *gin.Context) {
+		if key := api.GetAPIKeyFromRequest(c); key != "" {
+			verifyAPIKey(c, key, log)
+			return
+		}
+
 		if secret == nil {
 			jwtSecret, err := api.GetJWTSecret()
 			if err != nil {
@@ -31,6 +39,27 @@ func VerifyUser(log *zap.SugaredLogger) gin.HandlerFunc {
 			api.AbortRequest(c, http.StatusUnauthorized, "failed_to_verify_access_token", err, log)
 			return
 		}
+
+		sessionID, err := api.GetSessionIDFromToken(*token)
+		if err != nil {
+			err = errors.WithMessage(err, "Middleware: VerifyUser: failed to get session umid")
+			api.AbortRequest(c, http.StatusUnauthorized, "failed_to_verify_access_token", err, log)
+			return
+		}
+		if sessionID != umid.Nil {
+			active, err := universe.GetNode().GetUserSessions().IsActive(sessionID)
+			if err != nil {
+				err = errors.WithMessage(err, "Middleware: VerifyUser: failed to check session")
+				api.AbortRequest(c, http.StatusInternalServerError, "failed_to_verify_access_token", err, log)
+				return
+			}
+			if !active {
+				err = errors.Errorf("Middleware: VerifyUser: session is not active: %s", sessionID)
+				api.AbortRequest(c, http.StatusUnauthorized, "session_revoked", err, log)
+				return
+			}
+		}
+
 		c.Set(api.TokenContextKey, *token)
 	}
 }
@@ -1,5 +1,8 @@
 package api
 
 const (
-	TokenContextKey = "token"
+	TokenContextKey  = "token"
+	APIKeyContextKey = "api_key"
+
+	APIKeyHeader = "X-API-Key"
 )
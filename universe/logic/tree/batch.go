@@ -0,0 +1,626 @@
+package tree
+
+import (
+	"context"
+	"sort"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+type BatchOperationType string
+
+const (
+	CreateBatchOperationType   BatchOperationType = "create"
+	UpdateBatchOperationType   BatchOperationType = "update"
+	ReparentBatchOperationType BatchOperationType = "reparent"
+	DeleteBatchOperationType   BatchOperationType = "delete"
+)
+
+// BatchOperation is one operation of a batch, object_id of created objects is generated if not set.
+// Create requires parent_id and object_type_id, reparent requires parent_id,
+// update changes only the fields which are set.
+type BatchOperation struct {
+	Op           BatchOperationType `json:"op" binding:"required,oneof=create update reparent delete"`
+	ObjectID     *umid.UMID         `json:"object_id"`
+	ParentID     *umid.UMID         `json:"parent_id"`
+	ObjectTypeID *umid.UMID         `json:"object_type_id"`
+	ObjectName   *string            `json:"object_name"`
+	Asset2dID    *umid.UMID         `json:"asset_2d_id"`
+	Asset3dID    *umid.UMID         `json:"asset_3d_id"`
+	Transform    *cmath.Transform   `json:"transform"`
+}
+
+type BatchResult struct {
+	Created []umid.UMID `json:"created"`
+	Updated []umid.UMID `json:"updated"`
+	Removed []umid.UMID `json:"removed"`
+}
+
+// BatchOperationError is returned when an operation can't be applied, Index is its position in the batch.
+type BatchOperationError struct {
+	Index int
+	Err   error
+}
+
+func (e *BatchOperationError) Error() string {
+	return errors.WithMessagef(e.Err, "operation %d", e.Index).Error()
+}
+
+func (e *BatchOperationError) Unwrap() error {
+	return e.Err
+}
+
+type batchObject struct {
+	entry  *entry.Object
+	object universe.Object // nil for created objects
+	name   *string
+
+	objectType universe.ObjectType
+	asset2d    universe.Asset2d
+	asset3d    universe.Asset3d
+
+	created bool
+	moved   bool
+}
+
+// objectsBatch is the state of the tree under root with the operations applied so far,
+// objects not touched by the operations are read from the tree.
+type objectsBatch struct {
+	ctx     context.Context
+	db      database.DB
+	root    universe.Object
+	userID  umid.UMID
+	objects map[umid.UMID]*batchObject
+	removed map[umid.UMID]bool
+}
+
+// ApplyBatch applies the operations to the tree under the root in order, as the user.
+// All operations are checked first and saved in one transaction, if any of them fails nothing is changed.
+// Removed objects are moved to the trash, where they are kept for the retention.
+// Clients get one AddObjects message with the created, updated and moved objects and one RemoveObjects message.
+func ApplyBatch(
+	ctx context.Context,
+	db database.DB,
+	root universe.Object,
+	userID umid.UMID,
+	operations []*BatchOperation,
+	retention time.Duration,
+) (*BatchResult, error) {
+	batch := &objectsBatch{
+		ctx:     ctx,
+		db:      db,
+		root:    root,
+		userID:  userID,
+		objects: make(map[umid.UMID]*batchObject),
+		removed: make(map[umid.UMID]bool),
+	}
+
+	for i, operation := range operations {
+		if err := batch.apply(operation); err != nil {
+			return nil, &BatchOperationError{Index: i, Err: err}
+		}
+	}
+
+	objects := batch.getAliveObjects()
+	removedIDs := batch.getRemovedIDs()
+
+	dbBatch := &entry.ObjectsBatch{
+		Objects:          make([]*entry.Object, 0, len(objects)),
+		CreatedIDs:       make(map[umid.UMID]bool),
+		ObjectAttributes: make([]*entry.ObjectAttribute, 0),
+		TrashItems:       batch.getTrashItems(removedIDs, retention),
+	}
+	for _, object := range objects {
+		dbBatch.Objects = append(dbBatch.Objects, object.entry)
+		if object.created {
+			dbBatch.CreatedIDs[object.entry.ObjectID] = true
+		}
+		if object.name != nil {
+			dbBatch.ObjectAttributes = append(dbBatch.ObjectAttributes, getBatchNameAttribute(object))
+		}
+	}
+
+	if err := db.GetObjectsDB().ApplyObjectsBatch(ctx, dbBatch); err != nil {
+		return nil, errors.WithMessage(err, "failed to apply objects batch")
+	}
+
+	return batch.commit(objects, removedIDs)
+}
+
+func (b *objectsBatch) apply(operation *BatchOperation) error {
+	switch operation.Op {
+	case CreateBatchOperationType:
+		return b.create(operation)
+	case UpdateBatchOperationType:
+		return b.update(operation)
+	case ReparentBatchOperationType:
+		return b.reparent(operation)
+	case DeleteBatchOperationType:
+		return b.delete(operation)
+	}
+	return errors.Errorf("unknown operation: %s", operation.Op)
+}
+
+func (b *objectsBatch) create(operation *BatchOperation) error {
+	if operation.ParentID == nil || operation.ObjectTypeID == nil {
+		return errors.New("parent_id and object_type_id are required")
+	}
+
+	objectID := umid.New()
+	if operation.ObjectID != nil {
+		objectID = *operation.ObjectID
+	}
+	if _, ok := b.objects[objectID]; ok || b.removed[objectID] {
+		return errors.Errorf("object already exists: %s", objectID)
+	}
+	if _, ok := universe.GetNode().GetObjectFromAllObjects(objectID); ok {
+		return errors.Errorf("object already exists: %s", objectID)
+	}
+	// objects in the trash or not loaded are only in the db
+	if _, err := b.db.GetObjectsDB().GetObjectByID(b.ctx, objectID); err == nil {
+		return errors.Errorf("object already exists: %s", objectID)
+	} else if !errors.Is(err, pgx.ErrNoRows) {
+		return errors.WithMessagef(err, "failed to check object: %s", objectID)
+	}
+
+	if err := b.checkAlive(*operation.ParentID); err != nil {
+		return errors.WithMessage(err, "invalid parent")
+	}
+
+	object := &batchObject{
+		entry: &entry.Object{
+			ObjectID: objectID,
+			OwnerID:  b.userID,
+			ParentID: *operation.ParentID,
+		},
+		name:    utils.GetPTR(objectID.String()),
+		created: true,
+	}
+	if err := b.setFields(object, operation); err != nil {
+		return err
+	}
+	b.objects[objectID] = object
+
+	return nil
+}
+
+func (b *objectsBatch) update(operation *BatchOperation) error {
+	object, err := b.getObject(operation.ObjectID)
+	if err != nil {
+		return err
+	}
+
+	return b.setFields(object, operation)
+}
+
+func (b *objectsBatch) reparent(operation *BatchOperation) error {
+	if operation.ParentID == nil {
+		return errors.New("parent_id is required")
+	}
+
+	object, err := b.getObject(operation.ObjectID)
+	if err != nil {
+		return err
+	}
+	if object.entry.ObjectID == b.root.GetID() {
+		return errors.New("root can't be moved")
+	}
+
+	parentID := *operation.ParentID
+	if err := b.checkAlive(parentID); err != nil {
+		return errors.WithMessage(err, "invalid parent")
+	}
+	for id := parentID; id != b.root.GetID(); id = b.getParentID(id) {
+		if id == object.entry.ObjectID {
+			return errors.New("object can't be moved under itself")
+		}
+	}
+
+	object.entry.ParentID = parentID
+	object.moved = true
+
+	return nil
+}
+
+func (b *objectsBatch) delete(operation *BatchOperation) error {
+	object, err := b.getObject(operation.ObjectID)
+	if err != nil {
+		return err
+	}
+	if object.entry.ObjectID == b.root.GetID() {
+		return errors.New("root can't be removed")
+	}
+
+	b.removed[object.entry.ObjectID] = true
+
+	return nil
+}
+
+func (b *objectsBatch) setFields(object *batchObject, operation *BatchOperation) error {
+	node := universe.GetNode()
+
+	if operation.ObjectTypeID != nil {
+		objectType, ok := node.GetObjectTypes().GetObjectType(*operation.ObjectTypeID)
+		if !ok {
+			return errors.Errorf("object type not found: %s", operation.ObjectTypeID)
+		}
+		object.objectType = objectType
+		object.entry.ObjectTypeID = objectType.GetID()
+	}
+	if operation.Asset2dID != nil {
+		asset2d, ok := node.GetAssets2d().GetAsset2d(*operation.Asset2dID)
+		if !ok {
+			return errors.Errorf("asset 2d not found: %s", operation.Asset2dID)
+		}
+		object.asset2d = asset2d
+		object.entry.Asset2dID = operation.Asset2dID
+	}
+	if operation.Asset3dID != nil {
+		asset3d, ok := node.GetAssets3d().GetAsset3d(*operation.Asset3dID)
+		if !ok {
+			return errors.Errorf("asset 3d not found: %s", operation.Asset3dID)
+		}
+		object.asset3d = asset3d
+		object.entry.Asset3dID = operation.Asset3dID
+	}
+	if operation.Transform != nil {
+		object.entry.Transform = utils.GetPTR(*operation.Transform)
+	}
+	if operation.ObjectName != nil {
+		object.name = operation.ObjectName
+	}
+
+	return nil
+}
+
+// getObject returns the alive object of the batch, touching it.
+func (b *objectsBatch) getObject(objectID *umid.UMID) (*batchObject, error) {
+	if objectID == nil {
+		return nil, errors.New("object_id is required")
+	}
+	if err := b.checkAlive(*objectID); err != nil {
+		return nil, err
+	}
+
+	if object, ok := b.objects[*objectID]; ok {
+		return object, nil
+	}
+
+	existing, _ := universe.GetNode().GetObjectFromAllObjects(*objectID)
+	if lock := existing.GetLock(true); lock != nil && lock.UserID != b.userID {
+		return nil, errors.Errorf("object is locked by user: %s", lock.UserID)
+	}
+
+	object := &batchObject{
+		entry:  existing.GetEntry(),
+		object: existing,
+	}
+	b.objects[*objectID] = object
+
+	return object, nil
+}
+
+// checkAlive checks that the object exists under the root and neither it nor its ancestors are removed.
+func (b *objectsBatch) checkAlive(objectID umid.UMID) error {
+	if _, ok := b.objects[objectID]; !ok {
+		if _, ok := universe.GetNode().GetObjectFromAllObjects(objectID); !ok {
+			return errors.Errorf("object not found: %s", objectID)
+		}
+	}
+
+	for id := objectID; ; id = b.getParentID(id) {
+		if b.removed[id] {
+			return errors.Errorf("object is removed: %s", objectID)
+		}
+		if id == b.root.GetID() {
+			return nil
+		}
+		if id == umid.Nil {
+			return errors.Errorf("object is not under the root: %s", objectID)
+		}
+	}
+}
+
+// getParentID returns the parent of the object with the operations applied, umid.Nil for objects without one.
+func (b *objectsBatch) getParentID(objectID umid.UMID) umid.UMID {
+	if object, ok := b.objects[objectID]; ok {
+		return object.entry.ParentID
+	}
+
+	object, ok := universe.GetNode().GetObjectFromAllObjects(objectID)
+	if !ok {
+		return umid.Nil
+	}
+	parent := object.GetParent()
+	if parent == nil || parent.GetID() == objectID {
+		return umid.Nil
+	}
+	return parent.GetID()
+}
+
+func (b *objectsBatch) getDepth(objectID umid.UMID) int {
+	depth := 0
+	for id := objectID; id != b.root.GetID() && id != umid.Nil; id = b.getParentID(id) {
+		depth++
+	}
+	return depth
+}
+
+// getAliveObjects returns touched objects which are not removed, parents before their children.
+func (b *objectsBatch) getAliveObjects() []*batchObject {
+	objects := make([]*batchObject, 0, len(b.objects))
+	depths := make(map[umid.UMID]int, len(b.objects))
+	for id, object := range b.objects {
+		if b.checkAlive(id) != nil {
+			continue
+		}
+		objects = append(objects, object)
+		depths[id] = b.getDepth(id)
+	}
+
+	sort.Slice(objects, func(i, j int) bool {
+		a, b := objects[i].entry.ObjectID, objects[j].entry.ObjectID
+		if depths[a] != depths[b] {
+			return depths[a] < depths[b]
+		}
+		return compareUMIDs(a, b) < 0
+	})
+
+	return objects
+}
+
+// getRemovedIDs returns removed objects existing before the batch, parents before their children.
+func (b *objectsBatch) getRemovedIDs() []umid.UMID {
+	removedIDs := make([]umid.UMID, 0, len(b.removed))
+	for id := range b.removed {
+		if object, ok := b.objects[id]; ok && object.created {
+			continue
+		}
+		removedIDs = append(removedIDs, id)
+	}
+
+	sort.Slice(removedIDs, func(i, j int) bool {
+		return compareUMIDs(removedIDs[i], removedIDs[j]) < 0
+	})
+
+	return removedIDs
+}
+
+// getTrashItems returns the trash items of removed objects which aren't removed with their ancestors.
+func (b *objectsBatch) getTrashItems(removedIDs []umid.UMID, retention time.Duration) []*entry.ObjectTrashItem {
+	now := time.Now()
+	items := make([]*entry.ObjectTrashItem, 0, len(removedIDs))
+	for _, id := range removedIDs {
+		object, ok := universe.GetNode().GetObjectFromAllObjects(id)
+		if !ok {
+			continue
+		}
+		parent := object.GetParent()
+		if parent == nil || b.hasRemovedAncestor(object) {
+			continue
+		}
+
+		items = append(items, &entry.ObjectTrashItem{
+			ObjectID:  id,
+			WorldID:   object.GetWorld().GetID(),
+			ParentID:  parent.GetID(),
+			Name:      object.GetName(),
+			DeletedBy: utils.GetPTR(b.userID),
+			DeletedAt: now,
+			ExpiresAt: now.Add(retention),
+		})
+	}
+
+	return items
+}
+
+// commit applies the saved batch to the tree and notifies clients.
+func (b *objectsBatch) commit(objects []*batchObject, removedIDs []umid.UMID) (*BatchResult, error) {
+	node := universe.GetNode()
+	world := b.root.GetWorld()
+
+	result := &BatchResult{
+		Created: make([]umid.UMID, 0),
+		Updated: make([]umid.UMID, 0),
+		Removed: make([]umid.UMID, 0),
+	}
+	parents := make(map[umid.UMID]universe.Object)
+	disabled := make([]universe.Object, 0)
+
+	var errs *multierror.Error
+	for _, object := range objects {
+		if object.created {
+			parent, ok := node.GetObjectFromAllObjects(object.entry.ParentID)
+			if !ok {
+				errs = multierror.Append(errs, errors.Errorf("parent not found: %s", object.entry.ParentID))
+				continue
+			}
+			created, err := parent.CreateObject(object.entry.ObjectID)
+			if err != nil {
+				errs = multierror.Append(
+					errs, errors.WithMessagef(err, "failed to create object: %s", object.entry.ObjectID),
+				)
+				continue
+			}
+			if err := created.SetOwnerID(object.entry.OwnerID, false); err != nil {
+				errs = multierror.Append(errs, errors.WithMessage(err, "failed to set owner"))
+			}
+			if err := created.Run(); err != nil {
+				errs = multierror.Append(errs, errors.WithMessage(err, "failed to run object"))
+			}
+			object.object = created
+			parents[parent.GetID()] = parent
+			result.Created = append(result.Created, object.entry.ObjectID)
+		} else {
+			// preventing messages of every change, the object is sent once the batch is applied
+			if object.object.GetEnabled() {
+				object.object.SetEnabled(false)
+				disabled = append(disabled, object.object)
+			}
+			result.Updated = append(result.Updated, object.entry.ObjectID)
+		}
+
+		if err := b.commitFields(object); err != nil {
+			errs = multierror.Append(
+				errs, errors.WithMessagef(err, "failed to update object: %s", object.entry.ObjectID),
+			)
+		}
+
+		// created objects are already under their final parent
+		if object.moved && !object.created {
+			oldParent := object.object.GetParent()
+			newParent, ok := node.GetObjectFromAllObjects(object.entry.ParentID)
+			if oldParent == nil || !ok {
+				errs = multierror.Append(errs, errors.Errorf("failed to move object: %s", object.entry.ObjectID))
+				continue
+			}
+			if _, err := oldParent.RemoveObject(object.object, false, false); err != nil {
+				errs = multierror.Append(
+					errs, errors.WithMessagef(err, "failed to move object: %s", object.entry.ObjectID),
+				)
+				continue
+			}
+			if err := newParent.AddObject(object.object, false); err != nil {
+				errs = multierror.Append(
+					errs, errors.WithMessagef(err, "failed to move object: %s", object.entry.ObjectID),
+				)
+				continue
+			}
+			parents[oldParent.GetID()] = oldParent
+			parents[newParent.GetID()] = newParent
+		}
+	}
+
+	for _, id := range removedIDs {
+		object, ok := node.GetObjectFromAllObjects(id)
+		if !ok {
+			continue
+		}
+		parent := object.GetParent()
+		// children go with the removed ancestor
+		if parent == nil || b.hasRemovedAncestor(object) {
+			continue
+		}
+
+		object.SetEnabled(false)
+		if _, err := RemoveObjectFromParent(parent, object, false); err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to remove object: %s", id))
+			continue
+		}
+		delete(parents, id)
+		result.Removed = append(result.Removed, id)
+	}
+
+	for _, object := range disabled {
+		object.SetEnabled(true)
+	}
+
+	for _, parent := range parents {
+		if err := parent.UpdateChildrenPosition(true); err != nil {
+			errs = multierror.Append(
+				errs, errors.WithMessagef(err, "failed to update children position: %s", parent.GetID()),
+			)
+		}
+	}
+
+	definitions := make([]posbus.ObjectDefinition, 0, len(objects))
+	for _, object := range objects {
+		if object.object == nil {
+			continue
+		}
+		if object.created {
+			object.object.SetEnabled(true)
+		}
+		if err := object.object.UpdateSpawnMessage(); err != nil {
+			errs = multierror.Append(
+				errs, errors.WithMessagef(err, "failed to update spawn message: %s", object.entry.ObjectID),
+			)
+			continue
+		}
+		definitions = append(definitions, *object.object.GetObjectDefinition())
+	}
+
+	if world != nil {
+		if len(definitions) > 0 {
+			if err := world.Send(posbus.WSMessage(&posbus.AddObjects{Objects: definitions}), true); err != nil {
+				errs = multierror.Append(errs, errors.WithMessage(err, "failed to send add objects message"))
+			}
+		}
+		if len(result.Removed) > 0 {
+			if err := world.Send(posbus.WSMessage(&posbus.RemoveObjects{Objects: result.Removed}), true); err != nil {
+				errs = multierror.Append(errs, errors.WithMessage(err, "failed to send remove objects message"))
+			}
+		}
+	}
+
+	return result, errs.ErrorOrNil()
+}
+
+func (b *objectsBatch) commitFields(object *batchObject) error {
+	if object.objectType != nil {
+		if err := object.object.SetObjectType(object.objectType, false); err != nil {
+			return errors.WithMessage(err, "failed to set object type")
+		}
+	}
+	if object.asset2d != nil {
+		if err := object.object.SetAsset2D(object.asset2d, false); err != nil {
+			return errors.WithMessage(err, "failed to set asset 2d")
+		}
+	}
+	if object.asset3d != nil {
+		if err := object.object.SetAsset3D(object.asset3d, false); err != nil {
+			return errors.WithMessage(err, "failed to set asset 3d")
+		}
+	}
+	if object.entry.Transform != object.object.GetTransform() {
+		if err := object.object.SetTransform(object.entry.Transform, false); err != nil {
+			return errors.WithMessage(err, "failed to set transform")
+		}
+	}
+	if object.name != nil {
+		if err := object.object.SetName(*object.name, false); err != nil {
+			return errors.WithMessage(err, "failed to set name")
+		}
+	}
+	return nil
+}
+
+func (b *objectsBatch) hasRemovedAncestor(object universe.Object) bool {
+	for parent := object.GetParent(); parent != nil && parent.GetID() != b.root.GetID(); parent = parent.GetParent() {
+		if b.removed[parent.GetID()] {
+			return true
+		}
+	}
+	return false
+}
+
+// getBatchNameAttribute returns the name attribute of the object to save, keeping other values of existing one.
+func getBatchNameAttribute(object *batchObject) *entry.ObjectAttribute {
+	attributeID := entry.NewAttributeID(universe.GetSystemPluginID(), universe.ReservedAttributes.Object.Name.Name)
+
+	payload := entry.NewAttributePayload(&entry.AttributeValue{}, nil)
+	if object.object != nil {
+		if current, ok := object.object.GetObjectAttributes().GetPayload(attributeID); ok && current != nil {
+			payload.Options = current.Options
+			if current.Value != nil {
+				for k, v := range *current.Value {
+					(*payload.Value)[k] = v
+				}
+			}
+		}
+	}
+	(*payload.Value)[universe.ReservedAttributes.Object.Name.Key] = *object.name
+
+	return entry.NewObjectAttribute(entry.NewObjectAttributeID(attributeID, object.entry.ObjectID), payload)
+}
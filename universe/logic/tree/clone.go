@@ -0,0 +1,278 @@
+package tree
+
+import (
+	"encoding/json"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/modify"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// CloneReport is the result of CloneObject, it is filled as far as cloning went when it fails.
+type CloneReport struct {
+	ObjectID            umid.UMID `json:"object_id"`
+	TotalObjects        int       `json:"total_objects"`
+	ClonedObjects       int       `json:"cloned_objects"`
+	ClonedAttributes    int       `json:"cloned_attributes"`
+	DefaultedAttributes int       `json:"defaulted_attributes"`
+	SkippedAttributes   int       `json:"skipped_attributes"`
+	// source object umid to the clone umid
+	ObjectIDs map[umid.UMID]umid.UMID `json:"object_ids"`
+}
+
+// CloneObject clones the object with all its children (if recursive) under the parent, which can be in another world.
+// Only attributes with the "cloneable" option are cloned, with the "use_default" value of the option if it's set.
+// References to the cloned objects in options and attribute values are replaced by the clones.
+// Cloned attributes are recorded in the attributes history as changed by the user.
+// The partial clone is removed when cloning fails.
+func CloneObject(
+	object universe.Object, parentID umid.UMID, userID umid.UMID, transform *cmath.Transform, recursive bool,
+	updateDB bool,
+) (*CloneReport, error) {
+	report := &CloneReport{}
+
+	objectTemplate := ObjectToTemplate(object)
+	if !recursive {
+		objectTemplate.Objects = nil
+	}
+	objectTemplate.ParentID = parentID
+	if transform != nil {
+		objectTemplate.Transform = transform
+	}
+
+	if err := filterCloneableAttributes(objectTemplate, report); err != nil {
+		return report, errors.WithMessage(err, "failed to filter cloneable attributes")
+	}
+
+	ids, err := RemapObjectTemplate(objectTemplate)
+	if err != nil {
+		return report, errors.WithMessage(err, "failed to remap object template")
+	}
+	report.TotalObjects = len(ids)
+	report.ObjectIDs = make(map[umid.UMID]umid.UMID, len(ids))
+
+	sourceIDs := make(map[umid.UMID]umid.UMID, len(ids))
+	for sourceID, cloneID := range ids {
+		sourceIDs[cloneID] = sourceID
+	}
+
+	if err := addClonedObject(objectTemplate, sourceIDs, userID, updateDB, report); err != nil {
+		err := errors.WithMessagef(err, "cloned %d of %d objects", report.ClonedObjects, report.TotalObjects)
+		if rollbackErr := removeClonedObject(*objectTemplate.ObjectID, updateDB); rollbackErr != nil {
+			return report, multierror.Append(err, errors.WithMessage(rollbackErr, "failed to remove partial clone"))
+		}
+		return report, err
+	}
+	report.ObjectID = *objectTemplate.ObjectID
+
+	return report, nil
+}
+
+// removeClonedObject removes the clone root with its children if it was added.
+func removeClonedObject(objectID umid.UMID, updateDB bool) error {
+	object, ok := universe.GetNode().GetObjectFromAllObjects(objectID)
+	if !ok {
+		return nil
+	}
+	if _, err := RemoveObjectFromParent(object.GetParent(), object, updateDB); err != nil {
+		return errors.WithMessagef(err, "failed to remove object from parent: %s", objectID)
+	}
+	return nil
+}
+
+// addClonedObject adds objects of the template one by one, so the report shows how far cloning went.
+func addClonedObject(
+	objectTemplate *ObjectTemplate, sourceIDs map[umid.UMID]umid.UMID, userID umid.UMID, updateDB bool,
+	report *CloneReport,
+) error {
+	children := objectTemplate.Objects
+	objectTemplate.Objects = nil
+	// attributes are upserted by the user afterwards, so they get into the history
+	attributes := objectTemplate.ObjectAttributes
+	objectTemplate.ObjectAttributes = nil
+
+	objectID, err := AddObjectFromTemplate(objectTemplate, updateDB)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to add object from template: %s", sourceIDs[*objectTemplate.ObjectID])
+	}
+	report.ClonedObjects++
+	report.ObjectIDs[sourceIDs[objectID]] = objectID
+
+	object, ok := universe.GetNode().GetObjectFromAllObjects(objectID)
+	if !ok {
+		return errors.Errorf("cloned object not found: %s", objectID)
+	}
+	for _, attribute := range attributes {
+		if _, err := object.GetObjectAttributes().UpsertByUser(
+			userID, attribute.AttributeID, modify.MergeWith(attribute.AttributePayload), updateDB,
+		); err != nil {
+			return errors.WithMessagef(err, "failed to upsert object attribute: %+v", attribute.AttributeID)
+		}
+	}
+
+	for i := range children {
+		children[i].ParentID = objectID
+		if err := addClonedObject(children[i], sourceIDs, userID, updateDB, report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// filterCloneableAttributes keeps only cloneable attributes of the template objects.
+func filterCloneableAttributes(objectTemplate *ObjectTemplate, report *CloneReport) error {
+	object, ok := universe.GetNode().GetObjectFromAllObjects(*objectTemplate.ObjectID)
+	if !ok {
+		return errors.Errorf("object not found: %s", objectTemplate.ObjectID)
+	}
+
+	attributes := make([]*entry.Attribute, 0, len(objectTemplate.ObjectAttributes))
+	for _, attribute := range objectTemplate.ObjectAttributes {
+		effectiveOptions, _ := object.GetObjectAttributes().GetEffectiveOptions(attribute.AttributeID)
+		if effectiveOptions == nil {
+			report.SkippedAttributes++
+			continue
+		}
+		cloneable := utils.GetFromAnyMap(*effectiveOptions, "cloneable", map[string]any(nil))
+		if cloneable == nil {
+			report.SkippedAttributes++
+			continue
+		}
+
+		payload := entry.NewAttributePayload(nil, nil)
+		if attribute.AttributePayload != nil {
+			payload.Options = attribute.Options
+			payload.Value = attribute.Value
+		}
+		if defaultValue := utils.GetFromAnyMap(cloneable, "use_default", map[string]any(nil)); defaultValue != nil {
+			payload.Value = utils.GetPTR(entry.AttributeValue(defaultValue))
+			report.DefaultedAttributes++
+		} else {
+			report.ClonedAttributes++
+		}
+
+		attributes = append(attributes, entry.NewAttribute(attribute.AttributeID, payload))
+	}
+	objectTemplate.ObjectAttributes = attributes
+
+	for i := range objectTemplate.Objects {
+		if err := filterCloneableAttributes(objectTemplate.Objects[i], report); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// RemapObjectTemplate gives the template objects new umids and replaces references to them
+// in options and attribute values, it returns the old to new umids map.
+// Values of the template are copied, so it can be safely made from live objects.
+func RemapObjectTemplate(objectTemplate *ObjectTemplate) (map[umid.UMID]umid.UMID, error) {
+	return remapObjectTemplate(objectTemplate, func(*ObjectTemplate) umid.UMID {
+		return umid.New()
+	})
+}
+
+// remapObjectTemplate is RemapObjectTemplate with new umids given by newIDFn.
+func remapObjectTemplate(
+	objectTemplate *ObjectTemplate, newIDFn func(objectTemplate *ObjectTemplate) umid.UMID,
+) (map[umid.UMID]umid.UMID, error) {
+	ids := make(map[umid.UMID]umid.UMID)
+	WalkObjectTemplate(objectTemplate, func(objectTemplate *ObjectTemplate) {
+		newID := newIDFn(objectTemplate)
+		if objectTemplate.ObjectID != nil {
+			ids[*objectTemplate.ObjectID] = newID
+		}
+		objectTemplate.ObjectID = utils.GetPTR(newID)
+	})
+
+	var errs error
+	WalkObjectTemplate(objectTemplate, func(objectTemplate *ObjectTemplate) {
+		if errs != nil {
+			return
+		}
+		if objectTemplate.Options != nil {
+			var options entry.ObjectOptions
+			if err := remapUMIDsInCopy(objectTemplate.Options, &options, ids); err != nil {
+				errs = errors.WithMessagef(err, "failed to remap options: %s", objectTemplate.ObjectID)
+				return
+			}
+			objectTemplate.Options = &options
+		}
+		for i, attribute := range objectTemplate.ObjectAttributes {
+			if attribute.AttributePayload == nil || attribute.Value == nil {
+				continue
+			}
+			var value entry.AttributeValue
+			if err := remapUMIDsInCopy(attribute.Value, &value, ids); err != nil {
+				errs = errors.WithMessagef(err, "failed to remap attribute value: %+v", attribute.AttributeID)
+				return
+			}
+			objectTemplate.ObjectAttributes[i] = entry.NewAttribute(
+				attribute.AttributeID, entry.NewAttributePayload(&value, attribute.Options),
+			)
+		}
+	})
+
+	return ids, errs
+}
+
+// remapUMIDsInCopy copies the src to the dst through json with the umids replaced.
+func remapUMIDsInCopy(src any, dst any, ids map[umid.UMID]umid.UMID) error {
+	data, err := json.Marshal(src)
+	if err != nil {
+		return errors.WithMessage(err, "failed to marshal")
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return errors.WithMessage(err, "failed to unmarshal")
+	}
+	if data, err = json.Marshal(remapUMIDs(value, ids)); err != nil {
+		return errors.WithMessage(err, "failed to marshal remapped")
+	}
+	if err := json.Unmarshal(data, dst); err != nil {
+		return errors.WithMessage(err, "failed to unmarshal remapped")
+	}
+	return nil
+}
+
+// remapUMIDs returns the value with the umids in keys and values replaced.
+func remapUMIDs(value any, ids map[umid.UMID]umid.UMID) any {
+	switch value := value.(type) {
+	case string:
+		if id, err := umid.Parse(value); err == nil {
+			if newID, ok := ids[id]; ok {
+				return newID.String()
+			}
+		}
+		return value
+	case map[string]any:
+		remapped := make(map[string]any, len(value))
+		for k, v := range value {
+			remapped[remapUMIDs(k, ids).(string)] = remapUMIDs(v, ids)
+		}
+		return remapped
+	case []any:
+		remapped := make([]any, len(value))
+		for i := range value {
+			remapped[i] = remapUMIDs(value[i], ids)
+		}
+		return remapped
+	}
+	return value
+}
+
+// WalkObjectTemplate calls fn for the object template and all its children.
+func WalkObjectTemplate(objectTemplate *ObjectTemplate, fn func(objectTemplate *ObjectTemplate)) {
+	fn(objectTemplate)
+	for _, child := range objectTemplate.Objects {
+		WalkObjectTemplate(child, fn)
+	}
+}
@@ -1,6 +1,8 @@
 package tree
 
 import (
+	"sort"
+
 	"github.com/hashicorp/go-multierror"
 	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
@@ -162,6 +164,87 @@ func AddObjectFromTemplate(objectTemplate *ObjectTemplate, updateDB bool) (umid.
 	return *objectID, nil
 }
 
+// AddObjectFromTemplateByUser is AddObjectFromTemplate with attributes of the objects
+// recorded in history as changed by the user.
+func AddObjectFromTemplateByUser(objectTemplate *ObjectTemplate, userID umid.UMID, updateDB bool) (umid.UMID, error) {
+	attributes := make(map[*ObjectTemplate][]*entry.Attribute)
+	WalkObjectTemplate(objectTemplate, func(objectTemplate *ObjectTemplate) {
+		if objectTemplate.ObjectID == nil {
+			objectTemplate.ObjectID = utils.GetPTR(umid.New())
+		}
+		attributes[objectTemplate] = objectTemplate.ObjectAttributes
+		objectTemplate.ObjectAttributes = nil
+	})
+
+	objectID, err := AddObjectFromTemplate(objectTemplate, updateDB)
+	if err != nil {
+		return umid.Nil, err
+	}
+
+	for template, objectAttributes := range attributes {
+		object, ok := universe.GetNode().GetObjectFromAllObjects(*template.ObjectID)
+		if !ok {
+			return umid.Nil, errors.Errorf("object not found: %s", template.ObjectID)
+		}
+		for _, attribute := range objectAttributes {
+			if _, err := object.GetObjectAttributes().UpsertByUser(
+				userID, attribute.AttributeID, modify.MergeWith(attribute.AttributePayload), updateDB,
+			); err != nil {
+				return umid.Nil, errors.WithMessagef(err, "failed to upsert object attribute: %+v", attribute)
+			}
+		}
+	}
+
+	return objectID, nil
+}
+
+// ObjectToTemplate is the reverse of AddObjectFromTemplate: it returns the template of the object with all its children.
+func ObjectToTemplate(object universe.Object) *ObjectTemplate {
+	objectTemplate := &ObjectTemplate{
+		ObjectID:     utils.GetPTR(object.GetID()),
+		ObjectName:   utils.GetPTR(object.GetName()),
+		ObjectTypeID: object.GetObjectType().GetID(),
+		OwnerID:      utils.GetPTR(object.GetOwnerID()),
+		Options:      object.GetOptions(),
+	}
+	if transform := object.GetTransform(); transform != nil {
+		objectTemplate.Transform = utils.GetPTR(*transform)
+	}
+	if parent := object.GetParent(); parent != nil {
+		objectTemplate.ParentID = parent.GetID()
+	}
+	if asset2d := object.GetAsset2D(); asset2d != nil {
+		objectTemplate.Asset2dID = utils.GetPTR(asset2d.GetID())
+	}
+	if asset3d := object.GetAsset3D(); asset3d != nil {
+		objectTemplate.Asset3dID = utils.GetPTR(asset3d.GetID())
+	}
+
+	attributes := object.GetObjectAttributes().GetAll()
+	objectTemplate.ObjectAttributes = make([]*entry.Attribute, 0, len(attributes))
+	for attributeID, payload := range attributes {
+		objectTemplate.ObjectAttributes = append(objectTemplate.ObjectAttributes, entry.NewAttribute(attributeID, payload))
+	}
+	sort.Slice(objectTemplate.ObjectAttributes, func(i, j int) bool {
+		a, b := objectTemplate.ObjectAttributes[i], objectTemplate.ObjectAttributes[j]
+		if a.PluginID != b.PluginID {
+			return a.PluginID.String() < b.PluginID.String()
+		}
+		return a.Name < b.Name
+	})
+
+	children := object.GetObjects(false)
+	objectTemplate.Objects = make([]*ObjectTemplate, 0, len(children))
+	for _, child := range children {
+		objectTemplate.Objects = append(objectTemplate.Objects, ObjectToTemplate(child))
+	}
+	sort.Slice(objectTemplate.Objects, func(i, j int) bool {
+		return objectTemplate.Objects[i].ObjectID.String() < objectTemplate.Objects[j].ObjectID.String()
+	})
+
+	return objectTemplate
+}
+
 func RemoveObjectFromParent(parent, object universe.Object, updateDB bool) (bool, error) {
 	if parent == nil {
 		return false, errors.Errorf("parent is nil")
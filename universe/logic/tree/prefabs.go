@@ -0,0 +1,630 @@
+package tree
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"regexp"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/modify"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var (
+	prefabParameterNameRegexp = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+	prefabPlaceholderRegexp   = regexp.MustCompile(`\{\{\s*([A-Za-z0-9_.-]+)\s*\}\}`)
+	prefabTextureRegexp       = regexp.MustCompile(`^[0-9a-f]{32}$`)
+)
+
+// NewPrefabTemplate returns the template of the object to publish as a prefab, detached from its parent and owners.
+// Prefabs are readable by everyone, so only cloneable attributes the user is allowed to read are published.
+func NewPrefabTemplate(ctx context.Context, object universe.Object, userID umid.UMID) (*ObjectTemplate, error) {
+	objectTemplate := ObjectToTemplate(object)
+	if err := filterCloneableAttributes(objectTemplate, &CloneReport{}); err != nil {
+		return nil, errors.WithMessage(err, "failed to filter cloneable attributes")
+	}
+
+	var errs *multierror.Error
+	WalkObjectTemplate(objectTemplate, func(objectTemplate *ObjectTemplate) {
+		object, ok := universe.GetNode().GetObjectFromAllObjects(*objectTemplate.ObjectID)
+		if !ok {
+			errs = multierror.Append(errs, errors.Errorf("object not found: %s", objectTemplate.ObjectID))
+			return
+		}
+		readable := newReadableObjectAttributes(ctx, object, userID)
+		attributes := make([]*entry.Attribute, 0, len(objectTemplate.ObjectAttributes))
+		for _, attribute := range objectTemplate.ObjectAttributes {
+			if readable.canRead(attribute.AttributeID) {
+				attributes = append(attributes, attribute)
+			}
+		}
+		objectTemplate.ObjectAttributes = attributes
+
+		objectTemplate.OwnerID = nil
+		objectTemplate.Label = nil
+	})
+	if err := errs.ErrorOrNil(); err != nil {
+		return nil, err
+	}
+	objectTemplate.ParentID = umid.Nil
+
+	return objectTemplate, nil
+}
+
+// ValidatePrefabTemplateAttributes checks that attributes of the template given as is are of cloneable
+// attribute types, options of the attributes in the template are not taken into account.
+func ValidatePrefabTemplateAttributes(template []byte) error {
+	var objectTemplate ObjectTemplate
+	if err := json.Unmarshal(template, &objectTemplate); err != nil {
+		return errors.WithMessage(err, "invalid template")
+	}
+
+	var errs *multierror.Error
+	WalkObjectTemplate(&objectTemplate, func(objectTemplate *ObjectTemplate) {
+		for _, attribute := range objectTemplate.ObjectAttributes {
+			attributeType, ok := universe.GetNode().GetAttributeTypes().GetAttributeType(
+				entry.AttributeTypeID(attribute.AttributeID),
+			)
+			if !ok {
+				errs = multierror.Append(errs, errors.Errorf("attribute type not found: %+v", attribute.AttributeID))
+				continue
+			}
+			options := attributeType.GetOptions()
+			if options == nil || utils.GetFromAnyMap(*options, "cloneable", map[string]any(nil)) == nil {
+				errs = multierror.Append(errs, errors.Errorf("attribute is not cloneable: %+v", attribute.AttributeID))
+			}
+		}
+	})
+
+	return errs.ErrorOrNil()
+}
+
+// ValidatePrefab checks the parameters definitions and that the template uses only defined parameters.
+func ValidatePrefab(template []byte, parameters []entry.PrefabParameter) error {
+	var objectTemplate ObjectTemplate
+	if err := json.Unmarshal(template, &objectTemplate); err != nil {
+		return errors.WithMessage(err, "invalid template")
+	}
+	if _, ok := universe.GetNode().GetObjectTypes().GetObjectType(objectTemplate.ObjectTypeID); !ok {
+		return errors.Errorf("object type not found: %s", objectTemplate.ObjectTypeID)
+	}
+
+	names := make(map[string]bool, len(parameters))
+	for _, parameter := range parameters {
+		if !prefabParameterNameRegexp.MatchString(parameter.Name) {
+			return errors.Errorf("invalid parameter name: %q", parameter.Name)
+		}
+		if names[parameter.Name] {
+			return errors.Errorf("duplicate parameter: %s", parameter.Name)
+		}
+		names[parameter.Name] = true
+
+		switch parameter.Kind {
+		case entry.PrefabParameterText, entry.PrefabParameterTexture, entry.PrefabParameterLink:
+		default:
+			return errors.Errorf("unknown kind of parameter: %s: %s", parameter.Name, parameter.Kind)
+		}
+		if parameter.Default != nil {
+			if err := validatePrefabParameterValue(parameter.Kind, parameter.Default); err != nil {
+				return errors.WithMessagef(err, "invalid default of parameter: %s", parameter.Name)
+			}
+		}
+	}
+
+	for _, match := range prefabPlaceholderRegexp.FindAllSubmatch(template, -1) {
+		if name := string(match[1]); !names[name] {
+			return errors.Errorf("template uses undefined parameter: %s", name)
+		}
+	}
+
+	return nil
+}
+
+func validatePrefabParameterValue(kind entry.PrefabParameterKind, value any) error {
+	str, ok := value.(string)
+
+	switch kind {
+	case entry.PrefabParameterText:
+		if !ok {
+			return errors.New("text expected")
+		}
+	case entry.PrefabParameterTexture:
+		if !ok || !prefabTextureRegexp.MatchString(str) {
+			return errors.New("image hash expected")
+		}
+	case entry.PrefabParameterLink:
+		if !ok {
+			return errors.New("link expected")
+		}
+		link, err := url.Parse(str)
+		if err != nil || (link.Scheme != "http" && link.Scheme != "https") || link.Host == "" {
+			return errors.New("http(s) link expected")
+		}
+	default:
+		return errors.Errorf("unknown parameter kind: %s", kind)
+	}
+
+	return nil
+}
+
+// ResolvePrefabParameters returns values of all parameters, defaults are used for missing values.
+func ResolvePrefabParameters(parameters []entry.PrefabParameter, values map[string]any) (map[string]any, error) {
+	known := make(map[string]bool, len(parameters))
+	resolved := make(map[string]any, len(parameters))
+	for _, parameter := range parameters {
+		known[parameter.Name] = true
+
+		value, ok := values[parameter.Name]
+		if !ok {
+			if parameter.Default == nil && parameter.Required {
+				return nil, errors.Errorf("missing required parameter: %s", parameter.Name)
+			}
+			// optional parameters without a value are replaced with an empty text
+			value = parameter.Default
+			if value == nil {
+				value = ""
+			}
+			resolved[parameter.Name] = value
+			continue
+		}
+
+		if err := validatePrefabParameterValue(parameter.Kind, value); err != nil {
+			return nil, errors.WithMessagef(err, "invalid parameter: %s", parameter.Name)
+		}
+		resolved[parameter.Name] = value
+	}
+
+	for name := range values {
+		if !known[name] {
+			return nil, errors.Errorf("unknown parameter: %s", name)
+		}
+	}
+
+	return resolved, nil
+}
+
+// InstantiatePrefab adds objects of the prefab version under the parent with the parameter values applied.
+// Objects get new umids, except the root which gets the objectID if it's set.
+// It returns umids of the added objects by umids of the template objects they are made from.
+func InstantiatePrefab(
+	prefab *entry.PrefabVersion,
+	parentID umid.UMID,
+	objectID *umid.UMID,
+	transform *cmath.Transform,
+	values map[string]any,
+	updateDB bool,
+) (umid.UMID, map[umid.UMID]umid.UMID, error) {
+	objectTemplate, objectIDs, err := newPrefabObjectTemplate(prefab, objectID, values)
+	if err != nil {
+		return umid.Nil, nil, err
+	}
+	objectTemplate.ParentID = parentID
+	if transform != nil {
+		objectTemplate.Transform = transform
+	}
+
+	id, err := AddObjectFromTemplate(objectTemplate, updateDB)
+	if err != nil {
+		return umid.Nil, nil, err
+	}
+
+	return id, objectIDs, nil
+}
+
+// ReplacePrefabInstance rebuilds the instance made from the instance version from the prefab version in place,
+// as the user, keeping its umid, parent and transform.
+// objectIDs are umids of the instance objects by umids of the template objects they were made from,
+// the umids for the prefab version are returned once the instance is replaced.
+// Objects of the new version are added and the instance root is updated first, all of it is undone on failure.
+// Objects added to the instance by users are kept, the ones nested in objects of the prefab are moved
+// to the objects made from the same template objects, the instance is not replaced if there are none.
+func ReplacePrefabInstance(
+	ctx context.Context,
+	object universe.Object,
+	userID umid.UMID,
+	objectIDs map[umid.UMID]umid.UMID,
+	instanceVersion *entry.PrefabVersion,
+	prefab *entry.PrefabVersion,
+	values map[string]any,
+) (map[umid.UMID]umid.UMID, error) {
+	node := universe.GetNode()
+
+	// values of parameters removed by the version are dropped
+	versionValues := make(map[string]any, len(values))
+	for _, parameter := range prefab.Parameters {
+		if value, ok := values[parameter.Name]; ok {
+			versionValues[parameter.Name] = value
+		}
+	}
+
+	instanceTemplate, _, err := newPrefabObjectTemplate(instanceVersion, nil, values)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get instance version template")
+	}
+	objectTemplate, newObjectIDs, err := newPrefabObjectTemplate(
+		prefab, utils.GetPTR(object.GetID()), versionValues,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get version template")
+	}
+	if len(objectIDs) == 0 && len(instanceTemplate.Objects) > 0 {
+		return nil, errors.New("objects of the instance are not recorded")
+	}
+
+	// template object umids by instance object umids
+	templateIDs := make(map[umid.UMID]umid.UMID, len(objectIDs))
+	for templateID, objectID := range objectIDs {
+		templateIDs[objectID] = templateID
+	}
+	templateIDs[object.GetID()] = umid.Nil
+
+	var instanceObjects []universe.Object
+	userObjects := make(map[universe.Object]umid.UMID)
+	for _, child := range object.GetObjects(false) {
+		templateID, ok := templateIDs[child.GetID()]
+		if !ok {
+			continue
+		}
+		instanceObjects = append(instanceObjects, child)
+		collectPrefabUserObjects(child, templateID, templateIDs, userObjects)
+	}
+	for userObject, templateID := range userObjects {
+		if _, ok := newObjectIDs[templateID]; !ok {
+			return nil, errors.Errorf("object is nested in a removed object of the prefab: %s", userObject.GetID())
+		}
+	}
+
+	objectType, ok := node.GetObjectTypes().GetObjectType(objectTemplate.ObjectTypeID)
+	if !ok {
+		return nil, errors.Errorf("failed to get object type: %s", objectTemplate.ObjectTypeID)
+	}
+	var asset2d universe.Asset2d
+	if objectTemplate.Asset2dID != nil {
+		asset2d, ok = node.GetAssets2d().GetAsset2d(*objectTemplate.Asset2dID)
+		if !ok {
+			return nil, errors.Errorf("asset 2d not found: %s", objectTemplate.Asset2dID)
+		}
+	}
+	var asset3d universe.Asset3d
+	if objectTemplate.Asset3dID != nil {
+		asset3d, ok = node.GetAssets3d().GetAsset3d(*objectTemplate.Asset3dID)
+		if !ok {
+			return nil, errors.Errorf("asset 3d not found: %s", objectTemplate.Asset3dID)
+		}
+	}
+
+	removedAttributeIDs := getPrefabRemovedAttributeIDs(instanceTemplate, objectTemplate)
+	if err := checkPrefabAttributesWrite(
+		ctx, object, userID, objectTemplate.ObjectAttributes, removedAttributeIDs,
+	); err != nil {
+		return nil, err
+	}
+
+	// everything done is undone in reverse order on failure
+	var undo []func() error
+	rollback := func(err error) error {
+		errs := multierror.Append(nil, err)
+		for i := len(undo) - 1; i >= 0; i-- {
+			if err := undo[i](); err != nil {
+				errs = multierror.Append(errs, errors.WithMessage(err, "failed to rollback"))
+			}
+		}
+		return errs.ErrorOrNil()
+	}
+
+	// adding objects of the new version
+	for i := range objectTemplate.Objects {
+		childTemplate := objectTemplate.Objects[i]
+		childTemplate.ParentID = object.GetID()
+		_, err := AddObjectFromTemplateByUser(childTemplate, userID, true)
+		// the object can be added partly
+		if child, ok := object.GetObject(*childTemplate.ObjectID, false); ok {
+			undo = append(undo, func() error {
+				_, err := RemoveObjectFromParent(object, child, true)
+				return err
+			})
+		}
+		if err != nil {
+			return nil, rollback(errors.WithMessage(err, "failed to add object from template"))
+		}
+	}
+
+	rootTemplate := ObjectToTemplate(object)
+	undo = append(undo, func() error {
+		return restorePrefabInstance(object, userID, rootTemplate, objectTemplate)
+	})
+	if err := updatePrefabInstance(
+		object, userID, objectTemplate, removedAttributeIDs, objectType, asset2d, asset3d,
+	); err != nil {
+		return nil, rollback(errors.WithMessage(err, "failed to update instance"))
+	}
+
+	for userObject, templateID := range userObjects {
+		userObject := userObject
+		oldParent := userObject.GetParent()
+		newParent, ok := node.GetObjectFromAllObjects(newObjectIDs[templateID])
+		if !ok {
+			return nil, rollback(errors.Errorf("object not found: %s", newObjectIDs[templateID]))
+		}
+		if err := moveObject(userObject, newParent); err != nil {
+			return nil, rollback(errors.WithMessagef(err, "failed to move object: %s", userObject.GetID()))
+		}
+		undo = append(undo, func() error {
+			return moveObject(userObject, oldParent)
+		})
+	}
+
+	// removing objects of the instance version, the new version is in place
+	if err := removePrefabObjects(object, instanceObjects); err != nil {
+		return newObjectIDs, errors.WithMessage(err, "failed to remove instance version objects")
+	}
+
+	return newObjectIDs, nil
+}
+
+// newPrefabObjectTemplate returns the object template of the prefab version with the parameter values applied.
+// Objects get new umids, except the root which gets the objectID if it's set.
+// It returns the new umids by umids of the template objects.
+func newPrefabObjectTemplate(
+	prefab *entry.PrefabVersion, objectID *umid.UMID, values map[string]any,
+) (*ObjectTemplate, map[umid.UMID]umid.UMID, error) {
+	resolved, err := ResolvePrefabParameters(prefab.Parameters, values)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to resolve parameters")
+	}
+
+	var template any
+	if err := json.Unmarshal(prefab.Template, &template); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to unmarshal template")
+	}
+	data, err := json.Marshal(applyPrefabParameters(template, resolved))
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to marshal template")
+	}
+
+	var objectTemplate ObjectTemplate
+	if err := json.Unmarshal(data, &objectTemplate); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to unmarshal object template")
+	}
+
+	root := &objectTemplate
+	objectIDs, err := remapObjectTemplate(root, func(objectTemplate *ObjectTemplate) umid.UMID {
+		if objectTemplate == root && objectID != nil {
+			return *objectID
+		}
+		return umid.New()
+	})
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to remap object template")
+	}
+
+	return root, objectIDs, nil
+}
+
+// collectPrefabUserObjects adds descendants of the instance object which are not made from the prefab
+// to the user objects with the template object umid of their closest instance object ancestor.
+func collectPrefabUserObjects(
+	object universe.Object,
+	templateID umid.UMID,
+	templateIDs map[umid.UMID]umid.UMID,
+	userObjects map[universe.Object]umid.UMID,
+) {
+	for _, child := range object.GetObjects(false) {
+		if childTemplateID, ok := templateIDs[child.GetID()]; ok {
+			collectPrefabUserObjects(child, childTemplateID, templateIDs, userObjects)
+			continue
+		}
+		// children of the user objects go with them
+		userObjects[child] = templateID
+	}
+}
+
+// getPrefabRemovedAttributeIDs returns attributes of the instance template root missing in the object template root.
+func getPrefabRemovedAttributeIDs(instanceTemplate, objectTemplate *ObjectTemplate) []entry.AttributeID {
+	attributeIDs := make(map[entry.AttributeID]bool, len(objectTemplate.ObjectAttributes))
+	for _, attribute := range objectTemplate.ObjectAttributes {
+		attributeIDs[attribute.AttributeID] = true
+	}
+
+	var removed []entry.AttributeID
+	for _, attribute := range instanceTemplate.ObjectAttributes {
+		if !attributeIDs[attribute.AttributeID] {
+			removed = append(removed, attribute.AttributeID)
+		}
+	}
+
+	return removed
+}
+
+// checkPrefabAttributesWrite checks that the user is allowed to write the attributes of the instance root.
+func checkPrefabAttributesWrite(
+	ctx context.Context,
+	object universe.Object, userID umid.UMID, attributes []*entry.Attribute, removedAttributeIDs []entry.AttributeID,
+) error {
+	attributeIDs := append([]entry.AttributeID(nil), removedAttributeIDs...)
+	for _, attribute := range attributes {
+		attributeIDs = append(attributeIDs, attribute.AttributeID)
+	}
+
+	for _, attributeID := range attributeIDs {
+		attributeType, ok := universe.GetNode().GetAttributeTypes().GetAttributeType(
+			entry.AttributeTypeID(attributeID),
+		)
+		if !ok {
+			return errors.Errorf("attribute type not found: %+v", attributeID)
+		}
+		allowed, err := auth.CheckAttributePermissions[entry.AttributeID](
+			ctx, *attributeType.GetEntry(), object.GetObjectAttributes(), attributeID, userID,
+			auth.WriteOperation,
+		)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to check attribute permissions: %+v", attributeID)
+		}
+		if !allowed {
+			return errors.Errorf("attribute write is not permitted for user: %+v", attributeID)
+		}
+	}
+
+	return nil
+}
+
+// updatePrefabInstance sets the instance root from the object template as the user.
+func updatePrefabInstance(
+	object universe.Object,
+	userID umid.UMID,
+	objectTemplate *ObjectTemplate,
+	removedAttributeIDs []entry.AttributeID,
+	objectType universe.ObjectType,
+	asset2d universe.Asset2d,
+	asset3d universe.Asset3d,
+) error {
+	if err := object.SetObjectType(objectType, true); err != nil {
+		return errors.WithMessagef(err, "failed to set object type: %s", objectTemplate.ObjectTypeID)
+	}
+	if err := object.SetAsset2D(asset2d, true); err != nil {
+		return errors.WithMessagef(err, "failed to set asset 2d: %s", objectTemplate.Asset2dID)
+	}
+	if err := object.SetAsset3D(asset3d, true); err != nil {
+		return errors.WithMessagef(err, "failed to set asset 3d: %s", objectTemplate.Asset3dID)
+	}
+	if _, err := object.SetOptions(modify.ReplaceWith(objectTemplate.Options), true); err != nil {
+		return errors.WithMessage(err, "failed to set options")
+	}
+	if objectTemplate.ObjectName != nil {
+		if err := object.SetName(*objectTemplate.ObjectName, true); err != nil {
+			return errors.WithMessage(err, "failed to set object name")
+		}
+	}
+
+	for _, attribute := range objectTemplate.ObjectAttributes {
+		if _, err := object.GetObjectAttributes().UpsertByUser(
+			userID, attribute.AttributeID, modify.ReplaceWith(attribute.AttributePayload), true,
+		); err != nil {
+			return errors.WithMessagef(err, "failed to upsert object attribute: %+v", attribute.AttributeID)
+		}
+	}
+	for _, attributeID := range removedAttributeIDs {
+		if _, err := object.GetObjectAttributes().RemoveByUser(userID, attributeID, true); err != nil {
+			return errors.WithMessagef(err, "failed to remove object attribute: %+v", attributeID)
+		}
+	}
+
+	if err := object.Update(true); err != nil {
+		return errors.WithMessage(err, "failed to update object")
+	}
+
+	return nil
+}
+
+// restorePrefabInstance sets the instance root back to the root template made before updating it from
+// the object template.
+func restorePrefabInstance(
+	object universe.Object, userID umid.UMID, rootTemplate *ObjectTemplate, objectTemplate *ObjectTemplate,
+) error {
+	node := universe.GetNode()
+
+	objectType, ok := node.GetObjectTypes().GetObjectType(rootTemplate.ObjectTypeID)
+	if !ok {
+		return errors.Errorf("failed to get object type: %s", rootTemplate.ObjectTypeID)
+	}
+	var asset2d universe.Asset2d
+	if rootTemplate.Asset2dID != nil {
+		asset2d, _ = node.GetAssets2d().GetAsset2d(*rootTemplate.Asset2dID)
+	}
+	var asset3d universe.Asset3d
+	if rootTemplate.Asset3dID != nil {
+		asset3d, _ = node.GetAssets3d().GetAsset3d(*rootTemplate.Asset3dID)
+	}
+
+	// attributes added from the object template are removed
+	removedAttributeIDs := getPrefabRemovedAttributeIDs(objectTemplate, rootTemplate)
+
+	return updatePrefabInstance(object, userID, rootTemplate, removedAttributeIDs, objectType, asset2d, asset3d)
+}
+
+// moveObject moves the object with its children under the new parent in the same world.
+func moveObject(object universe.Object, parent universe.Object) error {
+	oldParent := object.GetParent()
+	if oldParent == nil {
+		return errors.Errorf("object has no parent: %s", object.GetID())
+	}
+
+	if err := object.SetParent(parent, true); err != nil {
+		return errors.WithMessage(err, "failed to set parent")
+	}
+	if _, err := oldParent.RemoveObject(object, false, false); err != nil {
+		return errors.WithMessage(err, "failed to remove object from parent")
+	}
+	if err := parent.AddObject(object, false); err != nil {
+		return errors.WithMessage(err, "failed to add object to parent")
+	}
+
+	var errs *multierror.Error
+	for _, parent := range []universe.Object{oldParent, parent} {
+		if err := parent.UpdateChildrenPosition(true); err != nil {
+			errs = multierror.Append(
+				errs, errors.WithMessagef(err, "failed to update children position: %s", parent.GetID()),
+			)
+		}
+	}
+	if err := object.Update(true); err != nil {
+		errs = multierror.Append(errs, errors.WithMessage(err, "failed to update object"))
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// removePrefabObjects removes the objects from the instance.
+func removePrefabObjects(object universe.Object, objects []universe.Object) error {
+	var errs *multierror.Error
+	for _, child := range objects {
+		if _, err := RemoveObjectFromParent(object, child, true); err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to remove object: %s", child.GetID()))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+// applyPrefabParameters returns the value with placeholders replaced, a string which is a placeholder only
+// is replaced with the value itself.
+func applyPrefabParameters(value any, values map[string]any) any {
+	switch value := value.(type) {
+	case string:
+		if match := prefabPlaceholderRegexp.FindStringSubmatch(value); match != nil && match[0] == value {
+			if parameterValue, ok := values[match[1]]; ok {
+				return parameterValue
+			}
+			return value
+		}
+		return prefabPlaceholderRegexp.ReplaceAllStringFunc(value, func(placeholder string) string {
+			name := prefabPlaceholderRegexp.FindStringSubmatch(placeholder)[1]
+			if parameterValue, ok := values[name]; ok {
+				return fmt.Sprint(parameterValue)
+			}
+			return placeholder
+		})
+	case map[string]any:
+		applied := make(map[string]any, len(value))
+		for k, v := range value {
+			applied[k] = applyPrefabParameters(v, values)
+		}
+		return applied
+	case []any:
+		applied := make([]any, len(value))
+		for i := range value {
+			applied[i] = applyPrefabParameters(value[i], values)
+		}
+		return applied
+	}
+	return value
+}
@@ -0,0 +1,294 @@
+package tree
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// ObjectQueryMatch is an object matched by the query with its depth from the query root.
+type ObjectQueryMatch struct {
+	Object universe.Object
+	Depth  int
+
+	attributes *readableObjectAttributes
+}
+
+// readableObjectAttributes gives attributes of the object the user is allowed to read,
+// roles of the user are retrieved once for all attributes of the object.
+type readableObjectAttributes struct {
+	ctx    context.Context
+	object universe.Object
+	userID umid.UMID
+
+	roles       []entry.PermissionsRoleType
+	rolesErr    error
+	rolesLoaded bool
+}
+
+func newReadableObjectAttributes(ctx context.Context, object universe.Object, userID umid.UMID) *readableObjectAttributes {
+	return &readableObjectAttributes{
+		ctx:    ctx,
+		object: object,
+		userID: userID,
+	}
+}
+
+// getPayload returns the payload of the attribute, unreadable attributes are reported as missing.
+func (ra *readableObjectAttributes) getPayload(attributeID entry.AttributeID) (*entry.AttributePayload, bool) {
+	if !ra.canRead(attributeID) {
+		return nil, false
+	}
+	return ra.object.GetObjectAttributes().GetPayload(attributeID)
+}
+
+func (ra *readableObjectAttributes) canRead(attributeID entry.AttributeID) bool {
+	attributeType, ok := universe.GetNode().GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID))
+	if !ok {
+		return false
+	}
+
+	allowed, err := auth.CheckAttributeRolesPermissions[entry.AttributeID](
+		ra.ctx, *attributeType.GetEntry(), ra.object.GetObjectAttributes(), attributeID, ra.getRoles,
+		auth.ReadOperation,
+	)
+	return err == nil && allowed
+}
+
+func (ra *readableObjectAttributes) getRoles() ([]entry.PermissionsRoleType, error) {
+	if !ra.rolesLoaded {
+		ra.roles, ra.rolesErr = universe.GetNode().GetUserObjects().GetUserRoles(
+			entry.NewUserObjectID(ra.userID, ra.object.GetID()),
+		)
+		ra.rolesLoaded = true
+	}
+	return ra.roles, ra.rolesErr
+}
+
+// ValidateObjectQuery checks the query and the projection before running it.
+func ValidateObjectQuery(query *entry.ObjectQuery, projection *entry.ObjectQueryProjection) error {
+	if query.MinDepth != nil && *query.MinDepth < 0 {
+		return errors.New("min_depth must not be negative")
+	}
+	if query.MaxDepth != nil && *query.MaxDepth < 0 {
+		return errors.New("max_depth must not be negative")
+	}
+	if query.MinDepth != nil && query.MaxDepth != nil && *query.MinDepth > *query.MaxDepth {
+		return errors.New("min_depth is greater than max_depth")
+	}
+	if region := query.Region; region != nil {
+		if region.Min.X > region.Max.X || region.Min.Y > region.Max.Y || region.Min.Z > region.Max.Z {
+			return errors.New("region min is greater than max")
+		}
+	}
+
+	for _, field := range projection.Fields {
+		switch field {
+		case entry.ObjectQueryFieldName, entry.ObjectQueryFieldObjectTypeID, entry.ObjectQueryFieldParentID,
+			entry.ObjectQueryFieldOwnerID, entry.ObjectQueryFieldTransform, entry.ObjectQueryFieldDepth,
+			entry.ObjectQueryFieldChildrenCount, entry.ObjectQueryFieldCreatedAt, entry.ObjectQueryFieldUpdatedAt:
+		default:
+			return errors.Errorf("unknown field: %s", field)
+		}
+	}
+
+	return nil
+}
+
+// QueryObjects returns objects of the tree under the root, the root included, matching the query run by the user.
+// Attribute filters match only attributes the user is allowed to read.
+// Matches are ordered by umid and start after the afterID if set, more is true if there are more than limit.
+func QueryObjects(
+	ctx context.Context, root universe.Object, userID umid.UMID, query *entry.ObjectQuery, afterID *umid.UMID, limit int,
+) (matches []*ObjectQueryMatch, more bool) {
+	matches = make([]*ObjectQueryMatch, 0)
+	walkQueryObjects(ctx, root, userID, 0, query, func(match *ObjectQueryMatch) {
+		if afterID != nil && compareUMIDs(match.Object.GetID(), *afterID) <= 0 {
+			return
+		}
+		matches = append(matches, match)
+	})
+
+	sort.Slice(matches, func(i, j int) bool {
+		return compareUMIDs(matches[i].Object.GetID(), matches[j].Object.GetID()) < 0
+	})
+	if len(matches) > limit {
+		return matches[:limit], true
+	}
+
+	return matches, false
+}
+
+// ProjectObjectQueryMatch returns the selected fields and attribute values of the match, keyed by their json names.
+// Attribute values are keyed by plugin umid and attribute name,
+// missing attributes and attributes the user running the query is not allowed to read are omitted.
+func ProjectObjectQueryMatch(match *ObjectQueryMatch, projection *entry.ObjectQueryProjection) map[string]any {
+	object := match.Object
+
+	result := map[string]any{"object_id": object.GetID()}
+	for _, field := range projection.Fields {
+		switch field {
+		case entry.ObjectQueryFieldName:
+			result[string(field)] = object.GetName()
+		case entry.ObjectQueryFieldObjectTypeID:
+			result[string(field)] = object.GetObjectType().GetID()
+		case entry.ObjectQueryFieldParentID:
+			var parentID *umid.UMID
+			if parent := object.GetParent(); parent != nil {
+				id := parent.GetID()
+				parentID = &id
+			}
+			result[string(field)] = parentID
+		case entry.ObjectQueryFieldOwnerID:
+			result[string(field)] = object.GetOwnerID()
+		case entry.ObjectQueryFieldTransform:
+			result[string(field)] = object.GetActualTransform()
+		case entry.ObjectQueryFieldDepth:
+			result[string(field)] = match.Depth
+		case entry.ObjectQueryFieldChildrenCount:
+			result[string(field)] = len(object.GetChildIDs())
+		case entry.ObjectQueryFieldCreatedAt:
+			result[string(field)] = object.GetCreatedAt()
+		case entry.ObjectQueryFieldUpdatedAt:
+			result[string(field)] = object.GetUpdatedAt()
+		}
+	}
+
+	if len(projection.Attributes) > 0 {
+		attributes := make(map[umid.UMID]map[string]*entry.AttributeValue)
+		for _, attributeID := range projection.Attributes {
+			payload, ok := match.attributes.getPayload(attributeID)
+			if !ok || payload == nil || payload.Value == nil {
+				continue
+			}
+			value := payload.Value
+			if attributes[attributeID.PluginID] == nil {
+				attributes[attributeID.PluginID] = make(map[string]*entry.AttributeValue)
+			}
+			attributes[attributeID.PluginID][attributeID.Name] = value
+		}
+		result["attributes"] = attributes
+	}
+
+	return result
+}
+
+func walkQueryObjects(
+	ctx context.Context,
+	object universe.Object,
+	userID umid.UMID,
+	depth int,
+	query *entry.ObjectQuery,
+	matchFn func(match *ObjectQueryMatch),
+) {
+	if query.MaxDepth != nil && depth > *query.MaxDepth {
+		return
+	}
+
+	match := &ObjectQueryMatch{
+		Object:     object,
+		Depth:      depth,
+		attributes: newReadableObjectAttributes(ctx, object, userID),
+	}
+	if matchObjectQuery(match, query) {
+		matchFn(match)
+	}
+
+	for _, child := range object.GetObjects(false) {
+		walkQueryObjects(ctx, child, userID, depth+1, query, matchFn)
+	}
+}
+
+func matchObjectQuery(match *ObjectQueryMatch, query *entry.ObjectQuery) bool {
+	object, depth := match.Object, match.Depth
+
+	if query.MinDepth != nil && depth < *query.MinDepth {
+		return false
+	}
+
+	if len(query.ObjectTypeIDs) > 0 && !containsUMID(query.ObjectTypeIDs, object.GetObjectType().GetID()) {
+		return false
+	}
+	if len(query.OwnerIDs) > 0 && !containsUMID(query.OwnerIDs, object.GetOwnerID()) {
+		return false
+	}
+
+	if region := query.Region; region != nil {
+		transform := object.GetActualTransform()
+		if transform == nil {
+			return false
+		}
+		p := transform.Position
+		if p.X < region.Min.X || p.Y < region.Min.Y || p.Z < region.Min.Z ||
+			p.X > region.Max.X || p.Y > region.Max.Y || p.Z > region.Max.Z {
+			return false
+		}
+	}
+
+	for i := range query.Attributes {
+		if !matchObjectQueryAttribute(match.attributes, &query.Attributes[i]) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchObjectQueryAttribute checks the attribute filter, filters of attributes the user can't read never match.
+func matchObjectQueryAttribute(attributes *readableObjectAttributes, filter *entry.ObjectQueryAttributeFilter) bool {
+	exists := filter.Exists == nil || *filter.Exists
+
+	if !attributes.canRead(filter.AttributeID) {
+		return false
+	}
+	payload, ok := attributes.object.GetObjectAttributes().GetPayload(filter.AttributeID)
+	if !ok || payload == nil {
+		return !exists
+	}
+	if !exists {
+		return false
+	}
+
+	for key, expected := range filter.Value {
+		var actual any
+		if payload.Value != nil {
+			actual, ok = (*payload.Value)[key]
+			if !ok {
+				return false
+			}
+		}
+		if !isEqualJSON(expected, actual) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// isEqualJSON compares values by their json encoding, so numbers of different types are equal.
+func isEqualJSON(a, b any) bool {
+	dataA, errA := json.Marshal(a)
+	dataB, errB := json.Marshal(b)
+	return errA == nil && errB == nil && bytes.Equal(dataA, dataB)
+}
+
+func containsUMID(ids []umid.UMID, id umid.UMID) bool {
+	for i := range ids {
+		if ids[i] == id {
+			return true
+		}
+	}
+	return false
+}
+
+func compareUMIDs(a, b umid.UMID) int {
+	return bytes.Compare(a[:], b[:])
+}
@@ -0,0 +1,185 @@
+package tree
+
+import (
+	"context"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/database"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var ErrTrashParentNotFound = errors.New("parent of trashed object not found")
+
+// TrashObject removes the object with its children from the tree but keeps them in the database,
+// so they can be restored until the trash item expires.
+func TrashObject(
+	ctx context.Context, db database.DB, object universe.Object, userID umid.UMID, retention time.Duration,
+) (*entry.ObjectTrashItem, error) {
+	parent := object.GetParent()
+	if parent == nil {
+		return nil, errors.Errorf("object has no parent: %s", object.GetID())
+	}
+
+	now := time.Now()
+	item := &entry.ObjectTrashItem{
+		ObjectID:  object.GetID(),
+		WorldID:   object.GetWorld().GetID(),
+		ParentID:  parent.GetID(),
+		Name:      object.GetName(),
+		DeletedBy: utils.GetPTR(userID),
+		DeletedAt: now,
+		ExpiresAt: now.Add(retention),
+	}
+	if err := db.GetObjectTrashDB().UpsertObjectTrashItem(ctx, item); err != nil {
+		return nil, errors.WithMessage(err, "failed to upsert object trash item")
+	}
+
+	removed, err := RemoveObjectFromParent(parent, object, false)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to remove object from parent")
+	}
+	if !removed {
+		return nil, errors.Errorf("object not found in parent: %s", object.GetID())
+	}
+
+	return item, nil
+}
+
+// TrashWorld stops the world with all its objects, they are kept in the database until the trash item expires.
+func TrashWorld(
+	ctx context.Context, db database.DB, world universe.World, userID umid.UMID, retention time.Duration,
+) (*entry.ObjectTrashItem, error) {
+	now := time.Now()
+	item := &entry.ObjectTrashItem{
+		ObjectID:  world.GetID(),
+		WorldID:   world.GetID(),
+		ParentID:  universe.GetNode().GetID(),
+		Name:      world.GetName(),
+		DeletedBy: utils.GetPTR(userID),
+		DeletedAt: now,
+		ExpiresAt: now.Add(retention),
+	}
+	if err := db.GetObjectTrashDB().UpsertObjectTrashItem(ctx, item); err != nil {
+		return nil, errors.WithMessage(err, "failed to upsert object trash item")
+	}
+
+	if _, err := RemoveWorld(world, false); err != nil {
+		return nil, errors.WithMessage(err, "failed to remove world")
+	}
+
+	return item, nil
+}
+
+// RestoreTrashItem loads the trashed object with its children back into the tree under its former parent.
+// Returns ErrTrashParentNotFound if the parent is gone, the item is kept in the trash then.
+func RestoreTrashItem(ctx context.Context, db database.DB, item *entry.ObjectTrashItem) (universe.Object, error) {
+	if item.ObjectID == item.WorldID {
+		world, err := restoreWorld(ctx, db, item)
+		if err != nil {
+			return nil, err
+		}
+		return world.ToObject(), nil
+	}
+
+	node := universe.GetNode()
+	parent, ok := node.GetObjectFromAllObjects(item.ParentID)
+	if !ok {
+		return nil, errors.WithMessagef(ErrTrashParentNotFound, "parent: %s", item.ParentID)
+	}
+
+	objectEntry, err := db.GetObjectsDB().GetObjectByID(ctx, item.ObjectID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get object by umid")
+	}
+
+	object, err := parent.CreateObject(item.ObjectID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create object")
+	}
+	// the trash item is kept until the object is loaded, so a failed restore can be retried
+	if err := object.LoadFromEntry(objectEntry, true); err != nil {
+		err := errors.WithMessage(err, "failed to load object from entry")
+		return nil, unloadRestoredObject(parent, object, err)
+	}
+	if _, err := db.GetObjectTrashDB().RemoveObjectTrashItemByID(ctx, item.ObjectID); err != nil {
+		err := errors.WithMessage(err, "failed to remove object trash item")
+		return nil, unloadRestoredObject(parent, object, err)
+	}
+
+	var errs *multierror.Error
+	objects := object.GetObjects(true)
+	objects[object.GetID()] = object
+	for _, object := range objects {
+		if err := object.Run(); err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to run object: %s", object.GetID()))
+		}
+		object.SetEnabled(true)
+	}
+
+	if err := object.UpdateChildrenPosition(true); err != nil {
+		errs = multierror.Append(errs, errors.WithMessage(err, "failed to update children position"))
+	}
+	if err := parent.UpdateChildrenPosition(true); err != nil {
+		errs = multierror.Append(
+			errs, errors.WithMessagef(err, "failed to update children position: %s", parent.GetID()),
+		)
+	}
+	if err := object.Update(true); err != nil {
+		errs = multierror.Append(errs, errors.WithMessage(err, "failed to update object"))
+	}
+
+	logic.GetLogger().Infof("Helper: RestoreTrashItem: object restored: %s", object.GetID())
+
+	return object, errs.ErrorOrNil()
+}
+
+func restoreWorld(ctx context.Context, db database.DB, item *entry.ObjectTrashItem) (universe.World, error) {
+	node := universe.GetNode()
+	if _, ok := node.GetWorlds().GetWorld(item.WorldID); ok {
+		return nil, errors.Errorf("world already exists: %s", item.WorldID)
+	}
+
+	world, err := node.GetWorlds().CreateWorld(item.WorldID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create world")
+	}
+	if err := world.Load(); err != nil {
+		err := errors.WithMessage(err, "failed to load world")
+		return nil, unloadRestoredWorld(world, err)
+	}
+	if _, err := db.GetObjectTrashDB().RemoveObjectTrashItemByID(ctx, item.ObjectID); err != nil {
+		err := errors.WithMessage(err, "failed to remove object trash item")
+		return nil, unloadRestoredWorld(world, err)
+	}
+	if err := world.Run(); err != nil {
+		return nil, errors.WithMessage(err, "failed to run world")
+	}
+	world.SetEnabled(true)
+
+	logic.GetLogger().Infof("Helper: RestoreTrashItem: world restored: %s", world.GetID())
+
+	return world, nil
+}
+
+// unloadRestoredObject removes the object which failed to restore from the tree, leaving it in the db and the trash.
+func unloadRestoredObject(parent, object universe.Object, err error) error {
+	if _, removeErr := parent.RemoveObject(object, true, false); removeErr != nil {
+		return multierror.Append(err, errors.WithMessage(removeErr, "failed to unload object"))
+	}
+	return err
+}
+
+// unloadRestoredWorld removes the world which failed to restore from the node, leaving it in the db and the trash.
+func unloadRestoredWorld(world universe.World, err error) error {
+	if _, removeErr := RemoveWorld(world, false); removeErr != nil {
+		return multierror.Append(err, errors.WithMessage(removeErr, "failed to unload world"))
+	}
+	return err
+}
@@ -4,10 +4,12 @@ import (
 	"math/rand"
 	"time"
 
+	"github.com/hashicorp/go-multierror"
 	"github.com/pkg/errors"
 
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic"
 	"github.com/momentum-xyz/ubercontroller/utils"
 	"github.com/momentum-xyz/ubercontroller/utils/modify"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
@@ -138,6 +140,79 @@ func AddWorldFromTemplate(worldTemplate *WorldTemplate, updateDB bool) (umid.UMI
 	return id, err
 }
 
+// WorldToTemplate is the reverse of AddWorldFromTemplate: it returns the template of the world with all its objects.
+// Labels of the world objects are taken from the world settings, which are rebuilt on creation.
+func WorldToTemplate(world universe.World) *WorldTemplate {
+	worldTemplate := &WorldTemplate{
+		ObjectTemplate: *ObjectToTemplate(world),
+	}
+
+	settingsID := entry.NewAttributeID(universe.GetSystemPluginID(), universe.ReservedAttributes.World.Settings.Name)
+	for i, attribute := range worldTemplate.ObjectAttributes {
+		if attribute.AttributeID != settingsID || attribute.AttributePayload == nil || attribute.Value == nil {
+			continue
+		}
+
+		settings := make(entry.AttributeValue, len(*attribute.Value))
+		for key, value := range *attribute.Value {
+			if key != "objects" {
+				settings[key] = value
+			}
+		}
+		worldTemplate.ObjectAttributes[i] = entry.NewAttribute(
+			settingsID, entry.NewAttributePayload(&settings, attribute.Options),
+		)
+
+		var labels map[string]umid.UMID
+		if err := utils.MapDecode((*attribute.Value)["objects"], &labels); err != nil {
+			break
+		}
+		for label, objectID := range labels {
+			for _, objectTemplate := range worldTemplate.Objects {
+				if *objectTemplate.ObjectID == objectID {
+					objectTemplate.Label = utils.GetPTR(label)
+				}
+			}
+		}
+		break
+	}
+
+	return worldTemplate
+}
+
+// RemoveWorld stops the world with all its objects and removes them.
+func RemoveWorld(world universe.World, updateDB bool) (bool, error) {
+	node := universe.GetNode()
+
+	removed, err := node.GetWorlds().RemoveWorld(world, updateDB)
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to remove world: %s", world.GetID())
+	}
+	if !removed {
+		return false, nil
+	}
+
+	var errs *multierror.Error
+	for _, object := range world.GetAllObjects() {
+		// prevent spam while removing
+		object.SetEnabled(false)
+
+		if _, err := node.RemoveObjectFromAllObjects(object); err != nil {
+			errs = multierror.Append(
+				errs, errors.WithMessagef(err, "failed to remove object from node: %s", object.GetID()),
+			)
+		}
+	}
+
+	if err := world.Stop(); err != nil {
+		errs = multierror.Append(errs, errors.WithMessage(err, "failed to stop world"))
+	}
+
+	logic.GetLogger().Infof("Helper: RemoveWorld: world removed: %s", world.GetID())
+
+	return true, errs.ErrorOrNil()
+}
+
 func addNewWorldCreatedActivity(id umid.UMID) error {
 	node := universe.GetNode()
 	a, err := node.GetActivities().CreateActivity(umid.New())
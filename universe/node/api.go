@@ -27,12 +27,21 @@ import (
 // @name Authorization
 // @description Authorization header with "Bearer" followed by a space and JWT token.
 
+// @securityDefinitions.apikey APIKey
+// @in header
+// @name X-API-Key
+// @description API key issued by a node admin, limited by its scopes.
+
 // @tag.name auth
 // @tag.name users
 // @tag.name profile
+// @tag.name avatar
 // @tag.name worlds
 // @tag.name objects
 // @tag.name members
+// @tag.name friends
+// @tag.name moderation
+// @tag.name search
 // @tag.name media
 // @tag.name assets2d
 // @tag.name assets3d
@@ -76,6 +85,12 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 			auth.POST("/token", n.apiGenToken)
 
 			auth.POST("/guest-token", n.apiGuestToken)
+
+			auth.POST("/refresh", n.apiRefreshToken)
+
+			auth.GET("/oidc", n.apiOIDCGetProviders)
+			auth.GET("/oidc/:provider/authorize", n.apiOIDCAuthorize)
+			auth.POST("/oidc/:provider/token", n.apiOIDCGenToken)
 		}
 
 		verified := vx.Group("", middleware.VerifyUser(n.log))
@@ -88,8 +103,8 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 
 			media.POST("/upload/image", n.apiMediaUploadImage)
 
-			// media.GET("/get/:file", n.apiMediaGetPlugin)
-			media.Static("/render/plugin/", n.CFG.Media.Pluginpath)
+			media.GET("/render/plugin/*file", n.apiMediaGetPlugin)
+			media.HEAD("/render/plugin/*file", n.apiMediaGetPlugin)
 			media.POST("/upload/plugin", middleware.VerifyUser(n.log), middleware.AuthorizeNodeAdmin(n.log), n.apiMediaUploadPlugin)
 
 			media.GET("/render/video/:file", n.apiMediaGetVideo)
@@ -108,20 +123,58 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 		verified.GET("/leonardo/generate/:leonardoID", n.apiGetImageGeneration)
 		verified.POST("/leonardo/generate", n.apiPostImageGenerationID)
 
+		verified.GET("/search", n.apiSearch)
+
 		verifiedUsers := verified.Group("/users")
 		{
+			// reading endpoints available for api keys
 			userMe := verifiedUsers.Group("/me")
 			{
 				userMe.GET("", n.apiUsersGetMe)
 				userMe.GET("/attributes", n.apiGetMeUserAttributeValue)
+				userMe.GET("/stakes", n.apiGetMyStakes)
+				userMe.GET("/wallets", n.apiGetMyWallets)
+				userMe.GET("/friends", n.apiUsersGetMyFriends)
+				userMe.GET("/friends/requests", n.apiUsersGetMyFriendRequests)
+				userMe.GET("/following", n.apiUsersGetMyFollowing)
+				userMe.GET("/followers", n.apiUsersGetMyFollowers)
+				userMe.GET("/blocked", n.apiUsersGetMyBlocked)
+				userMe.GET("/avatar", n.apiUsersGetMyAvatar)
+			}
 
-				userMe.POST("/attach-account", n.apiAttachAccount)
-				userMe.DELETE("/remove-wallet", n.apiDeleteWallet)
+			// account management, not available for api keys
+			userMeDenyAPIKey := verifiedUsers.Group("/me", middleware.DenyAPIKey(n.log))
+			{
+				userMeDenyAPIKey.DELETE("", n.apiUsersRemoveMe)
+				userMeDenyAPIKey.GET("/export", n.apiUsersExportMe)
 
-				userMe.GET("/stakes", n.apiGetMyStakes)
-				userMe.POST("/stakes", n.apiAddPendingStakeTransaction)
+				userMeDenyAPIKey.POST("/attach-account", n.apiAttachAccount)
+				userMeDenyAPIKey.DELETE("/remove-wallet", n.apiDeleteWallet)
 
-				userMe.GET("/wallets", n.apiGetMyWallets)
+				userMeDenyAPIKey.GET("/oidc/:provider/authorize", n.apiOIDCAttachAuthorize)
+				userMeDenyAPIKey.POST("/oidc/:provider/attach", n.apiAttachOIDCAccount)
+
+				userMeDenyAPIKey.POST("/stakes", n.apiAddPendingStakeTransaction)
+
+				userMeDenyAPIKey.POST("/friends/:userID", n.apiUsersRequestFriendship)
+				userMeDenyAPIKey.DELETE("/friends/:userID", n.apiUsersRemoveFriendship)
+				userMeDenyAPIKey.POST("/friends/:userID/accept", n.apiUsersAcceptFriendship)
+				userMeDenyAPIKey.POST("/friends/:userID/join", n.apiUsersJoinFriend)
+
+				userMeDenyAPIKey.POST("/following/:userID", n.apiUsersFollow)
+				userMeDenyAPIKey.DELETE("/following/:userID", n.apiUsersUnfollow)
+
+				userMeDenyAPIKey.POST("/blocked/:userID", n.apiUsersBlock)
+				userMeDenyAPIKey.DELETE("/blocked/:userID", n.apiUsersUnblock)
+
+				userMeDenyAPIKey.PUT("/presence-visibility", n.apiUsersSetMyPresenceVisibility)
+
+				userMeDenyAPIKey.PUT("/avatar", n.apiUsersSetMyAvatar)
+				userMeDenyAPIKey.DELETE("/avatar", n.apiUsersRemoveMyAvatar)
+
+				userMeDenyAPIKey.GET("/sessions", n.apiUsersGetMySessions)
+				userMeDenyAPIKey.DELETE("/sessions", n.apiUsersRemoveMySessions)
+				userMeDenyAPIKey.DELETE("/sessions/:sessionID", n.apiUsersRemoveMySession)
 			}
 
 			verifiedUsers.POST("/mutual-docks", n.apiUsersCreateMutualDocks)
@@ -137,12 +190,19 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 				user.GET("/worlds", n.apiUsersGetOwnedWorlds)
 				user.GET("/staked-worlds", n.apiUsersGetStakedWorlds)
 
+				user.DELETE("/sessions", middleware.AuthorizeNodeAdmin(n.log), n.apiUsersRemoveSessions)
+
+				user.POST("/report", n.apiUsersReportUser)
+				user.GET("/presence", n.apiUsersGetPresence)
+				user.GET("/avatar", n.apiUsersGetAvatar)
+
 				userAttributesGroup := user.Group("/attributes")
 				{
 					userAttributesGroup.GET("", n.apiGetUserAttributeValue)
 					userAttributesGroup.GET("/sub", n.apiGetUserAttributeSubValue)
 
 					userAttributesGroup.POST("", n.apiSetUserAttributeValue)
+					userAttributesGroup.PATCH("", n.apiPatchUserAttributeValue)
 					userAttributesGroup.DELETE("", n.apiRemoveUserAttributeValue)
 
 					userAttributesGroup.POST("/sub", n.apiSetUserAttributeSubValue)
@@ -152,6 +212,7 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 
 			userUserAttributesGroup := verifiedUsers.Group("/attributes")
 			{
+				userUserAttributesGroup.PATCH("/:userID/:targetID", n.apiPatchUserUserAttributeValue)
 				userUserAttributesGroup.POST("/sub/:userID/:targetID", n.apiSetUserUserSubAttributeValue)
 			}
 		}
@@ -173,6 +234,7 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 			verifiedNode.GET("/attributes", n.apiNodeGetAttributesValue)
 
 			verifiedNode.POST("/attributes", n.apiNodeSetAttributesValue)
+			verifiedNode.PATCH("/attributes", n.apiNodePatchAttributesValue)
 			verifiedNode.DELETE("/attributes", n.apiNodeRemoveAttributesValue)
 
 			verifiedNode.GET("/hosting-allow-list", middleware.AuthorizeNodeAdmin(n.log), n.apiGetHostingAllowList)
@@ -180,6 +242,43 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 			verifiedNode.DELETE("/hosting-allow-list/:userID", middleware.AuthorizeNodeAdmin(n.log), n.apiDeleteItemFromHostingAllowList)
 
 			verifiedNode.POST("/activate-plugin", middleware.AuthorizeNodeAdmin(n.log), n.apiNodeActivatePlugin)
+
+			verifiedNode.POST("/search/reindex", middleware.AuthorizeNodeAdmin(n.log), n.apiNodeReindexSearch)
+
+			apiKeys := verifiedNode.Group("/api-keys", middleware.DenyAPIKey(n.log), middleware.AuthorizeNodeAdmin(n.log))
+			{
+				apiKeys.GET("", n.apiNodeGetAPIKeys)
+				apiKeys.POST("", n.apiNodeCreateAPIKey)
+				apiKeys.DELETE("/:apiKeyID", n.apiNodeRemoveAPIKey)
+				apiKeys.GET("/:apiKeyID/audit", n.apiNodeGetAPIKeyAudit)
+			}
+
+			moderation := verifiedNode.Group("/moderation", middleware.AuthorizeNodeAdmin(n.log))
+			{
+				moderation.GET("/bans", n.apiNodeGetBans)
+				moderation.POST("/bans", n.apiNodeBanUser)
+				moderation.DELETE("/bans/:banID", n.apiNodeRemoveBan)
+
+				moderation.GET("/mutes", n.apiNodeGetMutes)
+				moderation.POST("/mutes", n.apiNodeMuteUser)
+				moderation.DELETE("/mutes/:muteID", n.apiNodeRemoveMute)
+
+				moderation.GET("/reports", n.apiNodeGetUserReports)
+				moderation.PATCH("/reports/:reportID", n.apiNodeResolveUserReport)
+			}
+		}
+
+		verifiedPrefabs := verified.Group("/prefabs")
+		{
+			verifiedPrefabs.GET("", n.apiGetPrefabs)
+			verifiedPrefabs.POST("", n.apiPublishPrefab)
+
+			verifiedPrefabs.GET("/:prefabID", n.apiGetPrefab)
+			verifiedPrefabs.PUT("/:prefabID", n.apiUpdatePrefab)
+			verifiedPrefabs.DELETE("/:prefabID", n.apiRemovePrefab)
+
+			verifiedPrefabs.GET("/:prefabID/versions", n.apiGetPrefabVersions)
+			verifiedPrefabs.POST("/:prefabID/instantiate", n.apiInstantiatePrefab)
 		}
 
 		verifiedObjects := verified.Group("/objects")
@@ -199,6 +298,17 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 				object.POST("/spawn-by-user", n.apiSpawnByUser)
 
 				object.GET("/tree", n.apiGetObjectsTree)
+				object.POST("/query", n.apiQueryObjects)
+				object.GET("/lock", n.apiGetObjectLock)
+
+				edits := object.Group("/edits")
+				{
+					edits.GET("", n.apiGetEditJournal)
+					edits.POST("/begin", n.apiBeginEditTransaction)
+					edits.POST("/commit", n.apiCommitEditTransaction)
+					edits.POST("/undo", n.apiUndoEdit)
+					edits.POST("/redo", n.apiRedoEdit)
+				}
 
 				objectAdmin := object.Group("", middleware.AuthorizeAdmin(n.log))
 				{
@@ -212,9 +322,19 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 					objectAdmin.PATCH("", n.apiUpdateObject)
 
 					objectAdmin.POST("/clone", n.apiCloneObject)
+					objectAdmin.POST("/batch", n.apiApplyObjectsBatch)
+
+					objectAdmin.POST("/placement/preview", n.apiPreviewObjectPlacement)
+
+					objectAdmin.DELETE("/lock", n.apiForceUnlockObject)
+
+					objectAdmin.GET("/attributes/history", n.apiGetObjectAttributesHistory)
+					objectAdmin.POST("/attributes/restore", n.apiRestoreObjectAttribute)
+					objectAdmin.POST("/restore", n.apiRestoreObjectAttributes)
 				}
 
 				object.POST("/attributes", n.apiSetObjectAttributesValue)
+				object.PATCH("/attributes", n.apiPatchObjectAttributeValue)
 				object.DELETE("/attributes", n.apiRemoveObjectAttributeValue)
 
 				object.POST("/attributes/sub", n.apiSetObjectAttributeSubValue)
@@ -251,6 +371,8 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 					}
 				}
 
+				object.GET("/permissions", n.apiGetObjectUserPermissions)
+
 				members := object.Group("/members", middleware.AuthorizeAdmin(n.log))
 				{
 					members.GET("", n.apiMembersGetForObject)
@@ -262,6 +384,7 @@ func (n *Node) RegisterAPI(r *gin.Engine) {
 			objectUser := verifiedObjects.Group("/:objectID/:userID")
 			{
 				objectUser.POST("/attributes", n.apiSetObjectUserAttributesValue)
+				objectUser.PATCH("/attributes", n.apiPatchObjectUserAttributeValue)
 				objectUser.DELETE("/attributes", n.apiRemoveObjectUserAttributeValue)
 
 				objectUser.POST("/attributes/sub", n.apiSetObjectUserAttributeSubValue)
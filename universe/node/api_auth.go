@@ -2,12 +2,16 @@ package node
 
 import (
 	"net/http"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 
+	"github.com/momentum-xyz/ubercontroller/pkg/siwe"
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
 	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
@@ -16,7 +20,8 @@ import (
 )
 
 // @Summary Generate auth challenge
-// @Description Returns a new generated challenge based on params
+// @Description Returns a new Sign-In with Ethereum (EIP-4361) message to sign with the wallet.
+// @Description Clients can also build the message themselves using the returned nonce.
 // @Tags auth
 // @Param query query node.apiGenChallenge.InQuery true "query params"
 // @Success 200 {object} node.apiGenChallenge.Out
@@ -24,7 +29,8 @@ import (
 // @Router /api/v4/auth/challenge [get]
 func (n *Node) apiGenChallenge(c *gin.Context) {
 	type InQuery struct {
-		Wallet string `form:"wallet" json:"wallet" binding:"required"`
+		Wallet  string `form:"wallet" json:"wallet" binding:"required"`
+		ChainID int64  `form:"chain_id" json:"chain_id"`
 	}
 	var inQuery InQuery
 
@@ -34,52 +40,32 @@ func (n *Node) apiGenChallenge(c *gin.Context) {
 		return
 	}
 
-	challenge, err := api.GenerateChallenge(inQuery.Wallet)
+	message, err := n.newAuthChallenge(inQuery.Wallet, inQuery.ChainID)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiGenChallenge: failed to generate challenge")
 		api.AbortRequest(c, http.StatusInternalServerError, "challenge_generation_failed", err, n.log)
 		return
 	}
 
-	challengesKey := universe.ReservedAttributes.Kusama.Challenges.Key
-	modifyFn := func(current *entry.AttributeValue) (*entry.AttributeValue, error) {
-		if current == nil {
-			current = entry.NewAttributeValue()
-		}
-
-		challenges := utils.GetFromAnyMap(*current, challengesKey, map[string]any(nil))
-		if challenges == nil {
-			challenges = make(map[string]any)
-		}
-		challenges[inQuery.Wallet] = challenge
-
-		// store challenges because we don't know where we got it from
-		(*current)[challengesKey] = challenges
-
-		return current, nil
-	}
-
-	if _, err := n.GetNodeAttributes().UpdateValue(
-		entry.NewAttributeID(universe.GetKusamaPluginID(), universe.ReservedAttributes.Kusama.Challenges.Name),
-		modifyFn, true,
-	); err != nil {
-		err := errors.WithMessage(err, "Node: apiGenChallenge: failed to update node attribute value")
-		api.AbortRequest(c, http.StatusInternalServerError, "attribute_update_failed", err, n.log)
-		return
-	}
+	n.authChallenges.Put(n.ctx, message, n.cfg.Auth.ChallengeTTL)
 
 	type Out struct {
-		Challenge string `json:"challenge"`
+		Challenge string    `json:"challenge"`
+		Nonce     string    `json:"nonce"`
+		ExpiresAt time.Time `json:"expires_at"`
 	}
 	out := Out{
-		Challenge: challenge,
+		Challenge: message.String(),
+		Nonce:     message.Nonce,
+		ExpiresAt: *message.ExpirationTime,
 	}
 
 	c.JSON(http.StatusOK, out)
 }
 
 // @Summary Verifies a signed challenge
-// @Description Returns OK when a signature has been validated
+// @Description Attaches the wallet to the current user when a signature has been validated.
+// @Description Guest users are upgraded to registered users in place, keeping their content.
 // @Tags auth
 // @Param body body node.apiAttachAccount.InBody true "body params"
 // @Success 200 {object} nil
@@ -90,6 +76,8 @@ func (n *Node) apiAttachAccount(ctx *gin.Context) {
 		Wallet          string `json:"wallet" binding:"required"`
 		Network         string `json:"network"`
 		SignedChallenge string `json:"signedChallenge" binding:"required"`
+		// Signed message, defaults to the challenge issued for the wallet.
+		Message string `json:"message"`
 	}
 	var inBody InBody
 
@@ -112,44 +100,11 @@ func (n *Node) apiAttachAccount(ctx *gin.Context) {
 		return
 	}
 
-	challengeAttributeID := entry.NewAttributeID(universe.GetKusamaPluginID(), universe.ReservedAttributes.Kusama.Challenges.Name)
-
-	challengesAttributeValue, ok := n.GetNodeAttributes().GetValue(challengeAttributeID)
-	if !ok || challengesAttributeValue == nil {
-		err := errors.Errorf("Node: apiAttachAccount: node attribute not found")
-		api.AbortRequest(ctx, http.StatusInternalServerError, "attribute_not_found", err, n.log)
-		return
-	}
-
-	var challenge string
-	if store := utils.GetFromAnyMap(
-		*challengesAttributeValue, universe.ReservedAttributes.Kusama.Challenges.Key, (map[string]any)(nil),
-	); store != nil {
-		challenge = utils.GetFromAnyMap(store, inBody.Wallet, "")
-	}
-	if challenge == "" {
-		err := errors.Errorf("Node: apiAttachAccount: challenge not found")
-		api.AbortRequest(ctx, http.StatusNotFound, "challenge_not_found", err, n.log)
-		return
-	}
-
-	valid, err := func() (bool, error) {
-		switch inBody.Network {
-		case "ethereum":
-			return api.VerifyEthereumSignature(inBody.Wallet, challenge, inBody.SignedChallenge)
-		default:
-			return api.VerifyPolkadotSignature(inBody.Wallet, challenge, inBody.SignedChallenge)
-		}
-	}()
-	if err != nil {
-		err := errors.WithMessage(err, "Node: apiAttachAccount: failed to update node attribute value")
-		api.AbortRequest(ctx, http.StatusInternalServerError, "attribute_update_failed", err, n.log)
-		return
-	}
-
-	if !valid {
-		err := errors.Errorf("Node: apiAttachAccount: ethereum signature appears to be invalid")
-		api.AbortRequest(ctx, http.StatusNotFound, "invalid_signature", err, n.log)
+	if code, errCode, err := n.verifyAuthChallenge(
+		inBody.Wallet, inBody.Network, inBody.Message, inBody.SignedChallenge,
+	); err != nil {
+		err := errors.WithMessage(err, "Node: apiAttachAccount: failed to verify challenge")
+		api.AbortRequest(ctx, code, errCode, err, n.log)
 		return
 	}
 
@@ -200,6 +155,13 @@ func (n *Node) apiAttachAccount(ctx *gin.Context) {
 		return
 	}
 
+	// guest attaching a wallet becomes a registered user keeping everything created so far
+	if _, err := n.upgradeGuestUser(ctx, userID); err != nil {
+		err = errors.WithMessage(err, "Node: apiAttachAccount: failed to upgrade guest user")
+		api.AbortRequest(ctx, http.StatusInternalServerError, "failed_to_upgrade_user", err, n.log)
+		return
+	}
+
 	ctx.JSON(http.StatusAccepted, payload.Value)
 }
 
@@ -207,7 +169,7 @@ func (n *Node) apiAttachAccount(ctx *gin.Context) {
 // @Description Returns a new generated token based on params
 // @Tags auth
 // @Param body body node.apiGenToken.InBody true "body params"
-// @Success 200 {object} node.apiGenToken.Out
+// @Success 200 {object} entry.UserSessionTokens
 // @Failure 400 {object} api.HTTPError
 // @Failure 403 {object} api.HTTPError
 // @Failure 404 {object} api.HTTPError
@@ -217,6 +179,8 @@ func (n *Node) apiGenToken(c *gin.Context) {
 		Wallet          string `json:"wallet" binding:"required"`
 		Network         string `json:"network"`
 		SignedChallenge string `json:"signedChallenge" binding:"required"`
+		// Signed message, defaults to the challenge issued for the wallet.
+		Message string `json:"message"`
 	}
 	var inBody InBody
 
@@ -226,66 +190,11 @@ func (n *Node) apiGenToken(c *gin.Context) {
 		return
 	}
 
-	attributeID := entry.NewAttributeID(universe.GetKusamaPluginID(), universe.ReservedAttributes.Kusama.Challenges.Name)
-
-	challengesAttributeValue, ok := n.GetNodeAttributes().GetValue(attributeID)
-	if !ok || challengesAttributeValue == nil {
-		err := errors.Errorf("Node: apiGenToken: node attribute not found")
-		api.AbortRequest(c, http.StatusInternalServerError, "attribute_not_found", err, n.log)
-		return
-	}
-
-	var challenge string
-	if store := utils.GetFromAnyMap(
-		*challengesAttributeValue, universe.ReservedAttributes.Kusama.Challenges.Key, (map[string]any)(nil),
-	); store != nil {
-		challenge = utils.GetFromAnyMap(store, inBody.Wallet, "")
-	}
-	if challenge == "" {
-		err := errors.Errorf("Node: apiGenToken: challenge not found")
-		api.AbortRequest(c, http.StatusNotFound, "challenge_not_found", err, n.log)
-		return
-	}
-
-	valid, err := func() (bool, error) {
-		switch inBody.Network {
-		case "ethereum":
-			return api.VerifyEthereumSignature(inBody.Wallet, challenge, inBody.SignedChallenge)
-		default:
-			return api.VerifyPolkadotSignature(inBody.Wallet, challenge, inBody.SignedChallenge)
-		}
-	}()
-
-	if err != nil {
-		err := errors.WithMessage(err, "Node: apiGenToken: failed to verify wallet signature")
-		api.AbortRequest(c, http.StatusBadRequest, "signature_validation_failed", err, n.log)
-		return
-	}
-
-	if !valid {
-		err := errors.Errorf("Node: apiGenToken: invalid signature")
-		api.AbortRequest(c, http.StatusForbidden, "invalid_signature", err, n.log)
-		return
-	}
-
-	modifyFn := func(current *entry.AttributeValue) (*entry.AttributeValue, error) {
-		if current == nil {
-			return current, nil
-		}
-
-		challenges := utils.GetFromAnyMap(*current, universe.ReservedAttributes.Kusama.Challenges.Key, (map[string]any)(nil))
-		if challenges == nil {
-			return current, nil
-		}
-
-		delete(challenges, inBody.Wallet)
-
-		return current, nil
-	}
-
-	if _, err := n.GetNodeAttributes().UpdateValue(attributeID, modifyFn, true); err != nil {
-		err := errors.WithMessage(err, "Node: apiGenToken: failed to update node attribute value")
-		api.AbortRequest(c, http.StatusInternalServerError, "attribute_update_failed", err, n.log)
+	if code, errCode, err := n.verifyAuthChallenge(
+		inBody.Wallet, inBody.Network, inBody.Message, inBody.SignedChallenge,
+	); err != nil {
+		err := errors.WithMessage(err, "Node: apiGenToken: failed to verify challenge")
+		api.AbortRequest(c, code, errCode, err, n.log)
 		return
 	}
 
@@ -296,21 +205,18 @@ func (n *Node) apiGenToken(c *gin.Context) {
 		return
 	}
 
-	token, err := api.CreateJWTToken(userEntry.UserID)
+	_, tokens, err := n.userSessions.Create(userEntry.UserID, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
-		err = errors.WithMessage(err, "Node: apiGenToken: failed create token for user")
+		err = errors.WithMessage(err, "Node: apiGenToken: failed create session for user")
+		if errors.Is(err, errUserBanned) {
+			api.AbortRequest(c, http.StatusForbidden, "user_banned", err, n.log)
+			return
+		}
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_create_token", err, n.log)
 		return
 	}
 
-	type Out struct {
-		Token string `json:"token"`
-	}
-	out := Out{
-		Token: token,
-	}
-
-	c.JSON(http.StatusOK, out)
+	c.JSON(http.StatusOK, tokens)
 }
 
 // @Summary Generate jwt guest token
@@ -334,9 +240,9 @@ func (n *Node) apiGuestToken(c *gin.Context) {
 		return
 	}
 
-	token, err := api.CreateJWTToken(userEntry.UserID)
+	_, tokens, err := n.userSessions.Create(userEntry.UserID, c.Request.UserAgent(), c.ClientIP())
 	if err != nil {
-		err = errors.WithMessage(err, "Node: apiGuestToken: failed create token for user")
+		err = errors.WithMessage(err, "Node: apiGuestToken: failed create session for user")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_create_token", err, n.log)
 		return
 	}
@@ -346,13 +252,15 @@ func (n *Node) apiGuestToken(c *gin.Context) {
 		name = *userEntry.Profile.Name
 	}
 	outUser := dto.User{
-		ID:         userEntry.UserID,
-		UserTypeID: userEntry.UserTypeID,
-		Name:       name,
-		JWTToken:   &token,
-		CreatedAt:  userEntry.CreatedAt.Format(time.RFC3339),
-		UpdatedAt:  userEntry.UpdatedAt.Format(time.RFC3339),
-		IsGuest:    true,
+		ID:             userEntry.UserID,
+		UserTypeID:     userEntry.UserTypeID,
+		Name:           name,
+		JWTToken:       &tokens.AccessToken,
+		RefreshToken:   &tokens.RefreshToken,
+		TokenExpiresAt: tokens.ExpiresAt.Format(time.RFC3339),
+		CreatedAt:      userEntry.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:      userEntry.UpdatedAt.Format(time.RFC3339),
+		IsGuest:        true,
 	}
 
 	c.JSON(http.StatusOK, outUser)
@@ -444,3 +352,102 @@ func (n *Node) apiDeleteWallet(c *gin.Context) {
 
 	c.JSON(http.StatusOK, nil)
 }
+
+// newAuthChallenge creates Sign-In with Ethereum message for the wallet, bound to the node frontend url.
+func (n *Node) newAuthChallenge(wallet string, chainID int64) (*siwe.Message, error) {
+	domain, err := n.getAuthDomain()
+	if err != nil {
+		return nil, err
+	}
+
+	if chainID == 0 {
+		chainID, err = strconv.ParseInt(n.cfg.Arbitrum.BlockchainID, 10, 64)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to parse default chain id")
+		}
+	}
+
+	nonce, err := siwe.GenerateNonce()
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to generate nonce")
+	}
+
+	address := wallet
+	if common.IsHexAddress(wallet) {
+		// EIP-4361 requires EIP-55 checksum address
+		address = common.HexToAddress(wallet).Hex()
+	}
+
+	issuedAt := time.Now().UTC().Truncate(time.Second)
+	expiresAt := issuedAt.Add(n.cfg.Auth.ChallengeTTL)
+
+	return &siwe.Message{
+		Domain:         domain,
+		Address:        address,
+		Statement:      "Sign in to Odyssey with your wallet.",
+		URI:            n.cfg.Settings.FrontendURL,
+		Version:        siwe.Version,
+		ChainID:        chainID,
+		Nonce:          nonce,
+		IssuedAt:       issuedAt,
+		ExpirationTime: &expiresAt,
+	}, nil
+}
+
+// verifyAuthChallenge checks the message signed by the wallet against the challenge issued for it.
+// Returns http status and error code on failure, the challenge can be used only once.
+func (n *Node) verifyAuthChallenge(wallet, network, message, signature string) (int, string, error) {
+	challenge, ok := n.authChallenges.Get(wallet)
+	if !ok {
+		return http.StatusNotFound, "challenge_not_found", errors.Errorf("challenge not found: %s", wallet)
+	}
+
+	if message == "" {
+		message = challenge.String()
+	}
+	signed, err := siwe.ParseMessage(message)
+	if err != nil {
+		return http.StatusBadRequest, "invalid_message", errors.WithMessage(err, "failed to parse message")
+	}
+
+	if signed.Nonce != challenge.Nonce ||
+		!strings.EqualFold(signed.Address, challenge.Address) ||
+		signed.ChainID != challenge.ChainID {
+		return http.StatusForbidden, "challenge_mismatch", errors.New("message doesn't match the issued challenge")
+	}
+
+	domain, err := n.getAuthDomain()
+	if err != nil {
+		return http.StatusInternalServerError, "invalid_configuration", err
+	}
+	if err := signed.Validate(domain, n.cfg.Settings.FrontendURL, time.Now()); err != nil {
+		return http.StatusForbidden, "invalid_message", errors.WithMessage(err, "failed to validate message")
+	}
+
+	valid, err := func() (bool, error) {
+		switch network {
+		case "ethereum":
+			return api.VerifyEthereumSignature(wallet, message, signature)
+		default:
+			return api.VerifyPolkadotSignature(wallet, message, signature)
+		}
+	}()
+	if err != nil {
+		return http.StatusBadRequest, "signature_validation_failed", errors.WithMessage(err, "failed to verify wallet signature")
+	}
+	if !valid {
+		return http.StatusForbidden, "invalid_signature", errors.New("invalid signature")
+	}
+
+	n.authChallenges.Remove(wallet)
+
+	return http.StatusOK, "", nil
+}
+
+func (n *Node) getAuthDomain() (string, error) {
+	frontendURL, err := url.Parse(n.cfg.Settings.FrontendURL)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to parse frontend url")
+	}
+	return frontendURL.Host, nil
+}
@@ -0,0 +1,362 @@
+package node
+
+import (
+	"context"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/oidc"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/common"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get OpenID Connect providers
+// @Description Returns identity providers configured for login
+// @Tags auth
+// @Success 200 {array} node.apiOIDCGetProviders.Out
+// @Router /api/v4/auth/oidc [get]
+func (n *Node) apiOIDCGetProviders(c *gin.Context) {
+	type Out struct {
+		Name        string `json:"name"`
+		DisplayName string `json:"display_name"`
+	}
+	out := make([]Out, 0, len(n.oidcProviders))
+	for _, provider := range n.oidcProviders {
+		out = append(out, Out{Name: provider.GetName(), DisplayName: provider.GetDisplayName()})
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Name < out[j].Name
+	})
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Start OpenID Connect login
+// @Description Returns the provider authorization url (authorization code flow with PKCE) to redirect the user to.
+// @Description The provider redirects back with code and state which have to be passed to the token endpoint.
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Success 200 {object} node.oidcAuthorizeOut
+// @Failure 404 {object} api.HTTPError
+// @Failure 502 {object} api.HTTPError
+// @Router /api/v4/auth/oidc/{provider}/authorize [get]
+func (n *Node) apiOIDCAuthorize(c *gin.Context) {
+	out, code, errCode, err := n.newOIDCAuthRequest(c, c.Param("provider"), umid.Nil)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiOIDCAuthorize: failed to create auth request")
+		api.AbortRequest(c, code, errCode, err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Generate auth token with OpenID Connect
+// @Description Exchanges the authorization code for the user tokens, the user is created on first login
+// @Tags auth
+// @Param provider path string true "Provider name"
+// @Param body body node.oidcCallbackInBody true "body params"
+// @Success 200 {object} entry.UserSessionTokens
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/auth/oidc/{provider}/token [post]
+func (n *Node) apiOIDCGenToken(c *gin.Context) {
+	var inBody oidcCallbackInBody
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiOIDCGenToken: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	request, claims, code, errCode, err := n.exchangeOIDCCode(c, c.Param("provider"), inBody.Code, inBody.State)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiOIDCGenToken: failed to exchange code")
+		api.AbortRequest(c, code, errCode, err, n.log)
+		return
+	}
+	if request.UserID != umid.Nil {
+		err := errors.New("Node: apiOIDCGenToken: state was issued to attach account")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_state", err, n.log)
+		return
+	}
+
+	userEntry, err := n.getOrCreateUserFromOIDC(c, request.Provider, claims)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiOIDCGenToken: failed to get or create user")
+		api.AbortRequest(c, http.StatusInternalServerError, "get_or_create_user_failed", err, n.log)
+		return
+	}
+
+	_, tokens, err := n.userSessions.Create(userEntry.UserID, c.Request.UserAgent(), c.ClientIP())
+	if err != nil {
+		err = errors.WithMessage(err, "Node: apiOIDCGenToken: failed create session for user")
+		if errors.Is(err, errUserBanned) {
+			api.AbortRequest(c, http.StatusForbidden, "user_banned", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_create_token", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Start linking OpenID Connect identity
+// @Description Returns the provider authorization url to link the identity to the current user
+// @Tags auth,users
+// @Security Bearer
+// @Param provider path string true "Provider name"
+// @Success 200 {object} node.oidcAuthorizeOut
+// @Failure 404 {object} api.HTTPError
+// @Failure 502 {object} api.HTTPError
+// @Router /api/v4/users/me/oidc/{provider}/authorize [get]
+func (n *Node) apiOIDCAttachAuthorize(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiOIDCAttachAuthorize: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "get_user_id_failed", err, n.log)
+		return
+	}
+
+	out, code, errCode, err := n.newOIDCAuthRequest(c, c.Param("provider"), userID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiOIDCAttachAuthorize: failed to create auth request")
+		api.AbortRequest(c, code, errCode, err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Link OpenID Connect identity
+// @Description Exchanges the authorization code and links the identity to the current user, like attaching a wallet.
+// @Description Guest users are upgraded to registered users in place.
+// @Tags auth,users
+// @Security Bearer
+// @Param provider path string true "Provider name"
+// @Param body body node.oidcCallbackInBody true "body params"
+// @Success 202 {object} entry.AttributeValue
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Router /api/v4/users/me/oidc/{provider}/attach [post]
+func (n *Node) apiAttachOIDCAccount(c *gin.Context) {
+	var inBody oidcCallbackInBody
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiAttachOIDCAccount: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiAttachOIDCAccount: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "get_user_id_failed", err, n.log)
+		return
+	}
+
+	request, claims, code, errCode, err := n.exchangeOIDCCode(c, c.Param("provider"), inBody.Code, inBody.State)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiAttachOIDCAccount: failed to exchange code")
+		api.AbortRequest(c, code, errCode, err, n.log)
+		return
+	}
+	if request.UserID != userID {
+		err := errors.New("Node: apiAttachOIDCAccount: state was issued to another user")
+		api.AbortRequest(c, http.StatusForbidden, "invalid_state", err, n.log)
+		return
+	}
+
+	linkedUser, err := n.db.GetUsersDB().GetUserByOIDCIdentity(c, request.Provider, claims.Subject)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		err := errors.WithMessage(err, "Node: apiAttachOIDCAccount: failed to get user by identity")
+		api.AbortRequest(c, http.StatusInternalServerError, "invalid_identity_query", err, n.log)
+		return
+	}
+	if linkedUser != nil && linkedUser.UserID != userID {
+		err := errors.Errorf("Node: apiAttachOIDCAccount: identity already linked to another user")
+		api.AbortRequest(c, http.StatusConflict, "identity_already_exists", err, n.log)
+		return
+	}
+
+	payload, err := n.attachOIDCIdentity(userID, request.Provider, claims)
+	if err != nil {
+		err = errors.WithMessage(err, "Node: apiAttachOIDCAccount: failed to upsert user attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_upsert", err, n.log)
+		return
+	}
+
+	if _, err := n.upgradeGuestUser(c, userID); err != nil {
+		err = errors.WithMessage(err, "Node: apiAttachOIDCAccount: failed to upgrade guest user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_upgrade_user", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusAccepted, payload.Value)
+}
+
+type oidcAuthorizeOut struct {
+	AuthorizationURL string    `json:"authorization_url"`
+	State            string    `json:"state"`
+	ExpiresAt        time.Time `json:"expires_at"`
+}
+
+type oidcCallbackInBody struct {
+	Code  string `json:"code" binding:"required"`
+	State string `json:"state" binding:"required"`
+}
+
+// newOIDCAuthRequest stores a new pending authorization for the provider, returns http status and error code on failure.
+func (n *Node) newOIDCAuthRequest(
+	ctx context.Context, providerName string, userID umid.UMID,
+) (*oidcAuthorizeOut, int, string, error) {
+	provider, ok := n.oidcProviders[providerName]
+	if !ok {
+		return nil, http.StatusNotFound, "provider_not_found", errors.Errorf("provider not found: %s", providerName)
+	}
+
+	request, err := oidc.NewAuthRequest(providerName, userID)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "auth_request_generation_failed", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, request.State, request.Nonce, request.CodeChallenge())
+	if err != nil {
+		return nil, http.StatusBadGateway, "provider_unavailable", errors.WithMessage(err, "failed to get authorization url")
+	}
+
+	n.oidcRequests.Put(n.ctx, request, n.cfg.Auth.OIDCStateTTL)
+
+	return &oidcAuthorizeOut{
+		AuthorizationURL: authURL,
+		State:            request.State,
+		ExpiresAt:        time.Now().Add(n.cfg.Auth.OIDCStateTTL),
+	}, http.StatusOK, "", nil
+}
+
+// exchangeOIDCCode redeems the code of the pending authorization, the state can be used only once.
+func (n *Node) exchangeOIDCCode(
+	ctx context.Context, providerName, code, state string,
+) (*oidc.AuthRequest, *oidc.Claims, int, string, error) {
+	provider, ok := n.oidcProviders[providerName]
+	if !ok {
+		return nil, nil, http.StatusNotFound, "provider_not_found", errors.Errorf("provider not found: %s", providerName)
+	}
+
+	request, ok := n.oidcRequests.Pop(state)
+	if !ok {
+		return nil, nil, http.StatusNotFound, "state_not_found", errors.New("state not found")
+	}
+	if request.Provider != providerName {
+		return nil, nil, http.StatusBadRequest, "invalid_state", errors.New("state was issued for another provider")
+	}
+
+	claims, err := provider.Exchange(ctx, code, request.CodeVerifier, request.Nonce)
+	if err != nil {
+		if errors.Is(err, oidc.ErrInvalidToken) {
+			return nil, nil, http.StatusForbidden, "invalid_id_token", err
+		}
+		return nil, nil, http.StatusBadRequest, "code_exchange_failed", err
+	}
+
+	return request, claims, http.StatusOK, "", nil
+}
+
+func (n *Node) getOrCreateUserFromOIDC(ctx context.Context, provider string, claims *oidc.Claims) (*entry.User, error) {
+	userEntry, err := n.db.GetUsersDB().GetUserByOIDCIdentity(ctx, provider, claims.Subject)
+	if err == nil {
+		// keep email up to date
+		if _, err := n.attachOIDCIdentity(userEntry.UserID, provider, claims); err != nil {
+			return nil, errors.WithMessage(err, "failed to update identity")
+		}
+		return userEntry, nil
+	}
+	if !errors.Is(err, pgx.ErrNoRows) {
+		return nil, errors.WithMessage(err, "failed to get user by identity")
+	}
+
+	name := claims.Name
+	if name == "" {
+		name = claims.PreferredUsername
+	}
+	if name == "" {
+		name, _, _ = strings.Cut(claims.Email, "@")
+	}
+
+	userEntry = &entry.User{
+		UserID: umid.New(),
+		Profile: entry.UserProfile{
+			Name: &name,
+		},
+	}
+
+	normUserTypeID, err := common.GetNormalUserTypeID()
+	if err != nil {
+		return nil, errors.Errorf("failed to get normal user type umid")
+	}
+	userEntry.UserTypeID = normUserTypeID
+
+	if err := n.CreateUsers(ctx, userEntry); err != nil {
+		return nil, errors.WithMessagef(err, "failed to upsert user: %s", userEntry.UserID)
+	}
+
+	n.log.Infof("Node: getOrCreateUserFromOIDC: user created: %s: %s", userEntry.UserID, provider)
+
+	if _, err := n.attachOIDCIdentity(userEntry.UserID, provider, claims); err != nil {
+		// TODO: think about rollback
+		return nil, errors.WithMessagef(err, "failed to attach identity for user: %s", userEntry.UserID)
+	}
+
+	return userEntry, nil
+}
+
+// attachOIDCIdentity adds the identity to the user attribute or updates its email if already linked.
+func (n *Node) attachOIDCIdentity(
+	userID umid.UMID, provider string, claims *oidc.Claims,
+) (*entry.AttributePayload, error) {
+	identitiesKey := universe.ReservedAttributes.User.OIDCIdentity.Key
+	userAttributeID := entry.NewUserAttributeID(
+		entry.NewAttributeID(universe.GetSystemPluginID(), universe.ReservedAttributes.User.OIDCIdentity.Name),
+		userID,
+	)
+
+	identity := map[string]any{
+		"provider": provider,
+		"subject":  claims.Subject,
+		"email":    claims.Email,
+	}
+
+	modifyFn := func(current *entry.AttributePayload) (*entry.AttributePayload, error) {
+		if current == nil {
+			current = entry.NewAttributePayload(nil, nil)
+		}
+		if current.Value == nil {
+			current.Value = entry.NewAttributeValue()
+		}
+
+		identities := utils.GetFromAny((*current.Value)[identitiesKey], []any{})
+		for i := range identities {
+			linked, ok := identities[i].(map[string]any)
+			if ok && linked["provider"] == provider && linked["subject"] == claims.Subject {
+				identities[i] = identity
+				return current, nil
+			}
+		}
+		(*current.Value)[identitiesKey] = append(identities, identity)
+
+		return current, nil
+	}
+
+	return n.GetUserAttributes().Upsert(userAttributeID, modifyFn, true)
+}
@@ -0,0 +1,192 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get my edit journal
+// @Description Returns transactions of the current user in the world which can be undone and redone, the most recent first
+// @Tags objects,edits
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {object} node.apiGetEditJournal.Out
+// @Failure 400 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/edits [get]
+func (n *Node) apiGetEditJournal(c *gin.Context) {
+	userID, worldID, ok := n.bindEditJournalRequest(c, "apiGetEditJournal")
+	if !ok {
+		return
+	}
+
+	undo, redo := n.editJournal.GetJournal(userID, worldID)
+
+	type Out struct {
+		Undo []*entry.EditTransaction `json:"undo"`
+		Redo []*entry.EditTransaction `json:"redo"`
+	}
+	out := Out{
+		Undo: undo,
+		Redo: redo,
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Begin edit transaction
+// @Description Starts a transaction, edits of the current user in the world until commit are undone and redone together
+// @Tags objects,edits
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/edits/begin [post]
+func (n *Node) apiBeginEditTransaction(c *gin.Context) {
+	userID, worldID, ok := n.bindEditJournalRequest(c, "apiBeginEditTransaction")
+	if !ok {
+		return
+	}
+
+	n.editJournal.Begin(userID, worldID)
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Commit edit transaction
+// @Description Ends the transaction started by begin
+// @Tags objects,edits
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/edits/commit [post]
+func (n *Node) apiCommitEditTransaction(c *gin.Context) {
+	userID, worldID, ok := n.bindEditJournalRequest(c, "apiCommitEditTransaction")
+	if !ok {
+		return
+	}
+
+	n.editJournal.Commit(userID, worldID)
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Undo edit
+// @Description Reverts the last edit transaction of the current user in the world and returns it.
+// @Description Nothing is reverted if the edited objects were changed by someone else since, conflicts are returned with 409.
+// @Tags objects,edits
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {object} entry.EditTransaction
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 409 {object} node.editConflictError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/edits/undo [post]
+func (n *Node) apiUndoEdit(c *gin.Context) {
+	userID, worldID, ok := n.bindEditJournalRequest(c, "apiUndoEdit")
+	if !ok {
+		return
+	}
+
+	transaction, conflicts, err := n.editJournal.Undo(userID, worldID)
+	if err != nil {
+		n.abortEditJournalRequest(c, errors.WithMessage(err, "Node: apiUndoEdit: failed to undo"), conflicts)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// @Summary Redo edit
+// @Description Reapplies the last undone edit transaction of the current user in the world and returns it.
+// @Description Conflicts are handled the same way as by undo.
+// @Tags objects,edits
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {object} entry.EditTransaction
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 409 {object} node.editConflictError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/edits/redo [post]
+func (n *Node) apiRedoEdit(c *gin.Context) {
+	userID, worldID, ok := n.bindEditJournalRequest(c, "apiRedoEdit")
+	if !ok {
+		return
+	}
+
+	transaction, conflicts, err := n.editJournal.Redo(userID, worldID)
+	if err != nil {
+		n.abortEditJournalRequest(c, errors.WithMessage(err, "Node: apiRedoEdit: failed to redo"), conflicts)
+		return
+	}
+
+	c.JSON(http.StatusOK, transaction)
+}
+
+// editConflictError is api.HTTPError with the changes conflicting with edits of other users.
+type editConflictError struct {
+	api.HTTPError
+	Conflicts []*entry.EditChange `json:"conflicts"`
+}
+
+func (n *Node) bindEditJournalRequest(c *gin.Context, handler string) (umid.UMID, umid.UMID, bool) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to parse world umid", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, n.log)
+		return umid.Nil, umid.Nil, false
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to get user umid from context", handler)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return umid.Nil, umid.Nil, false
+	}
+
+	return userID, worldID, true
+}
+
+func (n *Node) abortEditJournalRequest(c *gin.Context, err error, conflicts []*entry.EditChange) {
+	switch {
+	case errors.Is(err, errNothingToUndo):
+		api.AbortRequest(c, http.StatusNotFound, "nothing_to_undo", err, n.log)
+	case errors.Is(err, errNothingToRedo):
+		api.AbortRequest(c, http.StatusNotFound, "nothing_to_redo", err, n.log)
+	case errors.Is(err, errEditNotPermitted):
+		api.AbortRequest(c, http.StatusForbidden, "edit_not_permitted", err, n.log)
+	case errors.Is(err, errEditConflict):
+		n.log.Debug(err)
+		c.AbortWithStatusJSON(http.StatusConflict, &editConflictError{
+			HTTPError: api.HTTPError{Error: api.HTTPErrorPayload{
+				Reason:  "edit_conflict",
+				Message: err.Error(),
+			}},
+			Conflicts: conflicts,
+		})
+	default:
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_apply_edit", err, n.log)
+	}
+}
+
+// recordEdit adds the change made by the user to the edit journal, a failure is logged as the edit is already done.
+func (n *Node) recordEdit(userID umid.UMID, world universe.World, change *entry.EditChange) {
+	if world == nil || change == nil {
+		return
+	}
+
+	if err := n.editJournal.Record(userID, world.GetID(), change); err != nil {
+		n.log.Error(errors.WithMessagef(err, "Node: recordEdit: failed to record edit: %+v", change.Target))
+	}
+}
@@ -1,11 +1,12 @@
 package node
 
 import (
-	"fmt"
-	"io"
+	"mime"
 	"net/http"
+	"path"
 	"regexp"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -45,17 +46,20 @@ func (n *Node) apiMediaGetImage(c *gin.Context) {
 		return
 	}
 
-	meta, filepath, err := n.media.GetImage(filename)
+	meta, file, err := n.media.GetImage(filename)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiMediaGetImage: failed to get image")
 		api.AbortRequest(c, http.StatusNotFound, "failed_to_get_image", err, n.log)
 		return
 	}
 
-	c.Header("Content-Type", meta.Mime)
 	c.Header("x-height", strconv.Itoa(meta.H))
 	c.Header("x-width", strconv.Itoa(meta.W))
-	c.File(*filepath)
+	if err := n.media.ServeFile(c, file, meta.Mime); err != nil {
+		err := errors.WithMessage(err, "Node: apiMediaGetImage: failed to serve image")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_serve", err, n.log)
+		return
+	}
 
 	n.log.Infof("Endpoint Hit: Image served: %s %d", filename, makeTimestamp()-tm1)
 }
@@ -125,18 +129,14 @@ func (n *Node) apiMediaGetVideo(c *gin.Context) {
 		return
 	}
 
-	file, fileInfo, contentType, err := n.media.GetVideo(filename)
+	file, contentType, err := n.media.GetVideo(filename)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiMediaGetVideo: failed to get video")
 		api.AbortRequest(c, http.StatusNotFound, "failed_to_get_video", err, n.log)
 		return
 	}
-	defer file.Close()
 
-	c.Header("Content-Type", contentType)
-	c.Header("Content-Length", fmt.Sprintf("%d", fileInfo.Size()))
-
-	if _, err := io.Copy(c.Writer, file); err != nil {
+	if err := n.media.ServeFile(c, file, contentType); err != nil {
 		err := errors.WithMessage(err, "Node: apiMediaGetVideo: failed to copy video")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_copy", err, n.log)
 		return
@@ -209,16 +209,18 @@ func (n *Node) apiMediaGetAudio(c *gin.Context) {
 		return
 	}
 
-	fileType, filepath, err := n.media.GetAudio(filename)
+	fileType, file, err := n.media.GetAudio(filename)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiMediaGetAudio: failed to get audio")
 		api.AbortRequest(c, http.StatusNotFound, "failed_to_get_audio", err, n.log)
 		return
 	}
 
-	c.Header("Content-Type", fileType.MIME.Value)
-
-	c.File(filepath)
+	if err := n.media.ServeFile(c, file, fileType.MIME.Value); err != nil {
+		err := errors.WithMessage(err, "Node: apiMediaGetAudio: failed to serve audio")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_serve", err, n.log)
+		return
+	}
 	n.log.Infof("Endpoint Hit: Audio served: %s", filename)
 }
 
@@ -334,17 +336,20 @@ func (n *Node) apiMediaGetTexture(c *gin.Context) {
 		return
 	}
 
-	meta, filepath, err := n.media.GetTexture(rsize, filename)
+	meta, file, err := n.media.GetTexture(rsize, filename)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiMediaGetTexture: failed to get texture")
 		api.AbortRequest(c, http.StatusNotFound, "failed_to_get_texture", err, n.log)
 		return
 	}
 
-	c.Header("Content-Type", meta.Mime)
 	c.Header("x-height", strconv.Itoa(meta.H))
 	c.Header("x-width", strconv.Itoa(meta.W))
-	c.File(*filepath)
+	if err := n.media.ServeFile(c, file, meta.Mime); err != nil {
+		err := errors.WithMessage(err, "Node: apiMediaGetTexture: failed to serve texture")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_serve", err, n.log)
+		return
+	}
 	n.log.Infof("Endpoint Hit: Texture served: %s %d", filename, makeTimestamp()-tm1)
 }
 
@@ -372,19 +377,52 @@ func (n *Node) apiMediaGetAsset(c *gin.Context) {
 		return
 	}
 
-	fileType, filepath, err := n.media.GetAsset(filename)
+	fileType, file, err := n.media.GetAsset(filename)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiMediaGetAsset: failed to get asset")
 		api.AbortRequest(c, http.StatusNotFound, "failed_to_get_asset", err, n.log)
 		return
 	}
 
-	c.Header("Content-Type", fileType.MIME.Value)
-
-	c.File(filepath)
+	if err := n.media.ServeFile(c, file, fileType.MIME.Value); err != nil {
+		err := errors.WithMessage(err, "Node: apiMediaGetAsset: failed to serve asset")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_serve", err, n.log)
+		return
+	}
 	n.log.Infof("Endpoint Hit: Asset served: %s", filename)
 }
 
+// @Summary Gets a plugin file from the media manager
+// @Description Serves a file of the uploaded plugin, the path starts with the plugin hash
+// @Tags media
+// @Security Bearer
+// @Param file path string true "plugin hash and file path"
+// @Success 200 {file} byte "plugin file"
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/media/render/plugin/{file} [get]
+func (n *Node) apiMediaGetPlugin(c *gin.Context) {
+	filename := strings.TrimPrefix(c.Param("file"), "/")
+	if filename == "" {
+		err := errors.New("Node: apiMediaGetPlugin: invalid filename format")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_format", err, n.log)
+		return
+	}
+
+	file, err := n.media.GetPluginFile(filename)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiMediaGetPlugin: failed to get plugin file")
+		api.AbortRequest(c, http.StatusNotFound, "failed_to_get_plugin_file", err, n.log)
+		return
+	}
+
+	if err := n.media.ServeFile(c, file, mime.TypeByExtension(path.Ext(filename))); err != nil {
+		err := errors.WithMessage(err, "Node: apiMediaGetPlugin: failed to serve plugin file")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_serve", err, n.log)
+		return
+	}
+}
+
 // @Summary Uploads a plugin to the media manager
 // @Description Sends a plugin file to the media manager and returns a hash (?)
 // @Tags media
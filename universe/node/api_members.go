@@ -5,6 +5,7 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
@@ -83,7 +84,7 @@ func (n *Node) apiMembersGetForObject(c *gin.Context) {
 }
 
 // @Summary Add member to object
-// @Description Add member to object
+// @Description Add member to object, role is either "admin" or a custom role defined by the world
 // @Tags members,objects
 // @Security Bearer
 // @Param body body node.apiPostMemberForObject.Body true "body params"
@@ -112,7 +113,17 @@ func (n *Node) apiPostMemberForObject(c *gin.Context) {
 		return
 	}
 
-	if inBody.Role != "admin" {
+	definitions, err := n.userObjects.GetRoleDefinitions(objectID)
+	if err != nil {
+		err = errors.WithMessage(err, "Node: apiPostMemberForObject: failed to get role definitions")
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	role := entry.PermissionsRoleType(inBody.Role)
+	if role != entry.PermissionAdmin && !slices.ContainsFunc(definitions, func(definition *entry.RoleDefinition) bool {
+		return definition.Name == role
+	}) {
 		err := errors.New("Node: apiPostMemberForObject: role not allowed")
 		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
 		return
@@ -150,7 +161,7 @@ func (n *Node) apiPostMemberForObject(c *gin.Context) {
 		if v == nil {
 			v = &entry.UserObjectValue{}
 		}
-		(*v)["role"] = inBody.Role
+		(*v)[universe.ReservedAttributes.User.Role.Key] = inBody.Role
 
 		return v, nil
 	}
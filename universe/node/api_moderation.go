@@ -0,0 +1,375 @@
+package node
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const userReportsDefaultLimit = 100
+
+// @Summary Report user
+// @Description Files a report against the user for node admins to review in the moderation queue
+// @Tags users,moderation
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Param body body node.apiUsersReportUser.InBody true "body params"
+// @Success 201 {object} entry.UserReport
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/{user_id}/report [post]
+func (n *Node) apiUsersReportUser(c *gin.Context) {
+	type InBody struct {
+		Reason  string     `json:"reason" binding:"required"`
+		WorldID *umid.UMID `json:"world_id"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersReportUser: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	targetID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersReportUser: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersReportUser: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	if targetID == userID {
+		err := errors.New("Node: apiUsersReportUser: user can not report themselves")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+	if _, err := n.db.GetUsersDB().GetUserByID(c, targetID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersReportUser: failed to get user by umid")
+		api.AbortRequest(c, http.StatusNotFound, "user_not_found", err, n.log)
+		return
+	}
+	if inBody.WorldID != nil {
+		if _, ok := n.GetWorlds().GetWorld(*inBody.WorldID); !ok {
+			err := errors.Errorf("Node: apiUsersReportUser: world not found: %s", *inBody.WorldID)
+			api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, n.log)
+			return
+		}
+	}
+
+	report, err := n.moderation.Report(userID, targetID, inBody.WorldID, inBody.Reason)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersReportUser: failed to report user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_report_user", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusCreated, report)
+}
+
+// @Summary Get user reports
+// @Description Returns the moderation queue, oldest reports first, node admin only
+// @Tags node,moderation
+// @Security Bearer
+// @Param query query node.apiNodeGetUserReports.InQuery false "query params"
+// @Success 200 {array} entry.UserReport
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/node/moderation/reports [get]
+func (n *Node) apiNodeGetUserReports(c *gin.Context) {
+	type InQuery struct {
+		Status string `form:"status" binding:"omitempty,oneof=open resolved dismissed"`
+		Limit  uint   `form:"limit"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeGetUserReports: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	status := entry.UserReportStatusOpen
+	if inQuery.Status != "" {
+		status = entry.UserReportStatus(inQuery.Status)
+	}
+	limit := inQuery.Limit
+	if limit == 0 {
+		limit = userReportsDefaultLimit
+	}
+
+	reports, err := n.moderation.GetReports(status, limit)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeGetUserReports: failed to get reports")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_reports", err, n.log)
+		return
+	}
+
+	if reports == nil {
+		reports = []*entry.UserReport{}
+	}
+
+	c.JSON(http.StatusOK, reports)
+}
+
+// @Summary Resolve user report
+// @Description Marks the report as resolved or dismissed, node admin only
+// @Tags node,moderation
+// @Security Bearer
+// @Param report_id path string true "Report UMID"
+// @Param body body node.apiNodeResolveUserReport.InBody true "body params"
+// @Success 200 {object} entry.UserReport
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/node/moderation/reports/{report_id} [patch]
+func (n *Node) apiNodeResolveUserReport(c *gin.Context) {
+	type InBody struct {
+		Status     string `json:"status" binding:"required,oneof=open resolved dismissed"`
+		Resolution string `json:"resolution"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeResolveUserReport: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	reportID, err := umid.Parse(c.Param("reportID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeResolveUserReport: failed to parse report umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_report_id", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeResolveUserReport: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	report, err := n.moderation.ResolveReport(reportID, entry.UserReportStatus(inBody.Status), userID, inBody.Resolution)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeResolveUserReport: failed to resolve report")
+		if errors.Is(err, errReportNotFound) {
+			api.AbortRequest(c, http.StatusNotFound, "report_not_found", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_resolve_report", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
+
+// @Summary Get node bans
+// @Description Returns active node-wide bans, node admin only
+// @Tags node,moderation
+// @Security Bearer
+// @Success 200 {array} entry.UserBan
+// @Failure 403 {object} api.HTTPError
+// @Router /api/v4/node/moderation/bans [get]
+func (n *Node) apiNodeGetBans(c *gin.Context) {
+	c.JSON(http.StatusOK, n.moderation.GetBans(nil))
+}
+
+// @Summary Ban user from node
+// @Description Disconnects the user, revokes all sessions and refuses new tokens until the ban expires, node admin only.
+// @Description The ban is permanent without expiration time.
+// @Tags node,moderation
+// @Security Bearer
+// @Param body body node.apiNodeBanUser.InBody true "body params"
+// @Success 201 {object} entry.UserBan
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/node/moderation/bans [post]
+func (n *Node) apiNodeBanUser(c *gin.Context) {
+	type InBody struct {
+		UserID    umid.UMID  `json:"user_id" binding:"required"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeBanUser: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, code, errCode, err := n.checkModerationRequest(c, inBody.UserID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeBanUser: invalid request")
+		api.AbortRequest(c, code, errCode, err, n.log)
+		return
+	}
+
+	ban, err := n.moderation.Ban(inBody.UserID, nil, inBody.Reason, userID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeBanUser: failed to ban user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_ban_user", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ban)
+}
+
+// @Summary Remove node ban
+// @Description Lifts the node-wide ban before it expires, node admin only
+// @Tags node,moderation
+// @Security Bearer
+// @Param ban_id path string true "Ban UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/node/moderation/bans/{ban_id} [delete]
+func (n *Node) apiNodeRemoveBan(c *gin.Context) {
+	banID, err := umid.Parse(c.Param("banID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeRemoveBan: failed to parse ban umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_ban_id", err, n.log)
+		return
+	}
+
+	if err := n.moderation.Unban(banID); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeRemoveBan: failed to remove ban")
+		if errors.Is(err, errBanNotFound) {
+			api.AbortRequest(c, http.StatusNotFound, "ban_not_found", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_ban", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Get node mutes
+// @Description Returns active node-wide mutes, node admin only
+// @Tags node,moderation
+// @Security Bearer
+// @Success 200 {array} entry.UserMute
+// @Failure 403 {object} api.HTTPError
+// @Router /api/v4/node/moderation/mutes [get]
+func (n *Node) apiNodeGetMutes(c *gin.Context) {
+	c.JSON(http.StatusOK, n.moderation.GetMutes(nil))
+}
+
+// @Summary Mute user in node
+// @Description Silences chat and high-fives of the user in all worlds until the mute expires, node admin only.
+// @Description The mute is permanent without expiration time.
+// @Tags node,moderation
+// @Security Bearer
+// @Param body body node.apiNodeMuteUser.InBody true "body params"
+// @Success 201 {object} entry.UserMute
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/node/moderation/mutes [post]
+func (n *Node) apiNodeMuteUser(c *gin.Context) {
+	type InBody struct {
+		UserID    umid.UMID  `json:"user_id" binding:"required"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeMuteUser: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, code, errCode, err := n.checkModerationRequest(c, inBody.UserID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeMuteUser: invalid request")
+		api.AbortRequest(c, code, errCode, err, n.log)
+		return
+	}
+
+	mute, err := n.moderation.Mute(inBody.UserID, nil, inBody.Reason, userID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeMuteUser: failed to mute user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_mute_user", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mute)
+}
+
+// @Summary Remove node mute
+// @Description Lifts the node-wide mute before it expires, node admin only
+// @Tags node,moderation
+// @Security Bearer
+// @Param mute_id path string true "Mute UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/node/moderation/mutes/{mute_id} [delete]
+func (n *Node) apiNodeRemoveMute(c *gin.Context) {
+	muteID, err := umid.Parse(c.Param("muteID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeRemoveMute: failed to parse mute umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_mute_id", err, n.log)
+		return
+	}
+
+	if err := n.moderation.Unmute(muteID); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeRemoveMute: failed to remove mute")
+		if errors.Is(err, errMuteNotFound) {
+			api.AbortRequest(c, http.StatusNotFound, "mute_not_found", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_mute", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// checkModerationRequest returns the current user if it can ban or mute the target user until the given time.
+func (n *Node) checkModerationRequest(
+	c *gin.Context, targetID umid.UMID, expiresAt *time.Time,
+) (umid.UMID, int, string, error) {
+	if expiresAt != nil && !expiresAt.After(time.Now()) {
+		return umid.Nil, http.StatusBadRequest, "invalid_expires_at", errors.New("expiration time is in the past")
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		return umid.Nil, http.StatusInternalServerError, "failed_to_get_user_id", errors.WithMessage(
+			err, "failed to get user umid from context",
+		)
+	}
+
+	switch targetID {
+	case userID:
+		return umid.Nil, http.StatusBadRequest, "invalid_user_id", errors.New("user can not moderate themselves")
+	case n.GetOwnerID():
+		return umid.Nil, http.StatusForbidden, "user_not_moderatable", errors.New("node owner can not be moderated")
+	}
+
+	if _, err := n.db.GetUsersDB().GetUserByID(c, targetID); err != nil {
+		return umid.Nil, http.StatusNotFound, "user_not_found", errors.WithMessage(err, "failed to get user by umid")
+	}
+
+	return userID, 0, "", nil
+}
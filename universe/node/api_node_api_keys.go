@@ -0,0 +1,173 @@
+package node
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const apiKeyAuditDefaultLimit = 100
+
+// @Summary Get api keys
+// @Description Returns all api keys of the node, node admin only
+// @Tags node,auth
+// @Security Bearer
+// @Success 200 {array} entry.APIKey
+// @Failure 403 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/node/api-keys [get]
+func (n *Node) apiNodeGetAPIKeys(c *gin.Context) {
+	keys, err := n.apiKeys.GetAll()
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeGetAPIKeys: failed to get api keys")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_api_keys", err, n.log)
+		return
+	}
+
+	if keys == nil {
+		keys = []*entry.APIKey{}
+	}
+
+	c.JSON(http.StatusOK, keys)
+}
+
+// @Summary Create api key
+// @Description Issues a new api key acting as the current user limited by the scopes, node admin only.
+// @Description The key is returned only once.
+// @Tags node,auth
+// @Security Bearer
+// @Param body body node.apiNodeCreateAPIKey.InBody true "body params"
+// @Success 201 {object} node.apiNodeCreateAPIKey.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/node/api-keys [post]
+func (n *Node) apiNodeCreateAPIKey(c *gin.Context) {
+	type InBody struct {
+		Name      string    `json:"name" binding:"required"`
+		Scopes    []string  `json:"scopes" binding:"required,min=1"`
+		ExpiresAt time.Time `json:"expires_at" binding:"required"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeCreateAPIKey: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	if !inBody.ExpiresAt.After(time.Now()) {
+		err := errors.New("Node: apiNodeCreateAPIKey: expiration time is in the past")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_expires_at", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeCreateAPIKey: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	apiKey, key, err := n.apiKeys.Create(inBody.Name, inBody.Scopes, userID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeCreateAPIKey: failed to create api key")
+		api.AbortRequest(c, http.StatusBadRequest, "failed_to_create_api_key", err, n.log)
+		return
+	}
+
+	type Out struct {
+		*entry.APIKey
+		Key string `json:"key"`
+	}
+	out := Out{
+		APIKey: apiKey,
+		Key:    key,
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// @Summary Revoke api key
+// @Description Revokes the api key, node admin only
+// @Tags node,auth
+// @Security Bearer
+// @Param api_key_id path string true "API key UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/node/api-keys/{api_key_id} [delete]
+func (n *Node) apiNodeRemoveAPIKey(c *gin.Context) {
+	apiKeyID, err := umid.Parse(c.Param("apiKeyID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeRemoveAPIKey: failed to parse api key umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_api_key_id", err, n.log)
+		return
+	}
+
+	if err := n.apiKeys.Revoke(apiKeyID); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeRemoveAPIKey: failed to revoke api key")
+		if errors.Is(err, errInvalidAPIKey) {
+			api.AbortRequest(c, http.StatusNotFound, "api_key_not_found", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_revoke_api_key", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Get api key audit log
+// @Description Returns the latest requests made with the api key, node admin only
+// @Tags node,auth
+// @Security Bearer
+// @Param api_key_id path string true "API key UMID"
+// @Param query query node.apiNodeGetAPIKeyAudit.InQuery false "query params"
+// @Success 200 {array} entry.APIKeyAuditEntry
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/node/api-keys/{api_key_id}/audit [get]
+func (n *Node) apiNodeGetAPIKeyAudit(c *gin.Context) {
+	type InQuery struct {
+		Limit uint `form:"limit"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeGetAPIKeyAudit: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+	if inQuery.Limit == 0 {
+		inQuery.Limit = apiKeyAuditDefaultLimit
+	}
+
+	apiKeyID, err := umid.Parse(c.Param("apiKeyID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeGetAPIKeyAudit: failed to parse api key umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_api_key_id", err, n.log)
+		return
+	}
+
+	auditEntries, err := n.apiKeys.GetAuditEntries(apiKeyID, inQuery.Limit)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeGetAPIKeyAudit: failed to get audit entries")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_api_key_audit", err, n.log)
+		return
+	}
+
+	if auditEntries == nil {
+		auditEntries = []*entry.APIKeyAuditEntry{}
+	}
+
+	c.JSON(http.StatusOK, auditEntries)
+}
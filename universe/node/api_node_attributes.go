@@ -59,6 +59,7 @@ func (n *Node) apiNodeGetAttributesValue(c *gin.Context) {
 		return
 	}
 
+	attributes.SetETag(c, out)
 	c.JSON(http.StatusOK, out)
 }
 
@@ -195,3 +196,80 @@ func (n *Node) apiNodeRemoveAttributesValue(c *gin.Context) {
 
 	c.JSON(http.StatusOK, nil)
 }
+
+// @Summary Patch node attribute
+// @Description Applies RFC 6902 JSON Patch or RFC 7396 JSON Merge Patch to the node attribute value.
+// @Description Optional version (or "If-Match" header) with the value ETag makes the update conditional.
+// @Tags attributes,node
+// @Security Bearer
+// @Param body body attributes.PatchBody true "body params"
+// @Success 200 {object} attributes.PatchOut
+// @Failure 400 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Failure 422 {object} api.HTTPError
+// @Router /api/v4/node/attributes [patch]
+func (n *Node) apiNodePatchAttributesValue(c *gin.Context) {
+	inBody, err := attributes.BindPatchBody(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodePatchAttributesValue: failed to bind patch")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	pluginID, err := umid.Parse(inBody.PluginID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodePatchAttributesValue: failed to parse plugin umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, n.log)
+		return
+	}
+
+	attributeID := entry.NewAttributeID(pluginID, inBody.AttributeName)
+	attrType, ok := n.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID))
+	if !ok {
+		err := errors.Errorf("Node: apiNodePatchAttributesValue: attribute type not found: %s", attributeID)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_attribute", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodePatchAttributesValue: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	allowed, err := auth.CheckAttributePermissions(
+		c, *attrType.GetEntry(), n.GetNodeAttributes(), attributeID, userID,
+		auth.WriteOperation)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodePatchAttributesValue: permissions check")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	} else if !allowed {
+		err := errors.New("Node: apiNodePatchAttributesValue: operation not permitted")
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	modifyFn, err := inBody.ModifyFn()
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodePatchAttributesValue: failed to create modify fn")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_patch", err, n.log)
+		return
+	}
+
+	payload, err := n.GetNodeAttributes().Upsert(attributeID, modifyFn, true)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiNodePatchAttributesValue: failed to upsert node attribute")
+		status, code := attributes.PatchErrorStatus(err)
+		api.AbortRequest(c, status, code, err, n.log)
+		return
+	}
+
+	out := attributes.PatchOut{
+		Value:   payload.Value,
+		Version: attributes.SetETag(c, payload.Value),
+	}
+
+	c.JSON(http.StatusOK, out)
+}
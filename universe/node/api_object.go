@@ -192,7 +192,7 @@ func (n *Node) apiGetObject(c *gin.Context) {
 
 // @Summary Delete a object by UMID
 // @Schemes
-// @Description Deletes a object by UMID
+// @Description Moves a object by UMID with its children to the trash of its world, it can be restored until it expires
 // @Tags objects
 // @Security Bearer
 // @Param object_id path string true "Object UMID"
@@ -242,19 +242,22 @@ func (n *Node) apiRemoveObject(c *gin.Context) {
 		return
 	}
 
-	removed, err := tree.RemoveObjectFromParent(object.GetParent(), object, true)
+	change, err := n.editJournal.Snapshot(entry.EditTarget{ObjectID: objectID, Kind: entry.EditTargetObject})
 	if err != nil {
-		err := errors.WithMessage(err, "Node: apiRemoveObject: failed to remove object from parent")
-		api.AbortRequest(c, http.StatusInternalServerError, "remove_failed", err, n.log)
+		err := errors.WithMessage(err, "Node: apiRemoveObject: failed to snapshot object")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
 		return
 	}
 
-	if !removed {
-		err := errors.Errorf("Node: apiRemoveObject: object not found in parent")
-		api.AbortRequest(c, http.StatusNotFound, "object_not_found_in_parent", err, n.log)
+	world := object.GetWorld()
+	if _, err := tree.TrashObject(c, n.db, object, userID, n.cfg.Common.TrashRetention); err != nil {
+		err := errors.WithMessage(err, "Node: apiRemoveObject: failed to trash object")
+		api.AbortRequest(c, http.StatusInternalServerError, "remove_failed", err, n.log)
 		return
 	}
 
+	n.recordEdit(userID, world, change)
+
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -299,6 +302,20 @@ func (n *Node) apiUpdateObject(c *gin.Context) {
 		return
 	}
 
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUpdateObject: failed to get user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	change, err := n.editJournal.Snapshot(entry.EditTarget{ObjectID: objectID, Kind: entry.EditTargetProperties})
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUpdateObject: failed to snapshot object")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
+		return
+	}
+
 	var asset2d universe.Asset2d
 	if inBody.Asset2dID != nil {
 		if *inBody.Asset2dID != "" {
@@ -368,6 +385,8 @@ func (n *Node) apiUpdateObject(c *gin.Context) {
 		return
 	}
 
+	n.recordEdit(userID, object.GetWorld(), change)
+
 	// TODO: output full object data
 	type Out struct {
 		ObjectID string `json:"object_id"`
@@ -380,15 +399,33 @@ func (n *Node) apiUpdateObject(c *gin.Context) {
 }
 
 // @Summary Clones an object by UMID
-// @Description Clones an object by UMID, 're-parenting' not supported, returns cloned object UMID.
+// @Description Clones an object with all its children (if recursive is true) under the same or another parent,
+// @Description which can be in another world. Only cloneable attributes are copied, references to the cloned objects
+// @Description are replaced by the clones. Returns the clone report, object_id is the clone of the object.
+// @Description A partial clone is removed when cloning fails.
 // @Tags objects
 // @Security Bearer
 // @Param object_id path string true "Object UMID"
-// @Success 200 {object} node.apiCloneObject.Out
+// @Param query query node.apiCloneObject.InQuery false "query params"
+// @Success 200 {object} tree.CloneReport
 // @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
 // @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
 // @Router /api/v4/objects/{object_id}/clone [post]
 func (n *Node) apiCloneObject(c *gin.Context) {
+	type InQuery struct {
+		ParentID  string `form:"parent_id"`
+		Recursive bool   `form:"recursive"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiCloneObject: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
 	objectID, err := umid.Parse(c.Param("objectID"))
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiCloneObject: failed to parse object umid")
@@ -410,124 +447,53 @@ func (n *Node) apiCloneObject(c *gin.Context) {
 		return
 	}
 
-	parentID := object.GetParent().GetID()
-	transform, err := tree.CalcObjectSpawnPosition(parentID, userID, object.GetTransform())
-	if err != nil {
-		err := errors.WithMessage(err, "Node: apiCloneObject: failed to calc object spawn position")
-		api.AbortRequest(c, http.StatusBadRequest, "calc_spawn_position_failed", err, n.log)
-		return
-	}
-
-	var asset2dID *umid.UMID
-	if object.GetAsset2D() != nil {
-		asset2dID = utils.GetPTR(object.GetAsset2D().GetID())
-	} else {
-		asset2dID = nil
-	}
-
-	var asset3dID *umid.UMID
-	if object.GetAsset3D() != nil {
-		asset3dID = utils.GetPTR(object.GetAsset3D().GetID())
-	} else {
-		asset3dID = nil
-	}
-
-	objectTemplate := tree.ObjectTemplate{
-		ObjectName:   utils.GetPTR(object.GetName()),
-		ObjectTypeID: object.GetObjectType().GetID(),
-		ParentID:     parentID,
-		OwnerID:      utils.GetPTR(object.GetOwnerID()),
-		Asset2dID:    asset2dID,
-		Asset3dID:    asset3dID,
-		Transform:    transform,
-		Options:      object.GetOptions(),
-	}
-
-	clonedObjectID, err := tree.AddObjectFromTemplate(&objectTemplate, true)
-	if err != nil {
-		err := errors.WithMessage(err, "Node: apiCloneObject: failed to clone object")
-		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_clone", err, n.log)
+	parent := object.GetParent()
+	if parent == nil {
+		err := errors.Errorf("Node: apiCloneObject: object has no parent: %s", objectID)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
 		return
 	}
-
-	type CloneableObjectAttribute struct {
-		AttributeID entry.AttributeID
-		Value       *entry.AttributeValue
-	}
-
-	var cloneableAttributes []CloneableObjectAttribute
-	objectAttributes := object.GetObjectAttributes().GetAll()
-	for attributeID := range objectAttributes {
-		effectiveOptions, _ := object.GetObjectAttributes().GetEffectiveOptions(attributeID)
-		if effectiveOptions != nil {
-			cloneable := utils.GetFromAnyMap(*effectiveOptions, "cloneable", map[string]any(nil))
-			if cloneable != nil {
-				defaultValue := utils.GetFromAnyMap(cloneable, "use_default", map[string]any(nil))
-				if defaultValue != nil {
-					attributeValue := entry.AttributeValue(defaultValue)
-					cloneableDefaultAttribute := CloneableObjectAttribute{
-						AttributeID: attributeID,
-						Value:       &attributeValue,
-					}
-
-					cloneableAttributes = append(cloneableAttributes, cloneableDefaultAttribute)
-				} else {
-					attributeValue, ok := object.GetObjectAttributes().GetValue(attributeID)
-					if !ok {
-						err := errors.Errorf("Node: apiCloneObject: attr value not found: %s", objectID)
-						api.AbortRequest(c, http.StatusNotFound, "attr_value_not_found", err, n.log)
-						return
-					}
-					cloneableObjectAttribute := CloneableObjectAttribute{
-						AttributeID: attributeID,
-						Value:       attributeValue,
-					}
-
-					cloneableAttributes = append(cloneableAttributes, cloneableObjectAttribute)
-				}
-			}
+	parentID := parent.GetID()
+	if inQuery.ParentID != "" {
+		if parentID, err = umid.Parse(inQuery.ParentID); err != nil {
+			err := errors.WithMessage(err, "Node: apiCloneObject: failed to parse parent umid")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_parent_id", err, n.log)
+			return
 		}
-	}
-
-	clonedObject, ok := n.GetObjectFromAllObjects(clonedObjectID)
-	if !ok {
-		err := errors.Errorf("Node: apiCloneObject: object not found: %s", clonedObjectID)
-		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
-		return
-	}
-
-	for _, clAtr := range cloneableAttributes {
-		var attributeModifyFunc modify.Fn[entry.AttributePayload]
-		attributeModifyFunc = func(current *entry.AttributePayload) (*entry.AttributePayload, error) {
-			if current == nil {
-				current = entry.NewAttributePayload(nil, nil)
-			}
-
-			if current.Value == nil {
-				current.Value = entry.NewAttributeValue()
-			}
-
-			current.Value = clAtr.Value
-
-			return current, nil
+		if _, ok := n.GetObjectFromAllObjects(parentID); !ok {
+			err := errors.Errorf("Node: apiCloneObject: parent not found: %s", parentID)
+			api.AbortRequest(c, http.StatusNotFound, "parent_not_found", err, n.log)
+			return
 		}
 
-		_, err = clonedObject.GetObjectAttributes().Upsert(clAtr.AttributeID, attributeModifyFunc, true)
+		isAdmin, err := n.db.GetUserObjectsDB().CheckIsIndirectAdminByID(c, entry.NewUserObjectID(userID, parentID))
 		if err != nil {
-			err = errors.WithMessage(err, "Node: apiCloneObject: failed to upsert object attribute")
-			api.AbortRequest(c, http.StatusInternalServerError, "internal_error", err, n.log)
+			err := errors.WithMessage(err, "Node: apiCloneObject: failed to check parent indirect admin")
+			api.AbortRequest(c, http.StatusBadRequest, "admin_check_failed", err, n.log)
+			return
+		}
+		if !isAdmin {
+			err := errors.New("Node: apiCloneObject: operation is not permitted for user")
+			api.AbortRequest(c, http.StatusForbidden, "object_creation_not_permitted", err, n.log)
 			return
 		}
 	}
 
-	type Out struct {
-		ObjectID string `json:"object_id"`
+	transform, err := tree.CalcObjectSpawnPosition(parentID, userID, object.GetTransform())
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiCloneObject: failed to calc object spawn position")
+		api.AbortRequest(c, http.StatusBadRequest, "calc_spawn_position_failed", err, n.log)
+		return
 	}
-	out := Out{
-		ObjectID: clonedObject.GetID().String(),
+
+	report, err := tree.CloneObject(object, parentID, userID, transform, inQuery.Recursive, true)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiCloneObject: failed to clone object")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_clone", err, n.log)
+		return
 	}
 
-	c.JSON(http.StatusOK, out)
+	c.JSON(http.StatusOK, report)
 }
 
 // @Summary Set object option by object UMID (use body key options)
@@ -925,7 +891,7 @@ func (n *Node) apiClaimAndCustomise(c *gin.Context) {
 		return current, nil
 	}
 
-	_, err = object.GetObjectAttributes().Upsert(attributeID, attributeModifyFunc, true)
+	_, err = object.GetObjectAttributes().UpsertByUser(userID, attributeID, attributeModifyFunc, true)
 	if err != nil {
 		err = errors.WithMessage(err, "Node: apiClaimAndCustomise: failed to upsert object attribute")
 		api.AbortRequest(c, http.StatusInternalServerError, "internal_error", err, n.log)
@@ -1001,7 +967,7 @@ func (n *Node) apiUnclaimAndClearCustomisation(c *gin.Context) {
 	}
 
 	attributeID := entry.NewAttributeID(universe.GetSystemPluginID(), "user_customisable_data")
-	_, err = object.GetObjectAttributes().Remove(attributeID, true)
+	_, err = object.GetObjectAttributes().RemoveByUser(userID, attributeID, true)
 	if err != nil {
 		err = errors.WithMessage(err, "Node: apiUnclaimAndClearCustomisation: failed to remove object attribute")
 		api.AbortRequest(c, http.StatusInternalServerError, "internal_error", err, n.log)
@@ -76,6 +76,7 @@ func (n *Node) apiGetObjectAttributesValue(c *gin.Context) {
 		return
 	}
 
+	attributes.SetETag(c, out)
 	c.JSON(http.StatusOK, out)
 }
 
@@ -118,9 +119,15 @@ func (n *Node) apiGetObjectWithChildrenAttributeValues(c *gin.Context) {
 		return
 	}
 
+	userRoles, err := n.userObjects.GetUserRoles(entry.NewUserObjectID(userID, objectID))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectWithChildrenAttributeValues: failed to get user roles")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	}
+
 	// TODO: either remove this API method, or implement recursive permission checks...
-	allowed, err := auth.CheckReadAllPermissions[entry.AttributeID](
-		c, *attrType.GetEntry(), rootObject.GetObjectAttributes(), userID)
+	allowed, err := auth.CheckReadAllPermissions(*attrType.GetEntry(), userRoles)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiGetObjectAttributesValue: permissions check")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
@@ -230,7 +237,7 @@ func (n *Node) apiSetObjectAttributesPublic(c *gin.Context) {
 		return current, nil
 	}
 
-	if _, err := object.GetObjectAttributes().Upsert(attributeID, modifyFn, true); err != nil {
+	if _, err := object.GetObjectAttributes().UpsertByUser(userID, attributeID, modifyFn, true); err != nil {
 		err := errors.WithMessage(err, "Node: apiSetObjectAttributesPublic: failed to set options")
 		api.AbortRequest(c, http.StatusInternalServerError, "set_options_failed", err, n.log)
 		return
@@ -308,6 +315,16 @@ func (n *Node) apiSetObjectAttributesValue(c *gin.Context) {
 		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
 		return
 	}
+
+	change, err := n.editJournal.Snapshot(entry.EditTarget{
+		ObjectID: objectID, Kind: entry.EditTargetAttribute, AttributeID: &attributeID,
+	})
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiSetObjectAttributesValue: failed to snapshot attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
+		return
+	}
+
 	modifyFn := func(current *entry.AttributePayload) (*entry.AttributePayload, error) {
 		newValue := func() *entry.AttributeValue {
 			value := entry.NewAttributeValue()
@@ -329,13 +346,15 @@ func (n *Node) apiSetObjectAttributesValue(c *gin.Context) {
 		return current, nil
 	}
 
-	payload, err := object.GetObjectAttributes().Upsert(attributeID, modifyFn, true)
+	payload, err := object.GetObjectAttributes().UpsertByUser(userID, attributeID, modifyFn, true)
 	if err != nil {
 		err = errors.WithMessage(err, "Node: apiSetObjectAttributesValue: failed to upsert object attribute")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_upsert", err, n.log)
 		return
 	}
 
+	n.recordEdit(userID, object.GetWorld(), change)
+
 	c.JSON(http.StatusAccepted, payload.Value)
 }
 
@@ -501,6 +520,15 @@ func (n *Node) apiSetObjectAttributeSubValue(c *gin.Context) {
 		return
 	}
 
+	change, err := n.editJournal.Snapshot(entry.EditTarget{
+		ObjectID: objectID, Kind: entry.EditTargetAttribute, AttributeID: &attributeID,
+	})
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiSetObjectAttributeSubValue: failed to snapshot attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
+		return
+	}
+
 	modifyFn := func(current *entry.AttributePayload) (*entry.AttributePayload, error) {
 		newValue := func() *entry.AttributeValue {
 			value := entry.NewAttributeValue()
@@ -522,13 +550,15 @@ func (n *Node) apiSetObjectAttributeSubValue(c *gin.Context) {
 		return current, nil
 	}
 
-	payload, err := object.GetObjectAttributes().Upsert(attributeID, modifyFn, true)
+	payload, err := object.GetObjectAttributes().UpsertByUser(userID, attributeID, modifyFn, true)
 	if err != nil {
 		err = errors.WithMessage(err, "Node: apiSetObjectAttributeSubValue: failed to upsert object attribute")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_upsert", err, n.log)
 		return
 	}
 
+	n.recordEdit(userID, object.GetWorld(), change)
+
 	out := dto.ObjectSubAttributes{
 		inBody.SubAttributeKey: (*payload.Value)[inBody.SubAttributeKey],
 	}
@@ -606,6 +636,15 @@ func (n *Node) apiRemoveObjectAttributeSubValue(c *gin.Context) {
 		return
 	}
 
+	change, err := n.editJournal.Snapshot(entry.EditTarget{
+		ObjectID: objectID, Kind: entry.EditTargetAttribute, AttributeID: &attributeID,
+	})
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRemoveObjectAttributeSubValue: failed to snapshot attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
+		return
+	}
+
 	modifyFn := func(current *entry.AttributeValue) (*entry.AttributeValue, error) {
 		if current == nil {
 			return current, nil
@@ -616,12 +655,14 @@ func (n *Node) apiRemoveObjectAttributeSubValue(c *gin.Context) {
 		return current, nil
 	}
 
-	if _, err := object.GetObjectAttributes().UpdateValue(attributeID, modifyFn, true); err != nil {
+	if _, err := object.GetObjectAttributes().UpdateValueByUser(userID, attributeID, modifyFn, true); err != nil {
 		err = errors.WithMessage(err, "Node: apiRemoveObjectAttributeSubValue: failed to update object attribute")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_update", err, n.log)
 		return
 	}
 
+	n.recordEdit(userID, object.GetWorld(), change)
+
 	c.JSON(http.StatusOK, nil)
 }
 
@@ -676,13 +717,128 @@ func (n *Node) apiRemoveObjectAttributeValue(c *gin.Context) {
 		return
 	}
 
-	if _, err := object.GetObjectAttributes().UpdateValue(
-		attributeID, modify.ReplaceWith[entry.AttributeValue](nil), true,
+	change, err := n.editJournal.Snapshot(entry.EditTarget{
+		ObjectID: objectID, Kind: entry.EditTargetAttribute, AttributeID: &attributeID,
+	})
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRemoveObjectAttributeValue: failed to snapshot attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
+		return
+	}
+
+	if _, err := object.GetObjectAttributes().UpdateValueByUser(
+		userID, attributeID, modify.ReplaceWith[entry.AttributeValue](nil), true,
 	); err != nil {
 		err = errors.WithMessage(err, "Node: apiRemoveObjectAttributeValue: failed to update object attribute")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_update", err, n.log)
 		return
 	}
 
+	n.recordEdit(userID, object.GetWorld(), change)
+
 	c.JSON(http.StatusOK, nil)
 }
+
+// @Summary Patch object attribute
+// @Description Applies RFC 6902 JSON Patch or RFC 7396 JSON Merge Patch to the object attribute value.
+// @Description Optional version (or "If-Match" header) with the value ETag makes the update conditional.
+// @Tags attributes,objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param body body attributes.PatchBody true "body params"
+// @Success 200 {object} attributes.PatchOut
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Failure 422 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/attributes [patch]
+func (n *Node) apiPatchObjectAttributeValue(c *gin.Context) {
+	inBody, err := attributes.BindPatchBody(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to bind patch")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	pluginID, err := umid.Parse(inBody.PluginID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to parse plugin umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiPatchObjectAttributeValue: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	attributeID := entry.NewAttributeID(pluginID, inBody.AttributeName)
+	attrType, ok := n.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID))
+	if !ok {
+		err := errors.Errorf("Node: apiPatchObjectAttributeValue: attribute type not found: %s", attributeID)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_attribute", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	allowed, err := auth.CheckAttributePermissions(
+		c, *attrType.GetEntry(), object.GetObjectAttributes(), attributeID, userID,
+		auth.WriteOperation)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: permissions check")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	} else if !allowed {
+		err := errors.New("Node: apiPatchObjectAttributeValue: operation not permitted")
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	change, err := n.editJournal.Snapshot(entry.EditTarget{
+		ObjectID: objectID, Kind: entry.EditTargetAttribute, AttributeID: &attributeID,
+	})
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to snapshot attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_snapshot", err, n.log)
+		return
+	}
+
+	modifyFn, err := inBody.ModifyFn()
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to create modify fn")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_patch", err, n.log)
+		return
+	}
+
+	payload, err := object.GetObjectAttributes().UpsertByUser(userID, attributeID, modifyFn, true)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectAttributeValue: failed to upsert object attribute")
+		status, code := attributes.PatchErrorStatus(err)
+		api.AbortRequest(c, status, code, err, n.log)
+		return
+	}
+
+	n.recordEdit(userID, object.GetWorld(), change)
+
+	out := attributes.PatchOut{
+		Value:   payload.Value,
+		Version: attributes.SetETag(c, payload.Value),
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,233 @@
+package node
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/attributes"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get object attributes history
+// @Description Returns history of changes made to object attributes, newest first. Narrowed down to a single attribute if plugin_id and attribute_name are given.
+// @Tags attributes,objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param query query node.apiGetObjectAttributesHistory.InQuery false "query params"
+// @Success 200 {array} entry.ObjectAttributeHistory
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/attributes/history [get]
+func (n *Node) apiGetObjectAttributesHistory(c *gin.Context) {
+	type InQuery struct {
+		PluginID      string `form:"plugin_id" json:"plugin_id"`
+		AttributeName string `form:"attribute_name" json:"attribute_name"`
+		Limit         uint   `form:"limit,default=10" json:"limit"`
+		Offset        uint   `form:"offset" json:"offset"`
+	}
+
+	var inQuery InQuery
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectAttributesHistory: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectAttributesHistory: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	if _, ok := n.GetObjectFromAllObjects(objectID); !ok {
+		err := errors.Errorf("Node: apiGetObjectAttributesHistory: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	limit := inQuery.Limit
+	if limit > 100 {
+		limit = 100
+	}
+
+	historyDB := n.db.GetObjectAttributesHistoryDB()
+
+	var history []*entry.ObjectAttributeHistory
+	if inQuery.PluginID == "" && inQuery.AttributeName == "" {
+		history, err = historyDB.GetObjectAttributesHistoryByObjectID(c, objectID, limit, inQuery.Offset)
+	} else {
+		_, attributeID, attrErr := attributes.PluginAttributeFromQuery(c, n)
+		if attrErr != nil {
+			err := fmt.Errorf("node: apiGetObjectAttributesHistory: failed to get plugin attribute: %w", attrErr)
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_attribute", err, n.log)
+			return
+		}
+		history, err = historyDB.GetObjectAttributeHistoryByID(
+			c, entry.NewObjectAttributeID(attributeID, objectID), limit, inQuery.Offset,
+		)
+	}
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectAttributesHistory: failed to get history")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_history", err, n.log)
+		return
+	}
+
+	if history == nil {
+		history = []*entry.ObjectAttributeHistory{}
+	}
+
+	c.JSON(http.StatusOK, history)
+}
+
+// @Summary Restore object attribute
+// @Description Restores object attribute to the value it had at the given time
+// @Tags attributes,objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param body body node.apiRestoreObjectAttribute.Body true "body params"
+// @Success 202 {object} node.apiRestoreObjectAttribute.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/attributes/restore [post]
+func (n *Node) apiRestoreObjectAttribute(c *gin.Context) {
+	type Body struct {
+		attributes.QueryPluginAttribute
+		Timestamp time.Time `json:"timestamp" binding:"required"`
+	}
+
+	var inBody Body
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttribute: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttribute: user from context")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttribute: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	pluginID, err := umid.Parse(inBody.PluginID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttribute: failed to parse plugin umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiRestoreObjectAttribute: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	attributeID := entry.NewAttributeID(pluginID, inBody.AttributeName)
+	if _, ok := n.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID)); !ok {
+		err := fmt.Errorf("attribute type not found")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_attribute", err, n.log)
+		return
+	}
+
+	restored, err := n.restoreObjectAttributes(
+		userID, map[umid.UMID]universe.Object{objectID: object}, &attributeID, inBody.Timestamp,
+	)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttribute: failed to restore object attribute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_restore", err, n.log)
+		return
+	}
+
+	type Out struct {
+		Restored []entry.ObjectAttributeID `json:"restored"`
+	}
+	out := Out{
+		Restored: restored,
+	}
+
+	c.JSON(http.StatusAccepted, out)
+}
+
+// @Summary Restore object attributes
+// @Description Restores all attributes of the object, and optionally of its whole subtree, to the values they had at the given time
+// @Tags attributes,objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param body body node.apiRestoreObjectAttributes.Body true "body params"
+// @Success 202 {object} node.apiRestoreObjectAttributes.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/restore [post]
+func (n *Node) apiRestoreObjectAttributes(c *gin.Context) {
+	type Body struct {
+		Timestamp time.Time `json:"timestamp" binding:"required"`
+		Recursive bool      `json:"recursive"`
+	}
+
+	var inBody Body
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttributes: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttributes: user from context")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttributes: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiRestoreObjectAttributes: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	objects := map[umid.UMID]universe.Object{objectID: object}
+	if inBody.Recursive {
+		for childID, child := range object.GetObjects(true) {
+			objects[childID] = child
+		}
+	}
+
+	restored, err := n.restoreObjectAttributes(userID, objects, nil, inBody.Timestamp)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRestoreObjectAttributes: failed to restore object attributes")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_restore", err, n.log)
+		return
+	}
+
+	type Out struct {
+		Restored []entry.ObjectAttributeID `json:"restored"`
+	}
+	out := Out{
+		Restored: restored,
+	}
+
+	c.JSON(http.StatusAccepted, out)
+}
@@ -0,0 +1,82 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const objectsBatchMaxOperations = 1000
+
+// @Summary Apply batch of object operations
+// @Description Creates, updates, reparents and removes objects under the object in the given order.
+// @Description Operations are saved in one transaction, if any of them fails nothing is changed.
+// @Description Removed objects are moved to the trash, they can be restored until the trash item expires.
+// @Description Objects created by the batch can be referenced by the following operations with the object_id set.
+// @Tags objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param body body node.apiApplyObjectsBatch.InBody true "body params"
+// @Success 200 {object} tree.BatchResult
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/batch [post]
+func (n *Node) apiApplyObjectsBatch(c *gin.Context) {
+	type InBody struct {
+		Operations []*tree.BatchOperation `json:"operations" binding:"required,min=1,dive"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiApplyObjectsBatch: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+	if len(inBody.Operations) > objectsBatchMaxOperations {
+		err := errors.Errorf("Node: apiApplyObjectsBatch: too many operations: %d", len(inBody.Operations))
+		api.AbortRequest(c, http.StatusBadRequest, "too_many_operations", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiApplyObjectsBatch: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiApplyObjectsBatch: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiApplyObjectsBatch: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	result, err := tree.ApplyBatch(c, n.db, object, userID, inBody.Operations, n.cfg.Common.TrashRetention)
+	if err != nil {
+		var operationErr *tree.BatchOperationError
+		if errors.As(err, &operationErr) {
+			err := errors.WithMessage(err, "Node: apiApplyObjectsBatch: invalid operation")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_operation", err, n.log)
+			return
+		}
+		err := errors.WithMessage(err, "Node: apiApplyObjectsBatch: failed to apply batch")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_apply_batch", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, result)
+}
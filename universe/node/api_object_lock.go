@@ -0,0 +1,117 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get object lock
+// @Description Returns the edit lock of the object, inherited from the subtree lock of its ancestor, or null
+// @Tags objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Success 200 {object} entry.ObjectLock
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/lock [get]
+func (n *Node) apiGetObjectLock(c *gin.Context) {
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectLock: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiGetObjectLock: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, object.GetLock(true))
+}
+
+// @Summary Force unlock object
+// @Description Releases the edit lock of the object held by any user.
+// @Description The lock can be inherited from the subtree lock of an ancestor, which requires admin rights on the ancestor.
+// @Tags objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Success 200 {object} entry.ObjectLock
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/lock [delete]
+func (n *Node) apiForceUnlockObject(c *gin.Context) {
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiForceUnlockObject: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiForceUnlockObject: failed to get user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiForceUnlockObject: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	lock := object.GetLock(true)
+	if lock == nil {
+		err := errors.Errorf("Node: apiForceUnlockObject: object is not locked: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "lock_not_found", err, n.log)
+		return
+	}
+
+	if lock.ObjectID != objectID {
+		isAdmin, err := n.db.GetUserObjectsDB().CheckIsIndirectAdminByID(
+			c, entry.NewUserObjectID(userID, lock.ObjectID),
+		)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiForceUnlockObject: failed to check locked object indirect admin")
+			api.AbortRequest(c, http.StatusBadRequest, "admin_check_failed", err, n.log)
+			return
+		}
+		if !isAdmin {
+			err := errors.Errorf("Node: apiForceUnlockObject: operation is not permitted for user: %s", lock.ObjectID)
+			api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+			return
+		}
+
+		if object, ok = n.GetObjectFromAllObjects(lock.ObjectID); !ok {
+			err := errors.Errorf("Node: apiForceUnlockObject: locked object not found: %s", lock.ObjectID)
+			api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+			return
+		}
+	}
+
+	released, ok := object.Unlock(userID, true)
+	if !ok {
+		err := errors.Errorf("Node: apiForceUnlockObject: lock already released: %s", lock.ObjectID)
+		api.AbortRequest(c, http.StatusNotFound, "lock_not_found", err, n.log)
+		return
+	}
+
+	if err := object.SendLockState(released.Subtree); err != nil {
+		err := errors.WithMessage(err, "Node: apiForceUnlockObject: failed to send lock state")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_send_lock_state", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, released)
+}
@@ -0,0 +1,63 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Preview child placement
+// @Description Returns transforms the placement gives to children of the object type without moving them.
+// @Description The child placement option of the object is used unless a placement is given, extra adds positions of the next children (at most 1000).
+// @Description Algos: circular, helix, sector, spiral, hexaspiral, slots, grid and expression.
+// @Tags objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param body body node.apiPreviewObjectPlacement.InBody true "body params"
+// @Success 200 {array} entry.ObjectChildPosition
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/placement/preview [post]
+func (n *Node) apiPreviewObjectPlacement(c *gin.Context) {
+	type InBody struct {
+		ObjectTypeID umid.UMID                   `json:"object_type_id"`
+		Placement    *entry.ObjectChildPlacement `json:"placement"`
+		Extra        uint                        `json:"extra" binding:"max=1000"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiPreviewObjectPlacement: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPreviewObjectPlacement: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiPreviewObjectPlacement: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	positions, err := object.PreviewChildrenPosition(inBody.ObjectTypeID, inBody.Placement, int(inBody.Extra))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPreviewObjectPlacement: failed to preview children position")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_placement", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, positions)
+}
@@ -0,0 +1,132 @@
+package node
+
+import (
+	"encoding/base64"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	objectQueryDefaultLimit = 100
+	objectQueryMaxLimit     = 1000
+)
+
+var objectQueryDefaultFields = []entry.ObjectQueryField{
+	entry.ObjectQueryFieldName, entry.ObjectQueryFieldObjectTypeID, entry.ObjectQueryFieldParentID,
+}
+
+// @Summary Query objects tree
+// @Description Returns objects of the tree under the object, the object included, matching the filter and ordered by umid.
+// @Description Attributes the user is not allowed to read are omitted and never match attribute filters.
+// @Description Fields and attribute values to return are selected by the projection, name, object_type_id and parent_id by default.
+// @Description Use next_cursor to get the following pages.
+// @Tags objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param body body node.apiQueryObjects.InBody true "body params"
+// @Success 200 {object} node.apiQueryObjects.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/query [post]
+func (n *Node) apiQueryObjects(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiQueryObjects: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "get_user_id_failed", err, n.log)
+		return
+	}
+
+	type InBody struct {
+		Filter     entry.ObjectQuery           `json:"filter"`
+		Projection entry.ObjectQueryProjection `json:"projection"`
+		Limit      uint                        `json:"limit"`
+		Cursor     string                      `json:"cursor"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiQueryObjects: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	if err := tree.ValidateObjectQuery(&inBody.Filter, &inBody.Projection); err != nil {
+		err := errors.WithMessage(err, "Node: apiQueryObjects: invalid query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_query", err, n.log)
+		return
+	}
+	if len(inBody.Projection.Fields) == 0 && len(inBody.Projection.Attributes) == 0 {
+		inBody.Projection.Fields = objectQueryDefaultFields
+	}
+
+	limit := inBody.Limit
+	if limit == 0 {
+		limit = objectQueryDefaultLimit
+	}
+	if limit > objectQueryMaxLimit {
+		limit = objectQueryMaxLimit
+	}
+
+	var afterID *umid.UMID
+	if inBody.Cursor != "" {
+		id, err := decodeObjectQueryCursor(inBody.Cursor)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiQueryObjects: failed to decode cursor")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_cursor", err, n.log)
+			return
+		}
+		afterID = &id
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiQueryObjects: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	object, ok := n.GetObjectFromAllObjects(objectID)
+	if !ok {
+		err := errors.Errorf("Node: apiQueryObjects: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	matches, more := tree.QueryObjects(c, object, userID, &inBody.Filter, afterID, int(limit))
+
+	type Out struct {
+		Objects    []map[string]any `json:"objects"`
+		NextCursor *string          `json:"next_cursor"`
+	}
+	out := Out{
+		Objects: make([]map[string]any, 0, len(matches)),
+	}
+	for _, match := range matches {
+		out.Objects = append(out.Objects, tree.ProjectObjectQueryMatch(match, &inBody.Projection))
+	}
+	if more {
+		cursor := encodeObjectQueryCursor(matches[len(matches)-1].Object.GetID())
+		out.NextCursor = &cursor
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+func encodeObjectQueryCursor(objectID umid.UMID) string {
+	return base64.RawURLEncoding.EncodeToString(objectID[:])
+}
+
+func decodeObjectQueryCursor(value string) (umid.UMID, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return umid.Nil, errors.WithMessage(err, "failed to decode base64")
+	}
+	return umid.FromBytes(data)
+}
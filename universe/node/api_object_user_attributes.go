@@ -81,6 +81,7 @@ func (n *Node) apiGetObjectUserAttributesValue(c *gin.Context) {
 		return
 	}
 
+	attributes.SetETag(c, out)
 	c.JSON(http.StatusOK, out)
 }
 
@@ -600,8 +601,14 @@ func (n *Node) apiGetObjectAllUsersAttributeValuesList(c *gin.Context) {
 		return
 	}
 
-	allowed, err := auth.CheckReadAllPermissions[entry.ObjectUserAttributeID](
-		c, *attrType.GetEntry(), n.GetObjectUserAttributes(), userID)
+	userRoles, err := n.userObjects.GetUserRoles(entry.NewUserObjectID(userID, objectID))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectAllUsersAttributeValuesList: failed to get user roles")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	}
+
+	allowed, err := auth.CheckReadAllPermissions(*attrType.GetEntry(), userRoles)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiGetObjectUserAttributesValue: permissions check")
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
@@ -753,8 +760,13 @@ func (n *Node) apiObjectUserAttributeValueEntries(c *gin.Context) {
 		api.AbortRequest(c, http.StatusBadRequest, "invalid_user", err, n.log)
 		return
 	}
-	allowed, err := auth.CheckReadAllPermissions[entry.ObjectUserAttributeID](
-		c, *attrType.GetEntry(), n.GetObjectUserAttributes(), userID)
+	userRoles, err := n.userObjects.GetUserRoles(entry.NewUserObjectID(userID, objectID))
+	if err != nil {
+		err := fmt.Errorf("get user roles: %w", err)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	}
+	allowed, err := auth.CheckReadAllPermissions(*attrType.GetEntry(), userRoles)
 	if err != nil {
 		err := fmt.Errorf("check read permissions: %w", err)
 		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
@@ -838,3 +850,98 @@ func (n *Node) apiObjectUserAttributeValueEntries(c *gin.Context) {
 	}
 	c.JSON(http.StatusOK, result)
 }
+
+// @Summary Patch object user attribute
+// @Description Applies RFC 6902 JSON Patch or RFC 7396 JSON Merge Patch to the object user attribute value.
+// @Description Optional version (or "If-Match" header) with the value ETag makes the update conditional.
+// @Tags attributes,objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param user_id path string true "User UMID"
+// @Param body body attributes.PatchBody true "body params"
+// @Success 200 {object} attributes.PatchOut
+// @Failure 400 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Failure 422 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/{user_id}/attributes [patch]
+func (n *Node) apiPatchObjectUserAttributeValue(c *gin.Context) {
+	inBody, err := attributes.BindPatchBody(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to bind patch")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	targetUserID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	pluginID, err := umid.Parse(inBody.PluginID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to parse plugin umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, n.log)
+		return
+	}
+
+	attributeID := entry.NewAttributeID(pluginID, inBody.AttributeName)
+	attrType, ok := n.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID))
+	if !ok {
+		err := errors.Errorf("Node: apiPatchObjectUserAttributeValue: attribute type not found: %s", attributeID)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_attribute", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	objectUserAttributeID := entry.NewObjectUserAttributeID(attributeID, objectID, targetUserID)
+
+	allowed, err := auth.CheckAttributePermissions(
+		c, *attrType.GetEntry(), n.GetObjectUserAttributes(), objectUserAttributeID, userID,
+		auth.WriteOperation)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: permissions check")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	} else if !allowed {
+		err := errors.New("Node: apiPatchObjectUserAttributeValue: operation not permitted")
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	modifyFn, err := inBody.ModifyFn()
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to create modify fn")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_patch", err, n.log)
+		return
+	}
+
+	payload, err := n.GetObjectUserAttributes().Upsert(objectUserAttributeID, modifyFn, true)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchObjectUserAttributeValue: failed to upsert object user attribute")
+		status, code := attributes.PatchErrorStatus(err)
+		api.AbortRequest(c, status, code, err, n.log)
+		return
+	}
+
+	out := attributes.PatchOut{
+		Value:   payload.Value,
+		Version: attributes.SetETag(c, payload.Value),
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,118 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get user permissions on object
+// @Description Returns effective roles of the user on the object, together with the roles assigned on the object and its ancestors.
+// @Description Defaults to the current user, inspecting other users requires admin role on the object.
+// @Tags members,objects
+// @Security Bearer
+// @Param object_id path string true "Object UMID"
+// @Param query query node.apiGetObjectUserPermissions.InQuery false "query params"
+// @Success 200 {object} node.apiGetObjectUserPermissions.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/objects/{object_id}/permissions [get]
+func (n *Node) apiGetObjectUserPermissions(c *gin.Context) {
+	type InQuery struct {
+		UserID string `form:"user_id" json:"user_id"`
+	}
+
+	var inQuery InQuery
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	currentUserID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	objectID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to parse object umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_id", err, n.log)
+		return
+	}
+
+	if _, ok := n.GetObjectFromAllObjects(objectID); !ok {
+		err := errors.Errorf("Node: apiGetObjectUserPermissions: object not found: %s", objectID)
+		api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+		return
+	}
+
+	userID := currentUserID
+	if inQuery.UserID != "" {
+		userID, err = umid.Parse(inQuery.UserID)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to parse user umid")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+			return
+		}
+	}
+
+	if userID != currentUserID {
+		isAdmin, err := n.userObjects.CheckIsIndirectAdmin(entry.NewUserObjectID(currentUserID, objectID))
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to check is indirect admin")
+			api.AbortRequest(c, http.StatusInternalServerError, "check_failed", err, n.log)
+			return
+		}
+		if !isAdmin {
+			err := errors.New("Node: apiGetObjectUserPermissions: user is not admin")
+			api.AbortRequest(c, http.StatusForbidden, "not_admin", err, n.log)
+			return
+		}
+	}
+
+	userObjectID := entry.NewUserObjectID(userID, objectID)
+
+	roles, err := n.userObjects.GetUserRoles(userObjectID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to get user roles")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_roles", err, n.log)
+		return
+	}
+
+	assigned, err := n.userObjects.GetAssignedRoles(userObjectID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetObjectUserPermissions: failed to get assigned roles")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_roles", err, n.log)
+		return
+	}
+	if assigned == nil {
+		assigned = []entry.UserObjectRole{}
+	}
+
+	type Out struct {
+		UserID   umid.UMID                   `json:"user_id"`
+		ObjectID umid.UMID                   `json:"object_id"`
+		Roles    []entry.PermissionsRoleType `json:"roles"`
+		Assigned []entry.UserObjectRole      `json:"assigned"`
+		IsAdmin  bool                        `json:"is_admin"`
+	}
+	out := Out{
+		UserID:   userID,
+		ObjectID: objectID,
+		Roles:    roles,
+		Assigned: assigned,
+		IsAdmin:  slices.Contains(roles, entry.PermissionAdmin),
+	}
+
+	c.JSON(http.StatusOK, out)
+}
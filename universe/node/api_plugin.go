@@ -2,17 +2,17 @@ package node
 
 import (
 	"net/http"
-	"strings"
 
 	"github.com/gin-gonic/gin"
 	"github.com/pkg/errors"
 
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
-	"github.com/momentum-xyz/ubercontroller/utils"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
 )
 
+const pluginsSearchLimit = 100
+
 // @Summary Get plugins list
 // @Description Returns plugins list
 // @Tags plugins
@@ -56,34 +56,40 @@ func (n *Node) apiGetPluginsList(c *gin.Context) {
 
 	filtered := make([]*entry.Plugin, 0)
 
-	id := pluginID
-	text := inQuery.Text
-
-	for _, plugin := range plugins {
-		if text == nil {
-			if id == nil {
+	if inQuery.Text == nil {
+		for _, plugin := range plugins {
+			if pluginID == nil || *pluginID == plugin.PluginID {
 				filtered = append(filtered, plugin)
 			}
-			if id != nil {
-				if *id == plugin.PluginID {
-					filtered = append(filtered, plugin)
-				}
-			}
 		}
 
-		if text != nil {
-			name := utils.GetFromAnyMap(plugin.Meta, "name", "")
-			if id != nil {
-				if *id == plugin.PluginID && strings.Contains(name, *text) {
-					filtered = append(filtered, plugin)
-				}
-			}
-			if id == nil {
-				if strings.Contains(name, *text) {
-					filtered = append(filtered, plugin)
-				}
-			}
+		c.JSON(http.StatusOK, filtered)
+		return
+	}
+
+	query := &entry.SearchQuery{
+		Text:  *inQuery.Text,
+		Kinds: []entry.SearchDocumentKind{entry.SearchDocumentKindPlugin},
+	}
+	documents, err := n.db.GetSearchDB().SearchDocuments(c, query, nil, pluginsSearchLimit)
+	if err != nil {
+		err = errors.WithMessage(err, "Node: apiGetPluginsList: failed to search plugins")
+		api.AbortRequest(c, http.StatusInternalServerError, "internal_error", err, n.log)
+		return
+	}
+
+	pluginsByID := make(map[umid.UMID]*entry.Plugin, len(plugins))
+	for _, plugin := range plugins {
+		pluginsByID[plugin.PluginID] = plugin
+	}
+
+	// keep the ranking of the search
+	for _, document := range documents {
+		plugin, ok := pluginsByID[document.DocumentID]
+		if !ok || (pluginID != nil && *pluginID != plugin.PluginID) {
+			continue
 		}
+		filtered = append(filtered, plugin)
 	}
 
 	c.JSON(http.StatusOK, filtered)
@@ -85,6 +85,20 @@ func (n *Node) handShake(socketConnection *websocket.Conn) error {
 	if err != nil {
 		return errors.WithMessagef(err, "failed to parse umid claim: %s", userID)
 	}
+	sessionIDClaim, err := api.GetSessionIDFromToken(*parsed)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get session claim")
+	}
+	if sessionIDClaim != umid.Nil {
+		active, err := n.userSessions.IsActive(sessionIDClaim)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to check session: %s", sessionIDClaim)
+		}
+		if !active {
+			return errors.Errorf("session is not active: %s", sessionIDClaim)
+		}
+	}
+
 	if !((userID == userIDClaim) || (userIDClaim.String() == "69e1d7f6-3130-4005-9969-31edf9af9445") || (userIDClaim.String() == "eb50bbc8-ba4e-46a3-a480-a9b30141ce91")) {
 		return nil
 	}
@@ -94,6 +108,9 @@ func (n *Node) handShake(socketConnection *websocket.Conn) error {
 		return errors.WithMessagef(err, "failed to load user from entry: %s", userID)
 	}
 	user.SetConnection(sessionID, socketConnection)
+	if sessionIDClaim != umid.Nil {
+		n.userSessions.AddConnection(sessionIDClaim, user)
+	}
 	return user.Run()
 
 	//world, ok := n.GetWorlds().GetWorld(targetWorldId)
@@ -0,0 +1,641 @@
+package node
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	prefabsDefaultLimit = 20
+	prefabsMaxLimit     = 100
+)
+
+// prefabBody is the body of prefab publishing, the template is taken from the object if object_id is set.
+type prefabBody struct {
+	Name        string                  `json:"name" binding:"required"`
+	Description string                  `json:"description"`
+	ObjectID    *umid.UMID              `json:"object_id"`
+	Template    json.RawMessage         `json:"template"`
+	Parameters  []entry.PrefabParameter `json:"parameters"`
+}
+
+// @Summary Get prefabs
+// @Description Returns prefabs of the node library with the name containing the query, the latest updated first
+// @Tags prefabs
+// @Security Bearer
+// @Param query query node.apiGetPrefabs.InQuery false "query params"
+// @Success 200 {array} entry.Prefab
+// @Failure 400 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs [get]
+func (n *Node) apiGetPrefabs(c *gin.Context) {
+	type InQuery struct {
+		Query   string `form:"query"`
+		OwnerID string `form:"owner_id"`
+		Limit   uint   `form:"limit"`
+		Offset  uint   `form:"offset"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiGetPrefabs: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	var ownerID *umid.UMID
+	if inQuery.OwnerID != "" {
+		id, err := umid.Parse(inQuery.OwnerID)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiGetPrefabs: failed to parse owner umid")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_owner_id", err, n.log)
+			return
+		}
+		ownerID = &id
+	}
+
+	limit := inQuery.Limit
+	if limit == 0 {
+		limit = prefabsDefaultLimit
+	}
+	if limit > prefabsMaxLimit {
+		limit = prefabsMaxLimit
+	}
+
+	prefabs, err := n.db.GetPrefabsDB().GetPrefabs(c, inQuery.Query, ownerID, limit, inQuery.Offset)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetPrefabs: failed to get prefabs")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_prefabs", err, n.log)
+		return
+	}
+
+	if prefabs == nil {
+		prefabs = []*entry.Prefab{}
+	}
+
+	c.JSON(http.StatusOK, prefabs)
+}
+
+// @Summary Get prefab
+// @Description Returns the prefab with the template of its latest or the given version
+// @Tags prefabs
+// @Security Bearer
+// @Param prefab_id path string true "Prefab UMID"
+// @Param query query node.apiGetPrefab.InQuery false "query params"
+// @Success 200 {object} node.apiGetPrefab.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs/{prefab_id} [get]
+func (n *Node) apiGetPrefab(c *gin.Context) {
+	type InQuery struct {
+		Version int `form:"version"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiGetPrefab: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	prefab, ok := n.getPrefabFromParam(c, "apiGetPrefab")
+	if !ok {
+		return
+	}
+
+	version := prefab.Version
+	if inQuery.Version != 0 {
+		version = inQuery.Version
+	}
+
+	prefabVersion, ok := n.getPrefabVersion(c, "apiGetPrefab", prefab.PrefabID, version)
+	if !ok {
+		return
+	}
+
+	type Out struct {
+		Prefab  *entry.Prefab        `json:"prefab"`
+		Version *entry.PrefabVersion `json:"version"`
+	}
+	out := Out{
+		Prefab:  prefab,
+		Version: prefabVersion,
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Get prefab versions
+// @Description Returns all versions of the prefab, the latest first
+// @Tags prefabs
+// @Security Bearer
+// @Param prefab_id path string true "Prefab UMID"
+// @Success 200 {array} entry.PrefabVersion
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs/{prefab_id}/versions [get]
+func (n *Node) apiGetPrefabVersions(c *gin.Context) {
+	prefab, ok := n.getPrefabFromParam(c, "apiGetPrefabVersions")
+	if !ok {
+		return
+	}
+
+	versions, err := n.db.GetPrefabsDB().GetPrefabVersions(c, prefab.PrefabID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiGetPrefabVersions: failed to get prefab versions")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_prefab_versions", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, versions)
+}
+
+// @Summary Publish prefab
+// @Description Adds a prefab to the node library from the object with its children or from the object template.
+// @Description String values of the template can contain "{{parameter}}" placeholders for the parameters.
+// @Description Only cloneable attributes the user can read are published from the object,
+// @Description attributes of the template must be of cloneable attribute types.
+// @Tags prefabs
+// @Security Bearer
+// @Param body body node.prefabBody true "body params"
+// @Success 201 {object} entry.Prefab
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs [post]
+func (n *Node) apiPublishPrefab(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPublishPrefab: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	inBody, template, ok := n.bindPrefabBody(c, "apiPublishPrefab", userID)
+	if !ok {
+		return
+	}
+
+	prefab := &entry.Prefab{
+		PrefabID:    umid.New(),
+		Name:        inBody.Name,
+		Description: inBody.Description,
+		OwnerID:     userID,
+		Version:     1,
+		Parameters:  inBody.Parameters,
+	}
+	if err := n.db.GetPrefabsDB().InsertPrefab(c, prefab, template); err != nil {
+		err := errors.WithMessage(err, "Node: apiPublishPrefab: failed to insert prefab")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_insert_prefab", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusCreated, prefab)
+}
+
+// @Summary Publish prefab version
+// @Description Publishes the next version of the prefab, owner only.
+// @Description Unless propagate is false, linked instances are rebuilt from it, keeping their umids, parents and transforms.
+// @Description Instances are rebuilt as the users who linked them, instances of users who aren't their admins anymore fail.
+// @Tags prefabs
+// @Security Bearer
+// @Param prefab_id path string true "Prefab UMID"
+// @Param query query node.apiUpdatePrefab.InQuery false "query params"
+// @Param body body node.prefabBody true "body params"
+// @Success 200 {object} node.apiUpdatePrefab.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs/{prefab_id} [put]
+func (n *Node) apiUpdatePrefab(c *gin.Context) {
+	type InQuery struct {
+		Propagate *bool `form:"propagate"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiUpdatePrefab: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUpdatePrefab: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	prefab, ok := n.getPrefabFromParam(c, "apiUpdatePrefab")
+	if !ok {
+		return
+	}
+	if prefab.OwnerID != userID {
+		err := errors.Errorf("Node: apiUpdatePrefab: user is not the prefab owner: %s", userID)
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	inBody, template, ok := n.bindPrefabBody(c, "apiUpdatePrefab", userID)
+	if !ok {
+		return
+	}
+
+	prefab.Name = inBody.Name
+	prefab.Description = inBody.Description
+	prefab.Parameters = inBody.Parameters
+	version, err := n.db.GetPrefabsDB().InsertPrefabVersion(c, prefab, template)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUpdatePrefab: failed to insert prefab version")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_insert_prefab_version", err, n.log)
+		return
+	}
+
+	type Out struct {
+		Version    int         `json:"version"`
+		Propagated []umid.UMID `json:"propagated"`
+		Failed     []umid.UMID `json:"failed"`
+	}
+	out := Out{
+		Version:    version,
+		Propagated: []umid.UMID{},
+		Failed:     []umid.UMID{},
+	}
+
+	if inQuery.Propagate == nil || *inQuery.Propagate {
+		prefabVersion := &entry.PrefabVersion{
+			PrefabID:   prefab.PrefabID,
+			Version:    version,
+			Template:   template,
+			Parameters: prefab.Parameters,
+		}
+		out.Propagated, out.Failed, err = n.propagatePrefab(c, prefabVersion)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiUpdatePrefab: failed to propagate prefab")
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_propagate_prefab", err, n.log)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Remove prefab
+// @Description Removes the prefab with all its versions from the node library, owner only. Instances are kept unlinked.
+// @Tags prefabs
+// @Security Bearer
+// @Param prefab_id path string true "Prefab UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs/{prefab_id} [delete]
+func (n *Node) apiRemovePrefab(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRemovePrefab: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	prefab, ok := n.getPrefabFromParam(c, "apiRemovePrefab")
+	if !ok {
+		return
+	}
+	if prefab.OwnerID != userID {
+		err := errors.Errorf("Node: apiRemovePrefab: user is not the prefab owner: %s", userID)
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	if _, err := n.db.GetPrefabsDB().RemovePrefabByID(c, prefab.PrefabID); err != nil {
+		err := errors.WithMessage(err, "Node: apiRemovePrefab: failed to remove prefab")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_prefab", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Instantiate prefab
+// @Description Adds objects of the prefab under the parent, which can be in any world the user is admin of.
+// @Description Linked instances are rebuilt when a new version of the prefab is published.
+// @Tags prefabs
+// @Security Bearer
+// @Param prefab_id path string true "Prefab UMID"
+// @Param body body node.apiInstantiatePrefab.InBody true "body params"
+// @Success 201 {object} node.apiInstantiatePrefab.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/prefabs/{prefab_id}/instantiate [post]
+func (n *Node) apiInstantiatePrefab(c *gin.Context) {
+	type InBody struct {
+		ParentID   umid.UMID        `json:"parent_id" binding:"required"`
+		Version    int              `json:"version"`
+		Parameters map[string]any   `json:"parameters"`
+		Transform  *cmath.Transform `json:"transform"`
+		Linked     bool             `json:"linked"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiInstantiatePrefab: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiInstantiatePrefab: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	prefab, ok := n.getPrefabFromParam(c, "apiInstantiatePrefab")
+	if !ok {
+		return
+	}
+
+	version := prefab.Version
+	if inBody.Version != 0 {
+		version = inBody.Version
+	}
+	prefabVersion, ok := n.getPrefabVersion(c, "apiInstantiatePrefab", prefab.PrefabID, version)
+	if !ok {
+		return
+	}
+
+	if _, ok := n.GetObjectFromAllObjects(inBody.ParentID); !ok {
+		err := errors.Errorf("Node: apiInstantiatePrefab: parent not found: %s", inBody.ParentID)
+		api.AbortRequest(c, http.StatusNotFound, "parent_not_found", err, n.log)
+		return
+	}
+
+	isAdmin, err := n.db.GetUserObjectsDB().CheckIsIndirectAdminByID(c, entry.NewUserObjectID(userID, inBody.ParentID))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiInstantiatePrefab: failed to check parent indirect admin")
+		api.AbortRequest(c, http.StatusBadRequest, "admin_check_failed", err, n.log)
+		return
+	}
+	if !isAdmin {
+		err := errors.New("Node: apiInstantiatePrefab: operation is not permitted for user")
+		api.AbortRequest(c, http.StatusForbidden, "object_creation_not_permitted", err, n.log)
+		return
+	}
+
+	if _, err := tree.ResolvePrefabParameters(prefabVersion.Parameters, inBody.Parameters); err != nil {
+		err := errors.WithMessage(err, "Node: apiInstantiatePrefab: invalid parameters")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_parameters", err, n.log)
+		return
+	}
+
+	transform := inBody.Transform
+	if transform == nil {
+		if transform, err = tree.CalcObjectSpawnPosition(inBody.ParentID, userID, nil); err != nil {
+			err := errors.WithMessage(err, "Node: apiInstantiatePrefab: failed to calc object spawn position")
+			api.AbortRequest(c, http.StatusBadRequest, "calc_spawn_position_failed", err, n.log)
+			return
+		}
+	}
+
+	objectID, objectIDs, err := tree.InstantiatePrefab(
+		prefabVersion, inBody.ParentID, nil, transform, inBody.Parameters, true,
+	)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiInstantiatePrefab: failed to instantiate prefab")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_instantiate_prefab", err, n.log)
+		return
+	}
+
+	if inBody.Linked {
+		instance := &entry.PrefabInstance{
+			ObjectID:        objectID,
+			PrefabID:        prefab.PrefabID,
+			Version:         prefabVersion.Version,
+			ParameterValues: inBody.Parameters,
+			CreatedBy:       utils.GetPTR(userID),
+			ObjectIDs:       objectIDs,
+		}
+		if instance.ParameterValues == nil {
+			instance.ParameterValues = map[string]any{}
+		}
+		if err := n.db.GetPrefabsDB().UpsertPrefabInstance(c, instance); err != nil {
+			err := errors.WithMessage(err, "Node: apiInstantiatePrefab: failed to upsert prefab instance")
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_link_instance", err, n.log)
+			return
+		}
+	}
+
+	type Out struct {
+		ObjectID umid.UMID `json:"object_id"`
+		Version  int       `json:"version"`
+	}
+	out := Out{
+		ObjectID: objectID,
+		Version:  prefabVersion.Version,
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
+
+// propagatePrefab rebuilds linked instances of older versions from the prefab version as the users who linked them,
+// instances of users who are not admins of them anymore fail. It returns the rebuilt and failed instances.
+func (n *Node) propagatePrefab(c *gin.Context, prefabVersion *entry.PrefabVersion) ([]umid.UMID, []umid.UMID, error) {
+	instances, err := n.db.GetPrefabsDB().GetPrefabInstancesByPrefabID(c, prefabVersion.PrefabID)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to get prefab instances")
+	}
+
+	propagated := []umid.UMID{}
+	failed := []umid.UMID{}
+	instanceVersions := make(map[int]*entry.PrefabVersion)
+	for _, instance := range instances {
+		if instance.Version >= prefabVersion.Version {
+			continue
+		}
+
+		object, ok := n.GetObjectFromAllObjects(instance.ObjectID)
+		if !ok {
+			continue
+		}
+
+		if instance.CreatedBy == nil {
+			n.log.Errorf("Node: propagatePrefab: instance user is removed: %s", instance.ObjectID)
+			failed = append(failed, instance.ObjectID)
+			continue
+		}
+		isAdmin, err := n.db.GetUserObjectsDB().CheckIsIndirectAdminByID(
+			c, entry.NewUserObjectID(*instance.CreatedBy, instance.ObjectID),
+		)
+		if err != nil {
+			return propagated, failed, errors.WithMessagef(
+				err, "failed to check instance indirect admin: %s", instance.ObjectID,
+			)
+		}
+		if !isAdmin {
+			n.log.Errorf("Node: propagatePrefab: instance user is not admin: %s: %s", instance.ObjectID, instance.CreatedBy)
+			failed = append(failed, instance.ObjectID)
+			continue
+		}
+
+		instanceVersion, ok := instanceVersions[instance.Version]
+		if !ok {
+			instanceVersion, err = n.db.GetPrefabsDB().GetPrefabVersion(c, instance.PrefabID, instance.Version)
+			if err != nil {
+				return propagated, failed, errors.WithMessagef(err, "failed to get prefab version: %d", instance.Version)
+			}
+			instanceVersions[instance.Version] = instanceVersion
+		}
+
+		objectIDs, err := tree.ReplacePrefabInstance(
+			c, object, *instance.CreatedBy, instance.ObjectIDs, instanceVersion, prefabVersion, instance.ParameterValues,
+		)
+		if err != nil {
+			n.log.Error(errors.WithMessagef(err, "Node: propagatePrefab: failed to replace instance: %s", instance.ObjectID))
+			failed = append(failed, instance.ObjectID)
+			// objects of the instance are replaced even if removing the old ones failed
+			if objectIDs == nil {
+				continue
+			}
+		}
+
+		instance.Version = prefabVersion.Version
+		instance.ObjectIDs = objectIDs
+		if err := n.db.GetPrefabsDB().UpsertPrefabInstance(c, instance); err != nil {
+			return propagated, failed, errors.WithMessagef(err, "failed to upsert prefab instance: %s", instance.ObjectID)
+		}
+		if err == nil {
+			propagated = append(propagated, instance.ObjectID)
+		}
+	}
+
+	return propagated, failed, nil
+}
+
+func (n *Node) getPrefabFromParam(c *gin.Context, handler string) (*entry.Prefab, bool) {
+	prefabID, err := umid.Parse(c.Param("prefabID"))
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to parse prefab umid", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_prefab_id", err, n.log)
+		return nil, false
+	}
+
+	prefab, err := n.db.GetPrefabsDB().GetPrefabByID(c, prefabID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err := errors.Errorf("Node: %s: prefab not found: %s", handler, prefabID)
+			api.AbortRequest(c, http.StatusNotFound, "prefab_not_found", err, n.log)
+			return nil, false
+		}
+		err := errors.WithMessagef(err, "Node: %s: failed to get prefab", handler)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_prefab", err, n.log)
+		return nil, false
+	}
+
+	return prefab, true
+}
+
+func (n *Node) getPrefabVersion(
+	c *gin.Context, handler string, prefabID umid.UMID, version int,
+) (*entry.PrefabVersion, bool) {
+	prefabVersion, err := n.db.GetPrefabsDB().GetPrefabVersion(c, prefabID, version)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			err := errors.Errorf("Node: %s: prefab version not found: %d", handler, version)
+			api.AbortRequest(c, http.StatusNotFound, "prefab_version_not_found", err, n.log)
+			return nil, false
+		}
+		err := errors.WithMessagef(err, "Node: %s: failed to get prefab version", handler)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_prefab_version", err, n.log)
+		return nil, false
+	}
+
+	return prefabVersion, true
+}
+
+// bindPrefabBody binds and validates the body, it returns the json encoded template of the prefab.
+func (n *Node) bindPrefabBody(c *gin.Context, handler string, userID umid.UMID) (*prefabBody, []byte, bool) {
+	var inBody prefabBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to bind json", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return nil, nil, false
+	}
+	if inBody.Parameters == nil {
+		inBody.Parameters = []entry.PrefabParameter{}
+	}
+
+	template := []byte(inBody.Template)
+	switch {
+	case inBody.ObjectID != nil:
+		object, ok := n.GetObjectFromAllObjects(*inBody.ObjectID)
+		if !ok {
+			err := errors.Errorf("Node: %s: object not found: %s", handler, inBody.ObjectID)
+			api.AbortRequest(c, http.StatusNotFound, "object_not_found", err, n.log)
+			return nil, nil, false
+		}
+
+		isAdmin, err := n.db.GetUserObjectsDB().CheckIsIndirectAdminByID(
+			c, entry.NewUserObjectID(userID, *inBody.ObjectID),
+		)
+		if err != nil {
+			err := errors.WithMessagef(err, "Node: %s: failed to check object indirect admin", handler)
+			api.AbortRequest(c, http.StatusBadRequest, "admin_check_failed", err, n.log)
+			return nil, nil, false
+		}
+		if !isAdmin {
+			err := errors.Errorf("Node: %s: operation is not permitted for user", handler)
+			api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+			return nil, nil, false
+		}
+
+		objectTemplate, err := tree.NewPrefabTemplate(c, object, userID)
+		if err != nil {
+			err := errors.WithMessagef(err, "Node: %s: failed to get prefab template", handler)
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_template", err, n.log)
+			return nil, nil, false
+		}
+		if template, err = json.Marshal(objectTemplate); err != nil {
+			err := errors.WithMessagef(err, "Node: %s: failed to marshal template", handler)
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_marshal_template", err, n.log)
+			return nil, nil, false
+		}
+	case len(template) == 0:
+		err := errors.Errorf("Node: %s: object_id or template is required", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return nil, nil, false
+	default:
+		if err := tree.ValidatePrefabTemplateAttributes(template); err != nil {
+			err := errors.WithMessagef(err, "Node: %s: invalid template attributes", handler)
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_prefab", err, n.log)
+			return nil, nil, false
+		}
+	}
+
+	if err := tree.ValidatePrefab(template, inBody.Parameters); err != nil {
+		err := errors.WithMessagef(err, "Node: %s: invalid prefab", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_prefab", err, n.log)
+		return nil, nil, false
+	}
+
+	return &inBody, template, true
+}
@@ -0,0 +1,177 @@
+package node
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const searchFacetsLimit = 20
+
+// @Summary Search
+// @Description Full-text search across worlds, objects, users and plugins, ranked by relevance.
+// @Description Facets are returned with the first page only, use next_cursor to get the following pages.
+// @Tags search
+// @Security Bearer
+// @Param query query node.apiSearch.InQuery false "query params"
+// @Success 200 {object} node.apiSearch.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/search [get]
+func (n *Node) apiSearch(c *gin.Context) {
+	type InQuery struct {
+		Text          string                     `form:"q"`
+		Kinds         []entry.SearchDocumentKind `form:"kind" binding:"dive,oneof=world object user plugin"`
+		ObjectTypeIDs []string                   `form:"object_type_id"`
+		OwnerIDs      []string                   `form:"owner_id"`
+		Tags          []string                   `form:"tag"`
+		WorldID       string                     `form:"world_id"`
+		Limit         uint                       `form:"limit,default=20" binding:"min=1,max=100"`
+		Cursor        string                     `form:"cursor"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiSearch: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiSearch: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	query := &entry.SearchQuery{
+		Text:     inQuery.Text,
+		Kinds:    inQuery.Kinds,
+		Tags:     inQuery.Tags,
+		ViewerID: userID,
+	}
+	if query.ObjectTypeIDs, err = parseUMIDs(inQuery.ObjectTypeIDs); err != nil {
+		err := errors.WithMessage(err, "Node: apiSearch: failed to parse object type umids")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_object_type_id", err, n.log)
+		return
+	}
+	if query.OwnerIDs, err = parseUMIDs(inQuery.OwnerIDs); err != nil {
+		err := errors.WithMessage(err, "Node: apiSearch: failed to parse owner umids")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_owner_id", err, n.log)
+		return
+	}
+	if inQuery.WorldID != "" {
+		worldID, err := umid.Parse(inQuery.WorldID)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiSearch: failed to parse world umid")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, n.log)
+			return
+		}
+		query.WorldID = &worldID
+	}
+
+	var after *entry.SearchCursor
+	if inQuery.Cursor != "" {
+		if after, err = decodeSearchCursor(inQuery.Cursor); err != nil {
+			err := errors.WithMessage(err, "Node: apiSearch: failed to decode cursor")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_cursor", err, n.log)
+			return
+		}
+	}
+
+	documents, err := n.db.GetSearchDB().SearchDocuments(c, query, after, inQuery.Limit)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiSearch: failed to search documents")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_search", err, n.log)
+		return
+	}
+
+	type Out struct {
+		Documents  []*entry.SearchDocument `json:"documents"`
+		Facets     *entry.SearchFacets     `json:"facets,omitempty"`
+		NextCursor *string                 `json:"next_cursor"`
+	}
+	out := Out{
+		Documents: documents,
+	}
+	if out.Documents == nil {
+		out.Documents = []*entry.SearchDocument{}
+	}
+
+	if after == nil {
+		if out.Facets, err = n.db.GetSearchDB().GetSearchFacets(c, query, searchFacetsLimit); err != nil {
+			err := errors.WithMessage(err, "Node: apiSearch: failed to get facets")
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_facets", err, n.log)
+			return
+		}
+	}
+
+	if uint(len(documents)) == inQuery.Limit {
+		last := documents[len(documents)-1]
+		cursor, err := encodeSearchCursor(&entry.SearchCursor{Rank: last.Rank, DocumentID: last.DocumentID})
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiSearch: failed to encode cursor")
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_encode_cursor", err, n.log)
+			return
+		}
+		out.NextCursor = &cursor
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Rebuild search index
+// @Description Rebuilds the whole search index, it is normally kept up to date automatically
+// @Tags search
+// @Security Bearer
+// @Success 200 {object} nil
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/node/search/reindex [post]
+func (n *Node) apiNodeReindexSearch(c *gin.Context) {
+	if err := n.db.GetSearchDB().ReindexSearchDocuments(c); err != nil {
+		err := errors.WithMessage(err, "Node: apiNodeReindexSearch: failed to reindex")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_reindex", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func parseUMIDs(values []string) ([]umid.UMID, error) {
+	ids := make([]umid.UMID, len(values))
+	for i := range values {
+		id, err := umid.Parse(values[i])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to parse: %s", values[i])
+		}
+		ids[i] = id
+	}
+	return ids, nil
+}
+
+func encodeSearchCursor(cursor *entry.SearchCursor) (string, error) {
+	data, err := json.Marshal(cursor)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to marshal cursor")
+	}
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+func decodeSearchCursor(value string) (*entry.SearchCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to decode base64")
+	}
+	var cursor entry.SearchCursor
+	if err := json.Unmarshal(data, &cursor); err != nil {
+		return nil, errors.WithMessage(err, "failed to unmarshal cursor")
+	}
+	return &cursor, nil
+}
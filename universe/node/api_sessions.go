@@ -0,0 +1,212 @@
+package node
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Refresh access token
+// @Description Issues a new access token for the session, the refresh token is rotated and can only be used once
+// @Tags auth
+// @Param body body node.apiRefreshToken.InBody true "body params"
+// @Success 200 {object} entry.UserSessionTokens
+// @Failure 400 {object} api.HTTPError
+// @Failure 401 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Router /api/v4/auth/refresh [post]
+func (n *Node) apiRefreshToken(c *gin.Context) {
+	type InBody struct {
+		RefreshToken string `json:"refresh_token" binding:"required"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiRefreshToken: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	_, tokens, err := n.userSessions.Refresh(inBody.RefreshToken)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiRefreshToken: failed to refresh session")
+		if errors.Is(err, errInvalidRefreshToken) {
+			api.AbortRequest(c, http.StatusUnauthorized, "invalid_refresh_token", err, n.log)
+			return
+		}
+		if errors.Is(err, errUserBanned) {
+			api.AbortRequest(c, http.StatusForbidden, "user_banned", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_refresh_token", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, tokens)
+}
+
+// @Summary Get my sessions
+// @Description Returns active sessions of the current user
+// @Tags users,auth
+// @Security Bearer
+// @Success 200 {array} node.apiUsersGetMySessions.Out
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/sessions [get]
+func (n *Node) apiUsersGetMySessions(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMySessions: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	currentSessionID, err := api.GetSessionIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMySessions: failed to get session umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_session_id", err, n.log)
+		return
+	}
+
+	sessions, err := n.userSessions.GetActive(userID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMySessions: failed to get sessions")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_sessions", err, n.log)
+		return
+	}
+
+	type Out struct {
+		SessionID umid.UMID `json:"session_id"`
+		UserAgent string    `json:"user_agent"`
+		IPAddress string    `json:"ip_address"`
+		Current   bool      `json:"current"`
+		CreatedAt time.Time `json:"created_at"`
+		UpdatedAt time.Time `json:"updated_at"`
+		ExpiresAt time.Time `json:"expires_at"`
+	}
+	out := make([]Out, 0, len(sessions))
+	for _, session := range sessions {
+		out = append(
+			out, Out{
+				SessionID: session.SessionID,
+				UserAgent: session.UserAgent,
+				IPAddress: session.IPAddress,
+				Current:   session.SessionID == currentSessionID,
+				CreatedAt: session.CreatedAt,
+				UpdatedAt: session.UpdatedAt,
+				ExpiresAt: session.ExpiresAt,
+			},
+		)
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Revoke my session
+// @Description Revokes a session of the current user, websocket connections of the session are closed
+// @Tags users,auth
+// @Security Bearer
+// @Param session_id path string true "Session UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/sessions/{session_id} [delete]
+func (n *Node) apiUsersRemoveMySession(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMySession: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	sessionID, err := umid.Parse(c.Param("sessionID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMySession: failed to parse session umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_session_id", err, n.log)
+		return
+	}
+
+	sessions, err := n.userSessions.GetActive(userID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMySession: failed to get sessions")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_sessions", err, n.log)
+		return
+	}
+	if !containsSession(sessions, sessionID) {
+		err := errors.Errorf("Node: apiUsersRemoveMySession: session not found: %s", sessionID)
+		api.AbortRequest(c, http.StatusNotFound, "session_not_found", err, n.log)
+		return
+	}
+
+	if err := n.userSessions.Revoke(sessionID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMySession: failed to revoke session")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_revoke_session", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Revoke all my sessions
+// @Description Revokes all sessions of the current user including the current one
+// @Tags users,auth
+// @Security Bearer
+// @Success 200 {object} nil
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/sessions [delete]
+func (n *Node) apiUsersRemoveMySessions(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMySessions: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	if err := n.userSessions.RevokeAll(userID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMySessions: failed to revoke sessions")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_revoke_sessions", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Log out user everywhere
+// @Description Revokes all sessions of the user and closes their websocket connections, node admin only
+// @Tags users,auth
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Router /api/v4/users/{user_id}/sessions [delete]
+func (n *Node) apiUsersRemoveSessions(c *gin.Context) {
+	userID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveSessions: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	if err := n.userSessions.RevokeAll(userID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveSessions: failed to revoke sessions")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_revoke_sessions", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func containsSession(sessions []*entry.UserSession, sessionID umid.UMID) bool {
+	for _, session := range sessions {
+		if session.SessionID == sessionID {
+			return true
+		}
+	}
+	return false
+}
@@ -52,6 +52,7 @@ func (n *Node) apiGetMeUserAttributeValue(c *gin.Context) {
 		return
 	}
 
+	attributes.SetETag(c, out)
 	c.JSON(http.StatusOK, out)
 }
 
@@ -109,6 +110,7 @@ func (n *Node) apiGetUserAttributeValue(c *gin.Context) {
 		return
 	}
 
+	attributes.SetETag(c, out)
 	c.JSON(http.StatusOK, out)
 }
 
@@ -556,3 +558,91 @@ func (n *Node) apiRemoveUserAttributeValue(c *gin.Context) {
 
 	c.JSON(http.StatusOK, nil)
 }
+
+// @Summary Patch user attribute
+// @Description Applies RFC 6902 JSON Patch or RFC 7396 JSON Merge Patch to the user attribute value.
+// @Description Optional version (or "If-Match" header) with the value ETag makes the update conditional.
+// @Tags attributes,users
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Param body body attributes.PatchBody true "body params"
+// @Success 200 {object} attributes.PatchOut
+// @Failure 400 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Failure 422 {object} api.HTTPError
+// @Router /api/v4/users/{user_id}/attributes [patch]
+func (n *Node) apiPatchUserAttributeValue(c *gin.Context) {
+	inBody, err := attributes.BindPatchBody(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: failed to bind patch")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	targetUserID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	pluginID, err := umid.Parse(inBody.PluginID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: failed to parse plugin umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, n.log)
+		return
+	}
+
+	attributeID := entry.NewAttributeID(pluginID, inBody.AttributeName)
+	attrType, ok := n.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID))
+	if !ok {
+		err := errors.Errorf("Node: apiPatchUserAttributeValue: attribute type not found: %s", attributeID)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_attribute", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	userAttributeID := entry.NewUserAttributeID(attributeID, targetUserID)
+
+	allowed, err := auth.CheckAttributePermissions(
+		c, *attrType.GetEntry(), n.GetUserAttributes(), userAttributeID, userID,
+		auth.WriteOperation,
+	)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: permissions check")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	} else if !allowed {
+		err := errors.New("Node: apiPatchUserAttributeValue: operation not permitted")
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	modifyFn, err := inBody.ModifyFn()
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: failed to create modify fn")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_patch", err, n.log)
+		return
+	}
+
+	payload, err := n.GetUserAttributes().Upsert(userAttributeID, modifyFn, true)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserAttributeValue: failed to upsert user attribute")
+		status, code := attributes.PatchErrorStatus(err)
+		api.AbortRequest(c, status, code, err, n.log)
+		return
+	}
+
+	out := attributes.PatchOut{
+		Value:   payload.Value,
+		Version: attributes.SetETag(c, payload.Value),
+	}
+
+	c.JSON(http.StatusOK, out)
+}
@@ -0,0 +1,131 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get my avatar
+// @Description Returns the structured avatar of the current user
+// @Tags users,avatar
+// @Security Bearer
+// @Success 200 {object} entry.UserAvatar
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/avatar [get]
+func (n *Node) apiUsersGetMyAvatar(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMyAvatar: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	n.getUserAvatar(c, userID, "apiUsersGetMyAvatar")
+}
+
+// @Summary Get user avatar
+// @Description Returns the structured avatar of a user
+// @Tags users,avatar
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} entry.UserAvatar
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/{user_id}/avatar [get]
+func (n *Node) apiUsersGetAvatar(c *gin.Context) {
+	userID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetAvatar: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	n.getUserAvatar(c, userID, "apiUsersGetAvatar")
+}
+
+// @Summary Set my avatar
+// @Description Validates and stores the structured avatar of the current user and broadcasts it to the current world
+// @Tags users,avatar
+// @Security Bearer
+// @Param body body entry.UserAvatar true "body params"
+// @Success 200 {object} entry.UserAvatar
+// @Failure 400 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/avatar [put]
+func (n *Node) apiUsersSetMyAvatar(c *gin.Context) {
+	var inBody entry.UserAvatar
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyAvatar: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyAvatar: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	profile, err := n.setUserAvatar(c, userID, &inBody)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyAvatar: failed to set avatar")
+		if errors.Is(err, errInvalidUserAvatar) {
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_avatar", err, n.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_set_avatar", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile.Avatar)
+}
+
+// @Summary Remove my avatar
+// @Description Removes the structured avatar of the current user
+// @Tags users,avatar
+// @Security Bearer
+// @Success 200 {object} nil
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/avatar [delete]
+func (n *Node) apiUsersRemoveMyAvatar(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMyAvatar: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	if _, err := n.setUserAvatar(c, userID, nil); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMyAvatar: failed to remove avatar")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_avatar", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (n *Node) getUserAvatar(c *gin.Context, userID umid.UMID, handler string) {
+	profile, err := n.db.GetUsersDB().GetUserProfileByUserID(c, userID)
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to get user profile", handler)
+		api.AbortRequest(c, http.StatusNotFound, "user_not_found", err, n.log)
+		return
+	}
+	if profile.Avatar == nil {
+		err := errors.Errorf("Node: %s: avatar not found", handler)
+		api.AbortRequest(c, http.StatusNotFound, "avatar_not_found", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, profile.Avatar)
+}
@@ -0,0 +1,106 @@
+package node
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Export my data
+// @Description Returns a zip archive with all the data tied to the current user and media uploaded by the user
+// @Tags users
+// @Security Bearer
+// @Produce application/zip
+// @Success 200 {file} binary
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/export [get]
+func (n *Node) apiUsersExportMe(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersExportMe: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	var archive bytes.Buffer
+	if err := n.exportUserData(c, userID, &archive); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersExportMe: failed to export user data")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_export_user_data", err, n.log)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "user-"+userID.String()+".zip"))
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// @Summary Remove my account
+// @Description Removes the current user with all the data tied to it.
+// @Description Owned worlds are deleted or transferred to another user (the node owner by default) according to the policy.
+// @Tags users
+// @Security Bearer
+// @Param query query node.apiUsersRemoveMe.InQuery true "query params"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me [delete]
+func (n *Node) apiUsersRemoveMe(c *gin.Context) {
+	type InQuery struct {
+		WorldsPolicy string `form:"worlds_policy" binding:"required,oneof=delete transfer"`
+		TransferTo   string `form:"transfer_to"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMe: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMe: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	if userID == n.GetOwnerID() {
+		err := errors.New("Node: apiUsersRemoveMe: node owner can not be removed")
+		api.AbortRequest(c, http.StatusForbidden, "node_owner_not_removable", err, n.log)
+		return
+	}
+
+	transferTo := n.GetOwnerID()
+	if inQuery.WorldsPolicy == userWorldsPolicyTransfer && inQuery.TransferTo != "" {
+		transferTo, err = umid.Parse(inQuery.TransferTo)
+		if err != nil {
+			err := errors.WithMessage(err, "Node: apiUsersRemoveMe: failed to parse transfer user umid")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_transfer_to", err, n.log)
+			return
+		}
+		if transferTo == userID {
+			err := errors.New("Node: apiUsersRemoveMe: can not transfer worlds to removed user")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_transfer_to", err, n.log)
+			return
+		}
+		if _, err := n.db.GetUsersDB().GetUserByID(c, transferTo); err != nil {
+			err := errors.WithMessage(err, "Node: apiUsersRemoveMe: transfer user not found")
+			api.AbortRequest(c, http.StatusBadRequest, "invalid_transfer_to", err, n.log)
+			return
+		}
+	}
+
+	if err := n.removeUserData(c, userID, inQuery.WorldsPolicy, transferTo); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveMe: failed to remove user data")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_user", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
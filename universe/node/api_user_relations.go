@@ -0,0 +1,516 @@
+package node
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get my friends
+// @Description Returns friends of the current user with their presence
+// @Tags users,friends
+// @Security Bearer
+// @Success 200 {array} entry.UserPresence
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/friends [get]
+func (n *Node) apiUsersGetMyFriends(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMyFriends: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	friends, err := n.userRelations.GetFriends(userID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMyFriends: failed to get friends")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_friends", err, n.log)
+		return
+	}
+
+	out := make([]*entry.UserPresence, 0, len(friends))
+	for _, friend := range friends {
+		presence, err := n.userRelations.GetPresence(userID, friend.TargetUserID)
+		if err != nil {
+			err := errors.WithMessagef(err, "Node: apiUsersGetMyFriends: failed to get presence: %s", friend.TargetUserID)
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_presence", err, n.log)
+			return
+		}
+		out = append(out, presence)
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Get my friend requests
+// @Description Returns pending friend requests sent to and by the current user
+// @Tags users,friends
+// @Security Bearer
+// @Success 200 {object} node.apiUsersGetMyFriendRequests.Out
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/friends/requests [get]
+func (n *Node) apiUsersGetMyFriendRequests(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMyFriendRequests: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	incoming, outgoing, err := n.userRelations.GetFriendRequests(userID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetMyFriendRequests: failed to get friend requests")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_friend_requests", err, n.log)
+		return
+	}
+
+	type Out struct {
+		Incoming []*entry.UserRelation `json:"incoming"`
+		Outgoing []*entry.UserRelation `json:"outgoing"`
+	}
+	out := Out{
+		Incoming: incoming,
+		Outgoing: outgoing,
+	}
+	if out.Incoming == nil {
+		out.Incoming = []*entry.UserRelation{}
+	}
+	if out.Outgoing == nil {
+		out.Outgoing = []*entry.UserRelation{}
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Send friend request
+// @Description Sends a friend request to the user, a pending request from the user is accepted instead
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} node.apiUsersRequestFriendship.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Router /api/v4/users/me/friends/{user_id} [post]
+func (n *Node) apiUsersRequestFriendship(c *gin.Context) {
+	userID, targetID, ok := n.getUserRelationParams(c, "apiUsersRequestFriendship")
+	if !ok {
+		return
+	}
+
+	relationType, err := n.userRelations.RequestFriendship(userID, targetID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRequestFriendship: failed to request friendship")
+		n.abortUserRelationRequest(c, err, "failed_to_request_friendship")
+		return
+	}
+
+	type Out struct {
+		RelationType entry.UserRelationType `json:"relation_type"`
+	}
+	out := Out{
+		RelationType: relationType,
+	}
+
+	c.JSON(http.StatusOK, out)
+}
+
+// @Summary Accept friend request
+// @Description Accepts a pending friend request from the user
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/friends/{user_id}/accept [post]
+func (n *Node) apiUsersAcceptFriendship(c *gin.Context) {
+	userID, requesterID, ok := n.getUserRelationParams(c, "apiUsersAcceptFriendship")
+	if !ok {
+		return
+	}
+
+	if err := n.userRelations.AcceptFriendship(userID, requesterID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersAcceptFriendship: failed to accept friendship")
+		n.abortUserRelationRequest(c, err, "failed_to_accept_friendship")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Remove friend
+// @Description Removes the user from friends, declines or cancels a pending friend request
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/friends/{user_id} [delete]
+func (n *Node) apiUsersRemoveFriendship(c *gin.Context) {
+	userID, otherUserID, ok := n.getUserRelationParams(c, "apiUsersRemoveFriendship")
+	if !ok {
+		return
+	}
+
+	if err := n.userRelations.RemoveFriendship(userID, otherUserID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersRemoveFriendship: failed to remove friendship")
+		n.abortUserRelationRequest(c, err, "failed_to_remove_friendship")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Join friend
+// @Description Asks the connected client of the current user to fly to the world of the friend.
+// @Description Returns the presence of the friend, the friend must be online and visible.
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} entry.UserPresence
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/friends/{user_id}/join [post]
+func (n *Node) apiUsersJoinFriend(c *gin.Context) {
+	userID, friendID, ok := n.getUserRelationParams(c, "apiUsersJoinFriend")
+	if !ok {
+		return
+	}
+
+	isFriend, err := n.db.GetUserRelationsDB().CheckUserRelationExists(
+		c, entry.NewUserRelationID(userID, friendID, entry.UserRelationFriend),
+	)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersJoinFriend: failed to check friendship")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_check_friendship", err, n.log)
+		return
+	}
+	if !isFriend {
+		err := errors.Errorf("Node: apiUsersJoinFriend: user is not a friend: %s", friendID)
+		api.AbortRequest(c, http.StatusForbidden, "not_a_friend", err, n.log)
+		return
+	}
+
+	presence, err := n.userRelations.GetPresence(userID, friendID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersJoinFriend: failed to get presence")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_presence", err, n.log)
+		return
+	}
+	if presence.WorldID == nil {
+		err := errors.Errorf("Node: apiUsersJoinFriend: friend is not in a world: %s", friendID)
+		api.AbortRequest(c, http.StatusNotFound, "friend_not_in_world", err, n.log)
+		return
+	}
+
+	if user, ok := n.userRelations.GetOnlineUser(userID); ok {
+		fwm := posbus.FlyToMe{
+			Pilot:    friendID,
+			ObjectID: *presence.WorldID,
+		}
+		if friend, ok := n.userRelations.GetOnlineUser(friendID); ok {
+			if profile := friend.GetProfile(); profile != nil && profile.Name != nil {
+				fwm.PilotName = *profile.Name
+			}
+		}
+		if err := user.Send(posbus.WSMessage(&fwm)); err != nil {
+			err := errors.WithMessage(err, "Node: apiUsersJoinFriend: failed to send fly to friend")
+			api.AbortRequest(c, http.StatusInternalServerError, "failed_to_dispatch_event", err, n.log)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, presence)
+}
+
+// @Summary Get followed users
+// @Description Returns users followed by the current user
+// @Tags users,friends
+// @Security Bearer
+// @Success 200 {array} entry.UserRelation
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/following [get]
+func (n *Node) apiUsersGetMyFollowing(c *gin.Context) {
+	n.getMyUserRelations(c, "apiUsersGetMyFollowing", n.userRelations.GetFollowing)
+}
+
+// @Summary Get followers
+// @Description Returns users following the current user
+// @Tags users,friends
+// @Security Bearer
+// @Success 200 {array} entry.UserRelation
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/followers [get]
+func (n *Node) apiUsersGetMyFollowers(c *gin.Context) {
+	n.getMyUserRelations(c, "apiUsersGetMyFollowers", n.userRelations.GetFollowers)
+}
+
+// @Summary Follow user
+// @Description Follows the user
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Router /api/v4/users/me/following/{user_id} [post]
+func (n *Node) apiUsersFollow(c *gin.Context) {
+	userID, targetID, ok := n.getUserRelationParams(c, "apiUsersFollow")
+	if !ok {
+		return
+	}
+
+	if err := n.userRelations.Follow(userID, targetID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersFollow: failed to follow user")
+		n.abortUserRelationRequest(c, err, "failed_to_follow")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Unfollow user
+// @Description Stops following the user
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/following/{user_id} [delete]
+func (n *Node) apiUsersUnfollow(c *gin.Context) {
+	userID, targetID, ok := n.getUserRelationParams(c, "apiUsersUnfollow")
+	if !ok {
+		return
+	}
+
+	if err := n.userRelations.Unfollow(userID, targetID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersUnfollow: failed to unfollow user")
+		n.abortUserRelationRequest(c, err, "failed_to_unfollow")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Get blocked users
+// @Description Returns users blocked by the current user
+// @Tags users,friends
+// @Security Bearer
+// @Success 200 {array} entry.UserRelation
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/blocked [get]
+func (n *Node) apiUsersGetMyBlocked(c *gin.Context) {
+	n.getMyUserRelations(c, "apiUsersGetMyBlocked", n.userRelations.GetBlocked)
+}
+
+// @Summary Block user
+// @Description Blocks the user, removing friendship, requests and follows between the users
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/blocked/{user_id} [post]
+func (n *Node) apiUsersBlock(c *gin.Context) {
+	userID, targetID, ok := n.getUserRelationParams(c, "apiUsersBlock")
+	if !ok {
+		return
+	}
+
+	if err := n.userRelations.Block(userID, targetID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersBlock: failed to block user")
+		n.abortUserRelationRequest(c, err, "failed_to_block")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Unblock user
+// @Description Unblocks the user
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/users/me/blocked/{user_id} [delete]
+func (n *Node) apiUsersUnblock(c *gin.Context) {
+	userID, targetID, ok := n.getUserRelationParams(c, "apiUsersUnblock")
+	if !ok {
+		return
+	}
+
+	if err := n.userRelations.Unblock(userID, targetID); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersUnblock: failed to unblock user")
+		n.abortUserRelationRequest(c, err, "failed_to_unblock")
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Set my presence visibility
+// @Description Sets who can see which world the current user is in: everyone, friends (default) or nobody
+// @Tags users,friends
+// @Security Bearer
+// @Param body body node.apiUsersSetMyPresenceVisibility.InBody true "body params"
+// @Success 200 {object} entry.UserOptions
+// @Failure 400 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/me/presence-visibility [put]
+func (n *Node) apiUsersSetMyPresenceVisibility(c *gin.Context) {
+	type InBody struct {
+		Visibility entry.PresenceVisibility `json:"visibility" binding:"required,oneof=everyone friends nobody"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyPresenceVisibility: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyPresenceVisibility: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	userEntry, err := n.db.GetUsersDB().GetUserByID(c, userID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyPresenceVisibility: failed to get user by umid")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user", err, n.log)
+		return
+	}
+
+	options := &entry.UserOptions{}
+	if userEntry.Options != nil {
+		*options = *userEntry.Options
+	}
+	options.PresenceVisibility = &inBody.Visibility
+
+	if err := n.db.GetUsersDB().UpdateUserOptions(c, userID, options); err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSetMyPresenceVisibility: failed to update user options")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_update_options", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, options)
+}
+
+// @Summary Get user presence
+// @Description Returns which world the user is in, if the privacy settings of the user allow it
+// @Tags users,friends
+// @Security Bearer
+// @Param user_id path string true "User UMID"
+// @Success 200 {object} entry.UserPresence
+// @Failure 400 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/users/{user_id}/presence [get]
+func (n *Node) apiUsersGetPresence(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetPresence: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	targetID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetPresence: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	presence, err := n.userRelations.GetPresence(userID, targetID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersGetPresence: failed to get presence")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_presence", err, n.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, presence)
+}
+
+// getUserRelationParams returns the current user and the existing target user of the request.
+func (n *Node) getUserRelationParams(c *gin.Context, handler string) (umid.UMID, umid.UMID, bool) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to get user umid from context", handler)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return umid.Nil, umid.Nil, false
+	}
+
+	targetID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to parse user umid", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return umid.Nil, umid.Nil, false
+	}
+	if targetID == userID {
+		err := errors.Errorf("Node: %s: user can not relate to themselves", handler)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return umid.Nil, umid.Nil, false
+	}
+
+	if _, err := n.db.GetUsersDB().GetUserByID(c, targetID); err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to get user by umid", handler)
+		api.AbortRequest(c, http.StatusNotFound, "user_not_found", err, n.log)
+		return umid.Nil, umid.Nil, false
+	}
+
+	return userID, targetID, true
+}
+
+func (n *Node) getMyUserRelations(
+	c *gin.Context, handler string, getFn func(userID umid.UMID) ([]*entry.UserRelation, error),
+) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to get user umid from context", handler)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	relations, err := getFn(userID)
+	if err != nil {
+		err := errors.WithMessagef(err, "Node: %s: failed to get user relations", handler)
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_relations", err, n.log)
+		return
+	}
+
+	if relations == nil {
+		relations = []*entry.UserRelation{}
+	}
+
+	c.JSON(http.StatusOK, relations)
+}
+
+func (n *Node) abortUserRelationRequest(c *gin.Context, err error, errCode string) {
+	switch {
+	case errors.Is(err, errUserBlocked):
+		api.AbortRequest(c, http.StatusForbidden, "user_blocked", err, n.log)
+	case errors.Is(err, errUserRelationExists):
+		api.AbortRequest(c, http.StatusConflict, "user_relation_exists", err, n.log)
+	case errors.Is(err, errUserRelationNotFound):
+		api.AbortRequest(c, http.StatusNotFound, "user_relation_not_found", err, n.log)
+	default:
+		api.AbortRequest(c, http.StatusInternalServerError, errCode, err, n.log)
+	}
+}
@@ -117,3 +117,98 @@ func (n *Node) apiSetUserUserSubAttributeValue(c *gin.Context) {
 
 	c.JSON(http.StatusAccepted, userUserAttribute.Value)
 }
+
+// @Summary Patch user user attribute
+// @Description Applies RFC 6902 JSON Patch or RFC 7396 JSON Merge Patch to the user user attribute value.
+// @Description Optional version (or "If-Match" header) with the value ETag makes the update conditional.
+// @Tags attributes,users
+// @Security Bearer
+// @Param user_id path string true "Source user UMID"
+// @Param target_id path string true "Target user UMID"
+// @Param body body attributes.PatchBody true "body params"
+// @Success 200 {object} attributes.PatchOut
+// @Failure 400 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Failure 422 {object} api.HTTPError
+// @Router /api/v4/users/attributes/{user_id}/{target_id} [patch]
+func (n *Node) apiPatchUserUserAttributeValue(c *gin.Context) {
+	inBody, err := attributes.BindPatchBody(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to bind patch")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, n.log)
+		return
+	}
+
+	sourceID, err := umid.Parse(c.Param("userID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to parse user umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_user_id", err, n.log)
+		return
+	}
+
+	targetID, err := umid.Parse(c.Param("targetID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to parse target umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_target_id", err, n.log)
+		return
+	}
+
+	pluginID, err := umid.Parse(inBody.PluginID)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to parse plugin umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_plugin_id", err, n.log)
+		return
+	}
+
+	attributeID := entry.NewAttributeID(pluginID, inBody.AttributeName)
+	attrType, ok := n.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attributeID))
+	if !ok {
+		err := errors.Errorf("Node: apiPatchUserUserAttributeValue: attribute type not found: %s", attributeID)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_attribute", err, n.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	userUserAttributeID := entry.NewUserUserAttributeID(attributeID, sourceID, targetID)
+
+	allowed, err := auth.CheckAttributePermissions(
+		c, *attrType.GetEntry(), n.GetUserUserAttributes(), userUserAttributeID, userID,
+		auth.WriteOperation)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: permissions check")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_permissions_check", err, n.log)
+		return
+	} else if !allowed {
+		err := errors.New("Node: apiPatchUserUserAttributeValue: operation not permitted")
+		api.AbortRequest(c, http.StatusForbidden, "operation_not_permitted", err, n.log)
+		return
+	}
+
+	modifyFn, err := inBody.ModifyFn()
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to create modify fn")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_patch", err, n.log)
+		return
+	}
+
+	payload, err := n.GetUserUserAttributes().Upsert(userUserAttributeID, modifyFn, true)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiPatchUserUserAttributeValue: failed to upsert user user attribute")
+		status, code := attributes.PatchErrorStatus(err)
+		api.AbortRequest(c, status, code, err, n.log)
+		return
+	}
+
+	out := attributes.PatchOut{
+		Value:   payload.Value,
+		Version: attributes.SetETag(c, payload.Value),
+	}
+
+	c.JSON(http.StatusOK, out)
+}
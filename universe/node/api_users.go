@@ -388,7 +388,14 @@ func (n *Node) apiUsersSearchUsers(c *gin.Context) {
 		return
 	}
 
-	users, err := n.apiUsersFilterUsers(inQuery.SearchQuery)
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Node: apiUsersSearchUsers: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, n.log)
+		return
+	}
+
+	users, err := n.apiUsersFilterUsers(c, userID, inQuery.SearchQuery)
 	if err != nil {
 		err := errors.WithMessage(err, "Node: apiUsersSearchUsers: failed to filter objects")
 		api.AbortRequest(c, http.StatusBadRequest, "failed_to_filter", err, n.log)
@@ -398,29 +405,45 @@ func (n *Node) apiUsersSearchUsers(c *gin.Context) {
 	c.JSON(http.StatusOK, users)
 }
 
-func (n *Node) apiUsersFilterUsers(searchQuery string) (dto.UserSearchResults, error) {
-	predicateFn := func(userID umid.UMID, user universe.User) bool {
-		var name string
-		loadedUser, _ := n.LoadUser(userID)
-		profile := loadedUser.GetProfile()
-
-		if profile != nil && profile.Name != nil {
-			name = *profile.Name
-		}
+const usersSearchLimit = 100
 
-		name = strings.ToLower(name)
-		searchQuery = strings.ToLower(searchQuery)
-		return strings.Contains(name, searchQuery)
+func (n *Node) apiUsersFilterUsers(
+	ctx context.Context, viewerID umid.UMID, searchQuery string,
+) (dto.UserSearchResults, error) {
+	query := &entry.SearchQuery{
+		Text:     searchQuery,
+		Kinds:    []entry.SearchDocumentKind{entry.SearchDocumentKindUser},
+		ViewerID: viewerID,
+	}
+	documents, err := n.db.GetSearchDB().SearchDocuments(ctx, query, nil, usersSearchLimit)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to search users")
 	}
 
-	filteredUsers, _ := n.Filter(predicateFn)
-	options := make([]dto.UserSearchResult, 0, len(filteredUsers))
+	userIDs := make([]umid.UMID, len(documents))
+	for i := range documents {
+		userIDs[i] = documents[i].DocumentID
+	}
+	users, err := n.db.GetUsersDB().GetUsersByIDs(ctx, userIDs)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get users by ids")
+	}
+	usersByID := make(map[umid.UMID]*entry.User, len(users))
+	for _, user := range users {
+		usersByID[user.UserID] = user
+	}
 
-	for _, filteredUser := range filteredUsers {
-		profile := filteredUser.GetProfile()
+	// keep the ranking of the search
+	options := make([]dto.UserSearchResult, 0, len(documents))
+	for _, document := range documents {
+		user, ok := usersByID[document.DocumentID]
+		if !ok {
+			continue
+		}
+		profile := user.Profile
 
 		option := dto.UserSearchResult{
-			ID:     filteredUser.GetID(),
+			ID:     user.UserID,
 			Name:   profile.Name,
 			Wallet: nil,
 			Profile: dto.Profile{
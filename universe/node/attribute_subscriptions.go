@@ -0,0 +1,182 @@
+package node
+
+import (
+	"sync"
+
+	"github.com/gorilla/websocket"
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var _ universe.AttributeSubscriptions = (*attributeSubscriptions)(nil)
+
+type attributeSubscriptions struct {
+	node *Node
+	mu   sync.RWMutex
+	data map[entry.AttributeSubscriptionID]map[umid.UMID]universe.User
+}
+
+func newAttributeSubscriptions(node *Node) *attributeSubscriptions {
+	return &attributeSubscriptions{
+		node: node,
+		data: make(map[entry.AttributeSubscriptionID]map[umid.UMID]universe.User),
+	}
+}
+
+func (as *attributeSubscriptions) Subscribe(user universe.User, subscriptionID entry.AttributeSubscriptionID) error {
+	attributeType, ok := as.node.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(subscriptionID.AttributeID))
+	if !ok {
+		return errors.Errorf("attribute type not found: %+v", subscriptionID.AttributeID)
+	}
+
+	value, allowed, err := as.getValue(*attributeType.GetEntry(), subscriptionID, user.GetID())
+	if err != nil {
+		return errors.WithMessage(err, "failed to check permissions")
+	}
+	if !allowed {
+		return errors.Errorf("operation not permitted: %+v", subscriptionID)
+	}
+
+	as.mu.Lock()
+	users, ok := as.data[subscriptionID]
+	if !ok {
+		users = make(map[umid.UMID]universe.User)
+		as.data[subscriptionID] = users
+	}
+	users[user.GetID()] = user
+	as.mu.Unlock()
+
+	if value == nil {
+		return nil
+	}
+
+	return user.Send(as.getMessage(subscriptionID, posbus.ChangedAttributeChangeType, value))
+}
+
+func (as *attributeSubscriptions) Unsubscribe(user universe.User, subscriptionID entry.AttributeSubscriptionID) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	as.unsubscribe(user, subscriptionID)
+}
+
+func (as *attributeSubscriptions) UnsubscribeAll(user universe.User) {
+	as.mu.Lock()
+	defer as.mu.Unlock()
+
+	for subscriptionID := range as.data {
+		as.unsubscribe(user, subscriptionID)
+	}
+}
+
+// unsubscribe is not thread safe, only removes the given user instance to not drop subscriptions of a newer connection
+func (as *attributeSubscriptions) unsubscribe(user universe.User, subscriptionID entry.AttributeSubscriptionID) {
+	users, ok := as.data[subscriptionID]
+	if !ok {
+		return
+	}
+	if subscriber, ok := users[user.GetID()]; !ok || subscriber != user {
+		return
+	}
+
+	delete(users, user.GetID())
+	if len(users) == 0 {
+		delete(as.data, subscriptionID)
+	}
+}
+
+func (as *attributeSubscriptions) Publish(
+	subscriptionID entry.AttributeSubscriptionID, changeType posbus.AttributeChangeType, value *entry.AttributeValue,
+) error {
+	as.mu.RLock()
+	users := make([]universe.User, 0, len(as.data[subscriptionID]))
+	for _, user := range as.data[subscriptionID] {
+		users = append(users, user)
+	}
+	as.mu.RUnlock()
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	message := as.getMessage(subscriptionID, changeType, value)
+
+	var errs *multierror.Error
+	for _, user := range users {
+		if err := user.Send(message); err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to send message: %s", user.GetID()))
+		}
+	}
+
+	return errs.ErrorOrNil()
+}
+
+func (as *attributeSubscriptions) getMessage(
+	subscriptionID entry.AttributeSubscriptionID, changeType posbus.AttributeChangeType, value *entry.AttributeValue,
+) *websocket.PreparedMessage {
+	return posbus.WSMessage(
+		&posbus.AttributeValueChanged{
+			PluginID:      subscriptionID.PluginID,
+			AttributeName: subscriptionID.Name,
+			ChangeType:    string(changeType),
+			Value:         (*posbus.StringAnyMap)(value),
+			TargetID:      subscriptionID.TargetID,
+		},
+	)
+}
+
+// getValue returns current value of the attribute and whether the user is allowed to read it.
+func (as *attributeSubscriptions) getValue(
+	attributeType entry.AttributeType, subscriptionID entry.AttributeSubscriptionID, userID umid.UMID,
+) (*entry.AttributeValue, bool, error) {
+	ctx := as.node.ctx
+
+	switch subscriptionID.TargetType {
+	case entry.ObjectAttributeSubscriptionTargetType:
+		object, ok := as.node.GetObjectFromAllObjects(subscriptionID.TargetID)
+		if !ok {
+			return nil, false, errors.Errorf("object not found: %s", subscriptionID.TargetID)
+		}
+		attributes := object.GetObjectAttributes()
+		allowed, err := auth.CheckAttributePermissions[entry.AttributeID](
+			ctx, attributeType, attributes, subscriptionID.AttributeID, userID, auth.ReadOperation,
+		)
+		if err != nil || !allowed {
+			return nil, false, err
+		}
+		value, _ := attributes.GetValue(subscriptionID.AttributeID)
+		return value, true, nil
+	case entry.UserAttributeSubscriptionTargetType:
+		userAttributeID := entry.NewUserAttributeID(subscriptionID.AttributeID, subscriptionID.TargetID)
+		attributes := as.node.GetUserAttributes()
+		allowed, err := auth.CheckAttributePermissions[entry.UserAttributeID](
+			ctx, attributeType, attributes, userAttributeID, userID, auth.ReadOperation,
+		)
+		if err != nil || !allowed {
+			return nil, false, err
+		}
+		value, _ := attributes.GetValue(userAttributeID)
+		return value, true, nil
+	case entry.ObjectUserAttributeSubscriptionTargetType:
+		objectUserAttributeID := entry.NewObjectUserAttributeID(
+			subscriptionID.AttributeID, subscriptionID.TargetID, subscriptionID.UserID,
+		)
+		attributes := as.node.GetObjectUserAttributes()
+		allowed, err := auth.CheckAttributePermissions[entry.ObjectUserAttributeID](
+			ctx, attributeType, attributes, objectUserAttributeID, userID, auth.ReadOperation,
+		)
+		if err != nil || !allowed {
+			return nil, false, err
+		}
+		value, _ := attributes.GetValue(objectUserAttributeID)
+		return value, true, nil
+	}
+
+	return nil, false, errors.Errorf("target type is not supported: %s", subscriptionID.TargetType)
+}
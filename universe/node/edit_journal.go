@@ -0,0 +1,627 @@
+package node
+
+import (
+	"bytes"
+	"encoding/json"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/modify"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	editJournalLimit = 100
+	// successive transforms of the same object are merged, so a drag is undone at once
+	editJournalMergeInterval = 2 * time.Second
+	// journals of users not editing the world for this long are dropped
+	editJournalIdleTTL         = time.Hour
+	editJournalCleanupInterval = 10 * time.Minute
+)
+
+var (
+	errEditConflict      = errors.New("edit conflicts with changes of other users")
+	errEditNotPermitted  = errors.New("edit is not permitted")
+	errNothingToUndo     = errors.New("nothing to undo")
+	errNothingToRedo     = errors.New("nothing to redo")
+	editJournalNullState = json.RawMessage("null")
+)
+
+var _ universe.EditJournal = (*editJournal)(nil)
+
+type editJournalKey struct {
+	userID  umid.UMID
+	worldID umid.UMID
+}
+
+type editJournalStacks struct {
+	undo []*entry.EditTransaction
+	redo []*entry.EditTransaction
+	// transaction between Begin and Commit
+	open *entry.EditTransaction
+	// last time the journal was used, idle journals are dropped
+	usedAt time.Time
+}
+
+// editObjectProperties is the state of the EditTargetProperties target.
+type editObjectProperties struct {
+	Name      string     `json:"name"`
+	Asset2dID *umid.UMID `json:"asset_2d_id"`
+	Asset3dID *umid.UMID `json:"asset_3d_id"`
+}
+
+type editJournal struct {
+	node     *Node
+	mu       sync.Mutex
+	journals map[editJournalKey]*editJournalStacks
+}
+
+func newEditJournal(node *Node) *editJournal {
+	return &editJournal{
+		node:     node,
+		journals: make(map[editJournalKey]*editJournalStacks),
+	}
+}
+
+// run drops journals idle for longer than editJournalIdleTTL.
+func (ej *editJournal) run() {
+	ticker := time.NewTicker(editJournalCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ej.node.ctx.Done():
+			return
+		case <-ticker.C:
+			ej.mu.Lock()
+			for key, stacks := range ej.journals {
+				if time.Since(stacks.usedAt) > editJournalIdleTTL {
+					delete(ej.journals, key)
+				}
+			}
+			ej.mu.Unlock()
+		}
+	}
+}
+
+func (ej *editJournal) Snapshot(target entry.EditTarget) (*entry.EditChange, error) {
+	state, err := ej.getState(target)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get state")
+	}
+
+	return &entry.EditChange{
+		Target: target,
+		Before: state,
+	}, nil
+}
+
+func (ej *editJournal) Record(userID umid.UMID, worldID umid.UMID, change *entry.EditChange) error {
+	state, err := ej.getState(change.Target)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get state")
+	}
+	change.After = state
+	if isSameEditState(change.Target.Kind, change.Before, change.After) {
+		return nil
+	}
+
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	stacks := ej.getStacks(userID, worldID)
+	stacks.redo = nil
+
+	now := time.Now()
+	switch {
+	case stacks.open != nil:
+		mergeEditChange(stacks.open, change)
+		stacks.open.UpdatedAt = now
+	case ej.canMergeWithLast(stacks, change, now):
+		last := stacks.undo[len(stacks.undo)-1]
+		mergeEditChange(last, change)
+		last.UpdatedAt = now
+		if len(last.Changes) == 0 {
+			stacks.undo = stacks.undo[:len(stacks.undo)-1]
+		}
+	default:
+		stacks.undo = append(stacks.undo, &entry.EditTransaction{
+			TransactionID: umid.New(),
+			UserID:        userID,
+			WorldID:       worldID,
+			Changes:       []*entry.EditChange{change},
+			CreatedAt:     now,
+			UpdatedAt:     now,
+		})
+		if len(stacks.undo) > editJournalLimit {
+			stacks.undo = stacks.undo[len(stacks.undo)-editJournalLimit:]
+		}
+	}
+
+	ej.notify(userID, worldID, stacks, nil)
+
+	return nil
+}
+
+func (ej *editJournal) Begin(userID umid.UMID, worldID umid.UMID) {
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	stacks := ej.getStacks(userID, worldID)
+	ej.commit(stacks)
+
+	now := time.Now()
+	stacks.open = &entry.EditTransaction{
+		TransactionID: umid.New(),
+		UserID:        userID,
+		WorldID:       worldID,
+		CreatedAt:     now,
+		UpdatedAt:     now,
+	}
+}
+
+func (ej *editJournal) Commit(userID umid.UMID, worldID umid.UMID) {
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	stacks, ok := ej.findStacks(userID, worldID)
+	if !ok || stacks.open == nil {
+		return
+	}
+	ej.commit(stacks)
+
+	ej.notify(userID, worldID, stacks, nil)
+}
+
+func (ej *editJournal) Undo(userID umid.UMID, worldID umid.UMID) (
+	*entry.EditTransaction, []*entry.EditChange, error,
+) {
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	stacks, ok := ej.findStacks(userID, worldID)
+	if ok {
+		ej.commit(stacks)
+	}
+	if !ok || len(stacks.undo) == 0 {
+		return nil, nil, errNothingToUndo
+	}
+
+	transaction := stacks.undo[len(stacks.undo)-1]
+	if conflicts, err := ej.apply(userID, transaction, true); err != nil {
+		if errors.Is(err, errEditConflict) {
+			ej.notify(userID, worldID, stacks, conflicts)
+		}
+		return nil, conflicts, err
+	}
+
+	stacks.undo = stacks.undo[:len(stacks.undo)-1]
+	stacks.redo = append(stacks.redo, transaction)
+
+	ej.notify(userID, worldID, stacks, nil)
+
+	return transaction, nil, nil
+}
+
+func (ej *editJournal) Redo(userID umid.UMID, worldID umid.UMID) (
+	*entry.EditTransaction, []*entry.EditChange, error,
+) {
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	stacks, ok := ej.findStacks(userID, worldID)
+	if ok {
+		ej.commit(stacks)
+	}
+	if !ok || len(stacks.redo) == 0 {
+		return nil, nil, errNothingToRedo
+	}
+
+	transaction := stacks.redo[len(stacks.redo)-1]
+	if conflicts, err := ej.apply(userID, transaction, false); err != nil {
+		if errors.Is(err, errEditConflict) {
+			ej.notify(userID, worldID, stacks, conflicts)
+		}
+		return nil, conflicts, err
+	}
+
+	stacks.redo = stacks.redo[:len(stacks.redo)-1]
+	stacks.undo = append(stacks.undo, transaction)
+
+	ej.notify(userID, worldID, stacks, nil)
+
+	return transaction, nil, nil
+}
+
+func (ej *editJournal) GetJournal(userID umid.UMID, worldID umid.UMID) (
+	[]*entry.EditTransaction, []*entry.EditTransaction,
+) {
+	ej.mu.Lock()
+	defer ej.mu.Unlock()
+
+	stacks, ok := ej.findStacks(userID, worldID)
+	if !ok {
+		return []*entry.EditTransaction{}, []*entry.EditTransaction{}
+	}
+
+	reversed := func(transactions []*entry.EditTransaction) []*entry.EditTransaction {
+		res := make([]*entry.EditTransaction, len(transactions))
+		for i := range transactions {
+			res[len(transactions)-1-i] = transactions[i]
+		}
+		return res
+	}
+
+	undo := stacks.undo
+	if stacks.open != nil && len(stacks.open.Changes) > 0 {
+		undo = append(undo[:len(undo):len(undo)], stacks.open)
+	}
+
+	return reversed(undo), reversed(stacks.redo)
+}
+
+func (ej *editJournal) getStacks(userID umid.UMID, worldID umid.UMID) *editJournalStacks {
+	stacks, ok := ej.findStacks(userID, worldID)
+	if !ok {
+		stacks = &editJournalStacks{usedAt: time.Now()}
+		ej.journals[editJournalKey{userID: userID, worldID: worldID}] = stacks
+	}
+	return stacks
+}
+
+func (ej *editJournal) findStacks(userID umid.UMID, worldID umid.UMID) (*editJournalStacks, bool) {
+	stacks, ok := ej.journals[editJournalKey{userID: userID, worldID: worldID}]
+	if ok {
+		stacks.usedAt = time.Now()
+	}
+	return stacks, ok
+}
+
+// commit moves the open transaction to the undo stack, empty transactions are dropped.
+func (ej *editJournal) commit(stacks *editJournalStacks) {
+	if stacks.open == nil {
+		return
+	}
+
+	if len(stacks.open.Changes) > 0 {
+		stacks.undo = append(stacks.undo, stacks.open)
+		if len(stacks.undo) > editJournalLimit {
+			stacks.undo = stacks.undo[len(stacks.undo)-editJournalLimit:]
+		}
+	}
+	stacks.open = nil
+}
+
+func (ej *editJournal) canMergeWithLast(stacks *editJournalStacks, change *entry.EditChange, now time.Time) bool {
+	if change.Target.Kind != entry.EditTargetTransform || len(stacks.undo) == 0 {
+		return false
+	}
+
+	last := stacks.undo[len(stacks.undo)-1]
+	if len(last.Changes) != 1 || now.Sub(last.UpdatedAt) > editJournalMergeInterval {
+		return false
+	}
+
+	return isSameEditTarget(last.Changes[0].Target, change.Target)
+}
+
+// apply sets the transaction targets to their states before (undo) or after (redo) the transaction.
+// Nothing is applied if any target doesn't have the expected state, the conflicting changes are returned.
+// Targets already set are set back if setting a target fails, so the transaction stays in its stack.
+func (ej *editJournal) apply(userID umid.UMID, transaction *entry.EditTransaction, undo bool) (
+	[]*entry.EditChange, error,
+) {
+	var conflicts []*entry.EditChange
+	for _, change := range transaction.Changes {
+		expected := change.Before
+		if undo {
+			expected = change.After
+		}
+
+		current, err := ej.getState(change.Target)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to get state: %+v", change.Target)
+		}
+		if !isSameEditState(change.Target.Kind, current, expected) {
+			conflicts = append(conflicts, change)
+			continue
+		}
+
+		permitted, err := ej.checkPermission(userID, change)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to check permission: %+v", change.Target)
+		}
+		if !permitted {
+			return nil, errors.Wrapf(errEditNotPermitted, "target: %+v", change.Target)
+		}
+	}
+	if len(conflicts) > 0 {
+		return conflicts, errors.Wrapf(errEditConflict, "%d conflicting changes", len(conflicts))
+	}
+
+	applied := make([]*entry.EditChange, 0, len(transaction.Changes))
+	for i := range transaction.Changes {
+		change := transaction.Changes[i]
+		state := change.After
+		if undo {
+			// reverse order, so objects are restored before their attributes
+			change = transaction.Changes[len(transaction.Changes)-1-i]
+			state = change.Before
+		}
+
+		if err := ej.setState(userID, change.Target, state); err != nil {
+			err := errors.WithMessagef(err, "failed to set state: %+v", change.Target)
+			if rollbackErr := ej.rollback(userID, applied, undo); rollbackErr != nil {
+				return nil, multierror.Append(err, rollbackErr)
+			}
+			return nil, err
+		}
+		applied = append(applied, change)
+	}
+
+	return nil, nil
+}
+
+// rollback sets the applied changes back in reverse order.
+func (ej *editJournal) rollback(userID umid.UMID, applied []*entry.EditChange, undo bool) error {
+	var errs *multierror.Error
+	for i := len(applied) - 1; i >= 0; i-- {
+		change := applied[i]
+		state := change.Before
+		if undo {
+			state = change.After
+		}
+
+		if err := ej.setState(userID, change.Target, state); err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to roll back state: %+v", change.Target))
+		}
+	}
+	return errs.ErrorOrNil()
+}
+
+// checkPermission checks the user is an admin of the target object or of the parent to restore it in.
+func (ej *editJournal) checkPermission(userID umid.UMID, change *entry.EditChange) (bool, error) {
+	objectID := change.Target.ObjectID
+	if _, ok := ej.node.GetObjectFromAllObjects(objectID); !ok {
+		state := change.Before
+		if isNullEditState(state) {
+			state = change.After
+		}
+
+		var objectTemplate tree.ObjectTemplate
+		if err := json.Unmarshal(state, &objectTemplate); err != nil {
+			return false, errors.WithMessage(err, "failed to unmarshal object template")
+		}
+		objectID = objectTemplate.ParentID
+	}
+
+	return ej.node.db.GetUserObjectsDB().CheckIsIndirectAdminByID(
+		ej.node.ctx, entry.NewUserObjectID(userID, objectID),
+	)
+}
+
+// getState returns the json encoded state of the target, "null" if the object or attribute doesn't exist.
+func (ej *editJournal) getState(target entry.EditTarget) (json.RawMessage, error) {
+	if target.Kind == entry.EditTargetAttribute && target.AttributeID == nil {
+		return nil, errors.New("attribute umid is required")
+	}
+
+	object, ok := ej.node.GetObjectFromAllObjects(target.ObjectID)
+	if !ok {
+		return editJournalNullState, nil
+	}
+
+	var state any
+	switch target.Kind {
+	case entry.EditTargetObject:
+		state = tree.ObjectToTemplate(object)
+	case entry.EditTargetProperties:
+		properties := editObjectProperties{
+			Name: object.GetName(),
+		}
+		if asset2d := object.GetAsset2D(); asset2d != nil {
+			properties.Asset2dID = utils.GetPTR(asset2d.GetID())
+		}
+		if asset3d := object.GetAsset3D(); asset3d != nil {
+			properties.Asset3dID = utils.GetPTR(asset3d.GetID())
+		}
+		state = properties
+	case entry.EditTargetTransform:
+		state = object.GetTransform()
+	case entry.EditTargetAttribute:
+		if payload, ok := object.GetObjectAttributes().GetPayload(*target.AttributeID); ok {
+			state = payload
+		}
+	default:
+		return nil, errors.Errorf("unknown target kind: %s", target.Kind)
+	}
+
+	return json.Marshal(state)
+}
+
+func (ej *editJournal) setState(userID umid.UMID, target entry.EditTarget, state json.RawMessage) error {
+	object, ok := ej.node.GetObjectFromAllObjects(target.ObjectID)
+
+	if target.Kind == entry.EditTargetObject {
+		if isNullEditState(state) {
+			if !ok {
+				return nil
+			}
+			if _, err := tree.TrashObject(
+				ej.node.ctx, ej.node.db, object, userID, ej.node.cfg.Common.TrashRetention,
+			); err != nil {
+				return errors.WithMessage(err, "failed to trash object")
+			}
+			return nil
+		}
+		if ok {
+			return nil
+		}
+
+		// removed objects are in the trash, restoring keeps their activities
+		item, err := ej.node.db.GetObjectTrashDB().GetObjectTrashItemByID(ej.node.ctx, target.ObjectID)
+		if err == nil {
+			if _, err := tree.RestoreTrashItem(ej.node.ctx, ej.node.db, item); err != nil {
+				return errors.WithMessage(err, "failed to restore trash item")
+			}
+			return nil
+		}
+		if !errors.Is(err, pgx.ErrNoRows) {
+			return errors.WithMessage(err, "failed to get object trash item")
+		}
+
+		var objectTemplate tree.ObjectTemplate
+		if err := json.Unmarshal(state, &objectTemplate); err != nil {
+			return errors.WithMessage(err, "failed to unmarshal object template")
+		}
+		if _, err := tree.AddObjectFromTemplate(&objectTemplate, true); err != nil {
+			return errors.WithMessage(err, "failed to add object from template")
+		}
+		return nil
+	}
+
+	if !ok {
+		return errors.Errorf("object not found: %s", target.ObjectID)
+	}
+
+	switch target.Kind {
+	case entry.EditTargetProperties:
+		var properties editObjectProperties
+		if err := json.Unmarshal(state, &properties); err != nil {
+			return errors.WithMessage(err, "failed to unmarshal properties")
+		}
+		return ej.setObjectProperties(object, &properties)
+	case entry.EditTargetTransform:
+		var transform *cmath.Transform
+		if err := json.Unmarshal(state, &transform); err != nil {
+			return errors.WithMessage(err, "failed to unmarshal transform")
+		}
+		return object.SetTransform(transform, true)
+	case entry.EditTargetAttribute:
+		if isNullEditState(state) {
+			if _, err := object.GetObjectAttributes().RemoveByUser(userID, *target.AttributeID, true); err != nil {
+				return errors.WithMessage(err, "failed to remove attribute")
+			}
+			return nil
+		}
+
+		var payload entry.AttributePayload
+		if err := json.Unmarshal(state, &payload); err != nil {
+			return errors.WithMessage(err, "failed to unmarshal attribute payload")
+		}
+		if _, err := object.GetObjectAttributes().UpsertByUser(
+			userID, *target.AttributeID, modify.ReplaceWith(&payload), true,
+		); err != nil {
+			return errors.WithMessage(err, "failed to upsert attribute")
+		}
+		return nil
+	}
+
+	return errors.Errorf("unknown target kind: %s", target.Kind)
+}
+
+func (ej *editJournal) setObjectProperties(object universe.Object, properties *editObjectProperties) error {
+	var asset2d universe.Asset2d
+	if properties.Asset2dID != nil {
+		var ok bool
+		asset2d, ok = ej.node.GetAssets2d().GetAsset2d(*properties.Asset2dID)
+		if !ok {
+			return errors.Errorf("asset 2d not found: %s", properties.Asset2dID)
+		}
+	}
+	var asset3d universe.Asset3d
+	if properties.Asset3dID != nil {
+		var ok bool
+		asset3d, ok = ej.node.GetAssets3d().GetAsset3d(*properties.Asset3dID)
+		if !ok {
+			return errors.Errorf("asset 3d not found: %s", properties.Asset3dID)
+		}
+	}
+
+	if err := object.SetAsset2D(asset2d, true); err != nil {
+		return errors.WithMessage(err, "failed to set asset 2d")
+	}
+	if err := object.SetAsset3D(asset3d, true); err != nil {
+		return errors.WithMessage(err, "failed to set asset 3d")
+	}
+	if err := object.SetName(properties.Name, true); err != nil {
+		return errors.WithMessage(err, "failed to set name")
+	}
+
+	return object.Update(false)
+}
+
+// notify sends the journal state to the user if online, conflicts are objects which prevented undo or redo.
+func (ej *editJournal) notify(
+	userID umid.UMID, worldID umid.UMID, stacks *editJournalStacks, conflicts []*entry.EditChange,
+) {
+	user, ok := ej.node.userRelations.GetOnlineUser(userID)
+	if !ok {
+		return
+	}
+
+	undoCount := len(stacks.undo)
+	if stacks.open != nil && len(stacks.open.Changes) > 0 {
+		undoCount++
+	}
+	msg := &posbus.EditJournalState{
+		WorldID:   worldID,
+		UndoCount: uint32(undoCount),
+		RedoCount: uint32(len(stacks.redo)),
+		Conflicts: make([]umid.UMID, 0, len(conflicts)),
+	}
+	for _, conflict := range conflicts {
+		msg.Conflicts = append(msg.Conflicts, conflict.Target.ObjectID)
+	}
+
+	user.Send(posbus.WSMessage(msg))
+}
+
+// mergeEditChange adds the change to the transaction, keeping the first state of the same target.
+func mergeEditChange(transaction *entry.EditTransaction, change *entry.EditChange) {
+	for i, existing := range transaction.Changes {
+		if !isSameEditTarget(existing.Target, change.Target) {
+			continue
+		}
+
+		existing.After = change.After
+		if isSameEditState(existing.Target.Kind, existing.Before, existing.After) {
+			transaction.Changes = append(transaction.Changes[:i], transaction.Changes[i+1:]...)
+		}
+		return
+	}
+
+	transaction.Changes = append(transaction.Changes, change)
+}
+
+func isSameEditTarget(a, b entry.EditTarget) bool {
+	if a.ObjectID != b.ObjectID || a.Kind != b.Kind {
+		return false
+	}
+	if a.AttributeID == nil || b.AttributeID == nil {
+		return a.AttributeID == b.AttributeID
+	}
+	return *a.AttributeID == *b.AttributeID
+}
+
+// isSameEditState compares states, objects are compared by existence only as their parts have own targets.
+func isSameEditState(kind entry.EditTargetKind, a, b json.RawMessage) bool {
+	if kind == entry.EditTargetObject {
+		return isNullEditState(a) == isNullEditState(b)
+	}
+	return bytes.Equal(a, b)
+}
+
+func isNullEditState(state json.RawMessage) bool {
+	return len(state) == 0 || bytes.Equal(state, editJournalNullState)
+}
@@ -0,0 +1,339 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var (
+	errUserBanned     = errors.New("user is banned")
+	errBanNotFound    = errors.New("ban not found")
+	errMuteNotFound   = errors.New("mute not found")
+	errReportNotFound = errors.New("report not found")
+)
+
+var _ universe.Moderation = (*moderation)(nil)
+
+type moderation struct {
+	node *Node
+	mu   sync.RWMutex
+	// active bans and mutes, checked on every join, chat and high-five
+	bans  map[umid.UMID]*entry.UserBan
+	mutes map[umid.UMID]*entry.UserMute
+}
+
+func newModeration(node *Node) *moderation {
+	return &moderation{
+		node:  node,
+		bans:  make(map[umid.UMID]*entry.UserBan),
+		mutes: make(map[umid.UMID]*entry.UserMute),
+	}
+}
+
+func (m *moderation) Load() error {
+	bans, err := m.node.db.GetModerationDB().GetActiveUserBans(m.node.ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get active bans")
+	}
+	mutes, err := m.node.db.GetModerationDB().GetActiveUserMutes(m.node.ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get active mutes")
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, ban := range bans {
+		m.bans[ban.BanID] = ban
+	}
+	for _, mute := range mutes {
+		m.mutes[mute.MuteID] = mute
+	}
+
+	return nil
+}
+
+func (m *moderation) Ban(
+	userID umid.UMID, worldID *umid.UMID, reason string, createdBy umid.UMID, expiresAt *time.Time,
+) (*entry.UserBan, error) {
+	ban := &entry.UserBan{
+		BanID:     umid.New(),
+		UserID:    userID,
+		WorldID:   worldID,
+		Reason:    reason,
+		CreatedBy: &createdBy,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := m.node.db.GetModerationDB().InsertUserBan(m.node.ctx, ban); err != nil {
+		return nil, errors.WithMessage(err, "failed to insert ban")
+	}
+
+	m.mu.Lock()
+	m.bans[ban.BanID] = ban
+	m.mu.Unlock()
+
+	for _, world := range m.getUserWorlds(userID, worldID) {
+		if _, err := m.kick(world, userID, posbus.SignalBanned); err != nil {
+			m.node.log.Warn(errors.WithMessagef(err, "Moderation: failed to kick banned user: %s", userID))
+		}
+	}
+	if worldID == nil {
+		// node-wide ban, no more tokens and connections
+		if err := m.node.userSessions.RevokeAll(userID); err != nil {
+			return ban, errors.WithMessage(err, "failed to revoke user sessions")
+		}
+	}
+
+	return ban, nil
+}
+
+func (m *moderation) Kick(world universe.World, userID umid.UMID) (bool, error) {
+	return m.kick(world, userID, posbus.SignalKicked)
+}
+
+// kick removes the user from the world and notifies the client with the signal.
+// The user stays connected and can teleport to another world.
+func (m *moderation) kick(world universe.World, userID umid.UMID, signal posbus.SignalType) (bool, error) {
+	user, ok := world.GetUser(userID, false)
+	if !ok {
+		return false, nil
+	}
+
+	removed, err := world.RemoveUser(user, true)
+	if err != nil {
+		return false, errors.WithMessagef(err, "failed to remove user from world: %s", world.GetID())
+	}
+	if err := user.Send(posbus.WSMessage(&posbus.Signal{Value: signal})); err != nil {
+		return removed, errors.WithMessage(err, "failed to send signal")
+	}
+
+	return removed, nil
+}
+
+func (m *moderation) Unban(banID umid.UMID) error {
+	removed, err := m.node.db.GetModerationDB().RemoveUserBanByID(m.node.ctx, banID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to remove ban")
+	}
+
+	m.mu.Lock()
+	delete(m.bans, banID)
+	m.mu.Unlock()
+
+	if !removed {
+		return errors.Wrapf(errBanNotFound, "ban: %s", banID)
+	}
+	return nil
+}
+
+func (m *moderation) GetBans(worldID *umid.UMID) []*entry.UserBan {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	bans := make([]*entry.UserBan, 0, len(m.bans))
+	for _, ban := range m.bans {
+		if !ban.IsActive() || !sameWorld(ban.WorldID, worldID) {
+			continue
+		}
+		bans = append(bans, ban)
+	}
+	return bans
+}
+
+func (m *moderation) GetBan(userID umid.UMID, worldID *umid.UMID) (*entry.UserBan, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, ban := range m.bans {
+		if ban.UserID == userID && ban.IsActive() && ban.Applies(worldID) {
+			return ban, true
+		}
+	}
+	return nil, false
+}
+
+func (m *moderation) Mute(
+	userID umid.UMID, worldID *umid.UMID, reason string, createdBy umid.UMID, expiresAt *time.Time,
+) (*entry.UserMute, error) {
+	mute := &entry.UserMute{
+		MuteID:    umid.New(),
+		UserID:    userID,
+		WorldID:   worldID,
+		Reason:    reason,
+		CreatedBy: &createdBy,
+		ExpiresAt: expiresAt,
+		CreatedAt: time.Now(),
+	}
+	if err := m.node.db.GetModerationDB().InsertUserMute(m.node.ctx, mute); err != nil {
+		return nil, errors.WithMessage(err, "failed to insert mute")
+	}
+
+	m.mu.Lock()
+	m.mutes[mute.MuteID] = mute
+	m.mu.Unlock()
+
+	for _, world := range m.getUserWorlds(userID, worldID) {
+		user, ok := world.GetUser(userID, false)
+		if !ok {
+			continue
+		}
+		if err := m.node.chatService.RemoveObjectMember(m.node.ctx, world.ToObject(), user); err != nil {
+			m.node.log.Warn(errors.WithMessagef(err, "Moderation: failed to remove muted user from chat: %s", userID))
+		}
+		user.Send(posbus.WSMessage(&posbus.Signal{Value: posbus.SignalMuted}))
+	}
+
+	return mute, nil
+}
+
+func (m *moderation) Unmute(muteID umid.UMID) error {
+	removed, err := m.node.db.GetModerationDB().RemoveUserMuteByID(m.node.ctx, muteID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to remove mute")
+	}
+
+	m.mu.Lock()
+	delete(m.mutes, muteID)
+	m.mu.Unlock()
+
+	if !removed {
+		return errors.Wrapf(errMuteNotFound, "mute: %s", muteID)
+	}
+	return nil
+}
+
+func (m *moderation) GetMutes(worldID *umid.UMID) []*entry.UserMute {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	mutes := make([]*entry.UserMute, 0, len(m.mutes))
+	for _, mute := range m.mutes {
+		if !mute.IsActive() || !sameWorld(mute.WorldID, worldID) {
+			continue
+		}
+		mutes = append(mutes, mute)
+	}
+	return mutes
+}
+
+func (m *moderation) GetMute(userID umid.UMID, worldID *umid.UMID) (*entry.UserMute, bool) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	for _, mute := range m.mutes {
+		if mute.UserID == userID && mute.IsActive() && mute.Applies(worldID) {
+			return mute, true
+		}
+	}
+	return nil, false
+}
+
+func (m *moderation) Report(
+	reporterID, targetUserID umid.UMID, worldID *umid.UMID, reason string,
+) (*entry.UserReport, error) {
+	now := time.Now()
+	report := &entry.UserReport{
+		ReportID:     umid.New(),
+		ReporterID:   &reporterID,
+		TargetUserID: targetUserID,
+		WorldID:      worldID,
+		Reason:       reason,
+		Status:       entry.UserReportStatusOpen,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := m.node.db.GetModerationDB().InsertUserReport(m.node.ctx, report); err != nil {
+		return nil, errors.WithMessage(err, "failed to insert report")
+	}
+
+	return report, nil
+}
+
+func (m *moderation) GetReports(status entry.UserReportStatus, limit uint) ([]*entry.UserReport, error) {
+	reports, err := m.node.db.GetModerationDB().GetUserReportsByStatus(m.node.ctx, status, limit)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get reports")
+	}
+	return reports, nil
+}
+
+func (m *moderation) ResolveReport(
+	reportID umid.UMID, status entry.UserReportStatus, resolvedBy umid.UMID, resolution string,
+) (*entry.UserReport, error) {
+	updated, err := m.node.db.GetModerationDB().UpdateUserReportStatus(
+		m.node.ctx, reportID, status, &resolvedBy, resolution,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to update report status")
+	}
+	if !updated {
+		return nil, errors.Wrapf(errReportNotFound, "report: %s", reportID)
+	}
+
+	report, err := m.node.db.GetModerationDB().GetUserReportByID(m.node.ctx, reportID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, errors.Wrapf(errReportNotFound, "report: %s", reportID)
+		}
+		return nil, errors.WithMessage(err, "failed to get report")
+	}
+
+	return report, nil
+}
+
+// getUserWorlds returns the loaded worlds the user is currently in, limited to the given world if any.
+func (m *moderation) getUserWorlds(userID umid.UMID, worldID *umid.UMID) []universe.World {
+	var worlds []universe.World
+	for _, world := range m.node.GetWorlds().GetWorlds() {
+		if worldID != nil && world.GetID() != *worldID {
+			continue
+		}
+		if _, ok := world.GetUser(userID, false); ok {
+			worlds = append(worlds, world)
+		}
+	}
+	return worlds
+}
+
+// run periodically drops expired bans and mutes from the cache.
+func (m *moderation) run() {
+	ticker := time.NewTicker(userSessionsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-m.node.ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			for banID, ban := range m.bans {
+				if !ban.IsActive() {
+					delete(m.bans, banID)
+				}
+			}
+			for muteID, mute := range m.mutes {
+				if !mute.IsActive() {
+					delete(m.mutes, muteID)
+				}
+			}
+			m.mu.Unlock()
+		}
+	}
+}
+
+// sameWorld compares optional world ids, nil means node-wide.
+func sameWorld(a, b *umid.UMID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
@@ -23,6 +23,8 @@ import (
 	"github.com/momentum-xyz/ubercontroller/database"
 	"github.com/momentum-xyz/ubercontroller/mplugin"
 	"github.com/momentum-xyz/ubercontroller/pkg/media"
+	"github.com/momentum-xyz/ubercontroller/pkg/oidc"
+	"github.com/momentum-xyz/ubercontroller/pkg/siwe"
 	"github.com/momentum-xyz/ubercontroller/seed"
 	"github.com/momentum-xyz/ubercontroller/types"
 	"github.com/momentum-xyz/ubercontroller/types/generic"
@@ -56,7 +58,16 @@ type Node struct {
 	attributeTypes universe.AttributeTypes
 	plugins        universe.Plugins
 
-	userObjects *userObjects
+	userObjects            *userObjects
+	attributeSubscriptions *attributeSubscriptions
+	userSessions           *userSessions
+	apiKeys                *apiKeys
+	moderation             *moderation
+	userRelations          *userRelations
+	editJournal            *editJournal
+	authChallenges         *siwe.NonceStore
+	oidcProviders          map[string]*oidc.Provider
+	oidcRequests           *oidc.AuthRequestStore
 
 	nodeAttributes       *nodeAttributes // WARNING: the Node is sharing the same mutex ("Mu") with it
 	userAttributes       *userAttributes
@@ -101,6 +112,14 @@ func NewNode(
 		objectIDToWorld: generic.NewSyncMap[umid.UMID, universe.World](0),
 	}
 	node.userObjects = newUserObjects(node)
+	node.attributeSubscriptions = newAttributeSubscriptions(node)
+	node.userSessions = newUserSessions(node)
+	node.apiKeys = newAPIKeys(node)
+	node.moderation = newModeration(node)
+	node.userRelations = newUserRelations(node)
+	node.editJournal = newEditJournal(node)
+	node.authChallenges = siwe.NewNonceStore()
+	node.oidcRequests = oidc.NewAuthRequestStore()
 	node.nodeAttributes = newNodeAttributes(node)
 	node.userAttributes = newUserAttributes(node)
 	node.userUserAttributes = newUserUserAttributes(node)
@@ -117,6 +136,11 @@ func (n *Node) Initialize(ctx types.NodeContext) error {
 	n.log = ctx.Logger()
 	n.cfg = ctx.Config()
 
+	n.oidcProviders = make(map[string]*oidc.Provider, len(n.cfg.Auth.OIDCProviders))
+	for name, providerCfg := range n.cfg.Auth.OIDCProviders {
+		n.oidcProviders[name] = oidc.NewProvider(name, providerCfg)
+	}
+
 	consoleWriter := zapcore.Lock(os.Stdout)
 	gin.DefaultWriter = consoleWriter
 
@@ -181,6 +205,30 @@ func (n *Node) GetUserObjects() universe.UserObjects {
 	return n.userObjects
 }
 
+func (n *Node) GetAttributeSubscriptions() universe.AttributeSubscriptions {
+	return n.attributeSubscriptions
+}
+
+func (n *Node) GetUserSessions() universe.UserSessions {
+	return n.userSessions
+}
+
+func (n *Node) GetAPIKeys() universe.APIKeys {
+	return n.apiKeys
+}
+
+func (n *Node) GetModeration() universe.Moderation {
+	return n.moderation
+}
+
+func (n *Node) GetUserRelations() universe.UserRelations {
+	return n.userRelations
+}
+
+func (n *Node) GetEditJournal() universe.EditJournal {
+	return n.editJournal
+}
+
 func (n *Node) GetNodeAttributes() universe.NodeAttributes {
 	return n.nodeAttributes
 }
@@ -276,6 +324,12 @@ func (n *Node) Run() error {
 	}
 	n.SetEnabled(true)
 
+	go n.userSessions.run()
+	go n.apiKeys.run()
+	go n.moderation.run()
+	go n.editJournal.run()
+	go n.runObjectTrashPurge()
+
 	//harvester.Initialise(ctx, log, cfg, pool)
 	//if cfg.Arbitrum.ArbitrumMOMTokenAddress != "" {
 	//	arbitrumAdapter := arbitrum_nova_adapter.NewArbitrumNovaAdapter(cfg)
@@ -360,6 +414,7 @@ func (n *Node) Load() error {
 			group.Go(n.GetObjectAttributes().Load)
 			group.Go(n.GetObjectTypes().Load)
 			group.Go(n.GetActivities().Load)
+			group.Go(n.moderation.Load)
 			if err := group.Wait(); err != nil {
 				return errors.WithMessage(err, "failed to load additional data")
 			}
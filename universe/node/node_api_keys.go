@@ -0,0 +1,200 @@
+package node
+
+import (
+	"crypto/subtle"
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const apiKeysAuditQueueSize = 1024
+
+var errInvalidAPIKey = errors.New("invalid api key")
+
+var _ universe.APIKeys = (*apiKeys)(nil)
+
+type apiKeys struct {
+	node *Node
+	mu   sync.RWMutex
+	// cache of keys checked on every request, revoked keys are updated in place
+	keys  map[umid.UMID]*entry.APIKey
+	audit chan *entry.APIKeyAuditEntry
+}
+
+func newAPIKeys(node *Node) *apiKeys {
+	return &apiKeys{
+		node:  node,
+		keys:  make(map[umid.UMID]*entry.APIKey),
+		audit: make(chan *entry.APIKeyAuditEntry, apiKeysAuditQueueSize),
+	}
+}
+
+func (ak *apiKeys) Create(
+	name string, scopes []string, createdBy umid.UMID, expiresAt time.Time,
+) (*entry.APIKey, string, error) {
+	if _, err := auth.ParseScopes(scopes); err != nil {
+		return nil, "", errors.WithMessage(err, "invalid scopes")
+	}
+
+	apiKeyID := umid.New()
+	key, keyHash, err := api.GenerateAPIKey(apiKeyID)
+	if err != nil {
+		return nil, "", errors.WithMessage(err, "failed to generate api key")
+	}
+
+	now := time.Now()
+	apiKey := &entry.APIKey{
+		APIKeyID:  apiKeyID,
+		Name:      name,
+		KeyHash:   keyHash,
+		Scopes:    scopes,
+		CreatedBy: createdBy,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if err := ak.node.db.GetAPIKeysDB().InsertAPIKey(ak.node.ctx, apiKey); err != nil {
+		return nil, "", errors.WithMessage(err, "failed to insert api key")
+	}
+
+	ak.mu.Lock()
+	ak.keys[apiKeyID] = apiKey
+	ak.mu.Unlock()
+
+	return apiKey, key, nil
+}
+
+func (ak *apiKeys) Verify(key string) (*entry.APIKey, error) {
+	apiKeyID, err := api.ParseAPIKey(key)
+	if err != nil {
+		return nil, errors.Wrap(errInvalidAPIKey, err.Error())
+	}
+
+	ak.mu.RLock()
+	apiKey, ok := ak.keys[apiKeyID]
+	ak.mu.RUnlock()
+
+	if !ok {
+		apiKey, err = ak.node.db.GetAPIKeysDB().GetAPIKeyByID(ak.node.ctx, apiKeyID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return nil, errors.Wrapf(errInvalidAPIKey, "api key not found: %s", apiKeyID)
+			}
+			return nil, errors.WithMessage(err, "failed to get api key")
+		}
+
+		ak.mu.Lock()
+		ak.keys[apiKeyID] = apiKey
+		ak.mu.Unlock()
+	}
+
+	if subtle.ConstantTimeCompare([]byte(api.HashAPIKey(key)), []byte(apiKey.KeyHash)) != 1 {
+		return nil, errors.Wrapf(errInvalidAPIKey, "api key hash mismatch: %s", apiKeyID)
+	}
+	if !apiKey.IsActive() {
+		return nil, errors.Wrapf(errInvalidAPIKey, "api key is not active: %s", apiKeyID)
+	}
+
+	return apiKey, nil
+}
+
+func (ak *apiKeys) GetAll() ([]*entry.APIKey, error) {
+	keys, err := ak.node.db.GetAPIKeysDB().GetAPIKeys(ak.node.ctx)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get api keys")
+	}
+	return keys, nil
+}
+
+func (ak *apiKeys) Revoke(apiKeyID umid.UMID) error {
+	revoked, err := ak.node.db.GetAPIKeysDB().RevokeAPIKey(ak.node.ctx, apiKeyID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to revoke api key")
+	}
+	if !revoked {
+		return errors.Wrapf(errInvalidAPIKey, "api key not found or already revoked: %s", apiKeyID)
+	}
+
+	now := time.Now()
+	ak.mu.Lock()
+	if apiKey, ok := ak.keys[apiKeyID]; ok {
+		revokedKey := *apiKey
+		revokedKey.RevokedAt = &now
+		ak.keys[apiKeyID] = &revokedKey
+	}
+	ak.mu.Unlock()
+
+	return nil
+}
+
+func (ak *apiKeys) GetAuditEntries(apiKeyID umid.UMID, limit uint) ([]*entry.APIKeyAuditEntry, error) {
+	auditEntries, err := ak.node.db.GetAPIKeysDB().GetAPIKeyAuditEntries(ak.node.ctx, apiKeyID, limit)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get api key audit entries")
+	}
+	return auditEntries, nil
+}
+
+func (ak *apiKeys) Audit(auditEntry *entry.APIKeyAuditEntry) {
+	select {
+	case ak.audit <- auditEntry:
+	default:
+		// never lose audit entries, just slow down the request
+		ak.record(auditEntry)
+	}
+}
+
+func (ak *apiKeys) record(auditEntry *entry.APIKeyAuditEntry) {
+	if err := ak.node.db.GetAPIKeysDB().InsertAPIKeyAuditEntry(ak.node.ctx, auditEntry); err != nil {
+		ak.node.log.Error(
+			errors.WithMessagef(err, "API keys: failed to insert audit entry: %s", auditEntry.APIKeyID),
+		)
+	}
+
+	if err := ak.node.db.GetAPIKeysDB().UpdateAPIKeyLastUsedAt(
+		ak.node.ctx, auditEntry.APIKeyID, auditEntry.CreatedAt,
+	); err != nil {
+		ak.node.log.Error(
+			errors.WithMessagef(err, "API keys: failed to update last used at: %s", auditEntry.APIKeyID),
+		)
+	}
+
+	ak.mu.Lock()
+	if apiKey, ok := ak.keys[auditEntry.APIKeyID]; ok {
+		usedKey := *apiKey
+		usedKey.LastUsedAt = &auditEntry.CreatedAt
+		ak.keys[auditEntry.APIKeyID] = &usedKey
+	}
+	ak.mu.Unlock()
+}
+
+// run records queued audit entries and periodically drops inactive keys from the cache.
+func (ak *apiKeys) run() {
+	ticker := time.NewTicker(userSessionsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ak.node.ctx.Done():
+			return
+		case auditEntry := <-ak.audit:
+			ak.record(auditEntry)
+		case <-ticker.C:
+			ak.mu.Lock()
+			for apiKeyID, apiKey := range ak.keys {
+				if !apiKey.IsActive() {
+					delete(ak.keys, apiKeyID)
+				}
+			}
+			ak.mu.Unlock()
+		}
+	}
+}
@@ -255,21 +255,9 @@ func (na *nodeAttributes) GetUserRoles(
 	targetID entry.AttributeID,
 	userID umid.UMID,
 ) ([]entry.PermissionsRoleType, error) {
-	var roles []entry.PermissionsRoleType
-	// owner is always considered an admin, TODO: add this to check function
-	if na.node.GetOwnerID() == userID {
-		roles = append(roles, entry.PermissionAdmin)
-	} else { // we have to lookup through the db user tree
-		userObjectID := entry.NewUserObjectID(userID, na.node.GetID())
-		isAdmin, err := na.node.db.GetUserObjectsDB().CheckIsIndirectAdminByID(ctx, userObjectID)
-		if err != nil {
-			return nil, errors.WithMessage(err, "failed to check admin status")
-		}
-		if isAdmin {
-			roles = append(roles, entry.PermissionAdmin)
-		}
+	roles, err := na.node.userObjects.GetUserRoles(entry.NewUserObjectID(userID, na.node.GetID()))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user roles")
 	}
 	return roles, nil
-	na.node.GetOwnerID()
-	return nil, nil
 }
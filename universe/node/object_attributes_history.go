@@ -0,0 +1,74 @@
+package node
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils/modify"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// restoreObjectAttributes reverts object attributes to the values they had at the given time.
+// All attributes are restored when "attributeID" is nil.
+// Returns ids of restored attributes, the restore itself is recorded in history as made by the given user.
+func (n *Node) restoreObjectAttributes(
+	userID umid.UMID, objects map[umid.UMID]universe.Object, attributeID *entry.AttributeID, at time.Time,
+) ([]entry.ObjectAttributeID, error) {
+	objectIDs := make([]umid.UMID, 0, len(objects))
+	for objectID := range objects {
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	history, err := n.db.GetObjectAttributesHistoryDB().GetObjectAttributesHistorySince(n.ctx, objectIDs, at.UTC())
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get object attributes history")
+	}
+
+	// the oldest change made after "at" holds the value the attribute had at that time
+	var restoreIDs []entry.ObjectAttributeID
+	restoreValues := make(map[entry.ObjectAttributeID]*entry.AttributeValue)
+	for _, change := range history {
+		if attributeID != nil && change.AttributeID != *attributeID {
+			continue
+		}
+		if _, ok := restoreValues[change.ObjectAttributeID]; ok {
+			continue
+		}
+		restoreIDs = append(restoreIDs, change.ObjectAttributeID)
+		restoreValues[change.ObjectAttributeID] = change.OldValue
+	}
+
+	restored := make([]entry.ObjectAttributeID, 0, len(restoreIDs))
+	for _, objectAttributeID := range restoreIDs {
+		object, ok := objects[objectAttributeID.ObjectID]
+		if !ok {
+			continue
+		}
+		attributes := object.GetObjectAttributes()
+		value := restoreValues[objectAttributeID]
+
+		if _, ok := attributes.GetPayload(objectAttributeID.AttributeID); ok {
+			if _, err := attributes.UpdateValueByUser(
+				userID, objectAttributeID.AttributeID, modify.ReplaceWith(value), true,
+			); err != nil {
+				return restored, errors.WithMessagef(err, "failed to update object attribute: %+v", objectAttributeID)
+			}
+		} else if value != nil {
+			if _, err := attributes.UpsertByUser(
+				userID, objectAttributeID.AttributeID,
+				modify.MergeWith(entry.NewAttributePayload(value, nil)), true,
+			); err != nil {
+				return restored, errors.WithMessagef(err, "failed to upsert object attribute: %+v", objectAttributeID)
+			}
+		} else {
+			continue
+		}
+
+		restored = append(restored, objectAttributeID)
+	}
+
+	return restored, nil
+}
@@ -0,0 +1,31 @@
+package node
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+const objectTrashPurgeInterval = time.Hour
+
+// runObjectTrashPurge periodically removes expired trash items with their children, attributes and activities.
+func (n *Node) runObjectTrashPurge() {
+	ticker := time.NewTicker(objectTrashPurgeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-n.ctx.Done():
+			return
+		case <-ticker.C:
+			purgedIDs, err := n.db.GetObjectTrashDB().PurgeExpiredObjectTrashItems(n.ctx, time.Now())
+			if err != nil {
+				n.log.Error(errors.WithMessage(err, "Node: runObjectTrashPurge: failed to purge expired trash items"))
+				continue
+			}
+			if len(purgedIDs) > 0 {
+				n.log.Infof("Node: runObjectTrashPurge: expired trash items purged: %v", purgedIDs)
+			}
+		}
+	}
+}
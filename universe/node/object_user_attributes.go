@@ -201,6 +201,19 @@ func (n *Node) onObjectUserAttributeChanged(
 	changeType posbus.AttributeChangeType, objectUserAttributeID entry.ObjectUserAttributeID,
 	value *entry.AttributeValue, effectiveOptions *entry.AttributeOptions,
 ) {
+	go func() {
+		if err := n.attributeSubscriptions.Publish(
+			entry.NewObjectUserAttributeSubscriptionID(objectUserAttributeID), changeType, value,
+		); err != nil {
+			n.log.Error(
+				errors.WithMessagef(
+					err, "Node: onObjectUserAttributeChanged: failed to publish to subscribers: %+v",
+					objectUserAttributeID,
+				),
+			)
+		}
+	}()
+
 	if effectiveOptions == nil {
 		options, ok := n.GetObjectUserAttributes().GetEffectiveOptions(objectUserAttributeID)
 		if !ok {
@@ -168,6 +168,18 @@ func (n *Node) onUserAttributeChanged(
 	changeType posbus.AttributeChangeType, userAttributeID entry.UserAttributeID, value *entry.AttributeValue,
 	effectiveOptions *entry.AttributeOptions,
 ) {
+	go func() {
+		if err := n.attributeSubscriptions.Publish(
+			entry.NewUserAttributeSubscriptionID(userAttributeID), changeType, value,
+		); err != nil {
+			n.log.Error(
+				errors.WithMessagef(
+					err, "Node: onUserAttributeChanged: failed to publish to subscribers: %+v", userAttributeID,
+				),
+			)
+		}
+	}()
+
 	if effectiveOptions == nil {
 		options, ok := n.GetUserAttributes().GetEffectiveOptions(userAttributeID)
 		if !ok {
@@ -198,7 +210,7 @@ func (ua *userAttributes) GetUserRoles(
 	targetID entry.UserAttributeID,
 	userID umid.UMID,
 ) ([]entry.PermissionsRoleType, error) {
-	var roles []entry.PermissionsRoleType
+	roles := []entry.PermissionsRoleType{entry.PermissionUser}
 	if targetID.UserID == userID {
 		roles = append(roles, entry.PermissionUserOwner)
 	} // TODO: user members, so walk the db user tree...
@@ -0,0 +1,71 @@
+package node
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var errInvalidUserAvatar = errors.New("invalid user avatar")
+
+// validateUserAvatar checks the format of the avatar and that all referenced assets exist.
+func (n *Node) validateUserAvatar(avatar *entry.UserAvatar) error {
+	if err := avatar.Validate(); err != nil {
+		return errors.Wrap(errInvalidUserAvatar, err.Error())
+	}
+
+	if _, ok := n.GetAssets3d().GetAsset3d(avatar.ModelAsset3dID); !ok {
+		return errors.Wrapf(errInvalidUserAvatar, "model asset 3d not found: %s", avatar.ModelAsset3dID)
+	}
+	for _, accessory := range avatar.Accessories {
+		if _, ok := n.GetAssets3d().GetAsset3d(accessory.Asset3dID); !ok {
+			return errors.Wrapf(
+				errInvalidUserAvatar, "accessory asset 3d not found: %s: %s", accessory.Slot, accessory.Asset3dID,
+			)
+		}
+	}
+
+	return nil
+}
+
+// setUserAvatar stores the avatar, nil removes it, and broadcasts the change to the world of the user.
+func (n *Node) setUserAvatar(ctx context.Context, userID umid.UMID, avatar *entry.UserAvatar) (*entry.UserProfile, error) {
+	if avatar != nil {
+		if err := n.validateUserAvatar(avatar); err != nil {
+			return nil, err
+		}
+	}
+
+	profile, err := n.db.GetUsersDB().GetUserProfileByUserID(ctx, userID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user profile")
+	}
+	profile.Avatar = avatar
+
+	if err := n.db.GetUsersDB().UpdateUserProfile(ctx, userID, profile); err != nil {
+		return nil, errors.WithMessage(err, "failed to update user profile")
+	}
+
+	user, ok := n.userRelations.GetOnlineUser(userID)
+	if !ok {
+		return profile, nil
+	}
+	user.SetProfile(profile)
+
+	world := user.GetWorld()
+	if world == nil {
+		return profile, nil
+	}
+	msg := posbus.WSMessage(
+		&posbus.UserAvatarChanged{UserID: userID, Avatar: user.GetUserDefinition().AvatarDefinition},
+	)
+	if err := world.Send(msg, true); err != nil {
+		n.log.Error(errors.WithMessagef(err, "Node: setUserAvatar: failed to broadcast avatar: %s", userID))
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,266 @@
+package node
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"path"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/media"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+	"github.com/momentum-xyz/ubercontroller/utils/ziputil"
+)
+
+// What happens with worlds owned by a removed user.
+const (
+	userWorldsPolicyDelete   = "delete"
+	userWorldsPolicyTransfer = "transfer"
+)
+
+const userExportMediaDir = "media"
+
+// exportUserData writes a zip archive with all the data tied to the user and media files referenced by it.
+func (n *Node) exportUserData(ctx context.Context, userID umid.UMID, w io.Writer) error {
+	user, err := n.db.GetUsersDB().GetUserByID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get user")
+	}
+	wallets, err := n.db.GetUsersDB().GetUserWalletsByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get user wallets")
+	}
+	userAttributes, err := n.db.GetUserAttributesDB().GetUserAttributesByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get user attributes")
+	}
+	sourceUserUserAttributes, err := n.db.GetUserUserAttributesDB().GetUserUserAttributesBySourceUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get source user user attributes")
+	}
+	targetUserUserAttributes, err := n.db.GetUserUserAttributesDB().GetUserUserAttributesByTargetUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get target user user attributes")
+	}
+	objectUserAttributes, err := n.db.GetObjectUserAttributesDB().GetObjectUserAttributesByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get object user attributes")
+	}
+	userObjects, err := n.db.GetUserObjectsDB().GetUserObjectsByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get user objects")
+	}
+	activities, err := n.db.GetActivitiesDB().GetActivitiesByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get activities")
+	}
+	ownedObjects, err := n.db.GetObjectsDB().GetObjectsByOwnerID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get owned objects")
+	}
+	sessions, err := n.db.GetUserSessionsDB().GetActiveUserSessionsByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get sessions")
+	}
+
+	relations := make(map[entry.UserRelationType][]*entry.UserRelation)
+	for _, relationType := range []entry.UserRelationType{
+		entry.UserRelationFriendRequest, entry.UserRelationFriend, entry.UserRelationFollow, entry.UserRelationBlock,
+	} {
+		userRelations, err := n.db.GetUserRelationsDB().GetUserRelationsBySourceUserID(ctx, userID, relationType)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get user relations: %s", relationType)
+		}
+		relations[relationType] = userRelations
+	}
+
+	var stakes []*entry.Stake
+	for _, wallet := range wallets {
+		walletStakes, err := n.db.GetStakesDB().GetStakesByWalletID(ctx, *wallet)
+		if err != nil {
+			return errors.WithMessagef(err, "failed to get stakes for wallet: %s", *wallet)
+		}
+		stakes = append(stakes, walletStakes...)
+	}
+
+	archive := zip.NewWriter(w)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{name: "user.json", data: user},
+		{name: "wallets.json", data: wallets},
+		{name: "user_attributes.json", data: userAttributes},
+		{
+			name: "user_user_attributes.json",
+			data: map[string]any{
+				"source": sourceUserUserAttributes,
+				"target": targetUserUserAttributes,
+			},
+		},
+		{name: "object_user_attributes.json", data: objectUserAttributes},
+		{name: "user_objects.json", data: userObjects},
+		{name: "activities.json", data: activities},
+		{name: "owned_objects.json", data: ownedObjects},
+		{name: "stakes.json", data: stakes},
+		{name: "sessions.json", data: sessions},
+		{name: "relations.json", data: relations},
+	}
+	for _, file := range files {
+		if err := ziputil.WriteJSON(archive, file.name, file.data); err != nil {
+			return errors.WithMessagef(err, "failed to write file: %s", file.name)
+		}
+	}
+
+	for _, hash := range getUserMediaHashes(user, activities) {
+		file, ok := n.media.FindFile(hash)
+		if !ok {
+			continue
+		}
+		if err := copyMediaFile(ctx, archive, path.Join(userExportMediaDir, hash), file); err != nil {
+			return errors.WithMessagef(err, "failed to write media file: %s", hash)
+		}
+	}
+
+	return archive.Close()
+}
+
+func copyMediaFile(ctx context.Context, archive *zip.Writer, name string, file *media.File) error {
+	src, err := file.Open(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to open file")
+	}
+	defer src.Close()
+
+	return ziputil.CopyReader(archive, name, src)
+}
+
+// removeUserData removes the user with everything tied to it.
+// Owned worlds are removed or transferred to another user depending on the policy,
+// other owned objects are transferred to the owners of their worlds,
+// changes the user made in shared content are kept anonymously.
+// Media files referenced only by the user are purged.
+func (n *Node) removeUserData(ctx context.Context, userID umid.UMID, worldsPolicy string, transferTo umid.UMID) error {
+	user, err := n.db.GetUsersDB().GetUserByID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get user")
+	}
+	activities, err := n.db.GetActivitiesDB().GetActivitiesByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get activities")
+	}
+	mediaHashes := getUserMediaHashes(user, activities)
+
+	// disconnect user before removing anything
+	if err := n.userSessions.RevokeAll(userID); err != nil {
+		return errors.WithMessage(err, "failed to revoke sessions")
+	}
+
+	for _, world := range n.GetWorldsByOwnerID(userID) {
+		switch worldsPolicy {
+		case userWorldsPolicyDelete:
+			if _, err := tree.RemoveWorld(world, true); err != nil {
+				return errors.WithMessagef(err, "failed to remove world: %s", world.GetID())
+			}
+		case userWorldsPolicyTransfer:
+			if err := world.SetOwnerID(transferTo, true); err != nil {
+				return errors.WithMessagef(err, "failed to transfer world: %s", world.GetID())
+			}
+		default:
+			return errors.Errorf("unknown worlds policy: %s", worldsPolicy)
+		}
+	}
+
+	// objects are removed in cascade with their owner, keep them for the worlds they are placed in
+	for _, object := range n.FilterAllObjects(
+		func(objectID umid.UMID, object universe.Object) bool {
+			return object.GetOwnerID() == userID
+		},
+	) {
+		newOwnerID := n.GetOwnerID()
+		if world := object.GetWorld(); world != nil && world.GetOwnerID() != userID {
+			newOwnerID = world.GetOwnerID()
+		}
+		if err := object.SetOwnerID(newOwnerID, true); err != nil {
+			return errors.WithMessagef(err, "failed to transfer object: %s", object.GetID())
+		}
+	}
+	// objects which are not loaded
+	ownedObjects, err := n.db.GetObjectsDB().GetObjectsByOwnerID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get owned objects")
+	}
+	for _, object := range ownedObjects {
+		if err := n.db.GetObjectsDB().UpdateObjectOwnerID(ctx, object.ObjectID, n.GetOwnerID()); err != nil {
+			return errors.WithMessagef(err, "failed to transfer object: %s", object.ObjectID)
+		}
+	}
+
+	userObjects, err := n.db.GetUserObjectsDB().GetUserObjectsByUserID(ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to get user objects")
+	}
+	userObjectIDs := make([]entry.UserObjectID, 0, len(userObjects))
+	for _, userObject := range userObjects {
+		userObjectIDs = append(userObjectIDs, userObject.UserObjectID)
+	}
+	if err := n.db.GetUserObjectsDB().RemoveUserObjectsByIDs(ctx, userObjectIDs); err != nil {
+		return errors.WithMessage(err, "failed to remove user objects")
+	}
+
+	if err := n.db.GetObjectAttributesHistoryDB().AnonymizeObjectAttributesHistoryByUserID(ctx, userID); err != nil {
+		return errors.WithMessage(err, "failed to anonymize attributes history")
+	}
+
+	// attributes, activities, sessions, etc. are removed in cascade
+	if err := n.db.GetUsersDB().RemoveUserByID(ctx, userID); err != nil {
+		return errors.WithMessage(err, "failed to remove user")
+	}
+
+	n.log.Infof("Node: removeUserData: user removed: %s", userID)
+
+	var errs *multierror.Error
+	for _, hash := range mediaHashes {
+		referenced, err := n.db.GetCommonDB().CheckIsMediaReferenced(ctx, hash)
+		if err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to check media references: %s", hash))
+			continue
+		}
+		if referenced {
+			continue
+		}
+		if err := n.media.DeleteFile(hash); err != nil {
+			errs = multierror.Append(errs, errors.WithMessagef(err, "failed to delete media: %s", hash))
+		}
+	}
+	if err := errs.ErrorOrNil(); err != nil {
+		// the user is already removed, leftover media doesn't contain personal data links anymore
+		n.log.Error(errors.WithMessage(err, "Node: removeUserData: failed to purge media"))
+	}
+
+	return nil
+}
+
+// getUserMediaHashes returns hashes of media files uploaded by the user.
+func getUserMediaHashes(user *entry.User, activities []*entry.Activity) []string {
+	var hashes []string
+	if user.Profile.AvatarHash != nil && *user.Profile.AvatarHash != "" {
+		hashes = append(hashes, *user.Profile.AvatarHash)
+	}
+	for _, activity := range activities {
+		if activity.Data == nil || activity.Data.Hash == nil || *activity.Data.Hash == "" {
+			continue
+		}
+		if !slices.Contains(hashes, *activity.Data.Hash) {
+			hashes = append(hashes, *activity.Data.Hash)
+		}
+	}
+	return hashes
+}
@@ -3,11 +3,14 @@ package node
 import (
 	"github.com/jackc/pgx/v4"
 	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
 
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
 
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/auth"
+	"github.com/momentum-xyz/ubercontroller/utils"
 	"github.com/momentum-xyz/ubercontroller/utils/modify"
 )
 
@@ -49,11 +52,122 @@ func (uo *userObjects) GetUserObjectsByObjectID(objectID umid.UMID) ([]*entry.Us
 }
 
 func (uo *userObjects) CheckIsIndirectAdmin(userObjectID entry.UserObjectID) (bool, error) {
-	isAdmin, err := uo.node.db.GetUserObjectsDB().CheckIsIndirectAdminByID(uo.node.ctx, userObjectID)
+	roles, err := uo.GetUserRoles(userObjectID)
 	if err != nil {
-		return false, errors.WithMessage(err, "failed to check is indirect admin by umid")
+		return false, errors.WithMessage(err, "failed to get user roles")
 	}
-	return isAdmin, nil
+	return slices.Contains(roles, entry.PermissionAdmin), nil
+}
+
+// GetUserRoles returns effective roles of the user on the object.
+// Roles assigned on the object ancestors are inherited and roles included by the world role definitions are expanded.
+func (uo *userObjects) GetUserRoles(userObjectID entry.UserObjectID) ([]entry.PermissionsRoleType, error) {
+	roles := []entry.PermissionsRoleType{entry.PermissionUser}
+
+	object, ok := uo.node.GetObjectFromAllObjects(userObjectID.ObjectID)
+	// owner is always considered an admin
+	isAdmin := ok && object.GetOwnerID() == userObjectID.UserID
+	if !isAdmin {
+		// we have to lookup through the db user tree
+		var err error
+		isAdmin, err = uo.node.db.GetUserObjectsDB().CheckIsIndirectAdminByID(uo.node.ctx, userObjectID)
+		if err != nil {
+			return nil, errors.WithMessage(err, "failed to check is indirect admin by umid")
+		}
+	}
+	if isAdmin {
+		roles = append(roles, entry.PermissionAdmin)
+	}
+
+	if !ok {
+		return roles, nil
+	}
+
+	assignedRoles, err := uo.GetAssignedRoles(userObjectID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get assigned roles")
+	}
+	for _, assignedRole := range assignedRoles {
+		roles = append(roles, assignedRole.Role)
+	}
+
+	definitions, err := uo.GetRoleDefinitions(userObjectID.ObjectID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get role definitions")
+	}
+
+	return auth.ExpandRoles(roles, definitions), nil
+}
+
+// GetAssignedRoles returns roles assigned to the user on the object and its ancestors, nearest object first.
+func (uo *userObjects) GetAssignedRoles(userObjectID entry.UserObjectID) ([]entry.UserObjectRole, error) {
+	object, ok := uo.node.GetObjectFromAllObjects(userObjectID.ObjectID)
+	if !ok {
+		return nil, errors.Errorf("object not found: %s", userObjectID.ObjectID)
+	}
+
+	var objectIDs []umid.UMID
+	for object != nil && !slices.Contains(objectIDs, object.GetID()) {
+		objectIDs = append(objectIDs, object.GetID())
+		object = object.GetParent()
+	}
+
+	userObjects, err := uo.node.db.GetUserObjectsDB().GetUserObjectsByUserIDAndObjectIDs(
+		uo.node.ctx, userObjectID.UserID, objectIDs,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user objects")
+	}
+
+	values := make(map[umid.UMID]*entry.UserObjectValue, len(userObjects))
+	for _, userObject := range userObjects {
+		values[userObject.ObjectID] = userObject.Value
+	}
+
+	var roles []entry.UserObjectRole
+	for _, objectID := range objectIDs {
+		value := values[objectID]
+		if value == nil {
+			continue
+		}
+		role := utils.GetFromAnyMap(*value, universe.ReservedAttributes.User.Role.Key, "")
+		if role == "" {
+			continue
+		}
+		roles = append(roles, entry.UserObjectRole{
+			ObjectID: objectID,
+			Role:     entry.PermissionsRoleType(role),
+		})
+	}
+
+	return roles, nil
+}
+
+// GetRoleDefinitions returns custom roles defined by the world the object belongs to.
+func (uo *userObjects) GetRoleDefinitions(objectID umid.UMID) ([]*entry.RoleDefinition, error) {
+	object, ok := uo.node.GetObjectFromAllObjects(objectID)
+	if !ok {
+		return nil, errors.Errorf("object not found: %s", objectID)
+	}
+
+	world := object.GetWorld()
+	if world == nil {
+		return nil, nil
+	}
+
+	value, ok := world.GetObjectAttributes().GetValue(
+		entry.NewAttributeID(universe.GetSystemPluginID(), universe.ReservedAttributes.World.Roles.Name),
+	)
+	if !ok || value == nil {
+		return nil, nil
+	}
+
+	var definitions entry.RoleDefinitions
+	if err := utils.MapDecode(*value, &definitions); err != nil {
+		return nil, errors.WithMessage(err, "failed to decode role definitions")
+	}
+
+	return definitions.Roles, nil
 }
 
 func (uo *userObjects) Upsert(
@@ -0,0 +1,368 @@
+package node
+
+import (
+	"encoding/json"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const defaultPresenceVisibility = entry.PresenceVisibilityFriends
+
+var (
+	errUserBlocked          = errors.New("user is blocked")
+	errUserRelationExists   = errors.New("user relation already exists")
+	errUserRelationNotFound = errors.New("user relation not found")
+)
+
+var _ universe.UserRelations = (*userRelations)(nil)
+
+type userRelations struct {
+	node *Node
+	mu   sync.RWMutex
+	// connected users, presence is derived from their current world
+	online map[umid.UMID]universe.User
+}
+
+func newUserRelations(node *Node) *userRelations {
+	return &userRelations{
+		node:   node,
+		online: make(map[umid.UMID]universe.User),
+	}
+}
+
+func (ur *userRelations) RequestFriendship(userID, targetUserID umid.UMID) (entry.UserRelationType, error) {
+	if err := ur.checkNotBlocked(userID, targetUserID); err != nil {
+		return "", err
+	}
+
+	db := ur.node.db.GetUserRelationsDB()
+	isFriend, err := db.CheckUserRelationExists(
+		ur.node.ctx, entry.NewUserRelationID(userID, targetUserID, entry.UserRelationFriend),
+	)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to check friendship")
+	}
+	if isFriend {
+		return "", errors.Wrapf(errUserRelationExists, "already friends: %s", targetUserID)
+	}
+
+	// both users want to be friends
+	accepted, err := db.AcceptFriendRequest(ur.node.ctx, targetUserID, userID)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to accept incoming friend request")
+	}
+	if accepted {
+		return entry.UserRelationFriend, nil
+	}
+
+	inserted, err := db.InsertUserRelation(
+		ur.node.ctx, entry.NewUserRelationID(userID, targetUserID, entry.UserRelationFriendRequest),
+	)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to insert friend request")
+	}
+	if !inserted {
+		return "", errors.Wrapf(errUserRelationExists, "friend request already sent: %s", targetUserID)
+	}
+
+	return entry.UserRelationFriendRequest, nil
+}
+
+func (ur *userRelations) AcceptFriendship(userID, requesterID umid.UMID) error {
+	accepted, err := ur.node.db.GetUserRelationsDB().AcceptFriendRequest(ur.node.ctx, requesterID, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to accept friend request")
+	}
+	if !accepted {
+		return errors.Wrapf(errUserRelationNotFound, "friend request not found: %s", requesterID)
+	}
+	return nil
+}
+
+func (ur *userRelations) RemoveFriendship(userID, otherUserID umid.UMID) error {
+	removed, err := ur.node.db.GetUserRelationsDB().RemoveFriendship(ur.node.ctx, userID, otherUserID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to remove friendship")
+	}
+	if !removed {
+		return errors.Wrapf(errUserRelationNotFound, "friendship not found: %s", otherUserID)
+	}
+	return nil
+}
+
+func (ur *userRelations) GetFriends(userID umid.UMID) ([]*entry.UserRelation, error) {
+	return ur.getBySource(userID, entry.UserRelationFriend)
+}
+
+func (ur *userRelations) GetFriendRequests(userID umid.UMID) ([]*entry.UserRelation, []*entry.UserRelation, error) {
+	incoming, err := ur.getByTarget(userID, entry.UserRelationFriendRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	outgoing, err := ur.getBySource(userID, entry.UserRelationFriendRequest)
+	if err != nil {
+		return nil, nil, err
+	}
+	return incoming, outgoing, nil
+}
+
+func (ur *userRelations) Follow(userID, targetUserID umid.UMID) error {
+	if err := ur.checkNotBlocked(userID, targetUserID); err != nil {
+		return err
+	}
+
+	inserted, err := ur.node.db.GetUserRelationsDB().InsertUserRelation(
+		ur.node.ctx, entry.NewUserRelationID(userID, targetUserID, entry.UserRelationFollow),
+	)
+	if err != nil {
+		return errors.WithMessage(err, "failed to insert follow")
+	}
+	if !inserted {
+		return errors.Wrapf(errUserRelationExists, "already following: %s", targetUserID)
+	}
+	return nil
+}
+
+func (ur *userRelations) Unfollow(userID, targetUserID umid.UMID) error {
+	return ur.remove(entry.NewUserRelationID(userID, targetUserID, entry.UserRelationFollow))
+}
+
+func (ur *userRelations) GetFollowing(userID umid.UMID) ([]*entry.UserRelation, error) {
+	return ur.getBySource(userID, entry.UserRelationFollow)
+}
+
+func (ur *userRelations) GetFollowers(userID umid.UMID) ([]*entry.UserRelation, error) {
+	return ur.getByTarget(userID, entry.UserRelationFollow)
+}
+
+func (ur *userRelations) Block(userID, targetUserID umid.UMID) error {
+	if err := ur.node.db.GetUserRelationsDB().BlockUser(ur.node.ctx, userID, targetUserID); err != nil {
+		return errors.WithMessage(err, "failed to block user")
+	}
+	return nil
+}
+
+func (ur *userRelations) Unblock(userID, targetUserID umid.UMID) error {
+	return ur.remove(entry.NewUserRelationID(userID, targetUserID, entry.UserRelationBlock))
+}
+
+func (ur *userRelations) GetBlocked(userID umid.UMID) ([]*entry.UserRelation, error) {
+	return ur.getBySource(userID, entry.UserRelationBlock)
+}
+
+func (ur *userRelations) GetPresence(viewerID, userID umid.UMID) (*entry.UserPresence, error) {
+	presence := &entry.UserPresence{UserID: userID}
+
+	ur.mu.RLock()
+	user, ok := ur.online[userID]
+	ur.mu.RUnlock()
+
+	if !ok {
+		return presence, nil
+	}
+
+	visible, err := ur.isPresenceVisible(viewerID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if !visible {
+		// hidden presence looks the same as offline
+		return presence, nil
+	}
+
+	presence.Online = true
+	if world := user.GetWorld(); world != nil {
+		worldID := world.GetID()
+		presence.WorldID = &worldID
+	}
+
+	return presence, nil
+}
+
+func (ur *userRelations) UserEnteredWorld(user universe.User, world universe.World) {
+	ur.mu.Lock()
+	_, wasOnline := ur.online[user.GetID()]
+	ur.online[user.GetID()] = user
+	ur.mu.Unlock()
+
+	notifyType := posbus.NotificationFriendEnteredWorld
+	if !wasOnline {
+		notifyType = posbus.NotificationFriendOnline
+	}
+
+	go func() {
+		if err := ur.notifyFriends(user, world.GetID(), notifyType); err != nil {
+			ur.node.log.Error(
+				errors.WithMessagef(err, "User relations: failed to notify friends: %s", user.GetID()),
+			)
+		}
+	}()
+}
+
+func (ur *userRelations) UserDisconnected(user universe.User) {
+	ur.mu.Lock()
+	defer ur.mu.Unlock()
+
+	// the user can be already replaced with a new connection
+	if ur.online[user.GetID()] == user {
+		delete(ur.online, user.GetID())
+	}
+}
+
+// GetOnlineUser returns the connection of the user if any.
+func (ur *userRelations) GetOnlineUser(userID umid.UMID) (universe.User, bool) {
+	ur.mu.RLock()
+	defer ur.mu.RUnlock()
+
+	user, ok := ur.online[userID]
+	return user, ok
+}
+
+func (ur *userRelations) notifyFriends(user universe.User, worldID umid.UMID, notifyType posbus.NotificationType) error {
+	visibility, err := ur.getPresenceVisibility(user.GetID())
+	if err != nil {
+		return err
+	}
+	if visibility == entry.PresenceVisibilityNobody {
+		return nil
+	}
+
+	friends, err := ur.GetFriends(user.GetID())
+	if err != nil {
+		return err
+	}
+	if len(friends) == 0 {
+		return nil
+	}
+
+	type Value struct {
+		UserID  umid.UMID `json:"user_id"`
+		Name    string    `json:"name"`
+		WorldID umid.UMID `json:"world_id"`
+	}
+	value := Value{
+		UserID:  user.GetID(),
+		WorldID: worldID,
+	}
+	if profile := user.GetProfile(); profile != nil && profile.Name != nil {
+		value.Name = *profile.Name
+	}
+	data, err := json.Marshal(&value)
+	if err != nil {
+		return errors.WithMessage(err, "failed to marshal notification value")
+	}
+	msg := posbus.WSMessage(&posbus.Notification{NotifyType: notifyType, Value: string(data)})
+
+	for _, friend := range friends {
+		friendUser, ok := ur.GetOnlineUser(friend.TargetUserID)
+		if !ok {
+			continue
+		}
+		if err := friendUser.Send(msg); err != nil {
+			ur.node.log.Warn(
+				errors.WithMessagef(err, "User relations: failed to send notification: %s", friend.TargetUserID),
+			)
+		}
+	}
+
+	return nil
+}
+
+func (ur *userRelations) isPresenceVisible(viewerID, userID umid.UMID) (bool, error) {
+	if viewerID == userID {
+		return true, nil
+	}
+
+	visibility, err := ur.getPresenceVisibility(userID)
+	if err != nil {
+		return false, err
+	}
+
+	db := ur.node.db.GetUserRelationsDB()
+	switch visibility {
+	case entry.PresenceVisibilityNobody:
+		return false, nil
+	case entry.PresenceVisibilityEveryone:
+		if err := ur.checkNotBlocked(viewerID, userID); err != nil {
+			if errors.Is(err, errUserBlocked) {
+				return false, nil
+			}
+			return false, err
+		}
+		return true, nil
+	default:
+		isFriend, err := db.CheckUserRelationExists(
+			ur.node.ctx, entry.NewUserRelationID(userID, viewerID, entry.UserRelationFriend),
+		)
+		if err != nil {
+			return false, errors.WithMessage(err, "failed to check friendship")
+		}
+		return isFriend, nil
+	}
+}
+
+func (ur *userRelations) getPresenceVisibility(userID umid.UMID) (entry.PresenceVisibility, error) {
+	user, err := ur.node.db.GetUsersDB().GetUserByID(ur.node.ctx, userID)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to get user")
+	}
+	if user.Options == nil || user.Options.PresenceVisibility == nil {
+		return defaultPresenceVisibility, nil
+	}
+	return *user.Options.PresenceVisibility, nil
+}
+
+// checkNotBlocked returns errUserBlocked if either of the users blocked the other one.
+func (ur *userRelations) checkNotBlocked(userID, otherUserID umid.UMID) error {
+	db := ur.node.db.GetUserRelationsDB()
+	for _, relationID := range []entry.UserRelationID{
+		entry.NewUserRelationID(userID, otherUserID, entry.UserRelationBlock),
+		entry.NewUserRelationID(otherUserID, userID, entry.UserRelationBlock),
+	} {
+		blocked, err := db.CheckUserRelationExists(ur.node.ctx, relationID)
+		if err != nil {
+			return errors.WithMessage(err, "failed to check block")
+		}
+		if blocked {
+			return errors.Wrapf(errUserBlocked, "%s blocked %s", relationID.SourceUserID, relationID.TargetUserID)
+		}
+	}
+	return nil
+}
+
+func (ur *userRelations) getBySource(
+	userID umid.UMID, relationType entry.UserRelationType,
+) ([]*entry.UserRelation, error) {
+	relations, err := ur.node.db.GetUserRelationsDB().GetUserRelationsBySourceUserID(ur.node.ctx, userID, relationType)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get user relations: %s", relationType)
+	}
+	return relations, nil
+}
+
+func (ur *userRelations) getByTarget(
+	userID umid.UMID, relationType entry.UserRelationType,
+) ([]*entry.UserRelation, error) {
+	relations, err := ur.node.db.GetUserRelationsDB().GetUserRelationsByTargetUserID(ur.node.ctx, userID, relationType)
+	if err != nil {
+		return nil, errors.WithMessagef(err, "failed to get user relations: %s", relationType)
+	}
+	return relations, nil
+}
+
+func (ur *userRelations) remove(relationID entry.UserRelationID) error {
+	removed, err := ur.node.db.GetUserRelationsDB().RemoveUserRelationByID(ur.node.ctx, relationID)
+	if err != nil {
+		return errors.WithMessagef(err, "failed to remove user relation: %s", relationID.RelationType)
+	}
+	if !removed {
+		return errors.Wrapf(errUserRelationNotFound, "%s: %s", relationID.RelationType, relationID.TargetUserID)
+	}
+	return nil
+}
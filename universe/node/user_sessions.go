@@ -0,0 +1,299 @@
+package node
+
+import (
+	"sync"
+	"time"
+
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const userSessionsCleanupInterval = time.Hour
+
+// Clients refreshing concurrently with the same token within this time after the rotation don't revoke the session.
+const userSessionsRefreshReuseGrace = 10 * time.Second
+
+var errInvalidRefreshToken = errors.New("invalid refresh token")
+
+var _ universe.UserSessions = (*userSessions)(nil)
+
+type userSessions struct {
+	node *Node
+	mu   sync.RWMutex
+	// cache of sessions checked on every request, revoked sessions are updated in place
+	sessions    map[umid.UMID]*entry.UserSession
+	connections map[umid.UMID]map[universe.User]struct{}
+}
+
+func newUserSessions(node *Node) *userSessions {
+	return &userSessions{
+		node:        node,
+		sessions:    make(map[umid.UMID]*entry.UserSession),
+		connections: make(map[umid.UMID]map[universe.User]struct{}),
+	}
+}
+
+func (us *userSessions) Create(
+	userID umid.UMID, userAgent, ipAddress string,
+) (*entry.UserSession, *entry.UserSessionTokens, error) {
+	if ban, ok := us.node.moderation.GetBan(userID, nil); ok {
+		return nil, nil, errors.Wrapf(errUserBanned, "ban: %s", ban.BanID)
+	}
+
+	sessionID := umid.New()
+	refreshToken, refreshTokenHash, err := api.GenerateRefreshToken(sessionID)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to generate refresh token")
+	}
+
+	now := time.Now()
+	session := &entry.UserSession{
+		SessionID:        sessionID,
+		UserID:           userID,
+		RefreshTokenHash: refreshTokenHash,
+		UserAgent:        userAgent,
+		IPAddress:        ipAddress,
+		ExpiresAt:        now.Add(us.node.cfg.Auth.RefreshTokenTTL),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+	if err := us.node.db.GetUserSessionsDB().InsertUserSession(us.node.ctx, session); err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to insert user session")
+	}
+
+	tokens, err := us.createTokens(session, refreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	us.mu.Lock()
+	us.sessions[sessionID] = session
+	us.mu.Unlock()
+
+	return session, tokens, nil
+}
+
+func (us *userSessions) Refresh(refreshToken string) (*entry.UserSession, *entry.UserSessionTokens, error) {
+	sessionID, err := api.ParseRefreshToken(refreshToken)
+	if err != nil {
+		return nil, nil, errors.Wrap(errInvalidRefreshToken, err.Error())
+	}
+
+	current, err := us.node.db.GetUserSessionsDB().GetUserSessionByID(us.node.ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil, errors.Wrapf(errInvalidRefreshToken, "session not found: %s", sessionID)
+		}
+		return nil, nil, errors.WithMessage(err, "failed to get user session")
+	}
+	if !current.IsActive() {
+		return nil, nil, errors.Wrapf(errInvalidRefreshToken, "session is not active: %s", sessionID)
+	}
+	if ban, ok := us.node.moderation.GetBan(current.UserID, nil); ok {
+		return nil, nil, errors.Wrapf(errUserBanned, "ban: %s", ban.BanID)
+	}
+
+	oldHash := api.HashRefreshToken(refreshToken)
+	if oldHash != current.RefreshTokenHash {
+		return nil, nil, us.checkRefreshTokenReuse(current, oldHash)
+	}
+
+	newRefreshToken, newHash, err := api.GenerateRefreshToken(sessionID)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to generate refresh token")
+	}
+	expiresAt := time.Now().Add(us.node.cfg.Auth.RefreshTokenTTL)
+
+	rotated, err := us.node.db.GetUserSessionsDB().RotateUserSessionRefreshToken(
+		us.node.ctx, sessionID, oldHash, newHash, expiresAt,
+	)
+	if err != nil {
+		return nil, nil, errors.WithMessage(err, "failed to rotate refresh token")
+	}
+	if !rotated {
+		// rotated by a concurrent refresh in the meantime
+		return nil, nil, errors.Wrapf(errInvalidRefreshToken, "refresh token already rotated: %s", sessionID)
+	}
+
+	session := *current
+	session.RefreshTokenHash = newHash
+	session.ExpiresAt = expiresAt
+	session.UpdatedAt = time.Now()
+
+	tokens, err := us.createTokens(&session, newRefreshToken)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	us.mu.Lock()
+	us.sessions[sessionID] = &session
+	us.mu.Unlock()
+
+	return &session, tokens, nil
+}
+
+// checkRefreshTokenReuse revokes the session if the hash is of a refresh token issued for it before,
+// such token was used again and the session can be compromised.
+// Unknown tokens and the last rotated one within the grace time just don't match.
+func (us *userSessions) checkRefreshTokenReuse(session *entry.UserSession, hash string) error {
+	previous := session.PreviousRefreshTokenHashes
+	if !slices.Contains(previous, hash) {
+		return errors.Wrapf(errInvalidRefreshToken, "refresh token doesn't match: %s", session.SessionID)
+	}
+	if previous[len(previous)-1] == hash && time.Since(session.UpdatedAt) < userSessionsRefreshReuseGrace {
+		return errors.Wrapf(errInvalidRefreshToken, "refresh token already rotated: %s", session.SessionID)
+	}
+
+	if err := us.Revoke(session.SessionID); err != nil {
+		return errors.WithMessagef(err, "failed to revoke session: %s", session.SessionID)
+	}
+	return errors.Wrapf(errInvalidRefreshToken, "refresh token reused: %s", session.SessionID)
+}
+
+func (us *userSessions) GetActive(userID umid.UMID) ([]*entry.UserSession, error) {
+	sessions, err := us.node.db.GetUserSessionsDB().GetActiveUserSessionsByUserID(us.node.ctx, userID)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get active user sessions")
+	}
+	return sessions, nil
+}
+
+func (us *userSessions) IsActive(sessionID umid.UMID) (bool, error) {
+	us.mu.RLock()
+	session, ok := us.sessions[sessionID]
+	us.mu.RUnlock()
+
+	if !ok {
+		var err error
+		session, err = us.node.db.GetUserSessionsDB().GetUserSessionByID(us.node.ctx, sessionID)
+		if err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return false, nil
+			}
+			return false, errors.WithMessage(err, "failed to get user session")
+		}
+
+		us.mu.Lock()
+		us.sessions[sessionID] = session
+		us.mu.Unlock()
+	}
+
+	return session.IsActive(), nil
+}
+
+func (us *userSessions) Revoke(sessionID umid.UMID) error {
+	if err := us.node.db.GetUserSessionsDB().RevokeUserSession(us.node.ctx, sessionID); err != nil {
+		return errors.WithMessage(err, "failed to revoke user session")
+	}
+
+	us.revoked(sessionID)
+
+	return nil
+}
+
+func (us *userSessions) RevokeAll(userID umid.UMID) error {
+	sessionIDs, err := us.node.db.GetUserSessionsDB().RevokeUserSessionsByUserID(us.node.ctx, userID)
+	if err != nil {
+		return errors.WithMessage(err, "failed to revoke user sessions")
+	}
+
+	us.revoked(sessionIDs...)
+
+	return nil
+}
+
+func (us *userSessions) AddConnection(sessionID umid.UMID, user universe.User) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	users, ok := us.connections[sessionID]
+	if !ok {
+		users = make(map[universe.User]struct{})
+		us.connections[sessionID] = users
+	}
+	users[user] = struct{}{}
+}
+
+func (us *userSessions) RemoveConnection(user universe.User) {
+	us.mu.Lock()
+	defer us.mu.Unlock()
+
+	for sessionID, users := range us.connections {
+		delete(users, user)
+		if len(users) == 0 {
+			delete(us.connections, sessionID)
+		}
+	}
+}
+
+// revoked updates the cache and disconnects users of the revoked sessions.
+func (us *userSessions) revoked(sessionIDs ...umid.UMID) {
+	now := time.Now()
+	var users []universe.User
+
+	us.mu.Lock()
+	for _, sessionID := range sessionIDs {
+		if session, ok := us.sessions[sessionID]; ok {
+			revokedSession := *session
+			revokedSession.RevokedAt = &now
+			us.sessions[sessionID] = &revokedSession
+		}
+		for user := range us.connections[sessionID] {
+			users = append(users, user)
+		}
+		delete(us.connections, sessionID)
+	}
+	us.mu.Unlock()
+
+	for _, user := range users {
+		if err := user.Stop(); err != nil {
+			us.node.log.Warn(errors.WithMessagef(err, "User sessions: failed to disconnect user: %s", user.GetID()))
+		}
+	}
+}
+
+func (us *userSessions) createTokens(session *entry.UserSession, refreshToken string) (*entry.UserSessionTokens, error) {
+	accessToken, expiresAt, err := api.CreateAccessToken(
+		session.UserID, session.SessionID, us.node.cfg.Auth.AccessTokenTTL,
+	)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to create access token")
+	}
+
+	return &entry.UserSessionTokens{
+		AccessToken:  accessToken,
+		RefreshToken: refreshToken,
+		ExpiresAt:    expiresAt,
+	}, nil
+}
+
+// run periodically removes expired and revoked sessions.
+func (us *userSessions) run() {
+	ticker := time.NewTicker(userSessionsCleanupInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-us.node.ctx.Done():
+			return
+		case <-ticker.C:
+			if err := us.node.db.GetUserSessionsDB().RemoveExpiredUserSessions(us.node.ctx, time.Now()); err != nil {
+				us.node.log.Error(errors.WithMessage(err, "User sessions: failed to remove expired sessions"))
+			}
+
+			us.mu.Lock()
+			for sessionID, session := range us.sessions {
+				if !session.IsActive() {
+					delete(us.sessions, sessionID)
+				}
+			}
+			us.mu.Unlock()
+		}
+	}
+}
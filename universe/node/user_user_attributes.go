@@ -203,7 +203,7 @@ func (uua *userUserAttributes) GetUserRoles(
 	targetID entry.UserUserAttributeID,
 	userID umid.UMID,
 ) ([]entry.PermissionsRoleType, error) {
-	var roles []entry.PermissionsRoleType
+	roles := []entry.PermissionsRoleType{entry.PermissionUser}
 	if targetID.SourceUserID == userID {
 		roles = append(roles, entry.PermissionUserOwner)
 		// TODO: user members...
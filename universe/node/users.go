@@ -5,6 +5,7 @@ import (
 
 	"github.com/pkg/errors"
 
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
 	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
 	"github.com/momentum-xyz/ubercontroller/universe/logic/common"
@@ -49,3 +50,47 @@ func (n *Node) Filter(predicateFn func(userID umid.UMID, user universe.User) boo
 
 	return data, nil
 }
+
+// upgradeGuestUser converts the guest user to a normal user in place, returns false if the user isn't a guest.
+// The user keeps its id, so owned objects, user_object memberships and attributes are preserved.
+func (n *Node) upgradeGuestUser(ctx context.Context, userID umid.UMID) (bool, error) {
+	guestUserTypeID, err := common.GetGuestUserTypeID()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to get guest user type id")
+	}
+	normUserTypeID, err := common.GetNormalUserTypeID()
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to get normal user type id")
+	}
+	normUserType, ok := n.userTypes.GetUserType(normUserTypeID)
+	if !ok {
+		return false, errors.Errorf("normal user type not found: %s", normUserTypeID)
+	}
+
+	upgraded, err := n.db.GetUsersDB().ReplaceUserUserTypeID(ctx, userID, guestUserTypeID, normUserTypeID)
+	if err != nil {
+		return false, errors.WithMessage(err, "failed to update user type")
+	}
+	if !upgraded {
+		return false, nil
+	}
+
+	n.log.Infof("Node: upgradeGuestUser: guest upgraded: %s", userID)
+
+	// update connected user and let others know it isn't a guest anymore
+	for _, world := range n.worlds.GetWorlds() {
+		user, ok := world.GetUser(userID, true)
+		if !ok {
+			continue
+		}
+		if err := user.SetUserType(normUserType, false); err != nil {
+			return true, errors.WithMessage(err, "failed to set user type")
+		}
+		world.Send(
+			posbus.WSMessage(&posbus.AddUsers{Users: []posbus.UserData{*user.GetUserDefinition()}}),
+			true,
+		)
+	}
+
+	return true, nil
+}
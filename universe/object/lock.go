@@ -0,0 +1,139 @@
+package object
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	// LockTTL is the lease time, clients renew their locks by locking again before it expires.
+	LockTTL = 30 * time.Second
+	// LockSweepInterval is how often worlds release expired locks.
+	LockSweepInterval = 5 * time.Second
+)
+
+// locksMu serializes lock changes, as a lock depends on the locks of the object ancestors and children.
+var locksMu sync.Mutex
+
+func (o *Object) Lock(userID umid.UMID, subtree bool) (*entry.ObjectLock, bool) {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	for parent := o.GetParent(); parent != nil; parent = parent.GetParent() {
+		if lock := parent.GetLock(false); lock != nil && lock.Subtree && lock.UserID != userID {
+			return lock, false
+		}
+	}
+
+	current := o.GetLock(false)
+	if current != nil {
+		if current.UserID != userID {
+			return current, false
+		}
+		// renewal keeps the subtree lease
+		subtree = subtree || current.Subtree
+	}
+
+	if subtree {
+		for _, child := range o.GetObjects(true) {
+			if lock := child.GetLock(false); lock != nil && lock.UserID != userID {
+				return lock, false
+			}
+		}
+	}
+
+	lock := &entry.ObjectLock{
+		ObjectID:  o.GetID(),
+		UserID:    userID,
+		Subtree:   subtree,
+		ExpiresAt: time.Now().Add(LockTTL),
+	}
+	o.lock.Store(lock)
+
+	return lock, true
+}
+
+func (o *Object) Unlock(userID umid.UMID, force bool) (*entry.ObjectLock, bool) {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	lock := o.lock.Load()
+	if lock == nil || (!force && lock.UserID != userID) {
+		return nil, false
+	}
+	o.lock.Store(nil)
+
+	return lock, true
+}
+
+func (o *Object) UnlockExpired(now time.Time) (*entry.ObjectLock, bool) {
+	locksMu.Lock()
+	defer locksMu.Unlock()
+
+	lock := o.lock.Load()
+	if lock == nil || !lock.IsExpired(now) {
+		return nil, false
+	}
+	o.lock.Store(nil)
+
+	return lock, true
+}
+
+func (o *Object) GetLock(inherited bool) *entry.ObjectLock {
+	now := time.Now()
+	if lock := o.lock.Load(); lock != nil && !lock.IsExpired(now) {
+		return lock
+	}
+	if !inherited {
+		return nil
+	}
+
+	for parent := o.GetParent(); parent != nil; parent = parent.GetParent() {
+		if lock := parent.GetLock(false); lock != nil && lock.Subtree {
+			return lock
+		}
+	}
+
+	return nil
+}
+
+func (o *Object) GetLockUserID() umid.UMID {
+	if lock := o.GetLock(true); lock != nil {
+		return lock.UserID
+	}
+	return umid.Nil
+}
+
+func (o *Object) IsLockedByUser(user universe.User) bool {
+	return o.GetLockUserID() == user.GetID()
+}
+
+func (o *Object) SendLockState(recursive bool) error {
+	world := o.GetWorld()
+	if world == nil {
+		return errors.Errorf("object has no world: %s", o.GetID())
+	}
+
+	objects := map[umid.UMID]universe.Object{o.GetID(): o}
+	if recursive {
+		for childID, child := range o.GetObjects(true) {
+			objects[childID] = child
+		}
+	}
+
+	for objectID, object := range objects {
+		msg := &posbus.LockObjectResponse{ID: objectID, Result: 1, LockOwner: object.GetLockUserID()}
+		if err := world.Send(posbus.WSMessage(msg), true); err != nil {
+			return errors.WithMessagef(err, "failed to send lock state: %s", objectID)
+		}
+	}
+
+	return nil
+}
@@ -64,7 +64,7 @@ type Object struct {
 	messageAccept     atomic.Bool
 	numSendsQueued    atomic.Int64
 
-	lockedBy atomic.Value
+	lock atomic.Pointer[entry.ObjectLock]
 
 	createdAt time.Time
 	updatedAt time.Time
@@ -165,7 +165,6 @@ func (o *Object) Initialize(ctx types.NodeContext) error {
 	o.log = ctx.Logger()
 	o.CFG = ctx.Config()
 	o.numSendsQueued.Store(chanIsClosed)
-	o.lockedBy.Store(umid.Nil)
 
 	newPos := cmath.Transform{Position: *new(cmath.Vec3), Rotation: *new(cmath.Vec3), Scale: *new(cmath.Vec3)}
 	o.actualPosition.Store(&newPos)
@@ -616,6 +615,14 @@ func (o *Object) UpdateSpawnMessage() error {
 		return errors.Errorf("world is empty")
 	}
 
+	msg := posbus.WSMessage(&posbus.AddObjects{Objects: []posbus.ObjectDefinition{*o.GetObjectDefinition()}})
+	o.spawnMsg.Store(msg)
+
+	return nil
+}
+
+// GetObjectDefinition returns the definition of the object sent in the spawn message.
+func (o *Object) GetObjectDefinition() *posbus.ObjectDefinition {
 	parentID := umid.Nil
 	parent := o.GetParent()
 	if parent != nil {
@@ -650,15 +657,10 @@ func (o *Object) UpdateSpawnMessage() error {
 		visible = true
 	}
 
-	mData := make([]posbus.ObjectDefinition, 1)
-	mData[0] = posbus.ObjectDefinition{ID: o.GetID(), ParentID: parentID, AssetType: asset3dID, AssetFormat: assetFormat, Name: o.GetName(), IsEditable: *utils.GetFromAny(
+	return &posbus.ObjectDefinition{ID: o.GetID(), ParentID: parentID, AssetType: asset3dID, AssetFormat: assetFormat, Name: o.GetName(), IsEditable: *utils.GetFromAny(
 		effectiveOptions.Editable, utils.GetPTR(true),
 	),
 		ShowOnMiniMap: *utils.GetFromAny(effectiveOptions.Minimap, &visible), Transform: *o.GetActualTransform()}
-	msg := posbus.WSMessage(&posbus.AddObjects{Objects: mData})
-	o.spawnMsg.Store(msg)
-
-	return nil
 }
 
 func (o *Object) GetSpawnMessage() *websocket.PreparedMessage {
@@ -667,6 +669,9 @@ func (o *Object) GetSpawnMessage() *websocket.PreparedMessage {
 
 func (o *Object) SendSpawnMessage(sendFn func(*websocket.PreparedMessage) error, recursive bool) {
 	sendFn(o.spawnMsg.Load())
+	if lock := o.GetLock(true); lock != nil {
+		sendFn(posbus.WSMessage(&posbus.LockObjectResponse{ID: o.GetID(), Result: 1, LockOwner: lock.UserID}))
+	}
 	//time.Sleep(time.Millisecond * 100)
 	if !recursive {
 		return
@@ -733,22 +738,6 @@ func (o *Object) SetAttributesMsg(kind, name string, msg *websocket.PreparedMess
 	m.Store(name, msg)
 }
 
-func (o *Object) LockUIObject(user universe.User, state uint32) bool {
-	if state == 1 {
-		return o.lockedBy.CompareAndSwap(umid.Nil, user.GetID())
-	} else {
-		return o.lockedBy.CompareAndSwap(user.GetID(), umid.Nil)
-	}
-}
-
-func (o *Object) IsLockedByUser(user universe.User) bool {
-	return o.lockedBy.Load() == user.GetID()
-}
-
-func (o *Object) GetLockUserID() umid.UMID {
-	return o.lockedBy.Load().(umid.UMID)
-}
-
 func (o *Object) GetCreatedAt() time.Time {
 	return o.createdAt
 }
@@ -3,6 +3,7 @@ package object
 import (
 	"context"
 
+	"github.com/barkimedes/go-deepcopy"
 	"github.com/pkg/errors"
 
 	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
@@ -157,10 +158,28 @@ func (oa *objectAttributes) GetEffectiveOptions(attributeID entry.AttributeID) (
 
 func (oa *objectAttributes) Upsert(
 	attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributePayload], updateDB bool,
+) (*entry.AttributePayload, error) {
+	return oa.upsert(nil, attributeID, modifyFn, updateDB)
+}
+
+func (oa *objectAttributes) UpsertByUser(
+	userID umid.UMID, attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributePayload], updateDB bool,
+) (*entry.AttributePayload, error) {
+	return oa.upsert(&userID, attributeID, modifyFn, updateDB)
+}
+
+func (oa *objectAttributes) upsert(
+	changedBy *umid.UMID, attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributePayload], updateDB bool,
 ) (*entry.AttributePayload, error) {
 	oa.object.Mu.Lock()
 	defer oa.object.Mu.Unlock()
 
+	// modifyFn is allowed to change current payload in place
+	var oldValue *entry.AttributeValue
+	if updateDB {
+		oldValue = oa.copyValue(attributeID)
+	}
+
 	payload, err := modifyFn(oa.data[attributeID])
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed to modify payload")
@@ -173,6 +192,12 @@ func (oa *objectAttributes) Upsert(
 		); err != nil {
 			return nil, errors.WithMessagef(err, "failed to upsert object attribute")
 		}
+
+		var newValue *entry.AttributeValue
+		if payload != nil {
+			newValue = payload.Value
+		}
+		oa.addHistory(changedBy, attributeID, oldValue, newValue)
 	}
 
 	oa.data[attributeID] = payload
@@ -190,6 +215,18 @@ func (oa *objectAttributes) Upsert(
 
 func (oa *objectAttributes) UpdateValue(
 	attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributeValue], updateDB bool,
+) (*entry.AttributeValue, error) {
+	return oa.updateValue(nil, attributeID, modifyFn, updateDB)
+}
+
+func (oa *objectAttributes) UpdateValueByUser(
+	userID umid.UMID, attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributeValue], updateDB bool,
+) (*entry.AttributeValue, error) {
+	return oa.updateValue(&userID, attributeID, modifyFn, updateDB)
+}
+
+func (oa *objectAttributes) updateValue(
+	changedBy *umid.UMID, attributeID entry.AttributeID, modifyFn modify.Fn[entry.AttributeValue], updateDB bool,
 ) (*entry.AttributeValue, error) {
 	oa.object.Mu.Lock()
 	defer oa.object.Mu.Unlock()
@@ -202,6 +239,11 @@ func (oa *objectAttributes) UpdateValue(
 		payload = entry.NewAttributePayload(nil, nil)
 	}
 
+	var oldValue *entry.AttributeValue
+	if updateDB {
+		oldValue = oa.copyValue(attributeID)
+	}
+
 	value, err := modifyFn(payload.Value)
 	if err != nil {
 		return nil, errors.WithMessagef(err, "failed to modify value")
@@ -213,6 +255,8 @@ func (oa *objectAttributes) UpdateValue(
 		); err != nil {
 			return nil, errors.WithMessagef(err, "failed to update object attribute value")
 		}
+
+		oa.addHistory(changedBy, attributeID, oldValue, value)
 	}
 
 	payload.Value = value
@@ -267,6 +311,14 @@ func (oa *objectAttributes) UpdateOptions(
 }
 
 func (oa *objectAttributes) Remove(attributeID entry.AttributeID, updateDB bool) (bool, error) {
+	return oa.remove(nil, attributeID, updateDB)
+}
+
+func (oa *objectAttributes) RemoveByUser(userID umid.UMID, attributeID entry.AttributeID, updateDB bool) (bool, error) {
+	return oa.remove(&userID, attributeID, updateDB)
+}
+
+func (oa *objectAttributes) remove(changedBy *umid.UMID, attributeID entry.AttributeID, updateDB bool) (bool, error) {
 	effectiveOptions, ok := oa.GetEffectiveOptions(attributeID)
 	if !ok {
 		return false, nil
@@ -285,6 +337,8 @@ func (oa *objectAttributes) Remove(attributeID entry.AttributeID, updateDB bool)
 		); err != nil {
 			return false, errors.WithMessagef(err, "failed to remove object attribute")
 		}
+
+		oa.addHistory(changedBy, attributeID, oa.copyValue(attributeID), nil)
 	}
 
 	delete(oa.data, attributeID)
@@ -303,12 +357,64 @@ func (oa *objectAttributes) Len() int {
 	return len(oa.data)
 }
 
+// copyValue must be called under the object lock.
+func (oa *objectAttributes) copyValue(attributeID entry.AttributeID) *entry.AttributeValue {
+	payload, ok := oa.data[attributeID]
+	if !ok || payload == nil || payload.Value == nil {
+		return nil
+	}
+
+	value, err := deepcopy.Anything(payload.Value)
+	if err != nil {
+		oa.object.log.Error(
+			errors.WithMessagef(
+				err, "Object attributes: copyValue: failed to copy value: %s: %+v", oa.object.GetID(), attributeID,
+			),
+		)
+		return nil
+	}
+
+	return value.(*entry.AttributeValue)
+}
+
+// addHistory records the change in attribute history, failure doesn't revert the change itself.
+func (oa *objectAttributes) addHistory(
+	changedBy *umid.UMID, attributeID entry.AttributeID, oldValue, newValue *entry.AttributeValue,
+) {
+	history := entry.NewObjectAttributeHistory(
+		entry.NewObjectAttributeID(attributeID, oa.object.GetID()), changedBy, oldValue, newValue,
+	)
+	if err := oa.object.db.GetObjectAttributesHistoryDB().InsertObjectAttributeHistory(
+		oa.object.ctx, history,
+	); err != nil {
+		oa.object.log.Error(
+			errors.WithMessagef(
+				err, "Object attributes: addHistory: failed to insert history: %s: %+v", oa.object.GetID(), attributeID,
+			),
+		)
+	}
+}
+
 func (o *Object) onObjectAttributeChanged(
 	changeType posbus.AttributeChangeType, attributeID entry.AttributeID,
 	value *entry.AttributeValue, effectiveOptions *entry.AttributeOptions,
 ) {
 	go o.calendarOnObjectAttributeChanged(changeType, attributeID, value, effectiveOptions)
 
+	go func() {
+		if err := universe.GetNode().GetAttributeSubscriptions().Publish(
+			entry.NewObjectAttributeSubscriptionID(entry.NewObjectAttributeID(attributeID, o.GetID())),
+			changeType, value,
+		); err != nil {
+			o.log.Error(
+				errors.WithMessagef(
+					err, "Object: onObjectAttributeChanged: failed to publish to subscribers: %s: %+v",
+					o.GetID(), attributeID,
+				),
+			)
+		}
+	}()
+
 	if effectiveOptions == nil {
 		options, ok := o.GetObjectAttributes().GetEffectiveOptions(attributeID)
 		if !ok {
@@ -374,19 +480,9 @@ func (oa *objectAttributes) GetUserRoles(
 	targetID entry.AttributeID,
 	userID umid.UMID,
 ) ([]entry.PermissionsRoleType, error) {
-	var roles []entry.PermissionsRoleType
-	// owner is always considered an admin, TODO: add this to check function
-	if oa.object.GetOwnerID() == userID {
-		roles = append(roles, entry.PermissionAdmin)
-	} else { // we have to lookup through the db user tree
-		userObjectID := entry.NewUserObjectID(userID, oa.object.GetID())
-		isAdmin, err := oa.object.db.GetUserObjectsDB().CheckIsIndirectAdminByID(ctx, userObjectID)
-		if err != nil {
-			return nil, errors.WithMessage(err, "failed to check admin status")
-		}
-		if isAdmin {
-			roles = append(roles, entry.PermissionAdmin)
-		}
+	roles, err := universe.GetNode().GetUserObjects().GetUserRoles(entry.NewUserObjectID(userID, oa.object.GetID()))
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get user roles")
 	}
 	return roles, nil
 }
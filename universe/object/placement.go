@@ -1,9 +1,12 @@
 package object
 
 import (
+	"bytes"
 	"sort"
 
 	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
 
 	"github.com/pkg/errors"
@@ -20,6 +23,7 @@ func (o *Object) GetPlacement(placementMap *entry.ObjectChildPlacement) (positio
 	//fmt.Printf("PLSMAP %+v\n", placementMap)
 
 	var par position_algo.Algo
+	var err error
 	algo := "circular"
 	if placementMap.Algo != nil {
 		algo = *placementMap.Algo
@@ -37,6 +41,17 @@ func (o *Object) GetPlacement(placementMap *entry.ObjectChildPlacement) (positio
 		par = position_algo.NewSpiral(placementMap.Options)
 	case "hexaspiral":
 		par = position_algo.NewHexaSpiral(placementMap.Options)
+	case "slots":
+		par, err = position_algo.NewSlots(placementMap.Options)
+	case "grid":
+		par, err = position_algo.NewGrid(placementMap.Options)
+	case "expression":
+		par, err = position_algo.NewExpression(placementMap.Options)
+	default:
+		err = errors.Errorf("unknown algo: %s", algo)
+	}
+	if err != nil {
+		return nil, errors.WithMessagef(err, "invalid %s placement", algo)
 	}
 	//fmt.Printf("%+v\n", par)
 	return par, nil
@@ -130,9 +145,9 @@ func (o *Object) UpdateChildrenPosition(recursive bool) error {
 	//fmt.Println("pls1", o.GetID())
 	pls := o.GetPlacements()
 	//fmt.Printf("pls1a:%+v : %+v\n", o.GetID(), pls)
-	ChildMap := make(map[umid.UMID][]umid.UMID)
+	ChildMap := make(map[umid.UMID][]universe.Object)
 	for u := range pls {
-		ChildMap[u] = make([]umid.UMID, 0)
+		ChildMap[u] = make([]universe.Object, 0)
 	}
 	//fmt.Println("pls2", o.GetID())
 	o.Children.Mu.RLock()
@@ -144,7 +159,7 @@ func (o *Object) UpdateChildrenPosition(recursive bool) error {
 			if _, ok := pls[objectTypeID]; !ok {
 				objectTypeID = umid.Nil
 			}
-			ChildMap[objectTypeID] = append(ChildMap[objectTypeID], child.GetID())
+			ChildMap[objectTypeID] = append(ChildMap[objectTypeID], child)
 		}
 	}
 	//fmt.Println("pls3", o.GetID(), ChildMap)
@@ -152,23 +167,17 @@ func (o *Object) UpdateChildrenPosition(recursive bool) error {
 		//fmt.Println("pls4", o.GetID(), u)
 		lpm := ChildMap[u]
 		//fmt.Println("pls4a", o.GetID(), lpm)
-		sort.Slice(lpm, func(i, j int) bool { return lpm[i].ClockSequence() < lpm[j].ClockSequence() })
+		sortPlacementChildren(lpm)
 		//fmt.Println("pls4b", o.GetID(), lpm)
-		for i, k := range lpm {
+		for i, child := range lpm {
 			pos, theta := pls[u].CalcPos(o.theta, *o.GetActualTransform(), i, len(lpm))
 			//fmt.Printf(" Position: %o |  %+v\n", o.GetID(), pos)
 
-			child, ok := o.Children.Data[k]
-			//fmt.Println(ok)
-			if !ok {
-				o.log.Errorf("Object: UpdatePosition: failed to get object: %s", k)
-				continue
-			}
 			// TODO: rotation, should optionally come from new option field in parent or be calculated in the algo.
 			// TODO: scale, should optionally come from some new option field in the parent
 			pos.Scale = cmath.Vec3{X: 1, Y: 1, Z: 1}
 			if err := child.SetActualTransform(pos, theta); err != nil {
-				o.log.Errorf("Object: UpdatePosition: failed to update transform: %s", k)
+				o.log.Errorf("Object: UpdatePosition: failed to update transform: %s", child.GetID())
 			}
 
 			if !recursive {
@@ -181,3 +190,80 @@ func (o *Object) UpdateChildrenPosition(recursive bool) error {
 	//fmt.Println("pls10", o.GetID())
 	return nil
 }
+
+// maxPreviewExtra limits positions added to a preview for children which would be added next.
+const maxPreviewExtra = 1000
+
+// PreviewChildrenPosition calculates positions of the children placed by the placement for the object type
+// without moving them, extra positions are added for children which would be added next.
+// The placement of the object is used if placement is nil.
+func (o *Object) PreviewChildrenPosition(
+	objectTypeID umid.UMID, placement *entry.ObjectChildPlacement, extra int,
+) ([]*entry.ObjectChildPosition, error) {
+	if extra < 0 || extra > maxPreviewExtra {
+		return nil, errors.Errorf("extra must be between 0 and %d: %d", maxPreviewExtra, extra)
+	}
+
+	placements := o.GetEffectiveOptions().ChildPlacements
+	if placement == nil {
+		placement = placements[objectTypeID]
+		if placement == nil {
+			return nil, errors.Errorf("placement not found: %s", objectTypeID)
+		}
+	}
+
+	algo, err := o.GetPlacement(placement)
+	if err != nil {
+		return nil, errors.WithMessage(err, "failed to get placement")
+	}
+
+	children := make([]universe.Object, 0)
+	for _, child := range o.GetObjects(false) {
+		if child.GetTransform() != nil {
+			continue
+		}
+		childTypeID := child.GetObjectType().GetID()
+		if _, ok := placements[childTypeID]; !ok {
+			childTypeID = umid.Nil
+		}
+		if childTypeID == objectTypeID {
+			children = append(children, child)
+		}
+	}
+	sortPlacementChildren(children)
+
+	o.Mu.RLock()
+	theta := o.theta
+	o.Mu.RUnlock()
+
+	n := len(children) + extra
+	positions := make([]*entry.ObjectChildPosition, 0, n)
+	for i := 0; i < n; i++ {
+		pos, _ := algo.CalcPos(theta, *o.GetActualTransform(), i, n)
+		pos.Scale = cmath.Vec3{X: 1, Y: 1, Z: 1}
+
+		position := &entry.ObjectChildPosition{
+			Index:     i,
+			Transform: pos,
+		}
+		if i < len(children) {
+			position.ObjectID = utils.GetPTR(children[i].GetID())
+		}
+		positions = append(positions, position)
+	}
+
+	return positions, nil
+}
+
+// sortPlacementChildren orders children by their creation, so a new child is placed after its siblings
+// instead of reshuffling them.
+func sortPlacementChildren(children []universe.Object) {
+	sort.SliceStable(children, func(i, j int) bool {
+		ci, cj := children[i].GetCreatedAt(), children[j].GetCreatedAt()
+		if !ci.Equal(cj) {
+			return ci.Before(cj)
+		}
+		idI, idJ := children[i].GetID(), children[j].GetID()
+		return bytes.Compare(idI[:], idJ[:]) < 0
+	})
+}
@@ -21,6 +21,7 @@ import (
 // @Param objectID path string true "World or object UMID"
 // @Success 200 {object} streamchat.apiChannelToken.Response
 // @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
 // @Router /api/v4/streamchat/{objectID}/token [post]
 func (s *StreamChat) apiChannelToken(c *gin.Context) {
 	object, user, err := s.getRequestContextObjects(c)
@@ -30,6 +31,12 @@ func (s *StreamChat) apiChannelToken(c *gin.Context) {
 		return
 	}
 
+	if s.isMuted(object, user) {
+		err := errors.Errorf("Streamchat: user %s is muted", user.GetID())
+		api.AbortRequest(c, http.StatusForbidden, "user_muted", err, s.log)
+		return
+	}
+
 	channel, err := s.GetChannel(c, object)
 	if err != nil {
 		err = errors.WithMessage(err, "Streamchat: failed to get channel")
@@ -69,6 +76,7 @@ func (s *StreamChat) apiChannelToken(c *gin.Context) {
 // @Param objectID path string true "World or object UMID"
 // @Success 204 ""
 // @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
 // @Router /api/v4/streamchat/{objectID}/join [post]
 func (s *StreamChat) apiChannelJoin(c *gin.Context) {
 	object, user, err := s.getRequestContextObjects(c)
@@ -77,6 +85,12 @@ func (s *StreamChat) apiChannelJoin(c *gin.Context) {
 		return
 	}
 
+	if s.isMuted(object, user) {
+		err := errors.Errorf("Streamchat: user %s is muted", user.GetID())
+		api.AbortRequest(c, http.StatusForbidden, "user_muted", err, s.log)
+		return
+	}
+
 	channel, err := s.GetChannel(c, object)
 	if err != nil {
 		err = errors.WithMessage(err, "Streamchat: failed to get channel")
@@ -168,3 +182,14 @@ func (s *StreamChat) getUserFromContext(ctx *gin.Context, object universe.Object
 	}
 	return user, nil
 }
+
+// Muted users can't join the chat of the world (or the object in it).
+func (s *StreamChat) isMuted(object universe.Object, user universe.User) bool {
+	var worldID *idt.UMID
+	if world := object.GetWorld(); world != nil {
+		id := world.GetID()
+		worldID = &id
+	}
+	_, ok := s.node.GetModeration().GetMute(user.GetID(), worldID)
+	return ok
+}
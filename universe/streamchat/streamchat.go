@@ -214,3 +214,15 @@ func (s *StreamChat) RemoveMember(ctx context.Context, channel *stream.Channel,
 	}
 	return nil
 }
+
+// Remove user as a member from the channel of given object, if the chat service is loaded.
+func (s *StreamChat) RemoveObjectMember(ctx context.Context, object universe.Object, user universe.User) error {
+	if s.client == nil {
+		return nil
+	}
+	channel, err := s.GetChannel(ctx, object)
+	if err != nil {
+		return err
+	}
+	return s.RemoveMember(ctx, channel, user)
+}
@@ -17,11 +17,21 @@ type ObjectTypesFilterPredicateFn func(objectTypeID umid.UMID, objectType Object
 type UserTypesFilterPredicateFn func(userTypeID umid.UMID, userType UserType) bool
 
 type WorldSettings struct {
-	Kind        string               `db:"kind" json:"kind"`
-	Objects     map[string]umid.UMID `db:"objects" json:"objects"`
-	Attributes  map[string]umid.UMID `db:"attributes" json:"attributes"`
-	ObjectTypes map[string]umid.UMID `db:"object_types" json:"object_types"`
-	Effects     map[string]umid.UMID `db:"effects" json:"effects"`
+	Kind        string                  `db:"kind" json:"kind"`
+	Objects     map[string]umid.UMID    `db:"objects" json:"objects"`
+	Attributes  map[string]umid.UMID    `db:"attributes" json:"attributes"`
+	ObjectTypes map[string]umid.UMID    `db:"object_types" json:"object_types"`
+	Effects     map[string]umid.UMID    `db:"effects" json:"effects"`
+	Streaming   *WorldStreamingSettings `db:"streaming" json:"streaming,omitempty"`
+}
+
+// WorldStreamingSettings controls spawning of the world objects for joined users, the nearest objects are sent first.
+type WorldStreamingSettings struct {
+	// Radius around the user within which objects are spawned, objects farther away are spawned as the user moves.
+	// Zero spawns all objects on join.
+	Radius *float32 `db:"radius" json:"radius,omitempty"`
+	// PageSize is the max number of objects spawned for a user at once while moving.
+	PageSize *int `db:"page_size" json:"page_size,omitempty"`
 }
 
 type AssetUserIDPair struct {
@@ -61,6 +71,7 @@ var (
 			Meta                ReservedAttribute
 			Settings            ReservedAttribute
 			TeleportDestination ReservedAttribute
+			Roles               ReservedAttribute
 		}
 		Object struct {
 			Name               ReservedAttribute
@@ -73,16 +84,17 @@ var (
 			NewsFeedItems      ReservedAttribute
 			PortalDockFace     ReservedAttribute
 			Events             ReservedAttribute
+			Tags               ReservedAttribute
 		}
 		Kusama struct {
 			User struct {
 				Wallet ReservedAttribute
 			}
-			Challenges ReservedAttribute
 		}
 		User struct {
-			HighFive ReservedAttribute
-			Role     ReservedAttribute
+			HighFive     ReservedAttribute
+			Role         ReservedAttribute
+			OIDCIdentity ReservedAttribute
 		}
 	}{
 		Node: struct {
@@ -116,6 +128,7 @@ var (
 			Meta                ReservedAttribute
 			Settings            ReservedAttribute
 			TeleportDestination ReservedAttribute
+			Roles               ReservedAttribute
 		}{
 			Meta: ReservedAttribute{
 				Name: "world_meta",
@@ -127,6 +140,10 @@ var (
 				Name: "teleport",
 				Key:  "DestinationWorldID",
 			},
+			Roles: ReservedAttribute{
+				Name: "world_roles",
+				Key:  "roles",
+			},
 		},
 		Object: struct {
 			Name               ReservedAttribute
@@ -139,6 +156,7 @@ var (
 			NewsFeedItems      ReservedAttribute
 			PortalDockFace     ReservedAttribute
 			Events             ReservedAttribute
+			Tags               ReservedAttribute
 		}{
 			Name: ReservedAttribute{
 				Name: "name",
@@ -180,12 +198,15 @@ var (
 				Name: "events",
 				Key:  "",
 			},
+			Tags: ReservedAttribute{
+				Name: "tags",
+				Key:  "tags",
+			},
 		},
 		Kusama: struct {
 			User struct {
 				Wallet ReservedAttribute
 			}
-			Challenges ReservedAttribute
 		}{
 			User: struct {
 				Wallet ReservedAttribute
@@ -195,14 +216,11 @@ var (
 					Key:  "wallet",
 				},
 			},
-			Challenges: ReservedAttribute{
-				Name: "challenge_store",
-				Key:  "challenges",
-			},
 		},
 		User: struct {
-			HighFive ReservedAttribute
-			Role     ReservedAttribute
+			HighFive     ReservedAttribute
+			Role         ReservedAttribute
+			OIDCIdentity ReservedAttribute
 		}{
 			HighFive: ReservedAttribute{
 				Name: "high_five",
@@ -212,6 +230,10 @@ var (
 				Name: "role",
 				Key:  "role",
 			},
+			OIDCIdentity: ReservedAttribute{
+				Name: "oidc_identity",
+				Key:  "identities",
+			},
 		},
 	}
 )
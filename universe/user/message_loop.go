@@ -9,6 +9,7 @@ import (
 	"github.com/pkg/errors"
 
 	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
 	"github.com/momentum-xyz/ubercontroller/universe"
 	"github.com/momentum-xyz/ubercontroller/utils"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
@@ -43,10 +44,20 @@ func (u *User) OnMessage(buf []byte) error {
 		}
 	case posbus.TypeLockObject:
 		return u.LockObject(msg.(*posbus.LockObject))
+	case posbus.TypeLockObjectSubtree:
+		return u.LockObjectSubtree(msg.(*posbus.LockObjectSubtree))
 	case posbus.TypeUnlockObject:
 		return u.UnlockObject(msg.(*posbus.UnlockObject))
 	case posbus.TypeHighFive:
 		return u.HandleHighFive(msg.(*posbus.HighFive))
+	case posbus.TypeSubscribeAttribute:
+		return u.SubscribeAttribute(msg.(*posbus.SubscribeAttribute))
+	case posbus.TypeUnsubscribeAttribute:
+		return u.UnsubscribeAttribute(msg.(*posbus.UnsubscribeAttribute))
+	case posbus.TypeEditJournalAction:
+		return u.HandleEditJournalAction(msg.(*posbus.EditJournalAction))
+	case posbus.TypeSetObjectsLOD:
+		return u.SetObjectsLOD(msg.(*posbus.SetObjectsLOD))
 	default:
 		return errors.Errorf("unknown message: %d", msg.GetType())
 	}
@@ -92,7 +103,51 @@ func (u *User) UpdateObjectTransform(msg *posbus.ObjectTransform) error {
 	if !object.IsLockedByUser(u) {
 		return errors.Errorf("object is not locked by user: %s", u.GetID())
 	}
-	return object.SetTransform(utils.GetPTR(msg.Transform), true)
+
+	editJournal := universe.GetNode().GetEditJournal()
+	change, err := editJournal.Snapshot(entry.EditTarget{ObjectID: msg.ID, Kind: entry.EditTargetTransform})
+	if err != nil {
+		return errors.WithMessage(err, "failed to snapshot transform")
+	}
+	if err := object.SetTransform(utils.GetPTR(msg.Transform), true); err != nil {
+		return errors.WithMessage(err, "failed to set transform")
+	}
+	if world := object.GetWorld(); world != nil {
+		if err := editJournal.Record(u.GetID(), world.GetID(), change); err != nil {
+			return errors.WithMessage(err, "failed to record transform edit")
+		}
+	}
+
+	return nil
+}
+
+// HandleEditJournalAction applies the action to the edit journal of the user in the current world.
+func (u *User) HandleEditJournalAction(msg *posbus.EditJournalAction) error {
+	world := u.GetWorld()
+	if world == nil {
+		return errors.New("user is not in a world")
+	}
+
+	editJournal := universe.GetNode().GetEditJournal()
+	switch msg.Action {
+	case posbus.EditJournalActionBegin:
+		editJournal.Begin(u.GetID(), world.GetID())
+	case posbus.EditJournalActionCommit:
+		editJournal.Commit(u.GetID(), world.GetID())
+	case posbus.EditJournalActionUndo:
+		// conflicts are sent to the user by the journal
+		if _, _, err := editJournal.Undo(u.GetID(), world.GetID()); err != nil {
+			return errors.WithMessage(err, "failed to undo")
+		}
+	case posbus.EditJournalActionRedo:
+		if _, _, err := editJournal.Redo(u.GetID(), world.GetID()); err != nil {
+			return errors.WithMessage(err, "failed to redo")
+		}
+	default:
+		return errors.Errorf("unknown edit journal action: %d", msg.Action)
+	}
+
+	return nil
 }
 
 func (u *User) Teleport(target umid.UMID) error {
@@ -172,48 +227,55 @@ func (u *User) SignalsHandler(s *posbus.Signal) error {
 //}
 
 func (u *User) LockObject(lock *posbus.LockObject) error {
-	objectId := lock.ID
-	object, ok := u.GetWorld().GetObjectFromAllObjects(objectId)
+	return u.lockObject(lock.ID, false)
+}
+
+func (u *User) LockObjectSubtree(lock *posbus.LockObjectSubtree) error {
+	return u.lockObject(lock.ID, true)
+}
+
+// lockObject acquires or renews the edit lease of the user, clients renew it by locking again before it expires.
+func (u *User) lockObject(objectID umid.UMID, subtree bool) error {
+	object, ok := u.GetWorld().GetObjectFromAllObjects(objectID)
 	if !ok {
-		return errors.Errorf("object not found: %s", objectId)
+		return errors.Errorf("object not found: %s", objectID)
 	}
 
-	isAdmin, err := u.IsAdminOfObject(objectId)
+	isAdmin, err := u.IsAdminOfObject(objectID)
 	if err != nil {
 		return errors.WithMessage(err, "failed to check if user is admin of object")
 	}
 	if !isAdmin {
-		return errors.Errorf("user is not admin of object: %s", objectId)
+		return errors.Errorf("user is not admin of object: %s", objectID)
 	}
 
-	result := object.LockUIObject(u, 1)
-	if result {
-		return u.GetWorld().Send(
-			posbus.WSMessage(&posbus.LockObjectResponse{ID: objectId, Result: 1, LockOwner: u.GetID()}),
-			true,
-		)
+	current := object.GetLock(false)
+	lock, ok := object.Lock(u.GetID(), subtree)
+	if !ok {
+		return u.Send(posbus.WSMessage(&posbus.LockObjectResponse{ID: objectID, Result: 0, LockOwner: lock.UserID}))
+	}
+	// renewal doesn't change the lock state
+	if current != nil && current.UserID == lock.UserID && current.Subtree == lock.Subtree {
+		return nil
 	}
-	lockOwner := object.GetLockUserID()
-	return u.Send(posbus.WSMessage(&posbus.LockObjectResponse{ID: objectId, Result: 0, LockOwner: lockOwner}))
+
+	return object.SendLockState(lock.Subtree)
 }
 
 func (u *User) UnlockObject(lock *posbus.UnlockObject) error {
-	objectId := lock.ID
-	object, ok := u.GetWorld().GetObjectFromAllObjects(objectId)
+	objectID := lock.ID
+	object, ok := u.GetWorld().GetObjectFromAllObjects(objectID)
 	if !ok {
-		return errors.Errorf("object not found: %s", objectId)
+		return errors.Errorf("object not found: %s", objectID)
 	}
 
-	result := object.LockUIObject(u, 0)
-
-	if result {
-		return u.GetWorld().Send(
-			posbus.WSMessage(&posbus.LockObjectResponse{ID: objectId, Result: 1, LockOwner: umid.Nil}),
-			true,
-		)
+	released, ok := object.Unlock(u.GetID(), false)
+	if !ok {
+		lockOwner := object.GetLockUserID()
+		return u.Send(posbus.WSMessage(&posbus.LockObjectResponse{ID: objectID, Result: 0, LockOwner: lockOwner}))
 	}
-	lockOwner := object.GetLockUserID()
-	return u.Send(posbus.WSMessage(&posbus.LockObjectResponse{ID: objectId, Result: 0, LockOwner: lockOwner}))
+
+	return object.SendLockState(released.Subtree)
 }
 
 func (u *User) HandleHighFive(m *posbus.HighFive) error {
@@ -223,6 +285,12 @@ func (u *User) HandleHighFive(m *posbus.HighFive) error {
 	}
 
 	world := u.GetWorld()
+	worldID := world.GetID()
+	if _, ok := universe.GetNode().GetModeration().GetMute(u.GetID(), &worldID); ok {
+		u.Send(posbus.WSMessage(&posbus.Notification{NotifyType: posbus.NotificationTextMessage, Value: "You are muted"}))
+		return nil
+	}
+
 	_, ok := world.GetUser(targetID, false)
 	if !ok {
 		u.Send(posbus.WSMessage(&posbus.Notification{NotifyType: posbus.NotificationTextMessage, Value: "Target user not found"}))
@@ -249,6 +317,50 @@ func (u *User) HandleHighFive(m *posbus.HighFive) error {
 	return nil
 }
 
+func (u *User) SetObjectsLOD(msg *posbus.SetObjectsLOD) error {
+	world := u.GetWorld()
+	if world == nil {
+		return errors.New("user is not in a world")
+	}
+
+	if err := world.SetObjectsLOD(u.GetID(), msg.Group, msg.Level); err != nil {
+		return errors.WithMessagef(err, "failed to set objects lod: %s", msg.Group)
+	}
+	return nil
+}
+
+func (u *User) SubscribeAttribute(m *posbus.SubscribeAttribute) error {
+	subscriptionID := newAttributeSubscriptionID(
+		m.PluginID, m.AttributeName, m.TargetType, m.TargetID, m.UserID,
+	)
+	if err := universe.GetNode().GetAttributeSubscriptions().Subscribe(u, subscriptionID); err != nil {
+		return errors.WithMessagef(err, "failed to subscribe to attribute: %+v", subscriptionID)
+	}
+	return nil
+}
+
+func (u *User) UnsubscribeAttribute(m *posbus.UnsubscribeAttribute) error {
+	subscriptionID := newAttributeSubscriptionID(
+		m.PluginID, m.AttributeName, m.TargetType, m.TargetID, m.UserID,
+	)
+	universe.GetNode().GetAttributeSubscriptions().Unsubscribe(u, subscriptionID)
+	return nil
+}
+
+func newAttributeSubscriptionID(
+	pluginID umid.UMID, attributeName string, targetType string, targetID umid.UMID, userID umid.UMID,
+) entry.AttributeSubscriptionID {
+	subscriptionID := entry.AttributeSubscriptionID{
+		AttributeID: entry.NewAttributeID(pluginID, attributeName),
+		TargetType:  entry.AttributeSubscriptionTargetType(targetType),
+		TargetID:    targetID,
+	}
+	if subscriptionID.TargetType == entry.ObjectUserAttributeSubscriptionTargetType {
+		subscriptionID.UserID = userID
+	}
+	return subscriptionID
+}
+
 func hexToAddress(s string) ([]byte, error) {
 	b, err := hex.DecodeString(s[2:])
 	if err != nil {
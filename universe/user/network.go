@@ -8,6 +8,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/zakaria-chahboun/cute"
 
+	"github.com/momentum-xyz/ubercontroller/universe"
 	"github.com/momentum-xyz/ubercontroller/utils/umid"
 )
 
@@ -192,6 +193,10 @@ func (u *User) close(needToRemoveFromWorld bool) error {
 	//close(u.send)
 	u.conn.Close()
 
+	universe.GetNode().GetAttributeSubscriptions().UnsubscribeAll(u)
+	universe.GetNode().GetUserSessions().RemoveConnection(u)
+	universe.GetNode().GetUserRelations().UserDisconnected(u)
+
 	// then remove from world is necessary
 	if needToRemoveFromWorld {
 		world := u.GetWorld()
@@ -105,6 +105,22 @@ func (u *User) GetUserDefinition() *posbus.UserData {
 	if u.profile.AvatarHash != nil {
 		d.Avatar = *u.profile.AvatarHash
 	}
+	if u.profile.Avatar != nil {
+		d.AvatarDefinition = toAvatarDefinition(u.profile.Avatar)
+	}
+	return d
+}
+
+func toAvatarDefinition(avatar *entry.UserAvatar) *posbus.AvatarDefinition {
+	d := &posbus.AvatarDefinition{
+		Model:        avatar.ModelAsset3dID,
+		Colors:       avatar.Colors,
+		Accessories:  make([]posbus.AvatarAccessory, len(avatar.Accessories)),
+		AnimationSet: avatar.AnimationSet,
+	}
+	for i, accessory := range avatar.Accessories {
+		d.Accessories[i] = posbus.AvatarAccessory{Slot: accessory.Slot, Model: accessory.Asset3dID}
+	}
 	return d
 }
 
@@ -196,11 +212,19 @@ func (u *User) DeleteTemporaryUser(uid umid.UMID) error {
 	u.mu.Lock()
 	defer u.mu.Unlock()
 
-	u.log.Infof("Deleting temp user: %s", u.GetID())
+	guestUserTypeID, err := common.GetGuestUserTypeID()
+	if err != nil {
+		return errors.WithMessage(err, "failed to get guestUserTypeID")
+	}
 
-	if err := u.db.GetUsersDB().RemoveUserByID(u.ctx, uid); err != nil {
+	// user could be upgraded to a registered user in meantime
+	removed, err := u.db.GetUsersDB().RemoveUserByIDAndUserTypeID(u.ctx, uid, guestUserTypeID)
+	if err != nil {
 		return errors.WithMessage(err, "failed to delete temporary user by id")
 	}
+	if removed {
+		u.log.Infof("Deleted temp user: %s", u.GetID())
+	}
 
 	return nil
 }
@@ -212,6 +236,13 @@ func (u *User) GetProfile() *entry.UserProfile {
 	return u.profile
 }
 
+func (u *User) SetProfile(profile *entry.UserProfile) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+
+	u.profile = profile
+}
+
 func (u *User) Initialize(ctx types.LoggerContext) error {
 	u.ctx = ctx
 	u.log = ctx.Logger()
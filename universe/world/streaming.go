@@ -0,0 +1,242 @@
+package world
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/cmath"
+	"github.com/momentum-xyz/ubercontroller/pkg/posbus"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+const (
+	streamingDefaultRadius   = 250
+	streamingDefaultPageSize = 200
+	// user has to move this far before objects are looked up again
+	streamingMoveThreshold = 5
+)
+
+// spawnStream is the state of spawning the world objects for a user.
+type spawnStream struct {
+	mu      sync.Mutex
+	user    universe.User
+	spawned map[umid.UMID]bool
+	// requested level of detail by group
+	lod map[string]uint32
+
+	position cmath.Vec3
+	// objects in the radius are left to spawn
+	pending bool
+}
+
+func (w *World) getStreamingSettings() (float64, int) {
+	radius, pageSize := float64(streamingDefaultRadius), streamingDefaultPageSize
+
+	settings := w.GetSettings()
+	if settings == nil || settings.Streaming == nil {
+		return radius, pageSize
+	}
+	if settings.Streaming.Radius != nil && *settings.Streaming.Radius >= 0 {
+		radius = float64(*settings.Streaming.Radius)
+	}
+	if settings.Streaming.PageSize != nil && *settings.Streaming.PageSize > 0 {
+		pageSize = *settings.Streaming.PageSize
+	}
+
+	return radius, pageSize
+}
+
+// startSpawnStream spawns the objects around the user position, the rest is spawned by streamObjects.
+func (w *World) startSpawnStream(user universe.User) {
+	stream := &spawnStream{
+		user:    user,
+		spawned: make(map[umid.UMID]bool),
+		lod:     make(map[string]uint32),
+	}
+	w.streams.Store(user.GetID(), stream)
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	w.spawnNearestObjects(stream, 0)
+}
+
+func (w *World) stopSpawnStream(userID umid.UMID) {
+	w.streams.Remove(userID)
+}
+
+// streamObjects spawns the next page of objects nearest to each user who moved since the last time.
+func (w *World) streamObjects() {
+	_, pageSize := w.getStreamingSettings()
+
+	w.streams.Mu.RLock()
+	streams := make([]*spawnStream, 0, len(w.streams.Data))
+	for _, stream := range w.streams.Data {
+		streams = append(streams, stream)
+	}
+	w.streams.Mu.RUnlock()
+
+	for _, stream := range streams {
+		stream.mu.Lock()
+		position := stream.user.GetTransform().Position
+		if stream.pending || cmath.Distance(&position, &stream.position) >= streamingMoveThreshold {
+			w.spawnNearestObjects(stream, pageSize)
+		}
+		stream.mu.Unlock()
+	}
+}
+
+// spawnNearestObjects sends up to limit not yet spawned objects in the radius around the user, nearest first.
+// Objects without a 3d asset are always spawned, parents are spawned before their children.
+// Zero limit sends all objects in the radius.
+func (w *World) spawnNearestObjects(stream *spawnStream, limit int) {
+	radius, _ := w.getStreamingSettings()
+	position := stream.user.GetTransform().Position
+
+	type candidate struct {
+		object   universe.Object
+		distance float64
+	}
+	candidates := make([]candidate, 0)
+	for id, object := range w.GetAllObjects() {
+		if stream.spawned[id] || !object.GetEnabled() {
+			continue
+		}
+
+		distance := 0.0
+		if hasAsset3d(object) {
+			distance = cmath.Distance(&position, &object.GetActualTransform().Position)
+		}
+		if radius > 0 && distance > radius {
+			continue
+		}
+		candidates = append(candidates, candidate{object: object, distance: distance})
+	}
+
+	sort.Slice(candidates, func(i, j int) bool {
+		if candidates[i].distance != candidates[j].distance {
+			return candidates[i].distance < candidates[j].distance
+		}
+		return candidates[i].object.GetID().String() < candidates[j].object.GetID().String()
+	})
+
+	objects := make([]universe.Object, 0, len(candidates))
+	i := 0
+	for ; i < len(candidates) && (limit <= 0 || len(objects) < limit); i++ {
+		objects = append(objects, w.getUnspawnedAncestors(stream, candidates[i].object)...)
+	}
+
+	stream.position = position
+	stream.pending = i < len(candidates)
+
+	if err := w.sendSpawnStream(stream, objects); err != nil {
+		w.log.Error(
+			errors.WithMessagef(err, "World: spawnNearestObjects: failed to spawn objects: %s", stream.user.GetID()),
+		)
+	}
+}
+
+func hasAsset3d(object universe.Object) bool {
+	if object.GetAsset3D() != nil {
+		return true
+	}
+	objectType := object.GetObjectType()
+	return objectType != nil && objectType.GetAsset3d() != nil
+}
+
+// getUnspawnedAncestors marks the object with its not yet spawned ancestors as spawned and returns them,
+// the farthest ancestor first.
+func (w *World) getUnspawnedAncestors(stream *spawnStream, object universe.Object) []universe.Object {
+	var objects []universe.Object
+	for object != nil && object.GetID() != w.GetID() && !stream.spawned[object.GetID()] {
+		stream.spawned[object.GetID()] = true
+		objects = append(objects, object)
+		object = object.GetParent()
+	}
+
+	for i, j := 0, len(objects)-1; i < j; i, j = i+1, j-1 {
+		objects[i], objects[j] = objects[j], objects[i]
+	}
+
+	return objects
+}
+
+// sendSpawnStream sends one spawn message with the objects, followed by their locks and auto attributes.
+func (w *World) sendSpawnStream(stream *spawnStream, objects []universe.Object) error {
+	if len(objects) == 0 {
+		return nil
+	}
+
+	definitions := make([]posbus.ObjectDefinition, 0, len(objects))
+	for _, object := range objects {
+		definitions = append(definitions, *w.getStreamObjectDefinition(stream, object))
+	}
+	if err := stream.user.SendDirectly(posbus.WSMessage(&posbus.AddObjects{Objects: definitions})); err != nil {
+		return errors.WithMessage(err, "failed to send spawn message")
+	}
+
+	for _, object := range objects {
+		if lock := object.GetLock(true); lock != nil {
+			stream.user.SendDirectly(
+				posbus.WSMessage(&posbus.LockObjectResponse{ID: object.GetID(), Result: 1, LockOwner: lock.UserID}),
+			)
+		}
+		object.SendAllAutoAttributes(stream.user.SendDirectly, false)
+	}
+
+	return nil
+}
+
+// getStreamObjectDefinition returns the object definition with the asset of the level of detail the user requested.
+func (w *World) getStreamObjectDefinition(stream *spawnStream, object universe.Object) *posbus.ObjectDefinition {
+	definition := object.GetObjectDefinition()
+
+	options := object.GetEffectiveOptions()
+	if options == nil || options.LOD == nil {
+		return definition
+	}
+	level, ok := stream.lod[options.LOD.Group]
+	if !ok || level == 0 || int(level) > len(options.LOD.Levels) {
+		return definition
+	}
+	definition.AssetType = options.LOD.Levels[level-1].Asset3dID
+
+	return definition
+}
+
+// SetObjectsLOD switches spawned objects of the level of detail group to the level for the user.
+func (w *World) SetObjectsLOD(userID umid.UMID, group string, level uint32) error {
+	stream, ok := w.streams.Load(userID)
+	if !ok {
+		return errors.Errorf("user not found: %s", userID)
+	}
+
+	stream.mu.Lock()
+	defer stream.mu.Unlock()
+
+	if level == 0 {
+		delete(stream.lod, group)
+	} else {
+		stream.lod[group] = level
+	}
+
+	definitions := make([]posbus.ObjectDefinition, 0)
+	for id := range stream.spawned {
+		object, ok := w.GetObjectFromAllObjects(id)
+		if !ok {
+			continue
+		}
+		if options := object.GetEffectiveOptions(); options == nil || options.LOD == nil || options.LOD.Group != group {
+			continue
+		}
+		definitions = append(definitions, *w.getStreamObjectDefinition(stream, object))
+	}
+	if len(definitions) == 0 {
+		return nil
+	}
+
+	return stream.user.SendDirectly(posbus.WSMessage(&posbus.AddObjects{Objects: definitions}))
+}
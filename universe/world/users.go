@@ -35,6 +35,13 @@ func (w *World) AddUser(user universe.User, updateDB bool) error {
 		}
 	}()
 
+	worldID := w.GetID()
+	if ban, ok := universe.GetNode().GetModeration().GetBan(user.GetID(), &worldID); ok {
+		// keep the connection, user can teleport to another world
+		user.SendDirectly(posbus.WSMessage(&posbus.Signal{Value: posbus.SignalBanned}))
+		return errors.Errorf("user %s is banned from world %s: %s", user.GetID(), worldID, ban.BanID)
+	}
+
 	exUser, ok := w.Users.Load(user.GetID())
 
 	if ok {
@@ -100,8 +107,13 @@ func (w *World) AddUser(user universe.User, updateDB bool) error {
 		true,
 	)
 
-	err = w.initializeUI(user)
-	return err
+	if err = w.initializeUI(user); err != nil {
+		return err
+	}
+
+	universe.GetNode().GetUserRelations().UserEnteredWorld(user, w)
+
+	return nil
 }
 
 func (w *World) RemoveUser(user universe.User, updateDB bool) (bool, error) {
@@ -150,18 +162,14 @@ func (w *World) noLockRemoveUser(user universe.User, updateDB bool) (bool, error
 	}
 
 	delete(w.Users.Data, user.GetID())
+	w.stopSpawnStream(user.GetID())
 
-	// clean up all locks hold by this user,
-	// temporarily here.
-	w.allObjects.Mu.RLock()
-	defer w.allObjects.Mu.RUnlock()
-
-	for _, child := range w.allObjects.Data {
-		if child.LockUIObject(user, 0) {
-			w.Send(
-				posbus.WSMessage(&posbus.LockObjectResponse{ID: child.GetID(), Result: 0, LockOwner: umid.Nil}),
-				true,
-			)
+	// release all locks held by this user
+	for _, child := range w.GetAllObjects() {
+		if lock, ok := child.Unlock(user.GetID(), false); ok {
+			if err := child.SendLockState(lock.Subtree); err != nil {
+				w.log.Error(errors.WithMessagef(err, "World: RemoveUser: failed to send lock state: %s", child.GetID()))
+			}
 		}
 	}
 
@@ -192,12 +200,11 @@ func (w *World) initializeUI(user universe.User) error {
 	//	user.ReleaseSendBuffer()
 	//}()
 
-	w.SendSpawnMessage(user.SendDirectly, true)
+	// objects around the user go first, the rest is streamed as the user moves
+	w.SendSpawnMessage(user.SendDirectly, false)
+	w.SendAllAutoAttributes(user.SendDirectly, false)
+	w.startSpawnStream(user)
 	w.log.Infof("Sent Spawn: %+v\n", user.GetID())
-	//time.Sleep(1 * time.Second)
-
-	w.SendAllAutoAttributes(user.SendDirectly, true)
-	w.log.Infof("Sent Textures: %+v\n", user.GetID())
 	user.ReleaseSendBuffer()
 
 	w.SendUsersSpawnMessage(user)
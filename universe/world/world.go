@@ -31,6 +31,9 @@ const MaxPosUpdateInterval = 60 * 5
 
 const PosUpdateInterval = 500 * time.Millisecond
 
+// StreamingInterval is how often objects around moving users are spawned.
+const StreamingInterval = time.Second
+
 type World struct {
 	*object.Object
 	ctx              context.Context
@@ -45,16 +48,12 @@ type World struct {
 	metaData            Metadata
 	settings            atomic.Pointer[universe.WorldSettings]
 	allObjects          *generic.SyncMap[umid.UMID, universe.Object]
+	streams             *generic.SyncMap[umid.UMID, *spawnStream]
 	calendar            *calendar.Calendar
 	skyBoxMsg           atomic.Pointer[websocket.PreparedMessage]
 	lastPosUpdate       int64
 }
 
-func (w *World) LockUIObject(user universe.User, state uint32) bool {
-	//TODO implement me
-	panic("implement me")
-}
-
 func (w *World) GetTotalStake() uint8 {
 	//TODO implement me
 	panic("implement me")
@@ -94,6 +93,7 @@ func NewWorld(id umid.UMID, db database.DB, media *media.Media) *World {
 	world := &World{
 		db:         db,
 		allObjects: generic.NewSyncMap[umid.UMID, universe.Object](0),
+		streams:    generic.NewSyncMap[umid.UMID, *spawnStream](0),
 		media:      media,
 	}
 	world.Object = object.NewObject(id, db, world, media)
@@ -164,10 +164,14 @@ func (w *World) Run() error {
 		}()
 		go w.calendar.Run()
 		ticker := time.NewTicker(PosUpdateInterval)
+		locksTicker := time.NewTicker(object.LockSweepInterval)
+		streamingTicker := time.NewTicker(StreamingInterval)
 
 		defer func() {
 			w.calendar.Stop()
 			ticker.Stop()
+			locksTicker.Stop()
+			streamingTicker.Stop()
 			if err := w.stopObjects(); err != nil {
 				w.log.Error(errors.WithMessagef(err, "World: Run: failed to stop objects: %s", w.GetID()))
 			}
@@ -177,6 +181,10 @@ func (w *World) Run() error {
 			select {
 			case <-ticker.C:
 				go w.broadcastPositions()
+			case now := <-locksTicker.C:
+				w.releaseExpiredLocks(now)
+			case <-streamingTicker.C:
+				w.streamObjects()
 			case <-w.ctx.Done():
 				return
 			}
@@ -191,6 +199,19 @@ func (w *World) Stop() error {
 	return nil
 }
 
+// releaseExpiredLocks releases locks of clients which stopped renewing them.
+func (w *World) releaseExpiredLocks(now time.Time) {
+	for _, object := range w.GetAllObjects() {
+		lock, ok := object.UnlockExpired(now)
+		if !ok {
+			continue
+		}
+		if err := object.SendLockState(lock.Subtree); err != nil {
+			w.log.Error(errors.WithMessagef(err, "World: releaseExpiredLocks: failed to send lock state: %s", object.GetID()))
+		}
+	}
+}
+
 func (w *World) runObjects() error {
 	w.allObjects.Mu.RLock()
 	defer w.allObjects.Mu.RUnlock()
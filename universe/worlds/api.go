@@ -20,6 +20,7 @@ func (w *Worlds) RegisterAPI(r *gin.Engine) {
 			{
 				worlds.GET("", w.apiWorldsGet)
 				worlds.GET("/explore/search", w.apiWorldsSearchWorlds)
+				worlds.POST("/import", middleware.AuthorizeNodeAdmin(w.log), w.apiWorldsImport)
 
 				world := worlds.Group("/:objectID")
 				{
@@ -27,11 +28,30 @@ func (w *Worlds) RegisterAPI(r *gin.Engine) {
 					world.GET("/explore", w.apiWorldsGetObjectsWithChildren)
 					world.GET("/online-users", w.apiGetOnlineUsers)
 					world.PATCH("", w.apiWorldsUpdateByID)
+					world.GET("/roles", w.apiWorldsGetRoles)
+					world.GET("/lod-groups", w.apiWorldsGetLODGroups)
 
 					authorizedAdmin := world.Group("", middleware.AuthorizeAdmin(w.log))
 					{
 						authorizedAdmin.POST("/fly-to-me", w.apiWorldsFlyToMe)
 						authorizedAdmin.POST("/teleport-user", w.apiWorldsTeleportUser)
+						authorizedAdmin.PUT("/roles/:roleName", w.apiWorldsSetRole)
+						authorizedAdmin.DELETE("/roles/:roleName", w.apiWorldsRemoveRole)
+
+						authorizedAdmin.POST("/kick", w.apiWorldsKickUser)
+						authorizedAdmin.GET("/bans", w.apiWorldsGetBans)
+						authorizedAdmin.POST("/bans", w.apiWorldsBanUser)
+						authorizedAdmin.DELETE("/bans/:banID", w.apiWorldsRemoveBan)
+						authorizedAdmin.GET("/mutes", w.apiWorldsGetMutes)
+						authorizedAdmin.POST("/mutes", w.apiWorldsMuteUser)
+						authorizedAdmin.DELETE("/mutes/:muteID", w.apiWorldsRemoveMute)
+
+						authorizedAdmin.GET("/export", w.apiWorldsExport)
+
+						authorizedAdmin.DELETE("", w.apiWorldsRemove)
+						authorizedAdmin.GET("/trash", w.apiWorldsGetTrash)
+						authorizedAdmin.POST("/trash/:trashObjectID/restore", w.apiWorldsRestoreTrashItem)
+						authorizedAdmin.DELETE("/trash/:trashObjectID", w.apiWorldsPurgeTrashItem)
 					}
 				}
 			}
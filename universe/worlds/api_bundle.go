@@ -0,0 +1,144 @@
+package worlds
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Export world
+// @Description Returns a versioned zip archive with the world objects tree, their options and attributes,
+// @Description object types, 2D/3D assets and media files referenced by them
+// @Tags worlds
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Produce application/zip
+// @Success 200 {file} binary
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/export [get]
+func (w *Worlds) apiWorldsExport(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsExport: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	world, ok := w.GetWorld(worldID)
+	if !ok {
+		err := errors.Errorf("Worlds: apiWorldsExport: world not found: %s", worldID)
+		api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, w.log)
+		return
+	}
+
+	var archive bytes.Buffer
+	if err := w.exportWorld(world, &archive); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsExport: failed to export world")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_export_world", err, w.log)
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", "world-"+worldID.String()+".zip"))
+	c.Data(http.StatusOK, "application/zip", archive.Bytes())
+}
+
+// @Summary Import world
+// @Description Creates a world owned by the current user from an archive made by world export.
+// @Description Strategy "new" gives the world and its objects new umids, "keep" keeps them and fails if any of them
+// @Description exists (trashed included), "replace" keeps them and replaces the existing world,
+// @Description which is kept if the import fails.
+// @Description Attributes of plugins which are not installed on the node are skipped.
+// @Tags worlds
+// @Security Bearer
+// @Accept multipart/form-data
+// @Param file formData file true "world archive"
+// @Param query query worlds.apiWorldsImport.InQuery false "query params"
+// @Success 201 {object} worlds.apiWorldsImport.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Failure 500 {object} api.HTTPError
+// @Router /api/v4/worlds/import [post]
+func (w *Worlds) apiWorldsImport(c *gin.Context) {
+	type InQuery struct {
+		Strategy string `form:"strategy,default=new" binding:"oneof=new keep replace"`
+	}
+	var inQuery InQuery
+
+	if err := c.ShouldBindQuery(&inQuery); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to bind query")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_query", err, w.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+
+	archiveFile, err := c.FormFile("file")
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to read file")
+		api.AbortRequest(c, http.StatusBadRequest, "failed_to_read", err, w.log)
+		return
+	}
+
+	openedFile, err := archiveFile.Open()
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to open file")
+		api.AbortRequest(c, http.StatusBadRequest, "failed_to_open", err, w.log)
+		return
+	}
+
+	defer openedFile.Close()
+
+	archive, err := zip.NewReader(openedFile, archiveFile.Size)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to open archive")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_archive", err, w.log)
+		return
+	}
+
+	bundle, err := readWorldBundle(archive)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to read bundle")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_bundle", err, w.log)
+		return
+	}
+
+	worldID, skipped, err := w.importWorld(archive, bundle, userID, inQuery.Strategy)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsImport: failed to import world")
+		if errors.Is(err, errWorldImportConflict) {
+			api.AbortRequest(c, http.StatusConflict, "world_exists", err, w.log)
+			return
+		}
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_import_world", err, w.log)
+		return
+	}
+
+	type Out struct {
+		WorldID           umid.UMID           `json:"world_id"`
+		SkippedAttributes []entry.AttributeID `json:"skipped_attributes"`
+	}
+	out := Out{
+		WorldID:           worldID,
+		SkippedAttributes: skipped,
+	}
+	if out.SkippedAttributes == nil {
+		out.SkippedAttributes = []entry.AttributeID{}
+	}
+
+	c.JSON(http.StatusCreated, out)
+}
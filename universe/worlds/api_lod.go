@@ -0,0 +1,75 @@
+package worlds
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Get world level of detail groups
+// @Description Returns objects of the world by level of detail group with their lower detail levels, a group is switched with the "set_objects_lod" message
+// @Tags worlds
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {array} worlds.apiWorldsGetLODGroups.Group
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/lod-groups [get]
+func (w *Worlds) apiWorldsGetLODGroups(c *gin.Context) {
+	type Object struct {
+		ObjectID umid.UMID              `json:"object_id"`
+		Levels   []entry.ObjectLODLevel `json:"levels"`
+	}
+	type Group struct {
+		Group   string   `json:"group"`
+		Objects []Object `json:"objects"`
+	}
+
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetLODGroups: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	world, ok := w.GetWorld(worldID)
+	if !ok {
+		err := errors.Errorf("Worlds: apiWorldsGetLODGroups: world not found: %s", worldID)
+		api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, w.log)
+		return
+	}
+
+	groups := make(map[string]*Group)
+	for _, object := range world.GetAllObjects() {
+		options := object.GetEffectiveOptions()
+		if options == nil || options.LOD == nil || options.LOD.Group == "" {
+			continue
+		}
+
+		group, ok := groups[options.LOD.Group]
+		if !ok {
+			group = &Group{Group: options.LOD.Group, Objects: make([]Object, 0)}
+			groups[options.LOD.Group] = group
+		}
+		group.Objects = append(group.Objects, Object{ObjectID: object.GetID(), Levels: options.LOD.Levels})
+	}
+
+	out := make([]*Group, 0, len(groups))
+	for _, group := range groups {
+		sort.Slice(group.Objects, func(i, j int) bool {
+			return group.Objects[i].ObjectID.String() < group.Objects[j].ObjectID.String()
+		})
+		out = append(out, group)
+	}
+	sort.Slice(out, func(i, j int) bool {
+		return out[i].Group < out[j].Group
+	})
+
+	c.JSON(http.StatusOK, out)
+}
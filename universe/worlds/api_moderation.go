@@ -0,0 +1,333 @@
+package worlds
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Kick user from world
+// @Description Removes the user from the world, the user is notified with a "kicked" signal and can join again
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param body body worlds.apiWorldsKickUser.InBody true "body params"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/kick [post]
+func (w *Worlds) apiWorldsKickUser(c *gin.Context) {
+	type InBody struct {
+		UserID umid.UMID `json:"user_id" binding:"required"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsKickUser: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, w.log)
+		return
+	}
+
+	world, code, errCode, err := w.getModeratedWorld(c, inBody.UserID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsKickUser: invalid request")
+		api.AbortRequest(c, code, errCode, err, w.log)
+		return
+	}
+
+	kicked, err := universe.GetNode().GetModeration().Kick(world, inBody.UserID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsKickUser: failed to kick user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_kick_user", err, w.log)
+		return
+	}
+	if !kicked {
+		err := errors.Errorf("Worlds: apiWorldsKickUser: user not found in world: %s", inBody.UserID)
+		api.AbortRequest(c, http.StatusNotFound, "user_not_found", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Get world bans
+// @Description Returns active bans of the world
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {array} entry.UserBan
+// @Failure 400 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/bans [get]
+func (w *Worlds) apiWorldsGetBans(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetBans: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, universe.GetNode().GetModeration().GetBans(&worldID))
+}
+
+// @Summary Ban user from world
+// @Description Kicks the user and prevents joining the world until the ban expires, permanent without expiration time
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param body body worlds.apiWorldsBanUser.InBody true "body params"
+// @Success 201 {object} entry.UserBan
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/bans [post]
+func (w *Worlds) apiWorldsBanUser(c *gin.Context) {
+	type InBody struct {
+		UserID    umid.UMID  `json:"user_id" binding:"required"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsBanUser: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, w.log)
+		return
+	}
+	if inBody.ExpiresAt != nil && !inBody.ExpiresAt.After(time.Now()) {
+		err := errors.New("Worlds: apiWorldsBanUser: expiration time is in the past")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_expires_at", err, w.log)
+		return
+	}
+
+	world, code, errCode, err := w.getModeratedWorld(c, inBody.UserID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsBanUser: invalid request")
+		api.AbortRequest(c, code, errCode, err, w.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsBanUser: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+
+	worldID := world.GetID()
+	ban, err := universe.GetNode().GetModeration().Ban(inBody.UserID, &worldID, inBody.Reason, userID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsBanUser: failed to ban user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_ban_user", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusCreated, ban)
+}
+
+// @Summary Remove world ban
+// @Description Lifts the ban of the world before it expires
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param ban_id path string true "Ban UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/bans/{ban_id} [delete]
+func (w *Worlds) apiWorldsRemoveBan(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveBan: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+	banID, err := umid.Parse(c.Param("banID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveBan: failed to parse ban umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_ban_id", err, w.log)
+		return
+	}
+
+	moderation := universe.GetNode().GetModeration()
+	if !containsBan(moderation.GetBans(&worldID), banID) {
+		err := errors.Errorf("Worlds: apiWorldsRemoveBan: ban not found: %s", banID)
+		api.AbortRequest(c, http.StatusNotFound, "ban_not_found", err, w.log)
+		return
+	}
+	if err := moderation.Unban(banID); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveBan: failed to remove ban")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_ban", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// @Summary Get world mutes
+// @Description Returns active mutes of the world
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {array} entry.UserMute
+// @Failure 400 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/mutes [get]
+func (w *Worlds) apiWorldsGetMutes(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetMutes: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, universe.GetNode().GetModeration().GetMutes(&worldID))
+}
+
+// @Summary Mute user in world
+// @Description Silences chat and high-fives of the user in the world until the mute expires, permanent without expiration time
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param body body worlds.apiWorldsMuteUser.InBody true "body params"
+// @Success 201 {object} entry.UserMute
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/mutes [post]
+func (w *Worlds) apiWorldsMuteUser(c *gin.Context) {
+	type InBody struct {
+		UserID    umid.UMID  `json:"user_id" binding:"required"`
+		Reason    string     `json:"reason"`
+		ExpiresAt *time.Time `json:"expires_at"`
+	}
+	var inBody InBody
+
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsMuteUser: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, w.log)
+		return
+	}
+	if inBody.ExpiresAt != nil && !inBody.ExpiresAt.After(time.Now()) {
+		err := errors.New("Worlds: apiWorldsMuteUser: expiration time is in the past")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_expires_at", err, w.log)
+		return
+	}
+
+	world, code, errCode, err := w.getModeratedWorld(c, inBody.UserID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsMuteUser: invalid request")
+		api.AbortRequest(c, code, errCode, err, w.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsMuteUser: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+
+	worldID := world.GetID()
+	mute, err := universe.GetNode().GetModeration().Mute(inBody.UserID, &worldID, inBody.Reason, userID, inBody.ExpiresAt)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsMuteUser: failed to mute user")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_mute_user", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusCreated, mute)
+}
+
+// @Summary Remove world mute
+// @Description Lifts the mute of the world before it expires
+// @Tags worlds,moderation
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param mute_id path string true "Mute UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/mutes/{mute_id} [delete]
+func (w *Worlds) apiWorldsRemoveMute(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveMute: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+	muteID, err := umid.Parse(c.Param("muteID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveMute: failed to parse mute umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_mute_id", err, w.log)
+		return
+	}
+
+	moderation := universe.GetNode().GetModeration()
+	if !containsMute(moderation.GetMutes(&worldID), muteID) {
+		err := errors.Errorf("Worlds: apiWorldsRemoveMute: mute not found: %s", muteID)
+		api.AbortRequest(c, http.StatusNotFound, "mute_not_found", err, w.log)
+		return
+	}
+	if err := moderation.Unmute(muteID); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveMute: failed to remove mute")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_remove_mute", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// getModeratedWorld returns the world of the request if the target user can be moderated in it.
+// Admins can't moderate themselves, the world owner or the node owner.
+func (w *Worlds) getModeratedWorld(c *gin.Context, targetID umid.UMID) (universe.World, int, string, error) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid_world_id", errors.WithMessage(err, "failed to parse world umid")
+	}
+
+	world, ok := w.GetWorld(worldID)
+	if !ok {
+		return nil, http.StatusNotFound, "world_not_found", errors.Errorf("world not found: %s", worldID)
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		return nil, http.StatusInternalServerError, "failed_to_get_user_id", errors.WithMessage(
+			err, "failed to get user umid from context",
+		)
+	}
+
+	switch targetID {
+	case userID:
+		return nil, http.StatusBadRequest, "invalid_user_id", errors.New("user can not moderate themselves")
+	case world.GetOwnerID(), universe.GetNode().GetOwnerID():
+		return nil, http.StatusForbidden, "user_not_moderatable", errors.Errorf("user can not be moderated: %s", targetID)
+	}
+
+	return world, 0, "", nil
+}
+
+func containsBan(bans []*entry.UserBan, banID umid.UMID) bool {
+	for _, ban := range bans {
+		if ban.BanID == banID {
+			return true
+		}
+	}
+	return false
+}
+
+func containsMute(mutes []*entry.UserMute, muteID umid.UMID) bool {
+	for _, mute := range mutes {
+		if mute.MuteID == muteID {
+			return true
+		}
+	}
+	return false
+}
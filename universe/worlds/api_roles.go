@@ -0,0 +1,244 @@
+package worlds
+
+import (
+	"net/http"
+	"regexp"
+
+	"github.com/gin-gonic/gin"
+	"github.com/pkg/errors"
+	"golang.org/x/exp/slices"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+var roleNameRegexp = regexp.MustCompile(`^[a-z][a-z0-9_]{0,63}$`)
+
+// @Summary Get world roles
+// @Description Returns custom roles defined for the world
+// @Tags worlds,members
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {array} entry.RoleDefinition
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/roles [get]
+func (w *Worlds) apiWorldsGetRoles(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetRoles: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	if _, ok := w.GetWorld(worldID); !ok {
+		err := errors.New("Worlds: apiWorldsGetRoles: world not found")
+		api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, w.log)
+		return
+	}
+
+	definitions, err := universe.GetNode().GetUserObjects().GetRoleDefinitions(worldID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetRoles: failed to get role definitions")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_roles", err, w.log)
+		return
+	}
+
+	if definitions == nil {
+		definitions = []*entry.RoleDefinition{}
+	}
+
+	c.JSON(http.StatusOK, definitions)
+}
+
+// @Summary Set world role
+// @Description Creates or updates a custom role of the world, the role can then be assigned to members of the world objects
+// @Tags worlds,members
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param role_name path string true "Role name"
+// @Param body body worlds.apiWorldsSetRole.Body true "body params"
+// @Success 200 {object} entry.RoleDefinition
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/roles/{role_name} [put]
+func (w *Worlds) apiWorldsSetRole(c *gin.Context) {
+	type Body struct {
+		Description string                      `json:"description"`
+		Includes    []entry.PermissionsRoleType `json:"includes"`
+	}
+
+	var inBody Body
+	if err := c.ShouldBindJSON(&inBody); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsSetRole: failed to bind json")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_request_body", err, w.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsSetRole: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsSetRole: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	world, ok := w.GetWorld(worldID)
+	if !ok {
+		err := errors.New("Worlds: apiWorldsSetRole: world not found")
+		api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, w.log)
+		return
+	}
+
+	definition := &entry.RoleDefinition{
+		Name:        entry.PermissionsRoleType(c.Param("roleName")),
+		Description: inBody.Description,
+		Includes:    inBody.Includes,
+	}
+	if !roleNameRegexp.MatchString(string(definition.Name)) || entry.IsBuiltinRole(definition.Name) {
+		err := errors.Errorf("Worlds: apiWorldsSetRole: invalid role name: %s", definition.Name)
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_role_name", err, w.log)
+		return
+	}
+
+	modifyFn := func(definitions []*entry.RoleDefinition) ([]*entry.RoleDefinition, error) {
+		for _, include := range definition.Includes {
+			if include == definition.Name {
+				return nil, errors.Errorf("role can not include itself: %s", include)
+			}
+			if include != entry.PermissionAdmin && !containsRole(definitions, include) {
+				return nil, errors.Errorf("unknown included role: %s", include)
+			}
+		}
+
+		return append(withoutRole(definitions, definition.Name), definition), nil
+	}
+
+	if err := w.updateRoleDefinitions(userID, world, modifyFn); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsSetRole: failed to update role definitions")
+		api.AbortRequest(c, http.StatusBadRequest, "failed_to_update_roles", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, definition)
+}
+
+// @Summary Remove world role
+// @Description Removes a custom role of the world, members keep the role name but it no longer grants any included roles
+// @Tags worlds,members
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param role_name path string true "Role name"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/roles/{role_name} [delete]
+func (w *Worlds) apiWorldsRemoveRole(c *gin.Context) {
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveRole: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveRole: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	world, ok := w.GetWorld(worldID)
+	if !ok {
+		err := errors.New("Worlds: apiWorldsRemoveRole: world not found")
+		api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, w.log)
+		return
+	}
+
+	roleName := entry.PermissionsRoleType(c.Param("roleName"))
+	modifyFn := func(definitions []*entry.RoleDefinition) ([]*entry.RoleDefinition, error) {
+		if !containsRole(definitions, roleName) {
+			return nil, errors.Errorf("role not found: %s", roleName)
+		}
+		return withoutRole(definitions, roleName), nil
+	}
+
+	if err := w.updateRoleDefinitions(userID, world, modifyFn); err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemoveRole: failed to update role definitions")
+		api.AbortRequest(c, http.StatusNotFound, "role_not_found", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+func (w *Worlds) updateRoleDefinitions(
+	userID umid.UMID, world universe.World,
+	modifyFn func(definitions []*entry.RoleDefinition) ([]*entry.RoleDefinition, error),
+) error {
+	attributeID := entry.NewAttributeID(universe.GetSystemPluginID(), universe.ReservedAttributes.World.Roles.Name)
+
+	_, err := world.GetObjectAttributes().UpsertByUser(
+		userID, attributeID,
+		func(current *entry.AttributePayload) (*entry.AttributePayload, error) {
+			if current == nil {
+				current = entry.NewAttributePayload(nil, nil)
+			}
+
+			var definitions entry.RoleDefinitions
+			if current.Value != nil {
+				if err := utils.MapDecode(*current.Value, &definitions); err != nil {
+					return nil, errors.WithMessage(err, "failed to decode role definitions")
+				}
+			}
+
+			roles, err := modifyFn(definitions.Roles)
+			if err != nil {
+				return nil, err
+			}
+
+			values := make([]any, 0, len(roles))
+			for _, role := range roles {
+				value := make(map[string]any)
+				if err := utils.MapEncode(role, &value); err != nil {
+					return nil, errors.WithMessage(err, "failed to encode role definition")
+				}
+				values = append(values, value)
+			}
+
+			value := entry.NewAttributeValue()
+			(*value)[universe.ReservedAttributes.World.Roles.Key] = values
+			current.Value = value
+
+			return current, nil
+		},
+		true,
+	)
+
+	return err
+}
+
+func containsRole(definitions []*entry.RoleDefinition, role entry.PermissionsRoleType) bool {
+	return slices.ContainsFunc(definitions, func(definition *entry.RoleDefinition) bool {
+		return definition.Name == role
+	})
+}
+
+func withoutRole(definitions []*entry.RoleDefinition, role entry.PermissionsRoleType) []*entry.RoleDefinition {
+	result := make([]*entry.RoleDefinition, 0, len(definitions))
+	for _, definition := range definitions {
+		if definition.Name != role {
+			result = append(result, definition)
+		}
+	}
+	return result
+}
@@ -0,0 +1,192 @@
+package worlds
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/jackc/pgx/v4"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/api"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+)
+
+// @Summary Delete world
+// @Description Moves the world with all its objects to the trash, only the world owner can delete it
+// @Tags worlds
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {object} entry.ObjectTrashItem
+// @Failure 400 {object} api.HTTPError
+// @Failure 403 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id} [delete]
+func (w *Worlds) apiWorldsRemove(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemove: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	world, ok := w.GetWorld(worldID)
+	if !ok {
+		err := errors.Errorf("Worlds: apiWorldsRemove: world not found: %s", worldID)
+		api.AbortRequest(c, http.StatusNotFound, "world_not_found", err, w.log)
+		return
+	}
+
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemove: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+	if world.GetOwnerID() != userID {
+		err := errors.Errorf("Worlds: apiWorldsRemove: user is not the world owner: %s", userID)
+		api.AbortRequest(c, http.StatusForbidden, "not_owner", err, w.log)
+		return
+	}
+
+	item, err := tree.TrashWorld(c, w.db, world, userID, w.cfg.Common.TrashRetention)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRemove: failed to trash world")
+		api.AbortRequest(c, http.StatusInternalServerError, "remove_failed", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, item)
+}
+
+// @Summary Get world trash
+// @Description Returns the removed objects of the world, the most recently removed first, the world itself is listed if it's removed
+// @Tags worlds
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Success 200 {array} entry.ObjectTrashItem
+// @Failure 400 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/trash [get]
+func (w *Worlds) apiWorldsGetTrash(c *gin.Context) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetTrash: failed to parse world umid")
+		api.AbortRequest(c, http.StatusBadRequest, "invalid_world_id", err, w.log)
+		return
+	}
+
+	items, err := w.db.GetObjectTrashDB().GetObjectTrashItemsByWorldID(c, worldID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsGetTrash: failed to get object trash items")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_trash", err, w.log)
+		return
+	}
+	if items == nil {
+		items = []*entry.ObjectTrashItem{}
+	}
+
+	c.JSON(http.StatusOK, items)
+}
+
+// @Summary Restore trashed object
+// @Description Restores the removed object with its children and attributes under its former parent
+// @Tags worlds
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param trash_object_id path string true "Trashed object UMID"
+// @Success 200 {object} worlds.apiWorldsRestoreTrashItem.Out
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Failure 409 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/trash/{trash_object_id}/restore [post]
+func (w *Worlds) apiWorldsRestoreTrashItem(c *gin.Context) {
+	type Out struct {
+		ObjectID umid.UMID `json:"object_id"`
+	}
+
+	item, code, errCode, err := w.getTrashItem(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsRestoreTrashItem: invalid request")
+		api.AbortRequest(c, code, errCode, err, w.log)
+		return
+	}
+
+	object, err := tree.RestoreTrashItem(c, w.db, item)
+	if err != nil {
+		if errors.Is(err, tree.ErrTrashParentNotFound) {
+			err := errors.WithMessage(err, "Worlds: apiWorldsRestoreTrashItem: failed to restore trash item")
+			api.AbortRequest(c, http.StatusConflict, "parent_not_found", err, w.log)
+			return
+		}
+		if object == nil {
+			err := errors.WithMessage(err, "Worlds: apiWorldsRestoreTrashItem: failed to restore trash item")
+			api.AbortRequest(c, http.StatusInternalServerError, "restore_failed", err, w.log)
+			return
+		}
+		// the object is back, it's only not fully updated
+		w.log.Error(errors.WithMessage(err, "Worlds: apiWorldsRestoreTrashItem: failed to restore trash item"))
+	}
+
+	c.JSON(http.StatusOK, Out{ObjectID: item.ObjectID})
+}
+
+// @Summary Purge trashed object
+// @Description Removes the trashed object with its children, attributes and activities permanently
+// @Tags worlds
+// @Security Bearer
+// @Param object_id path string true "World UMID"
+// @Param trash_object_id path string true "Trashed object UMID"
+// @Success 200 {object} nil
+// @Failure 400 {object} api.HTTPError
+// @Failure 404 {object} api.HTTPError
+// @Router /api/v4/worlds/{object_id}/trash/{trash_object_id} [delete]
+func (w *Worlds) apiWorldsPurgeTrashItem(c *gin.Context) {
+	item, code, errCode, err := w.getTrashItem(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsPurgeTrashItem: invalid request")
+		api.AbortRequest(c, code, errCode, err, w.log)
+		return
+	}
+
+	purged, err := w.db.GetObjectTrashDB().PurgeObjectTrashItemByID(c, item.ObjectID)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsPurgeTrashItem: failed to purge object trash item")
+		api.AbortRequest(c, http.StatusInternalServerError, "purge_failed", err, w.log)
+		return
+	}
+	if !purged {
+		err := errors.Errorf("Worlds: apiWorldsPurgeTrashItem: trash item not found: %s", item.ObjectID)
+		api.AbortRequest(c, http.StatusNotFound, "trash_item_not_found", err, w.log)
+		return
+	}
+
+	c.JSON(http.StatusOK, nil)
+}
+
+// getTrashItem returns the trash item of the request world with the http status and error code to respond on error.
+func (w *Worlds) getTrashItem(c *gin.Context) (*entry.ObjectTrashItem, int, string, error) {
+	worldID, err := umid.Parse(c.Param("objectID"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid_world_id", errors.WithMessage(err, "failed to parse world umid")
+	}
+	objectID, err := umid.Parse(c.Param("trashObjectID"))
+	if err != nil {
+		return nil, http.StatusBadRequest, "invalid_object_id", errors.WithMessage(err, "failed to parse object umid")
+	}
+
+	item, err := w.db.GetObjectTrashDB().GetObjectTrashItemByID(c, objectID)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, http.StatusNotFound, "trash_item_not_found", errors.Errorf("trash item not found: %s", objectID)
+		}
+		return nil, http.StatusInternalServerError, "failed_to_get_trash_item", errors.WithMessage(
+			err, "failed to get object trash item",
+		)
+	}
+	if item.WorldID != worldID {
+		return nil, http.StatusNotFound, "trash_item_not_found", errors.Errorf("trash item not found: %s", objectID)
+	}
+
+	return item, 0, "", nil
+}
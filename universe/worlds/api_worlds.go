@@ -1,6 +1,7 @@
 package worlds
 
 import (
+	"context"
 	"fmt"
 	"math/big"
 	"net/http"
@@ -485,7 +486,14 @@ func (w *Worlds) apiWorldsSearchWorlds(c *gin.Context) {
 		return
 	}
 
-	worlds, err := w.apiWorldsFilterWorlds(inQuery.SearchQuery)
+	userID, err := api.GetUserIDFromContext(c)
+	if err != nil {
+		err := errors.WithMessage(err, "Worlds: apiWorldsSearchWorlds: failed to get user umid from context")
+		api.AbortRequest(c, http.StatusInternalServerError, "failed_to_get_user_id", err, w.log)
+		return
+	}
+
+	worlds, err := w.apiWorldsFilterWorlds(c, userID, inQuery.SearchQuery)
 	if err != nil {
 		err := errors.WithMessage(err, "Worlds: apiWorldsSearchWorlds: failed to filter objects")
 		api.AbortRequest(c, http.StatusBadRequest, "failed_to_filter", err, w.log)
@@ -495,32 +503,35 @@ func (w *Worlds) apiWorldsSearchWorlds(c *gin.Context) {
 	c.JSON(http.StatusOK, worlds)
 }
 
-func (w *Worlds) apiWorldsFilterWorlds(searchQuery string) (dto.SearchOptions, error) {
-	node := universe.GetNode()
-	worlds := node.GetWorlds()
-
-	predicateFn := func(worldID umid.UMID, world universe.World) bool {
-		name, _, err := w.apiWorldsResolveNameDescription(world)
-		if err != nil {
-			return false
-		}
+const worldsSearchLimit = 100
 
-		name = strings.ToLower(name)
-		searchQuery = strings.ToLower(searchQuery)
-		return strings.Contains(name, searchQuery)
+func (w *Worlds) apiWorldsFilterWorlds(
+	ctx context.Context, viewerID umid.UMID, searchQuery string,
+) (dto.SearchOptions, error) {
+	query := &entry.SearchQuery{
+		Text:     searchQuery,
+		Kinds:    []entry.SearchDocumentKind{entry.SearchDocumentKindWorld},
+		ViewerID: viewerID,
+	}
+	documents, err := w.db.GetSearchDB().SearchDocuments(ctx, query, nil, worldsSearchLimit)
+	if err != nil {
+		return nil, errors.WithMessage(err, "Worlds: apiWorldsFilterWorlds: failed to search worlds")
 	}
 
-	filteredWorlds := worlds.FilterWorlds(predicateFn)
-	options := make([]dto.ExploreOption, 0, len(filteredWorlds))
+	options := make([]dto.ExploreOption, 0, len(documents))
+	for _, document := range documents {
+		world, ok := w.GetWorld(document.DocumentID)
+		if !ok {
+			continue
+		}
 
-	for _, filteredWorld := range filteredWorlds {
-		name, description, err := w.apiWorldsResolveNameDescription(filteredWorld)
+		name, description, err := w.apiWorldsResolveNameDescription(world)
 		if err != nil {
 			return nil, errors.WithMessage(err, "Worlds: apiWorldsFilterWorlds: failed to get name description")
 		}
 
 		option := dto.ExploreOption{
-			ID:          filteredWorld.GetID(),
+			ID:          world.GetID(),
 			Name:        utils.GetPTR(name),
 			Description: utils.GetPTR(description),
 		}
@@ -0,0 +1,486 @@
+package worlds
+
+import (
+	"archive/zip"
+	"context"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/hashicorp/go-multierror"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/pkg/media"
+	"github.com/momentum-xyz/ubercontroller/types/entry"
+	"github.com/momentum-xyz/ubercontroller/universe"
+	"github.com/momentum-xyz/ubercontroller/universe/logic/tree"
+	"github.com/momentum-xyz/ubercontroller/utils"
+	"github.com/momentum-xyz/ubercontroller/utils/modify"
+	"github.com/momentum-xyz/ubercontroller/utils/umid"
+	"github.com/momentum-xyz/ubercontroller/utils/ziputil"
+)
+
+// Version of the world bundle format, bundles of newer versions can't be imported.
+const worldBundleVersion = 1
+
+const (
+	worldBundleManifestFile    = "manifest.json"
+	worldBundleWorldFile       = "world.json"
+	worldBundleObjectTypesFile = "object_types.json"
+	worldBundleAssets2dFile    = "assets_2d.json"
+	worldBundleAssets3dFile    = "assets_3d.json"
+	worldBundleMediaDir        = "media"
+)
+
+// What happens when the imported world or its objects already exist on the node.
+const (
+	// all world and object umids are replaced by new ones
+	worldImportStrategyNew = "new"
+	// umids are kept, the import fails if any of them exists
+	worldImportStrategyKeep = "keep"
+	// umids are kept, the existing world is replaced once everything else is imported
+	worldImportStrategyReplace = "replace"
+)
+
+var errWorldImportConflict = errors.New("world or objects already exist")
+
+var mediaHashRegexp = regexp.MustCompile(`^[0-9a-f]{32}$`)
+
+type worldBundleManifest struct {
+	Version    int       `json:"version"`
+	WorldID    umid.UMID `json:"world_id"`
+	WorldName  string    `json:"world_name"`
+	ExportedAt time.Time `json:"exported_at"`
+}
+
+// worldBundle is a world with everything it depends on, except plugins.
+type worldBundle struct {
+	manifest    *worldBundleManifest
+	world       *tree.WorldTemplate
+	objectTypes []*entry.ObjectType
+	assets2d    []*entry.Asset2d
+	assets3d    []*entry.Asset3d
+}
+
+// exportWorld writes a zip archive with the world objects tree, object types, assets and media files referenced by them.
+func (w *Worlds) exportWorld(world universe.World, out io.Writer) error {
+	node := universe.GetNode()
+
+	bundle := &worldBundle{
+		manifest: &worldBundleManifest{
+			Version:    worldBundleVersion,
+			WorldID:    world.GetID(),
+			WorldName:  world.GetName(),
+			ExportedAt: time.Now(),
+		},
+		world: tree.WorldToTemplate(world),
+	}
+
+	objectTypeIDs := make(map[umid.UMID]bool)
+	asset2dIDs := make(map[umid.UMID]bool)
+	asset3dIDs := make(map[umid.UMID]bool)
+	hashes := make(map[string]bool)
+
+	tree.WalkObjectTemplate(&bundle.world.ObjectTemplate, func(objectTemplate *tree.ObjectTemplate) {
+		objectTypeIDs[objectTemplate.ObjectTypeID] = true
+		if objectTemplate.Asset2dID != nil {
+			asset2dIDs[*objectTemplate.Asset2dID] = true
+		}
+		if objectTemplate.Asset3dID != nil {
+			asset3dIDs[*objectTemplate.Asset3dID] = true
+		}
+		for _, attribute := range objectTemplate.ObjectAttributes {
+			if attribute.AttributePayload != nil && attribute.Value != nil {
+				collectMediaHashes(map[string]any(*attribute.Value), hashes)
+			}
+		}
+	})
+
+	for objectTypeID := range objectTypeIDs {
+		objectType, ok := node.GetObjectTypes().GetObjectType(objectTypeID)
+		if !ok {
+			return errors.Errorf("object type not found: %s", objectTypeID)
+		}
+		if asset2d := objectType.GetAsset2d(); asset2d != nil {
+			asset2dIDs[asset2d.GetID()] = true
+		}
+		if asset3d := objectType.GetAsset3d(); asset3d != nil {
+			asset3dIDs[asset3d.GetID()] = true
+		}
+		bundle.objectTypes = append(bundle.objectTypes, objectType.GetEntry())
+	}
+	for asset2dID := range asset2dIDs {
+		asset2d, ok := node.GetAssets2d().GetAsset2d(asset2dID)
+		if !ok {
+			return errors.Errorf("asset 2d not found: %s", asset2dID)
+		}
+		bundle.assets2d = append(bundle.assets2d, asset2d.GetEntry())
+	}
+	for asset3dID := range asset3dIDs {
+		asset3d, ok := node.GetAssets3d().GetAsset3d(asset3dID)
+		if !ok {
+			return errors.Errorf("asset 3d not found: %s", asset3dID)
+		}
+		// uploaded assets are stored by the hash of the file which is also their umid
+		hashes[strings.ReplaceAll(asset3dID.String(), "-", "")] = true
+		if meta := asset3d.GetMeta(); meta != nil {
+			collectMediaHashes(map[string]any(*meta), hashes)
+		}
+		bundle.assets3d = append(bundle.assets3d, asset3d.GetEntry())
+	}
+
+	sort.Slice(bundle.objectTypes, func(i, j int) bool {
+		return bundle.objectTypes[i].ObjectTypeID.String() < bundle.objectTypes[j].ObjectTypeID.String()
+	})
+	sort.Slice(bundle.assets2d, func(i, j int) bool {
+		return bundle.assets2d[i].Asset2dID.String() < bundle.assets2d[j].Asset2dID.String()
+	})
+	sort.Slice(bundle.assets3d, func(i, j int) bool {
+		return bundle.assets3d[i].Asset3dID.String() < bundle.assets3d[j].Asset3dID.String()
+	})
+
+	archive := zip.NewWriter(out)
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{name: worldBundleManifestFile, data: bundle.manifest},
+		{name: worldBundleWorldFile, data: bundle.world},
+		{name: worldBundleObjectTypesFile, data: bundle.objectTypes},
+		{name: worldBundleAssets2dFile, data: bundle.assets2d},
+		{name: worldBundleAssets3dFile, data: bundle.assets3d},
+	}
+	for _, file := range files {
+		if err := ziputil.WriteJSON(archive, file.name, file.data); err != nil {
+			return errors.WithMessagef(err, "failed to write file: %s", file.name)
+		}
+	}
+
+	for hash := range hashes {
+		for _, kind := range media.FileKinds {
+			file, ok := w.media.FindFileOfKind(kind, hash)
+			if !ok {
+				continue
+			}
+			if err := exportMediaFile(w.ctx, archive, path.Join(worldBundleMediaDir, kind, hash), file); err != nil {
+				return errors.WithMessagef(err, "failed to write media file: %s", hash)
+			}
+		}
+	}
+
+	return archive.Close()
+}
+
+// readWorldBundle reads and validates the world bundle from the zip archive.
+func readWorldBundle(archive *zip.Reader) (*worldBundle, error) {
+	bundle := &worldBundle{}
+
+	if err := ziputil.ReadJSON(archive, worldBundleManifestFile, &bundle.manifest); err != nil {
+		return nil, errors.WithMessagef(err, "failed to read file: %s", worldBundleManifestFile)
+	}
+	if bundle.manifest.Version < 1 || bundle.manifest.Version > worldBundleVersion {
+		return nil, errors.Errorf("unsupported bundle version: %d", bundle.manifest.Version)
+	}
+
+	files := []struct {
+		name string
+		data any
+	}{
+		{name: worldBundleWorldFile, data: &bundle.world},
+		{name: worldBundleObjectTypesFile, data: &bundle.objectTypes},
+		{name: worldBundleAssets2dFile, data: &bundle.assets2d},
+		{name: worldBundleAssets3dFile, data: &bundle.assets3d},
+	}
+	for _, file := range files {
+		if err := ziputil.ReadJSON(archive, file.name, file.data); err != nil {
+			return nil, errors.WithMessagef(err, "failed to read file: %s", file.name)
+		}
+	}
+
+	if bundle.world == nil || bundle.world.ObjectID == nil {
+		return nil, errors.New("world is missing")
+	}
+
+	return bundle, nil
+}
+
+// importWorld recreates the world from the bundle owned by the given user.
+// Media files, assets and object types missing on the node are added as they are,
+// object attributes of plugins which are not installed are skipped and returned.
+func (w *Worlds) importWorld(
+	archive *zip.Reader, bundle *worldBundle, ownerID umid.UMID, strategy string,
+) (umid.UMID, []entry.AttributeID, error) {
+	node := universe.GetNode()
+	worldTemplate := bundle.world
+
+	var replacedWorld universe.World
+	switch strategy {
+	case worldImportStrategyNew:
+		if _, err := tree.RemapObjectTemplate(&worldTemplate.ObjectTemplate); err != nil {
+			return umid.Nil, nil, errors.WithMessage(err, "failed to remap world template")
+		}
+	case worldImportStrategyKeep, worldImportStrategyReplace:
+		var existingWorld universe.World
+		if strategy == worldImportStrategyReplace {
+			existingWorld, _ = w.GetWorld(*worldTemplate.ObjectID)
+		}
+		var conflictID *umid.UMID
+		var objectIDs []umid.UMID
+		tree.WalkObjectTemplate(&worldTemplate.ObjectTemplate, func(objectTemplate *tree.ObjectTemplate) {
+			if objectTemplate.ObjectID == nil {
+				objectTemplate.ObjectID = utils.GetPTR(umid.New())
+			}
+			objectIDs = append(objectIDs, *objectTemplate.ObjectID)
+			object, ok := node.GetObjectFromAllObjects(*objectTemplate.ObjectID)
+			if !ok || conflictID != nil {
+				return
+			}
+			if existingWorld == nil || object.GetWorld() == nil || object.GetWorld().GetID() != existingWorld.GetID() {
+				conflictID = objectTemplate.ObjectID
+			}
+		})
+		if conflictID != nil {
+			return umid.Nil, nil, errors.WithMessagef(errWorldImportConflict, "object exists: %s", conflictID)
+		}
+		// objects in the trash or of worlds which aren't loaded are only in the db
+		replacedID := umid.Nil
+		if existingWorld != nil {
+			replacedID = existingWorld.GetID()
+		}
+		existingIDs, err := w.db.GetObjectsDB().GetExistingObjectIDs(w.ctx, objectIDs, replacedID)
+		if err != nil {
+			return umid.Nil, nil, errors.WithMessage(err, "failed to get existing object ids")
+		}
+		if len(existingIDs) > 0 {
+			return umid.Nil, nil, errors.WithMessagef(errWorldImportConflict, "object exists: %s", existingIDs[0])
+		}
+		replacedWorld = existingWorld
+	default:
+		return umid.Nil, nil, errors.Errorf("unknown strategy: %s", strategy)
+	}
+
+	for _, file := range archive.File {
+		// media/<kind>/<hash>
+		parts := strings.Split(file.Name, "/")
+		if len(parts) != 3 || parts[0] != worldBundleMediaDir || file.FileInfo().IsDir() {
+			continue
+		}
+		if err := importMediaFile(w.media, file, parts[1], parts[2]); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to import media file: %s", file.Name)
+		}
+	}
+
+	for _, asset2dEntry := range bundle.assets2d {
+		if _, ok := node.GetAssets2d().GetAsset2d(asset2dEntry.Asset2dID); ok {
+			continue
+		}
+		asset2d, err := node.GetAssets2d().CreateAsset2d(asset2dEntry.Asset2dID)
+		if err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to create asset 2d: %s", asset2dEntry.Asset2dID)
+		}
+		if err := asset2d.LoadFromEntry(asset2dEntry); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to load asset 2d: %s", asset2dEntry.Asset2dID)
+		}
+		if err := node.GetAssets2d().AddAsset2d(asset2d, true); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to add asset 2d: %s", asset2dEntry.Asset2dID)
+		}
+	}
+	for _, asset3dEntry := range bundle.assets3d {
+		asset3d, err, isNew := node.GetAssets3d().CreateAsset3d(asset3dEntry.Asset3dID)
+		if err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to create asset 3d: %s", asset3dEntry.Asset3dID)
+		}
+		if !isNew {
+			continue
+		}
+		if err := asset3d.LoadFromEntry(asset3dEntry); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to load asset 3d: %s", asset3dEntry.Asset3dID)
+		}
+		if err := node.GetAssets3d().AddAsset3d(asset3d, true); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to add asset 3d: %s", asset3dEntry.Asset3dID)
+		}
+	}
+	for _, objectTypeEntry := range bundle.objectTypes {
+		if _, ok := node.GetObjectTypes().GetObjectType(objectTypeEntry.ObjectTypeID); ok {
+			continue
+		}
+		objectType, err := node.GetObjectTypes().CreateObjectType(objectTypeEntry.ObjectTypeID)
+		if err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to create object type: %s", objectTypeEntry.ObjectTypeID)
+		}
+		if err := objectType.LoadFromEntry(objectTypeEntry); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to load object type: %s", objectTypeEntry.ObjectTypeID)
+		}
+		if err := node.GetObjectTypes().AddObjectType(objectType, true); err != nil {
+			return umid.Nil, nil, errors.WithMessagef(err, "failed to add object type: %s", objectTypeEntry.ObjectTypeID)
+		}
+	}
+
+	var skipped []entry.AttributeID
+	tree.WalkObjectTemplate(&worldTemplate.ObjectTemplate, func(objectTemplate *tree.ObjectTemplate) {
+		// children inherit the owner of the world
+		objectTemplate.OwnerID = nil
+
+		attributes := make([]*entry.Attribute, 0, len(objectTemplate.ObjectAttributes))
+		for _, attribute := range objectTemplate.ObjectAttributes {
+			if _, ok := node.GetAttributeTypes().GetAttributeType(entry.AttributeTypeID(attribute.AttributeID)); !ok {
+				skipped = append(skipped, attribute.AttributeID)
+				continue
+			}
+			attributes = append(attributes, attribute)
+		}
+		objectTemplate.ObjectAttributes = attributes
+	})
+	worldTemplate.OwnerID = &ownerID
+
+	// everything is staged, so the existing world is removed only right before adding the imported one
+	if err := validateWorldTemplate(worldTemplate); err != nil {
+		return umid.Nil, nil, errors.WithMessage(err, "invalid world template")
+	}
+	if replacedWorld == nil {
+		worldID, err := addBundleWorld(worldTemplate)
+		if err != nil {
+			return umid.Nil, nil, err
+		}
+		return worldID, skipped, nil
+	}
+
+	// umids are kept, so the world can't go to the trash, it is added back from its template if the import fails
+	replacedTemplate := tree.WorldToTemplate(replacedWorld)
+	if _, err := tree.RemoveWorld(replacedWorld, true); err != nil {
+		return umid.Nil, nil, errors.WithMessagef(err, "failed to remove world: %s", replacedWorld.GetID())
+	}
+	worldID, err := addBundleWorld(worldTemplate)
+	if err != nil {
+		if world, ok := w.GetWorld(*worldTemplate.ObjectID); ok {
+			if _, removeErr := tree.RemoveWorld(world, true); removeErr != nil {
+				return umid.Nil, nil, multierror.Append(
+					err, errors.WithMessage(removeErr, "failed to remove imported world"),
+				)
+			}
+		}
+		if _, restoreErr := addBundleWorld(replacedTemplate); restoreErr != nil {
+			return umid.Nil, nil, multierror.Append(
+				err, errors.WithMessage(restoreErr, "failed to restore replaced world"),
+			)
+		}
+		return umid.Nil, nil, err
+	}
+
+	return worldID, skipped, nil
+}
+
+// addBundleWorld adds the world from the template with its options and assets.
+func addBundleWorld(worldTemplate *tree.WorldTemplate) (umid.UMID, error) {
+	node := universe.GetNode()
+
+	// world template doesn't apply them to the world itself
+	worldOptions := worldTemplate.Options
+	worldAsset2dID := worldTemplate.Asset2dID
+	worldAsset3dID := worldTemplate.Asset3dID
+
+	worldID, err := tree.AddWorldFromTemplate(worldTemplate, true)
+	if err != nil {
+		return umid.Nil, errors.WithMessage(err, "failed to add world from template")
+	}
+
+	world, ok := node.GetWorlds().GetWorld(worldID)
+	if !ok {
+		return umid.Nil, errors.Errorf("world not found: %s", worldID)
+	}
+	if worldOptions != nil {
+		if _, err := world.SetOptions(modify.MergeWith(worldOptions), true); err != nil {
+			return umid.Nil, errors.WithMessage(err, "failed to set world options")
+		}
+	}
+	if worldAsset2dID != nil {
+		if asset2d, ok := node.GetAssets2d().GetAsset2d(*worldAsset2dID); ok {
+			if err := world.SetAsset2D(asset2d, true); err != nil {
+				return umid.Nil, errors.WithMessage(err, "failed to set world asset 2d")
+			}
+		}
+	}
+	if worldAsset3dID != nil {
+		if asset3d, ok := node.GetAssets3d().GetAsset3d(*worldAsset3dID); ok {
+			if err := world.SetAsset3D(asset3d, true); err != nil {
+				return umid.Nil, errors.WithMessage(err, "failed to set world asset 3d")
+			}
+		}
+	}
+
+	return worldID, nil
+}
+
+// validateWorldTemplate checks that object types and assets of the world objects are on the node.
+func validateWorldTemplate(worldTemplate *tree.WorldTemplate) error {
+	node := universe.GetNode()
+
+	var errs *multierror.Error
+	tree.WalkObjectTemplate(&worldTemplate.ObjectTemplate, func(objectTemplate *tree.ObjectTemplate) {
+		if _, ok := node.GetObjectTypes().GetObjectType(objectTemplate.ObjectTypeID); !ok {
+			errs = multierror.Append(errs, errors.Errorf("object type not found: %s", objectTemplate.ObjectTypeID))
+		}
+		// assets of the world itself are optional
+		if objectTemplate == &worldTemplate.ObjectTemplate {
+			return
+		}
+		if objectTemplate.Asset2dID != nil {
+			if _, ok := node.GetAssets2d().GetAsset2d(*objectTemplate.Asset2dID); !ok {
+				errs = multierror.Append(errs, errors.Errorf("asset 2d not found: %s", objectTemplate.Asset2dID))
+			}
+		}
+		if objectTemplate.Asset3dID != nil {
+			if _, ok := node.GetAssets3d().GetAsset3d(*objectTemplate.Asset3dID); !ok {
+				errs = multierror.Append(errs, errors.Errorf("asset 3d not found: %s", objectTemplate.Asset3dID))
+			}
+		}
+	})
+
+	return errs.ErrorOrNil()
+}
+
+func exportMediaFile(ctx context.Context, archive *zip.Writer, name string, file *media.File) error {
+	src, err := file.Open(ctx)
+	if err != nil {
+		return errors.WithMessage(err, "failed to open file")
+	}
+	defer src.Close()
+
+	return ziputil.CopyReader(archive, name, src)
+}
+
+func importMediaFile(m *media.Media, file *zip.File, kind string, hash string) error {
+	src, err := file.Open()
+	if err != nil {
+		return errors.WithMessage(err, "failed to open file")
+	}
+	defer src.Close()
+
+	return m.ImportFile(kind, hash, src)
+}
+
+// collectMediaHashes adds all strings which look like hashes of media files from the value to the hashes.
+func collectMediaHashes(value any, hashes map[string]bool) {
+	switch value := value.(type) {
+	case string:
+		if mediaHashRegexp.MatchString(value) {
+			hashes[value] = true
+		}
+	case *string:
+		if value != nil {
+			collectMediaHashes(*value, hashes)
+		}
+	case map[string]any:
+		for _, v := range value {
+			collectMediaHashes(v, hashes)
+		}
+	case []any:
+		for _, v := range value {
+			collectMediaHashes(v, hashes)
+		}
+	}
+}
@@ -110,7 +110,8 @@ func (w *Worlds) AddWorlds(worlds []universe.World, updateDB bool) error {
 	return nil
 }
 
-// TODO: introduce "helper.RemoveWorld()" method and fix this one
+// RemoveWorld only forgets the world, use "tree.RemoveWorld()" to stop it and its objects as well.
+// Removing from database removes all the world objects too.
 func (w *Worlds) RemoveWorld(world universe.World, updateDB bool) (bool, error) {
 	w.worlds.Mu.Lock()
 	defer w.worlds.Mu.Unlock()
@@ -120,7 +121,9 @@ func (w *Worlds) RemoveWorld(world universe.World, updateDB bool) (bool, error)
 	}
 
 	if updateDB {
-		panic("not implemented")
+		if err := w.db.GetObjectsDB().RemoveObjectByID(w.ctx, world.GetID()); err != nil {
+			return false, errors.WithMessage(err, "failed to remove world from db")
+		}
 	}
 
 	delete(w.worlds.Data, world.GetID())
@@ -128,19 +131,22 @@ func (w *Worlds) RemoveWorld(world universe.World, updateDB bool) (bool, error)
 	return true, nil
 }
 
-// TODO: introduce "helper.RemoveWorld()" method and fix this one
 func (w *Worlds) RemoveWorlds(worlds []universe.World, updateDB bool) (bool, error) {
 	w.worlds.Mu.Lock()
 	defer w.worlds.Mu.Unlock()
 
+	worldIDs := make([]umid.UMID, 0, len(worlds))
 	for _, world := range worlds {
 		if _, ok := w.worlds.Data[world.GetID()]; !ok {
 			return false, nil
 		}
+		worldIDs = append(worldIDs, world.GetID())
 	}
 
 	if updateDB {
-		panic("not implemented")
+		if err := w.db.GetObjectsDB().RemoveObjectsByIDs(w.ctx, worldIDs); err != nil {
+			return false, errors.WithMessage(err, "failed to remove worlds from db")
+		}
 	}
 
 	for _, world := range worlds {
@@ -0,0 +1,442 @@
+// Package patch implements JSON Patch (RFC 6902) and JSON Merge Patch (RFC 7396) for generic JSON documents.
+package patch
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/goccy/go-reflect"
+	"github.com/pkg/errors"
+
+	"github.com/momentum-xyz/ubercontroller/utils/merge"
+)
+
+var (
+	ErrInvalidPatch = errors.New("invalid patch")
+	ErrTestFailed   = errors.New("test operation failed")
+)
+
+type OperationType string
+
+const (
+	AddOperationType     OperationType = "add"
+	RemoveOperationType  OperationType = "remove"
+	ReplaceOperationType OperationType = "replace"
+	MoveOperationType    OperationType = "move"
+	CopyOperationType    OperationType = "copy"
+	TestOperationType    OperationType = "test"
+)
+
+// Operation is a single RFC 6902 operation.
+type Operation struct {
+	Op    OperationType `json:"op"`
+	Path  string        `json:"path"`
+	From  string        `json:"from,omitempty"`
+	Value any           `json:"value,omitempty"`
+}
+
+// ApplyJSONPatch applies RFC 6902 operations to a copy of the document, the document itself is never modified.
+func ApplyJSONPatch(doc map[string]any, ops []Operation) (map[string]any, error) {
+	var res any
+	if err := normalize(doc, &res); err != nil {
+		return nil, errors.WithMessage(err, "failed to normalize document")
+	}
+	if res == nil {
+		res = map[string]any{}
+	}
+
+	for i := range ops {
+		var err error
+		res, err = apply(res, ops[i])
+		if err != nil {
+			return nil, errors.WithMessagef(err, "failed to apply operation %d: %s %q", i, ops[i].Op, ops[i].Path)
+		}
+	}
+
+	resMap, ok := res.(map[string]any)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidPatch, "document root must be an object: %T", res)
+	}
+
+	return resMap, nil
+}
+
+// ApplyMergePatch applies RFC 7396 merge patch to a copy of the document, the document itself is never modified.
+// Triggers are called for every patched value with the same paths as in the merge package (".key.subkey"),
+// so merge.AppendTriggerFn and merge.UniqueTriggerFn can be used to alter the default replacement of arrays.
+func ApplyMergePatch(doc map[string]any, patch map[string]any, triggers ...merge.Trigger) (map[string]any, error) {
+	var res any
+	if err := normalize(doc, &res); err != nil {
+		return nil, errors.WithMessage(err, "failed to normalize document")
+	}
+	var p any
+	if err := normalize(patch, &p); err != nil {
+		return nil, errors.WithMessage(err, "failed to normalize patch")
+	}
+
+	res, err := mergePatch(res, p, ".", triggers...)
+	if err != nil {
+		return nil, err
+	}
+
+	resMap, ok := res.(map[string]any)
+	if !ok {
+		return nil, errors.Wrapf(ErrInvalidPatch, "document root must be an object: %T", res)
+	}
+
+	return resMap, nil
+}
+
+// MergeTriggers creates append and unique merge triggers for the given JSON pointers.
+// Append triggers are placed first, so arrays are appended before duplicates are removed.
+func MergeTriggers(appendPaths, uniquePaths []string) ([]merge.Trigger, error) {
+	triggers := make([]merge.Trigger, 0, len(appendPaths)+len(uniquePaths))
+	for _, path := range appendPaths {
+		mergePath, err := MergePath(path)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid append path: %q", path)
+		}
+		triggers = append(triggers, merge.NewTrigger(mergePath, merge.AppendTriggerFn))
+	}
+	for _, path := range uniquePaths {
+		mergePath, err := MergePath(path)
+		if err != nil {
+			return nil, errors.WithMessagef(err, "invalid unique path: %q", path)
+		}
+		triggers = append(triggers, merge.NewTrigger(mergePath, merge.UniqueTriggerFn))
+	}
+	return triggers, nil
+}
+
+// MergePath converts JSON pointer to the merge package path.
+func MergePath(pointer string) (string, error) {
+	tokens, err := parsePointer(pointer)
+	if err != nil {
+		return "", err
+	}
+	path := "."
+	for _, token := range tokens {
+		path = addPathKey(path, token)
+	}
+	return path, nil
+}
+
+// ETag returns strong entity tag of the JSON representation of the value.
+func ETag(value any) (string, error) {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", errors.WithMessage(err, "failed to marshal value")
+	}
+	sum := sha256.Sum256(data)
+	return strconv.Quote(hex.EncodeToString(sum[:16])), nil
+}
+
+// MatchETag checks if the etag matches one of the comma separated entity tags from "If-Match" header.
+func MatchETag(etag, ifMatch string) bool {
+	for _, tag := range strings.Split(ifMatch, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "*" || tag == etag {
+			return true
+		}
+		// version can also be passed without quotes in request body
+		if strconv.Quote(tag) == etag {
+			return true
+		}
+	}
+	return false
+}
+
+func apply(doc any, op Operation) (any, error) {
+	path, err := parsePointer(op.Path)
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := normalize(op.Value, &value); err != nil {
+		return nil, errors.WithMessage(err, "failed to normalize value")
+	}
+
+	switch op.Op {
+	case AddOperationType:
+		return add(doc, path, value)
+	case RemoveOperationType:
+		res, _, err := remove(doc, path)
+		return res, err
+	case ReplaceOperationType:
+		return replace(doc, path, value)
+	case MoveOperationType:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		if op.From == op.Path {
+			return doc, nil
+		}
+		if strings.HasPrefix(op.Path, op.From+"/") {
+			return nil, errors.Wrapf(ErrInvalidPatch, "can not move %q into its own child", op.From)
+		}
+		res, fromValue, err := remove(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		return add(res, path, fromValue)
+	case CopyOperationType:
+		from, err := parsePointer(op.From)
+		if err != nil {
+			return nil, err
+		}
+		fromValue, err := get(doc, from)
+		if err != nil {
+			return nil, err
+		}
+		var valueCopy any
+		if err := normalize(fromValue, &valueCopy); err != nil {
+			return nil, errors.WithMessage(err, "failed to copy value")
+		}
+		return add(doc, path, valueCopy)
+	case TestOperationType:
+		current, err := get(doc, path)
+		if err != nil {
+			return nil, err
+		}
+		if !reflect.DeepEqual(current, value) {
+			return nil, errors.Wrapf(ErrTestFailed, "value mismatch: %q", op.Path)
+		}
+		return doc, nil
+	}
+
+	return nil, errors.Wrapf(ErrInvalidPatch, "unknown operation: %q", op.Op)
+}
+
+func get(node any, path []string) (any, error) {
+	for _, token := range path {
+		switch n := node.(type) {
+		case map[string]any:
+			child, ok := n[token]
+			if !ok {
+				return nil, errors.Wrapf(ErrInvalidPatch, "member not found: %q", token)
+			}
+			node = child
+		case []any:
+			i, err := parseIndex(token, len(n)-1)
+			if err != nil {
+				return nil, err
+			}
+			node = n[i]
+		default:
+			return nil, errors.Wrapf(ErrInvalidPatch, "can not traverse %T: %q", node, token)
+		}
+	}
+	return node, nil
+}
+
+func add(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token := path[0]
+	switch n := node.(type) {
+	case map[string]any:
+		if len(path) == 1 {
+			n[token] = value
+			return n, nil
+		}
+		child, ok := n[token]
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidPatch, "member not found: %q", token)
+		}
+		res, err := add(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = res
+		return n, nil
+	case []any:
+		if len(path) == 1 {
+			i := len(n)
+			if token != "-" {
+				var err error
+				if i, err = parseIndex(token, len(n)); err != nil {
+					return nil, err
+				}
+			}
+			res := make([]any, 0, len(n)+1)
+			res = append(res, n[:i]...)
+			res = append(res, value)
+			return append(res, n[i:]...), nil
+		}
+		i, err := parseIndex(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		res, err := add(n[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = res
+		return n, nil
+	}
+
+	return nil, errors.Wrapf(ErrInvalidPatch, "can not add to %T: %q", node, token)
+}
+
+func remove(node any, path []string) (any, any, error) {
+	if len(path) == 0 {
+		return nil, nil, errors.Wrap(ErrInvalidPatch, "can not remove document root")
+	}
+
+	token := path[0]
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[token]
+		if !ok {
+			return nil, nil, errors.Wrapf(ErrInvalidPatch, "member not found: %q", token)
+		}
+		if len(path) == 1 {
+			delete(n, token)
+			return n, child, nil
+		}
+		res, value, err := remove(child, path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[token] = res
+		return n, value, nil
+	case []any:
+		i, err := parseIndex(token, len(n)-1)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(path) == 1 {
+			res := make([]any, 0, len(n)-1)
+			res = append(res, n[:i]...)
+			return append(res, n[i+1:]...), n[i], nil
+		}
+		res, value, err := remove(n[i], path[1:])
+		if err != nil {
+			return nil, nil, err
+		}
+		n[i] = res
+		return n, value, nil
+	}
+
+	return nil, nil, errors.Wrapf(ErrInvalidPatch, "can not remove from %T: %q", node, token)
+}
+
+func replace(node any, path []string, value any) (any, error) {
+	if len(path) == 0 {
+		return value, nil
+	}
+
+	token := path[0]
+	switch n := node.(type) {
+	case map[string]any:
+		child, ok := n[token]
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalidPatch, "member not found: %q", token)
+		}
+		res, err := replace(child, path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[token] = res
+		return n, nil
+	case []any:
+		i, err := parseIndex(token, len(n)-1)
+		if err != nil {
+			return nil, err
+		}
+		res, err := replace(n[i], path[1:], value)
+		if err != nil {
+			return nil, err
+		}
+		n[i] = res
+		return n, nil
+	}
+
+	return nil, errors.Wrapf(ErrInvalidPatch, "can not replace in %T: %q", node, token)
+}
+
+func mergePatch(target, patch any, path string, triggers ...merge.Trigger) (any, error) {
+	res := patch
+	if patchMap, ok := patch.(map[string]any); ok {
+		targetMap, ok := target.(map[string]any)
+		if !ok {
+			targetMap = make(map[string]any, len(patchMap))
+		}
+		for key, value := range patchMap {
+			if value == nil {
+				delete(targetMap, key)
+				continue
+			}
+			resValue, err := mergePatch(targetMap[key], value, addPathKey(path, key), triggers...)
+			if err != nil {
+				return nil, err
+			}
+			targetMap[key] = resValue
+		}
+		res = targetMap
+	}
+
+	for i := range triggers {
+		val, ok, err := triggers[i](path, patch, target, res)
+		if err != nil {
+			return nil, errors.Wrapf(ErrInvalidPatch, "failed to handle trigger: %s: %s", path, err)
+		}
+		if ok {
+			res = val
+		}
+	}
+
+	return res, nil
+}
+
+// parsePointer parses RFC 6901 JSON pointer to the list of unescaped reference tokens.
+func parsePointer(pointer string) ([]string, error) {
+	if pointer == "" {
+		return nil, nil
+	}
+	if !strings.HasPrefix(pointer, "/") {
+		return nil, errors.Wrapf(ErrInvalidPatch, "invalid pointer: %q", pointer)
+	}
+
+	tokens := strings.Split(pointer[1:], "/")
+	for i := range tokens {
+		tokens[i] = strings.ReplaceAll(strings.ReplaceAll(tokens[i], "~1", "/"), "~0", "~")
+	}
+	return tokens, nil
+}
+
+func parseIndex(token string, max int) (int, error) {
+	if token == "" || (len(token) > 1 && token[0] == '0') {
+		return 0, errors.Wrapf(ErrInvalidPatch, "invalid array index: %q", token)
+	}
+	i, err := strconv.Atoi(token)
+	if err != nil || i < 0 || i > max {
+		return 0, errors.Wrapf(ErrInvalidPatch, "invalid array index: %q", token)
+	}
+	return i, nil
+}
+
+func addPathKey(path, key string) string {
+	res := bytes.NewBufferString(path)
+	if path != "." {
+		res.WriteString(".")
+	}
+	res.WriteString(key)
+	return res.String()
+}
+
+// normalize deep copies value to generic JSON types (map[string]any, []any, float64...).
+func normalize(value any, res *any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(data, res)
+}
@@ -0,0 +1,121 @@
+package patch
+
+import (
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyJSONPatch(t *testing.T) {
+	t.Parallel()
+
+	doc := map[string]any{
+		"name": "world",
+		"tags": []any{"a", "b"},
+		"nested": map[string]any{
+			"a/b": 1,
+			"m~n": 2,
+		},
+	}
+
+	res, err := ApplyJSONPatch(doc, []Operation{
+		{Op: TestOperationType, Path: "/name", Value: "world"},
+		{Op: AddOperationType, Path: "/tags/-", Value: "c"},
+		{Op: AddOperationType, Path: "/tags/0", Value: "z"},
+		{Op: RemoveOperationType, Path: "/tags/1"},
+		{Op: ReplaceOperationType, Path: "/nested/a~1b", Value: 3},
+		{Op: MoveOperationType, From: "/nested/m~0n", Path: "/moved"},
+		{Op: CopyOperationType, From: "/tags", Path: "/copy"},
+	})
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		map[string]any{
+			"name":   "world",
+			"tags":   []any{"z", "b", "c"},
+			"nested": map[string]any{"a/b": float64(3)},
+			"moved":  float64(2),
+			"copy":   []any{"z", "b", "c"},
+		},
+		res,
+	)
+
+	// original document is untouched
+	assert.Equal(t, []any{"a", "b"}, doc["tags"])
+
+	_, err = ApplyJSONPatch(doc, []Operation{{Op: TestOperationType, Path: "/name", Value: "other"}})
+	assert.True(t, errors.Is(err, ErrTestFailed))
+
+	_, err = ApplyJSONPatch(doc, []Operation{{Op: ReplaceOperationType, Path: "/missing", Value: 1}})
+	assert.True(t, errors.Is(err, ErrInvalidPatch))
+
+	_, err = ApplyJSONPatch(doc, []Operation{{Op: AddOperationType, Path: "/tags/5", Value: 1}})
+	assert.True(t, errors.Is(err, ErrInvalidPatch))
+
+	_, err = ApplyJSONPatch(doc, []Operation{{Op: MoveOperationType, From: "/nested", Path: "/nested/child"}})
+	assert.True(t, errors.Is(err, ErrInvalidPatch))
+}
+
+func TestApplyMergePatch(t *testing.T) {
+	t.Parallel()
+
+	doc := map[string]any{
+		"title": "Goodbye!",
+		"author": map[string]any{
+			"givenName":  "John",
+			"familyName": "Doe",
+		},
+		"tags":    []any{"example", "sample"},
+		"content": "This will be unchanged",
+	}
+	patch := map[string]any{
+		"title":       "Hello!",
+		"phoneNumber": "+01-123-456-7890",
+		"author": map[string]any{
+			"familyName": nil,
+		},
+		"tags": []any{"example"},
+	}
+
+	res, err := ApplyMergePatch(doc, patch)
+	assert.NoError(t, err)
+	assert.Equal(
+		t,
+		map[string]any{
+			"title":       "Hello!",
+			"author":      map[string]any{"givenName": "John"},
+			"tags":        []any{"example"},
+			"content":     "This will be unchanged",
+			"phoneNumber": "+01-123-456-7890",
+		},
+		res,
+	)
+
+	triggers, err := MergeTriggers([]string{"/tags"}, []string{"/tags"})
+	assert.NoError(t, err)
+
+	res, err = ApplyMergePatch(doc, map[string]any{"tags": []any{"example", "new"}}, triggers...)
+	assert.NoError(t, err)
+	assert.Equal(t, []any{"example", "sample", "new"}, res["tags"])
+}
+
+func TestETag(t *testing.T) {
+	t.Parallel()
+
+	etag1, err := ETag(map[string]any{"a": 1, "b": []any{"x"}})
+	assert.NoError(t, err)
+	etag2, err := ETag(map[string]any{"b": []any{"x"}, "a": 1})
+	assert.NoError(t, err)
+	assert.Equal(t, etag1, etag2)
+
+	etag3, err := ETag(map[string]any{"a": 2})
+	assert.NoError(t, err)
+	assert.NotEqual(t, etag1, etag3)
+
+	assert.True(t, MatchETag(etag1, etag1))
+	assert.True(t, MatchETag(etag1, etag1[1:len(etag1)-1]))
+	assert.True(t, MatchETag(etag1, etag3+", "+etag1))
+	assert.True(t, MatchETag(etag1, "*"))
+	assert.False(t, MatchETag(etag1, etag3))
+}
@@ -0,0 +1,58 @@
+package ziputil
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"io"
+	"os"
+
+	"github.com/pkg/errors"
+)
+
+// WriteJSON writes the data as indented json file to the archive.
+func WriteJSON(archive *zip.Writer, name string, data any) error {
+	file, err := archive.Create(name)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create file")
+	}
+	encoder := json.NewEncoder(file)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(data)
+}
+
+// CopyFile copies the file from the filepath to the archive.
+func CopyFile(archive *zip.Writer, name string, filepath string) error {
+	src, err := os.Open(filepath)
+	if err != nil {
+		return errors.WithMessage(err, "failed to open file")
+	}
+	defer src.Close()
+
+	return CopyReader(archive, name, src)
+}
+
+// CopyReader copies the content of the reader to the archive.
+func CopyReader(archive *zip.Writer, name string, src io.Reader) error {
+	dst, err := archive.Create(name)
+	if err != nil {
+		return errors.WithMessage(err, "failed to create file")
+	}
+	if _, err := io.Copy(dst, src); err != nil {
+		return errors.WithMessage(err, "failed to copy file")
+	}
+	return nil
+}
+
+// ReadJSON reads the json file from the archive into the data.
+func ReadJSON(archive *zip.Reader, name string, data any) error {
+	file, err := archive.Open(name)
+	if err != nil {
+		return errors.WithMessage(err, "failed to open file")
+	}
+	defer file.Close()
+
+	if err := json.NewDecoder(file).Decode(data); err != nil {
+		return errors.WithMessage(err, "failed to decode file")
+	}
+	return nil
+}